@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eithansmith/master-of-games/db"
+	"github.com/eithansmith/master-of-games/game"
+	"github.com/eithansmith/master-of-games/handlers"
+)
+
+// Database backends, chosen with STORE.
+const (
+	backendPostgres = "postgres" // DATABASE_URL
+	backendSQLite   = "sqlite"   // a single file at SQLITE_PATH
+)
+
+// store is what the app needs from a backend: the handlers' Store plus the audit log.
+type store interface {
+	handlers.Store
+	handlers.AuditLog
+}
+
+// backend is an open database: its store, the readiness check, and how to migrate and close it.
+type backend struct {
+	store    store
+	db       handlers.Pinger
+	migrate  func(ctx context.Context) ([]db.Migration, error)
+	rollback func(ctx context.Context, steps int) ([]db.Migration, error)
+	close    func()
+}
+
+// openBackend connects to the database STORE names. It doesn't apply migrations.
+func openBackend(ctx context.Context, loc *time.Location) (*backend, error) {
+	switch name := env("STORE", backendPostgres); name {
+	case backendPostgres:
+		pool, err := db.NewPool(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &backend{
+			store: game.NewPostgresStore(pool, loc),
+			db:    pool,
+			migrate: func(ctx context.Context) ([]db.Migration, error) {
+				return db.Migrate(ctx, pool, loc)
+			},
+			rollback: func(ctx context.Context, steps int) ([]db.Migration, error) {
+				return db.Rollback(ctx, pool, steps, loc)
+			},
+			close: pool.Close,
+		}, nil
+
+	case backendSQLite:
+		sqlDB, err := db.OpenSQLite(ctx, env("SQLITE_PATH", "master-of-games.db"))
+		if err != nil {
+			return nil, err
+		}
+		st := game.NewSQLiteStore(sqlDB, loc)
+		return &backend{
+			store: st,
+			db:    st,
+			migrate: func(ctx context.Context) ([]db.Migration, error) {
+				return db.MigrateSQLite(ctx, sqlDB)
+			},
+			rollback: func(ctx context.Context, steps int) ([]db.Migration, error) {
+				return db.RollbackSQLite(ctx, sqlDB, steps)
+			},
+			close: func() { _ = sqlDB.Close() },
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown STORE %q (want %s or %s)", name, backendPostgres, backendSQLite)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// runExport implements `server export`, writing the dataset to stdout or a file.
+// It only reads from the database and does not run migrations.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "json (everything) or csv (one table)")
+	table := fs.String("table", "games", "table to export as CSV: "+strings.Join(game.DatasetTables, ", "))
+	out := fs.String("o", "", "output file (default stdout)")
+	_ = fs.Parse(args)
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *format == "csv" && !slices.Contains(game.DatasetTables, *table) {
+		return fmt.Errorf("unknown table %q", *table)
+	}
+
+	loc, err := game.LoadLeagueLocation(env("LEAGUE_TZ", game.DefaultLeagueTimezone))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	be, err := openBackend(ctx, loc)
+	if err != nil {
+		return err
+	}
+	defer be.close()
+
+	d, err := game.LoadDataset(ctx, be.store, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	if *format == "csv" {
+		return d.WriteCSV(w, *table)
+	}
+	return d.WriteJSON(w)
+}
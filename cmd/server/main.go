@@ -2,11 +2,12 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
-	"github.com/eithansmith/master-of-games/db"
 	"github.com/eithansmith/master-of-games/game"
 	"github.com/eithansmith/master-of-games/handlers"
 )
@@ -17,23 +18,73 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	migrateOnly := flag.Bool("migrate", false, "apply pending schema migrations and exit")
+	rollback := flag.Int("rollback", 0, "roll back the N most recently applied migrations and exit")
+	flag.Parse()
+
 	addr := env("PORT", "8080")
 
+	loc, err := game.LoadLeagueLocation(env("LEAGUE_TZ", game.DefaultLeagueTimezone))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	meta := handlers.Meta{
 		Version:   version,
 		BuildTime: buildTime,
 		StartTime: time.Now().UTC().Format(time.RFC3339),
 	}
 
-	pool, err := db.NewPool(context.Background())
+	be, err := openBackend(context.Background(), loc)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer pool.Close()
+	defer be.close()
+
+	if *rollback > 0 {
+		reverted, err := be.rollback(context.Background(), *rollback)
+		for _, m := range reverted {
+			log.Printf("rolled back migration %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Schema changes are applied before any handler can touch the database.
+	applied, err := be.migrate(context.Background())
+	for _, m := range applied {
+		log.Printf("applied migration %04d_%s", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *migrateOnly {
+		return
+	}
+
+	// Every change made through the app is recorded in the audit log.
+	s := handlers.New(handlers.NewAuditStore(be.store, be.store, loc), be.db, meta, loc)
+
+	// A fresh install gets its first admin from the environment.
+	if err := s.BootstrapAdmin(context.Background(), os.Getenv("ADMIN_USER"), os.Getenv("ADMIN_PASS")); err != nil {
+		log.Fatal(err)
+	}
 
-	store := game.NewPostgresStore(pool)
+	// Webhook deliveries are queued and sent in the background for the life of the process.
+	webhooks := s.NewWebhookWorker()
+	go webhooks.Run(context.Background())
 
-	s := handlers.New(store, pool, meta)
+	// Each week is recapped when it closes, and the recap is sent to webhooks.
+	go runWeeklyRecaps(context.Background(), s, loc, webhooks)
 
 	mux := http.NewServeMux()
 
@@ -45,10 +96,10 @@ func main() {
 
 	srv := &http.Server{
 		Addr:              ":" + addr,
-		Handler:           logging(handlers.BasicAuth(mux)),
+		Handler:           logging(mux),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	log.Printf("starting master-of-games version=%s buildTime=%s startTime=%s", meta.Version, meta.BuildTime, meta.StartTime)
+	log.Printf("starting master-of-games version=%s buildTime=%s startTime=%s leagueTZ=%s store=%s", meta.Version, meta.BuildTime, meta.StartTime, loc, env("STORE", backendPostgres))
 	log.Fatal(srv.ListenAndServe())
 }
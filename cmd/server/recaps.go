@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+	"github.com/eithansmith/master-of-games/handlers"
+)
+
+// recapRetry is how soon recaps that couldn't be written or announced are tried again.
+const recapRetry = 5 * time.Minute
+
+// runWeeklyRecaps recaps each week once its Friday has ended in loc and hands it to every
+// notifier, until ctx is done. Each run catches up on every week closed since the last
+// recap, so a server that was down when weeks closed still recaps and announces them.
+func runWeeklyRecaps(ctx context.Context, s *handlers.Server, loc *time.Location, notifiers ...handlers.RecapNotifier) {
+	for {
+		wait := time.Until(game.NextWeekClose(time.Now(), loc))
+		if err := s.RecapClosedWeeks(ctx, notifiers...); err != nil {
+			log.Printf("recaps: %v", err)
+			wait = min(wait, recapRetry)
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
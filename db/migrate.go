@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the pg_advisory_lock key held while migrating,
+// so two machines starting at once don't race each other.
+const migrationLockID = 7_220_260_001
+
+// Migration is one versioned schema change, loaded from NNNN_name.up.sql / NNNN_name.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // empty if the migration has no down file
+}
+
+// Migrations returns the migrations embedded in the binary, ordered by version.
+func Migrations() ([]Migration, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(sub)
+}
+
+// LoadMigrations reads *.up.sql and *.down.sql files from the root of fsys.
+// Every version must have an up file; down files are optional.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationName(e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %04d has conflicting names %q and %q", version, m.Name, name)
+		}
+
+		switch direction {
+		case "up":
+			m.Up = string(b)
+		case "down":
+			m.Down = string(b)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+
+	return out, nil
+}
+
+// parseMigrationName splits "0002_add_results.up.sql" into (2, "add_results", "up").
+func parseMigrationName(file string) (int64, string, string, error) {
+	base := strings.TrimSuffix(file, ".sql")
+
+	var direction string
+	switch {
+	case strings.HasSuffix(base, ".up"):
+		direction = "up"
+	case strings.HasSuffix(base, ".down"):
+		direction = "down"
+	default:
+		return 0, "", "", fmt.Errorf("migration %s: expected .up.sql or .down.sql", file)
+	}
+	base = strings.TrimSuffix(base, "."+direction)
+
+	num, name, ok := strings.Cut(base, "_")
+	if !ok || name == "" {
+		return 0, "", "", fmt.Errorf("migration %s: expected NNNN_name", file)
+	}
+	version, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration %s: invalid version %q", file, num)
+	}
+
+	return version, name, direction, nil
+}
+
+// leagueTZSetting is the session setting a migration reads the league time zone from, as
+// current_setting('app.league_tz'), when it has to reinterpret stored wall-clock times.
+const leagueTZSetting = "app.league_tz"
+
+// Migrate applies every pending embedded migration in version order, each in its own transaction.
+// Migrations see loc, the league time zone, as the app.league_tz setting, so it must be an
+// IANA zone Postgres knows, as game.LoadLeagueLocation returns.
+// It returns the migrations that were applied by this call.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, loc *time.Location) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withMigrationLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if done[m.Version] {
+				continue
+			}
+			if err := runMigration(ctx, conn, m, m.Up, "up", loc); err != nil {
+				return err
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Rollback reverts the `steps` most recently applied migrations using their down files, with
+// loc set as in Migrate. It returns the migrations that were rolled back, newest first.
+func Rollback(ctx context.Context, pool *pgxpool.Pool, steps int, loc *time.Location) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	err = withMigrationLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if !done[m.Version] {
+				continue
+			}
+			if strings.TrimSpace(m.Down) == "" {
+				return fmt.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+			}
+			if err := runMigration(ctx, conn, m, m.Down, "down", loc); err != nil {
+				return err
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func withMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate acquire: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("migrate lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}()
+
+	if _, err := conn.Exec(ctx,
+		`CREATE SCHEMA IF NOT EXISTS app;
+		 CREATE TABLE IF NOT EXISTS app.schema_migrations
+		 (
+		     version    BIGINT PRIMARY KEY,
+		     name       TEXT        NOT NULL,
+		     applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		 );`,
+	); err != nil {
+		return fmt.Errorf("migrate init: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// appliedVersions returns the set of applied versions.
+//
+// Databases created before the runner existed had 0001_init applied by hand. If the version
+// table is empty but app.games already exists, version 1 is recorded as applied (baseline)
+// rather than re-run, since 0001 seeds rows and is not idempotent.
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]bool, error) {
+	rows, err := conn.Query(ctx, `SELECT version FROM app.schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate versions: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("migrate versions: %w", err)
+	}
+
+	done := make(map[int64]bool, len(versions))
+	for _, v := range versions {
+		done[v] = true
+	}
+	if len(done) > 0 {
+		return done, nil
+	}
+
+	var legacy bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('app.games') IS NOT NULL`).Scan(&legacy); err != nil {
+		return nil, fmt.Errorf("migrate baseline: %w", err)
+	}
+	if legacy {
+		if _, err := conn.Exec(ctx,
+			`INSERT INTO app.schema_migrations (version, name) VALUES (1, 'init')`,
+		); err != nil {
+			return nil, fmt.Errorf("migrate baseline: %w", err)
+		}
+		done[1] = true
+	}
+	return done, nil
+}
+
+func runMigration(ctx context.Context, conn *pgxpool.Conn, m Migration, sql, direction string, loc *time.Location) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT set_config($1, $2, true)`, leagueTZSetting, loc.String()); err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+
+	if direction == "up" {
+		_, err = tx.Exec(ctx, `INSERT INTO app.schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+	} else {
+		_, err = tx.Exec(ctx, `DELETE FROM app.schema_migrations WHERE version = $1`, m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s %s record: %w", m.Version, m.Name, direction, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("migration %04d_%s %s commit: %w", m.Version, m.Name, direction, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations_OrderedAndPaired(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_results.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"0002_results.down.sql": {Data: []byte("DROP TABLE b;")},
+		"0001_init.up.sql":      {Data: []byte("CREATE TABLE a ();")},
+		"0010_late.up.sql":      {Data: []byte("CREATE TABLE c ();")},
+		"README.md":             {Data: []byte("ignored")},
+	}
+
+	got, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3", len(got))
+	}
+
+	wantVersions := []int64{1, 2, 10}
+	for i, m := range got {
+		if m.Version != wantVersions[i] {
+			t.Errorf("got[%d].Version = %d, want %d", i, m.Version, wantVersions[i])
+		}
+	}
+	if got[1].Name != "results" || got[1].Down != "DROP TABLE b;" {
+		t.Errorf("got[1] = %+v, want name=results with down SQL", got[1])
+	}
+	if got[0].Down != "" {
+		t.Errorf("got[0].Down = %q, want empty", got[0].Down)
+	}
+}
+
+func TestLoadMigrations_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no direction", fstest.MapFS{"0001_init.sql": {Data: []byte("x")}}},
+		{"no name", fstest.MapFS{"0001.up.sql": {Data: []byte("x")}}},
+		{"bad version", fstest.MapFS{"abc_init.up.sql": {Data: []byte("x")}}},
+		{"down only", fstest.MapFS{"0001_init.down.sql": {Data: []byte("x")}}},
+		{"name mismatch", fstest.MapFS{
+			"0001_init.up.sql":  {Data: []byte("x")},
+			"0001_other.up.sql": {Data: []byte("y")},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadMigrations(tc.fsys); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMigrations_Embedded(t *testing.T) {
+	got, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Version != 1 || got[0].Name != "init" {
+		t.Fatalf("embedded migrations = %+v, want 0001_init first", got)
+	}
+	for _, m := range got {
+		if m.Down == "" {
+			t.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE app.games
+    DROP COLUMN IF EXISTS results;
@@ -0,0 +1,4 @@
+-- Optional per-participant placements and scores: a JSON array of
+-- {"PlayerID": 1, "Position": 1, "Score": 42}, where Position and Score may be 0/null.
+ALTER TABLE app.games
+    ADD COLUMN IF NOT EXISTS results jsonb DEFAULT '[]'::jsonb NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE app.games
+    DROP COLUMN IF EXISTS teams,
+    DROP COLUMN IF EXISTS mode;
@@ -0,0 +1,6 @@
+-- Team and co-op games. teams is a JSON array of player-ID arrays, one per team,
+-- and is empty for other modes. A co-op game's winner_ids is everyone or no one.
+ALTER TABLE app.games
+    ADD COLUMN IF NOT EXISTS mode  text  DEFAULT 'competitive' NOT NULL
+        CONSTRAINT chk_games_mode CHECK (mode IN ('competitive', 'team', 'coop')),
+    ADD COLUMN IF NOT EXISTS teams jsonb DEFAULT '[]'::jsonb   NOT NULL;
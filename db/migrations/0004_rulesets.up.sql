@@ -0,0 +1,12 @@
+-- Scoring rulesets. Each applies to periods starting on or after effective_from (a league-
+-- time date) until the next one takes over; periods before the first use the built-in
+-- default rules. weekly and yearly hold game.PeriodRules as JSON.
+CREATE TABLE IF NOT EXISTS app.rulesets
+(
+    id             BIGSERIAL PRIMARY KEY,
+    name           TEXT        NOT NULL,
+    effective_from DATE        NOT NULL UNIQUE,
+    weekly         JSONB       NOT NULL,
+    yearly         JSONB       NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,12 @@
+-- Named seasons: custom date ranges with their own standings, race and tiebreaker (scope
+-- 'season', keyed by the season's dates). start_date and end_date are inclusive league-time
+-- dates.
+CREATE TABLE IF NOT EXISTS app.seasons
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL UNIQUE,
+    start_date DATE        NOT NULL,
+    end_date   DATE        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (end_date >= start_date)
+);
@@ -0,0 +1,19 @@
+-- Every tiebreaker decision, oldest first. app.tiebreakers holds only the latest decision
+-- per (scope, scope_key); this keeps the ones it replaced.
+CREATE TABLE IF NOT EXISTS app.tiebreaker_history
+(
+    id         BIGSERIAL PRIMARY KEY,
+    scope      TEXT        NOT NULL,
+    scope_key  TEXT        NOT NULL,
+    data       JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS tiebreaker_history_scope_idx
+    ON app.tiebreaker_history (scope, scope_key, id);
+
+-- Start the history with the decisions already stored.
+INSERT INTO app.tiebreaker_history (scope, scope_key, data, created_at)
+SELECT scope, scope_key, data, updated_at
+FROM app.tiebreakers
+ORDER BY updated_at;
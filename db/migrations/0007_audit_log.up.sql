@@ -0,0 +1,16 @@
+-- Append-only record of every change made through the app: who, what, and the entity
+-- before and after as JSON (NULL when it didn't exist).
+CREATE TABLE IF NOT EXISTS app.audit_log
+(
+    id        BIGSERIAL PRIMARY KEY,
+    at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    actor     TEXT        NOT NULL,
+    action    TEXT        NOT NULL,
+    entity    TEXT        NOT NULL,
+    entity_id TEXT        NOT NULL DEFAULT '',
+    before    JSONB,
+    after     JSONB
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_at_idx ON app.audit_log (at);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON app.audit_log (entity, entity_id);
@@ -0,0 +1,25 @@
+-- Sign-in accounts, optionally linked to the player they play as. Passwords are stored as
+-- PBKDF2 hashes (see game.HashPassword).
+CREATE TABLE IF NOT EXISTS app.users
+(
+    id            BIGSERIAL PRIMARY KEY,
+    username      TEXT        NOT NULL,
+    password_hash TEXT        NOT NULL,
+    role          TEXT        NOT NULL DEFAULT 'viewer' CHECK (role IN ('viewer', 'recorder', 'admin')),
+    player_id     BIGINT REFERENCES app.players (id) ON DELETE SET NULL,
+    is_active     BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_idx ON app.users (lower(username));
+
+-- Signed-in browsers, keyed by a SHA-256 of the session cookie.
+CREATE TABLE IF NOT EXISTS app.sessions
+(
+    token_hash TEXT PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES app.users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON app.sessions (user_id);
@@ -0,0 +1,12 @@
+-- Named credentials for scripts and bots. Only a SHA-256 of each token is stored.
+CREATE TABLE IF NOT EXISTS app.api_tokens
+(
+    id           BIGSERIAL PRIMARY KEY,
+    name         TEXT        NOT NULL,
+    token_hash   TEXT        NOT NULL UNIQUE,
+    scope        TEXT        NOT NULL CHECK (scope IN ('read', 'write:games')),
+    created_by   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
@@ -0,0 +1,10 @@
+-- Weekly recaps, written once when each week closes. The recap is kept as JSON, as it was
+-- when written, so later edits to the week's games don't rewrite history.
+CREATE TABLE IF NOT EXISTS app.week_recaps
+(
+    year       INT         NOT NULL,
+    week       INT         NOT NULL,
+    data       JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (year, week)
+);
@@ -0,0 +1,2 @@
+UPDATE app.games
+SET played_at = (played_at AT TIME ZONE current_setting('app.league_tz')) AT TIME ZONE 'UTC';
@@ -0,0 +1,6 @@
+-- Games logged before the league time zone was configurable stored the wall-clock time that
+-- was entered, tagged as UTC. Reinterpret those times in the league time zone (LEAGUE_TZ,
+-- passed in as app.league_tz) so each game keeps the day, week and year it was logged for.
+-- SQLite databases were created after the change and need no counterpart.
+UPDATE app.games
+SET played_at = (played_at AT TIME ZONE 'UTC') AT TIME ZONE current_setting('app.league_tz');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS app.webhook_deliveries_key_idx;
+
+ALTER TABLE app.webhook_deliveries DROP COLUMN IF EXISTS dedupe_key;
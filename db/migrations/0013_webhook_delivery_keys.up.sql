@@ -0,0 +1,5 @@
+-- What a delivery announces, e.g. "tie.needs_breaking:2026-W07:[1 2]", so each webhook hears
+-- about it once across restarts and app instances. NULL for deliveries that are never repeated.
+ALTER TABLE app.webhook_deliveries ADD COLUMN IF NOT EXISTS dedupe_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_key_idx ON app.webhook_deliveries (webhook_id, dedupe_key);
@@ -0,0 +1 @@
+ALTER TABLE app.week_recaps DROP COLUMN IF EXISTS notified_at;
@@ -0,0 +1,6 @@
+-- When a recap was handed to every notifier. A recap without one is announced again until it
+-- is, so a failed or interrupted announcement isn't lost.
+ALTER TABLE app.week_recaps ADD COLUMN IF NOT EXISTS notified_at TIMESTAMPTZ;
+
+-- Recaps from before this migration were announced when they were written.
+UPDATE app.week_recaps SET notified_at = created_at WHERE notified_at IS NULL;
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" database/sql driver
+)
+
+//go:embed sqlite_migrations/*.sql
+var sqliteMigrationFiles embed.FS
+
+// OpenSQLite opens (creating if needed) the SQLite database file at path with foreign keys
+// enforced. The pool holds a single connection: SQLite allows one writer at a time, and a
+// single connection keeps transactions from waiting on each other.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("SQLITE_PATH is not set")
+	}
+
+	q := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"}}
+	sqlDB, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("open sqlite %s: %w", path, err)
+	}
+	return sqlDB, nil
+}
+
+// SQLiteMigrations returns the SQLite schema migrations embedded in the binary, ordered by
+// version. They mirror the Postgres migrations: the first creates the schema as of
+// 0009_api_tokens, and each later Postgres schema change needs a SQLite counterpart.
+func SQLiteMigrations() ([]Migration, error) {
+	sub, err := fs.Sub(sqliteMigrationFiles, "sqlite_migrations")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(sub)
+}
+
+// MigrateSQLite applies every pending SQLite migration in version order, each in its own
+// transaction. It returns the migrations that were applied by this call.
+func MigrateSQLite(ctx context.Context, sqlDB *sql.DB) ([]Migration, error) {
+	migrations, err := SQLiteMigrations()
+	if err != nil {
+		return nil, err
+	}
+	done, err := sqliteAppliedVersions(ctx, sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if done[m.Version] {
+			continue
+		}
+		if err := runSQLiteMigration(ctx, sqlDB, m, m.Up, "up"); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// RollbackSQLite reverts the `steps` most recently applied SQLite migrations using their
+// down files. It returns the migrations that were rolled back, newest first.
+func RollbackSQLite(ctx context.Context, sqlDB *sql.DB, steps int) ([]Migration, error) {
+	migrations, err := SQLiteMigrations()
+	if err != nil {
+		return nil, err
+	}
+	done, err := sqliteAppliedVersions(ctx, sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		m := migrations[i]
+		if !done[m.Version] {
+			continue
+		}
+		if strings.TrimSpace(m.Down) == "" {
+			return reverted, fmt.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+		}
+		if err := runSQLiteMigration(ctx, sqlDB, m, m.Down, "down"); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, m)
+	}
+	return reverted, nil
+}
+
+// sqliteAppliedVersions returns the set of applied versions, creating the version table on
+// first use.
+func sqliteAppliedVersions(ctx context.Context, sqlDB *sql.DB) (map[int64]bool, error) {
+	if _, err := sqlDB.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations
+		 (
+		     version    INTEGER PRIMARY KEY,
+		     name       TEXT NOT NULL,
+		     applied_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+		 )`,
+	); err != nil {
+		return nil, fmt.Errorf("migrate init: %w", err)
+	}
+
+	rows, err := sqlDB.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	done := map[int64]bool{}
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("migrate versions: %w", err)
+		}
+		done[v] = true
+	}
+	return done, rows.Err()
+}
+
+func runSQLiteMigration(ctx context.Context, sqlDB *sql.DB, m Migration, script, direction string) error {
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", m.Version, m.Name, direction, err)
+	}
+
+	if direction == "up" {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.Version, m.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s %s record: %w", m.Version, m.Name, direction, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration %04d_%s %s commit: %w", m.Version, m.Name, direction, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS rulesets;
+DROP TABLE IF EXISTS tiebreaker_history;
+DROP TABLE IF EXISTS tiebreakers;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS titles;
+DROP TABLE IF EXISTS players;
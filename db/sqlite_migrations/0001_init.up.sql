@@ -0,0 +1,170 @@
+-- The schema of the Postgres migrations 0001_init through 0009_api_tokens, for SQLite.
+--
+-- Times are TEXT in UTC as 2006-01-02T15:04:05.000000Z, so they sort and compare as
+-- strings; dates are TEXT as 2006-01-02. Booleans are 0/1 and ID lists are JSON arrays.
+
+CREATE TABLE players
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT    NOT NULL UNIQUE,
+    is_active  INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_players_is_active ON players (is_active);
+
+-- noinspection SpellCheckingInspection
+INSERT INTO players (name)
+VALUES ('AFAILLA'),
+       ('AMAAG'),
+       ('BAIRD'),
+       ('CNEUTZLING'),
+       ('DSCHMITT'),
+       ('ESMITH'),
+       ('EZAMORA'),
+       ('JWHITTEMORE'),
+       ('LCOOK'),
+       ('LGRAVOT'),
+       ('LWOOTTEN'),
+       ('RSTEUER'),
+       ('RWALL'),
+       ('SBLUE'),
+       ('TRIEDER'),
+       ('TSUMPTER'),
+       ('TCOX');
+
+CREATE TABLE titles
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT    NOT NULL UNIQUE,
+    is_active  INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_titles_is_active ON titles (is_active);
+
+INSERT INTO titles (name)
+VALUES ('Bang'),
+       ('Camel Up'),
+       ('Cockroach Poker'),
+       ('Coup'),
+       ('Dice Forge'),
+       ('Don''t LLAMA'),
+       ('Flip 7'),
+       ('King of New York'),
+       ('King of Tokyo'),
+       ('Martian Dice'),
+       ('Steampunk Rally'),
+       ('Strike Dice'),
+       ('Take 5'),
+       ('Zombie Dice');
+
+CREATE TABLE games
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    played_at       TEXT    NOT NULL,
+    created_at      TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    title_id        INTEGER NOT NULL REFERENCES titles (id),
+    mode            TEXT    NOT NULL DEFAULT 'competitive' CHECK (mode IN ('competitive', 'team', 'coop')),
+    participant_ids TEXT    NOT NULL DEFAULT '[]',
+    winner_ids      TEXT    NOT NULL DEFAULT '[]',
+    teams           TEXT    NOT NULL DEFAULT '[]',
+    results         TEXT    NOT NULL DEFAULT '[]',
+    notes           TEXT    NOT NULL DEFAULT '',
+    is_active       INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_games_played_at ON games (played_at DESC);
+CREATE INDEX idx_games_is_active ON games (is_active);
+
+-- The latest decision per period, and every decision in tiebreaker_history.
+CREATE TABLE tiebreakers
+(
+    scope      TEXT NOT NULL,
+    scope_key  TEXT NOT NULL,
+    data       TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY (scope, scope_key)
+);
+
+CREATE TABLE tiebreaker_history
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    scope      TEXT NOT NULL,
+    scope_key  TEXT NOT NULL,
+    data       TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX tiebreaker_history_scope_idx ON tiebreaker_history (scope, scope_key, id);
+
+CREATE TABLE rulesets
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    name           TEXT NOT NULL,
+    effective_from TEXT NOT NULL UNIQUE,
+    weekly         TEXT NOT NULL,
+    yearly         TEXT NOT NULL,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE seasons
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL UNIQUE,
+    start_date TEXT NOT NULL,
+    end_date   TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CHECK (end_date >= start_date)
+);
+
+CREATE TABLE audit_log
+(
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    at        TEXT NOT NULL,
+    actor     TEXT NOT NULL,
+    action    TEXT NOT NULL,
+    entity    TEXT NOT NULL,
+    entity_id TEXT NOT NULL DEFAULT '',
+    before    TEXT,
+    after     TEXT
+);
+
+CREATE INDEX audit_log_at_idx ON audit_log (at);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity, entity_id);
+
+CREATE TABLE users
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    username      TEXT    NOT NULL,
+    password_hash TEXT    NOT NULL,
+    role          TEXT    NOT NULL DEFAULT 'viewer' CHECK (role IN ('viewer', 'recorder', 'admin')),
+    player_id     INTEGER REFERENCES players (id) ON DELETE SET NULL,
+    is_active     INTEGER NOT NULL DEFAULT 1,
+    created_at    TEXT    NOT NULL
+);
+
+CREATE UNIQUE INDEX users_username_idx ON users (lower(username));
+
+CREATE TABLE sessions
+(
+    token_hash TEXT PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TEXT    NOT NULL,
+    expires_at TEXT    NOT NULL
+);
+
+CREATE INDEX sessions_user_idx ON sessions (user_id);
+
+CREATE TABLE api_tokens
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    name         TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    scope        TEXT NOT NULL CHECK (scope IN ('read', 'write:games')),
+    created_by   TEXT NOT NULL DEFAULT '',
+    created_at   TEXT NOT NULL,
+    last_used_at TEXT,
+    revoked_at   TEXT
+);
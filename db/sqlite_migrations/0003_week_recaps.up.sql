@@ -0,0 +1,9 @@
+-- Postgres migration 0011_week_recaps, for SQLite.
+CREATE TABLE week_recaps
+(
+    year       INTEGER NOT NULL,
+    week       INTEGER NOT NULL,
+    data       TEXT    NOT NULL,
+    created_at TEXT    NOT NULL,
+    PRIMARY KEY (year, week)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS webhook_deliveries_key_idx;
+
+ALTER TABLE webhook_deliveries DROP COLUMN dedupe_key;
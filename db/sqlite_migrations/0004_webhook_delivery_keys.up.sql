@@ -0,0 +1,4 @@
+-- Postgres migration 0013_webhook_delivery_keys, for SQLite.
+ALTER TABLE webhook_deliveries ADD COLUMN dedupe_key TEXT;
+
+CREATE UNIQUE INDEX webhook_deliveries_key_idx ON webhook_deliveries (webhook_id, dedupe_key);
@@ -0,0 +1 @@
+ALTER TABLE week_recaps DROP COLUMN notified_at;
@@ -0,0 +1,4 @@
+-- Postgres migration 0014_week_recap_notified, for SQLite.
+ALTER TABLE week_recaps ADD COLUMN notified_at TEXT;
+
+UPDATE week_recaps SET notified_at = created_at WHERE notified_at IS NULL;
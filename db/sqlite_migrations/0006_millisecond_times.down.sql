@@ -0,0 +1,2 @@
+-- The microseconds cut by the up migration can't be restored; millisecond times read fine.
+SELECT 1;
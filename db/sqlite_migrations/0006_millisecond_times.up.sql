@@ -0,0 +1,17 @@
+-- SQLite only: times written from Go used to carry microseconds, while the strftime column
+-- defaults write milliseconds. Cut the older values to milliseconds so every stored time
+-- has the same width and sorts in time order.
+UPDATE games SET played_at = substr(played_at, 1, 23) || 'Z' WHERE length(played_at) = 27;
+UPDATE audit_log SET at = substr(at, 1, 23) || 'Z' WHERE length(at) = 27;
+UPDATE users SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE sessions SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE sessions SET expires_at = substr(expires_at, 1, 23) || 'Z' WHERE length(expires_at) = 27;
+UPDATE api_tokens SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE api_tokens SET last_used_at = substr(last_used_at, 1, 23) || 'Z' WHERE length(last_used_at) = 27;
+UPDATE api_tokens SET revoked_at = substr(revoked_at, 1, 23) || 'Z' WHERE length(revoked_at) = 27;
+UPDATE webhooks SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE webhook_deliveries SET next_attempt_at = substr(next_attempt_at, 1, 23) || 'Z' WHERE length(next_attempt_at) = 27;
+UPDATE webhook_deliveries SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE webhook_deliveries SET delivered_at = substr(delivered_at, 1, 23) || 'Z' WHERE length(delivered_at) = 27;
+UPDATE week_recaps SET created_at = substr(created_at, 1, 23) || 'Z' WHERE length(created_at) = 27;
+UPDATE week_recaps SET notified_at = substr(notified_at, 1, 23) || 'Z' WHERE length(notified_at) = 27;
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateSQLite_UpAndDown(t *testing.T) {
+	ctx := context.Background()
+	sqlDB, err := OpenSQLite(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	all, err := SQLiteMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := MigrateSQLite(ctx, sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(all) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(all))
+	}
+	if again, err := MigrateSQLite(ctx, sqlDB); err != nil || len(again) != 0 {
+		t.Fatalf("second run applied %d (err %v), want 0", len(again), err)
+	}
+
+	var players int
+	if err := sqlDB.QueryRowContext(ctx, `SELECT count(*) FROM players`).Scan(&players); err != nil {
+		t.Fatal(err)
+	}
+	if players == 0 {
+		t.Error("no seeded players")
+	}
+
+	reverted, err := RollbackSQLite(ctx, sqlDB, len(all))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(all) {
+		t.Fatalf("rolled back %d migrations, want %d", len(reverted), len(all))
+	}
+	if err := sqlDB.QueryRowContext(ctx, `SELECT count(*) FROM players`).Scan(&players); err == nil {
+		t.Error("players table survived the rollback")
+	}
+}
+
+func TestOpenSQLite_RequiresPath(t *testing.T) {
+	if _, err := OpenSQLite(context.Background(), ""); err == nil {
+		t.Error("want error for an empty path")
+	}
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditEntry is one recorded change. Entries are append-only.
+type AuditEntry struct {
+	ID       int64
+	At       time.Time
+	Actor    string // who made the change, e.g. the signed-in user
+	Action   string // AuditCreate, AuditUpdate, ...
+	Entity   string // AuditGame, AuditPlayer, ...
+	EntityID string // "42", or "weekly/2026-W07" for a tiebreaker
+
+	// Before and After are JSON snapshots of the entity; nil when it didn't exist.
+	Before []byte
+	After  []byte
+}
+
+// Audit actions.
+const (
+	AuditCreate     = "create"
+	AuditUpdate     = "update"
+	AuditActivate   = "activate"
+	AuditDeactivate = "deactivate"
+	AuditDelete     = "delete"
+	AuditDecide     = "decide" // a tiebreaker was recorded
+	AuditImport     = "import"
+	AuditRevoke     = "revoke" // an API token was revoked
+)
+
+// Audited entities.
+const (
+	AuditGame       = "game"
+	AuditPlayer     = "player"
+	AuditTitle      = "title"
+	AuditTiebreaker = "tiebreaker"
+	AuditRuleset    = "ruleset"
+	AuditSeason     = "season"
+	AuditDataset    = "dataset"
+	AuditUser       = "user"
+	AuditAPIToken   = "api_token"
+	AuditWebhook    = "webhook"
+)
+
+// AuditActions and AuditEntities list the values above, for filters.
+var (
+	AuditActions  = []string{AuditCreate, AuditUpdate, AuditActivate, AuditDeactivate, AuditDelete, AuditDecide, AuditImport, AuditRevoke}
+	AuditEntities = []string{AuditGame, AuditPlayer, AuditTitle, AuditTiebreaker, AuditRuleset, AuditSeason, AuditDataset, AuditUser, AuditAPIToken, AuditWebhook}
+)
+
+// AuditFilter narrows a list of audit entries. Zero fields match everything.
+type AuditFilter struct {
+	Actor    string // case-insensitive exact match
+	Action   string
+	Entity   string
+	EntityID string
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Limit    int       // 0 = no limit
+}
+
+// Match reports whether e passes every set field of f (Limit aside).
+func (f AuditFilter) Match(e AuditEntry) bool {
+	switch {
+	case f.Actor != "" && !strings.EqualFold(f.Actor, e.Actor):
+		return false
+	case f.Action != "" && f.Action != e.Action:
+		return false
+	case f.Entity != "" && f.Entity != e.Entity:
+		return false
+	case f.EntityID != "" && f.EntityID != e.EntityID:
+		return false
+	case !f.From.IsZero() && e.At.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.At.Before(f.To):
+		return false
+	}
+	return true
+}
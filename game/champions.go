@@ -0,0 +1,108 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// PeriodChampion is the result of one week or one year.
+type PeriodChampion struct {
+	Scope    string // "weekly" | "yearly"
+	ScopeKey string // "2026-W07" | "2026"
+	Year     int    // ISO year for weeks, calendar year for years
+	Week     int    // 0 for years
+
+	Games         int
+	TopIDs        []int64
+	WinnerID      *int64
+	TieUnresolved bool
+	StaleTiebreak bool // a stored tiebreaker was decided among other players; see Standings
+	InProgress    bool // the period hasn't ended yet, so the winner is only the current leader
+}
+
+type ChampionYear struct {
+	Year   int
+	Yearly *PeriodChampion  // nil when no games were played in the calendar year
+	Weeks  []PeriodChampion // ISO weeks of this ISO year with games, newest first
+}
+
+type HallOfChampions struct {
+	Years []ChampionYear // newest first
+}
+
+// ComputeHallOfChampions runs the weekly and yearly standings for every period with active
+// games, each under the ruleset in force when it started (see RulesetFor) and applying
+// stored tiebreakers through getTB. Periods are judged in loc; those that haven't ended by
+// now are marked InProgress.
+func ComputeHallOfChampions(
+	games []Game,
+	loc *time.Location,
+	now time.Time,
+	rulesets []Ruleset,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) HallOfChampions {
+	type isoWeek struct{ year, week int }
+	byWeek := map[isoWeek][]Game{}
+	byYear := map[int][]Game{}
+
+	for _, g := range games {
+		if !g.IsActive {
+			continue
+		}
+		local := g.PlayedAt.In(loc)
+		y, w := local.ISOWeek()
+		byWeek[isoWeek{y, w}] = append(byWeek[isoWeek{y, w}], g)
+		byYear[local.Year()] = append(byYear[local.Year()], g)
+	}
+
+	years := map[int]*ChampionYear{}
+	yearOf := func(y int) *ChampionYear {
+		if years[y] == nil {
+			years[y] = &ChampionYear{Year: y}
+		}
+		return years[y]
+	}
+
+	for wk, wg := range byWeek {
+		start, end := WeekBounds(wk.year, wk.week, loc)
+		ws := ComputeWeekStandings(wg, wk.year, wk.week, loc, RulesetFor(rulesets, start).Weekly, getTB)
+		cy := yearOf(wk.year)
+		cy.Weeks = append(cy.Weeks, PeriodChampion{
+			Scope:         "weekly",
+			ScopeKey:      ws.ScopeKey,
+			Year:          wk.year,
+			Week:          wk.week,
+			Games:         ws.TotalGames,
+			TopIDs:        ws.TopIDs,
+			WinnerID:      ws.WinnerID,
+			TieUnresolved: ws.TieUnresolved,
+			StaleTiebreak: ws.StaleTiebreaker != nil,
+			InProgress:    end.After(now),
+		})
+	}
+
+	for y, yg := range byYear {
+		start, end := YearBounds(y, loc)
+		ys := ComputeYearStandings(yg, y, loc, RulesetFor(rulesets, start).Yearly, getTB)
+		yearOf(y).Yearly = &PeriodChampion{
+			Scope:         "yearly",
+			ScopeKey:      ys.ScopeKey,
+			Year:          y,
+			Games:         len(yg),
+			TopIDs:        ys.TopIDs,
+			WinnerID:      ys.WinnerID,
+			TieUnresolved: ys.TieUnresolved,
+			StaleTiebreak: ys.StaleTiebreaker != nil,
+			InProgress:    end.After(now),
+		}
+	}
+
+	var hall HallOfChampions
+	for _, cy := range years {
+		sort.Slice(cy.Weeks, func(i, j int) bool { return cy.Weeks[i].Week > cy.Weeks[j].Week })
+		hall.Years = append(hall.Years, *cy)
+	}
+	sort.Slice(hall.Years, func(i, j int) bool { return hall.Years[i].Year > hall.Years[j].Year })
+
+	return hall
+}
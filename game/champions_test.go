@@ -0,0 +1,58 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeHallOfChampions(t *testing.T) {
+	games := []Game{
+		profileGame(1, day(2025, 3, 3), 1, []int64{1, 2}, []int64{1}),  // 2025-W10
+		profileGame(2, day(2026, 1, 5), 1, []int64{1, 2}, []int64{2}),  // 2026-W02
+		profileGame(3, day(2026, 1, 12), 1, []int64{1, 2}, []int64{1}), // 2026-W03 tie
+		profileGame(4, day(2026, 1, 13), 1, []int64{1, 2}, []int64{2}),
+	}
+	now := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC) // mid 2026-W03
+
+	hall := ComputeHallOfChampions(games, time.UTC, now, nil, tbFor(YearScopeKey(2025), 2, 1, 2))
+
+	if len(hall.Years) != 2 || hall.Years[0].Year != 2026 || hall.Years[1].Year != 2025 {
+		t.Fatalf("years = %+v, want 2026 then 2025", hall.Years)
+	}
+
+	y26 := hall.Years[0]
+	if len(y26.Weeks) != 2 || y26.Weeks[0].Week != 3 || y26.Weeks[1].Week != 2 {
+		t.Fatalf("2026 weeks = %+v, want W03 then W02", y26.Weeks)
+	}
+	if w := y26.Weeks[0]; !w.InProgress || !w.TieUnresolved || w.WinnerID != nil || len(w.TopIDs) != 2 {
+		t.Errorf("W03 = %+v, want in progress unresolved tie", w)
+	}
+	if w := y26.Weeks[1]; w.InProgress || w.WinnerID == nil || *w.WinnerID != 2 || w.Games != 1 {
+		t.Errorf("W02 = %+v, want finished with winner 2", w)
+	}
+	if y26.Yearly == nil || !y26.Yearly.InProgress || y26.Yearly.Games != 3 {
+		t.Errorf("2026 yearly = %+v, want in progress with 3 games", y26.Yearly)
+	}
+
+	// 2025 has a single game won by 1; the stored tiebreaker is for a tie that doesn't exist.
+	y25 := hall.Years[1]
+	if y25.Yearly == nil || y25.Yearly.InProgress || y25.Yearly.WinnerID == nil || *y25.Yearly.WinnerID != 1 {
+		t.Errorf("2025 yearly = %+v, want finished with winner 1", y25.Yearly)
+	}
+}
+
+func TestComputeHallOfChampions_ISOWeekYear(t *testing.T) {
+	// 2027-01-01 is a Friday in 2026-W53: the week belongs to 2026, the year to 2027.
+	games := []Game{profileGame(1, day(2027, 1, 1), 1, []int64{1}, []int64{1})}
+	hall := ComputeHallOfChampions(games, time.UTC, day(2028, 1, 1), nil, noTB)
+
+	if len(hall.Years) != 2 {
+		t.Fatalf("years = %+v, want 2027 (yearly) and 2026 (week)", hall.Years)
+	}
+	if hall.Years[0].Yearly == nil || len(hall.Years[0].Weeks) != 0 {
+		t.Errorf("2027 = %+v, want yearly only", hall.Years[0])
+	}
+	if hall.Years[1].Yearly != nil || len(hall.Years[1].Weeks) != 1 || hall.Years[1].Weeks[0].Week != 53 {
+		t.Errorf("2026 = %+v, want W53 only", hall.Years[1])
+	}
+}
@@ -0,0 +1,678 @@
+package game
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatasetVersion is written to every JSON export so future formats can be told apart.
+const DatasetVersion = 1
+
+// DatasetTables lists the tables a dataset can be exported or imported as CSV, in import order.
+var DatasetTables = []string{"players", "titles", "games", "tiebreakers", "rulesets", "seasons"}
+
+// csvListSep joins multi-valued CSV cells (participants, winners, tied players).
+const csvListSep = ";"
+
+// A Dataset is the whole league in a portable form. Players and titles are referenced
+// by name rather than ID, so a dataset can be imported into a different database.
+type Dataset struct {
+	Version     int                 `json:"version"`
+	ExportedAt  time.Time           `json:"exported_at"`
+	Players     []DatasetPlayer     `json:"players"`
+	Titles      []DatasetTitle      `json:"titles"`
+	Games       []DatasetGame       `json:"games"`
+	Tiebreakers []DatasetTiebreaker `json:"tiebreakers"`
+	Rulesets    []DatasetRuleset    `json:"rulesets,omitempty"`
+	Seasons     []DatasetSeason     `json:"seasons,omitempty"`
+}
+
+type DatasetPlayer struct {
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type DatasetTitle struct {
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type DatasetGame struct {
+	PlayedAt     time.Time       `json:"played_at"`
+	Title        string          `json:"title"`
+	Mode         string          `json:"mode,omitempty"` // empty means competitive
+	Participants []string        `json:"participants"`
+	Winners      []string        `json:"winners"`
+	Teams        [][]string      `json:"teams,omitempty"`
+	Results      []DatasetResult `json:"results,omitempty"`
+	Notes        string          `json:"notes"`
+	IsActive     bool            `json:"is_active"`
+}
+
+type DatasetResult struct {
+	Player   string `json:"player"`
+	Position int    `json:"position,omitempty"`
+	Score    *int   `json:"score,omitempty"`
+}
+
+type DatasetTiebreaker struct {
+	Scope     string    `json:"scope"`
+	ScopeKey  string    `json:"scope_key"`
+	Tied      []string  `json:"tied"`
+	Winner    string    `json:"winner"`
+	Method    string    `json:"method"`
+	DecidedAt time.Time `json:"decided_at"`
+	Seed      string    `json:"seed,omitempty"`      // set for a server draw
+	Algorithm string    `json:"algorithm,omitempty"` // set for a server draw
+
+	PlayoffGame *DatasetGameRef `json:"playoff_game,omitempty"` // set for a play-off
+}
+
+// DatasetGameRef identifies a game by when and what was played, e.g. a play-off game.
+type DatasetGameRef struct {
+	PlayedAt time.Time `json:"played_at"`
+	Title    string    `json:"title"`
+}
+
+type DatasetRuleset struct {
+	Name          string       `json:"name"`
+	EffectiveFrom string       `json:"effective_from"` // YYYY-MM-DD in league time
+	Weekly        DatasetRules `json:"weekly"`
+	Yearly        DatasetRules `json:"yearly"`
+}
+
+type DatasetRules struct {
+	Metric        string `json:"metric"`
+	Qualifier     string `json:"qualifier"`
+	MinAttendance int    `json:"min_attendance"`
+	TiePolicy     string `json:"tie_policy"`
+}
+
+// Ruleset converts dr, reading EffectiveFrom as a date in loc. The rules aren't validated.
+func (dr DatasetRuleset) Ruleset(loc *time.Location) (Ruleset, error) {
+	from, err := time.ParseInLocation("2006-01-02", dr.EffectiveFrom, loc)
+	if err != nil {
+		return Ruleset{}, errors.New("effective_from must look like 2026-01-31")
+	}
+	rules := func(r DatasetRules) PeriodRules {
+		return PeriodRules{Metric: r.Metric, Qualifier: r.Qualifier, MinAttendance: r.MinAttendance, TiePolicy: r.TiePolicy}
+	}
+	return Ruleset{Name: dr.Name, EffectiveFrom: from, Weekly: rules(dr.Weekly), Yearly: rules(dr.Yearly)}, nil
+}
+
+type DatasetSeason struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD in league time
+	EndDate   string `json:"end_date"`   // inclusive
+}
+
+// Season converts ds, reading its dates in loc. The season isn't validated.
+func (ds DatasetSeason) Season(loc *time.Location) (Season, error) {
+	start, err := time.ParseInLocation("2006-01-02", ds.StartDate, loc)
+	if err != nil {
+		return Season{}, errors.New("start_date must look like 2026-01-31")
+	}
+	end, err := time.ParseInLocation("2006-01-02", ds.EndDate, loc)
+	if err != nil {
+		return Season{}, errors.New("end_date must look like 2026-01-31")
+	}
+	return Season{Name: ds.Name, StartDate: start, EndDate: end}, nil
+}
+
+// ImportSummary counts what an import changed.
+type ImportSummary struct {
+	PlayersAdded   int
+	TitlesAdded    int
+	GamesAdded     int
+	GamesSkipped   int // already present
+	TiebreakersSet int
+	RulesetsSet    int
+	SeasonsSet     int
+}
+
+// RowError is a problem with one row of an import. Row is 1-based within its table.
+type RowError struct {
+	Table   string
+	Row     int
+	Message string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("%s row %d: %s", e.Table, e.Row, e.Message)
+}
+
+// DatasetSource is what LoadDataset needs from a store.
+type DatasetSource interface {
+	ListPlayers(ctx context.Context) ([]Player, error)
+	ListTitles(ctx context.Context) ([]Title, error)
+	ListGames(ctx context.Context) ([]Game, error)
+	ListTiebreakers(ctx context.Context) ([]Tiebreaker, error)
+	ListRulesets(ctx context.Context) ([]Ruleset, error)
+	ListSeasons(ctx context.Context) ([]Season, error)
+}
+
+// LoadDataset reads everything from src into a Dataset.
+func LoadDataset(ctx context.Context, src DatasetSource, now time.Time) (Dataset, error) {
+	players, err := src.ListPlayers(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	titles, err := src.ListTitles(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	games, err := src.ListGames(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	tbs, err := src.ListTiebreakers(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	rulesets, err := src.ListRulesets(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	seasons, err := src.ListSeasons(ctx)
+	if err != nil {
+		return Dataset{}, err
+	}
+	return NewDataset(players, titles, games, tbs, rulesets, seasons, now), nil
+}
+
+// NewDataset converts stored records to their portable form, replacing IDs with names.
+// Ruleset and season dates are written in their own (league) time zone.
+func NewDataset(players []Player, titles []Title, games []Game, tbs []Tiebreaker, rulesets []Ruleset, seasons []Season, now time.Time) Dataset {
+	d := Dataset{
+		Version:     DatasetVersion,
+		ExportedAt:  now,
+		Players:     make([]DatasetPlayer, 0, len(players)),
+		Titles:      make([]DatasetTitle, 0, len(titles)),
+		Games:       make([]DatasetGame, 0, len(games)),
+		Tiebreakers: make([]DatasetTiebreaker, 0, len(tbs)),
+	}
+
+	playerNames := make(map[int64]string, len(players))
+	for _, p := range players {
+		playerNames[p.ID] = p.Name
+		d.Players = append(d.Players, DatasetPlayer{Name: p.Name, IsActive: p.IsActive})
+	}
+	names := func(ids []int64) []string {
+		out := make([]string, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, playerNames[id])
+		}
+		return out
+	}
+
+	teams := func(ts [][]int64) [][]string {
+		var out [][]string
+		for _, team := range ts {
+			out = append(out, names(team))
+		}
+		return out
+	}
+	results := func(rs []Result) []DatasetResult {
+		var out []DatasetResult
+		for _, r := range rs {
+			out = append(out, DatasetResult{Player: playerNames[r.PlayerID], Position: r.Position, Score: r.Score})
+		}
+		return out
+	}
+
+	titleNames := make(map[int64]string, len(titles))
+	for _, t := range titles {
+		titleNames[t.ID] = t.Name
+		d.Titles = append(d.Titles, DatasetTitle{Name: t.Name, IsActive: t.IsActive})
+	}
+
+	gameRefs := make(map[int64]*DatasetGameRef, len(games))
+	for _, g := range games {
+		gameRefs[g.ID] = &DatasetGameRef{PlayedAt: g.PlayedAt, Title: titleNames[g.TitleID]}
+		d.Games = append(d.Games, DatasetGame{
+			PlayedAt:     g.PlayedAt,
+			Title:        titleNames[g.TitleID],
+			Mode:         g.Mode,
+			Participants: names(g.ParticipantIDs),
+			Winners:      names(g.WinnerIDs),
+			Teams:        teams(g.Teams),
+			Results:      results(g.Results),
+			Notes:        g.Notes,
+			IsActive:     g.IsActive,
+		})
+	}
+
+	for _, tb := range tbs {
+		d.Tiebreakers = append(d.Tiebreakers, DatasetTiebreaker{
+			Scope:     tb.Scope,
+			ScopeKey:  tb.ScopeKey,
+			Tied:      names(tb.TiedPlayerIDs),
+			Winner:    playerNames[tb.WinnerID],
+			Method:    tb.Method,
+			DecidedAt: tb.DecidedAt,
+			Seed:      tb.Seed,
+			Algorithm: tb.Algorithm,
+
+			PlayoffGame: gameRefs[tb.GameID],
+		})
+	}
+
+	rules := func(r PeriodRules) DatasetRules {
+		return DatasetRules{Metric: r.Metric, Qualifier: r.Qualifier, MinAttendance: r.MinAttendance, TiePolicy: r.TiePolicy}
+	}
+	for _, r := range rulesets {
+		d.Rulesets = append(d.Rulesets, DatasetRuleset{
+			Name:          r.Name,
+			EffectiveFrom: r.EffectiveFrom.Format("2006-01-02"),
+			Weekly:        rules(r.Weekly),
+			Yearly:        rules(r.Yearly),
+		})
+	}
+	for _, se := range seasons {
+		d.Seasons = append(d.Seasons, DatasetSeason{
+			Name:      se.Name,
+			StartDate: se.StartDate.Format("2006-01-02"),
+			EndDate:   se.EndDate.Format("2006-01-02"),
+		})
+	}
+
+	return d
+}
+
+// WriteJSON writes d as a single indented JSON document.
+func (d Dataset) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// ReadDatasetJSON reads a document written by WriteJSON.
+func ReadDatasetJSON(r io.Reader) (Dataset, error) {
+	var d Dataset
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return Dataset{}, fmt.Errorf("invalid JSON dataset: %w", err)
+	}
+	if d.Version != DatasetVersion {
+		return Dataset{}, fmt.Errorf("unsupported dataset version %d", d.Version)
+	}
+	return d, nil
+}
+
+var datasetCSVHeaders = map[string][]string{
+	"players":     {"name", "is_active"},
+	"titles":      {"name", "is_active"},
+	"games":       {"played_at", "title", "mode", "participants", "winners", "teams", "results", "notes", "is_active"},
+	"tiebreakers": {"scope", "scope_key", "tied", "winner", "method", "decided_at", "seed", "algorithm", "playoff_played_at", "playoff_title"},
+	"rulesets": {
+		"name", "effective_from",
+		"weekly_metric", "weekly_qualifier", "weekly_min_attendance", "weekly_tie_policy",
+		"yearly_metric", "yearly_qualifier", "yearly_min_attendance", "yearly_tie_policy",
+	},
+	"seasons": {"name", "start_date", "end_date"},
+}
+
+// optionalCSVColumns may be missing from an imported CSV; they were added after the first format.
+var optionalCSVColumns = map[string]bool{"mode": true, "teams": true, "results": true, "seed": true, "algorithm": true, "playoff_played_at": true, "playoff_title": true}
+
+// csvTeamSep separates teams in a teams cell; members within a team use csvListSep.
+const csvTeamSep = "|"
+
+// WriteCSV writes one table of d as CSV with a header row.
+// Multi-valued cells (participants, winners, tied, results) are joined with ";"; each game
+// result is written as name:position:score, with either number left blank when not recorded.
+// Teams are written as "a;b|c;d".
+func (d Dataset) WriteCSV(w io.Writer, table string) error {
+	header, ok := datasetCSVHeaders[table]
+	if !ok {
+		return fmt.Errorf("unknown table %q", table)
+	}
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+
+	switch table {
+	case "players":
+		for _, p := range d.Players {
+			_ = cw.Write([]string{p.Name, strconv.FormatBool(p.IsActive)})
+		}
+	case "titles":
+		for _, t := range d.Titles {
+			_ = cw.Write([]string{t.Name, strconv.FormatBool(t.IsActive)})
+		}
+	case "games":
+		for _, g := range d.Games {
+			_ = cw.Write([]string{
+				g.PlayedAt.Format(time.RFC3339),
+				g.Title,
+				g.Mode,
+				strings.Join(g.Participants, csvListSep),
+				strings.Join(g.Winners, csvListSep),
+				formatCSVTeams(g.Teams),
+				formatCSVResults(g.Results),
+				g.Notes,
+				strconv.FormatBool(g.IsActive),
+			})
+		}
+	case "tiebreakers":
+		for _, tb := range d.Tiebreakers {
+			var playoffAt, playoffTitle string
+			if tb.PlayoffGame != nil {
+				playoffAt, playoffTitle = tb.PlayoffGame.PlayedAt.Format(time.RFC3339), tb.PlayoffGame.Title
+			}
+			_ = cw.Write([]string{
+				tb.Scope,
+				tb.ScopeKey,
+				strings.Join(tb.Tied, csvListSep),
+				tb.Winner,
+				tb.Method,
+				tb.DecidedAt.Format(time.RFC3339),
+				tb.Seed,
+				tb.Algorithm,
+				playoffAt,
+				playoffTitle,
+			})
+		}
+	case "rulesets":
+		for _, r := range d.Rulesets {
+			_ = cw.Write([]string{
+				r.Name,
+				r.EffectiveFrom,
+				r.Weekly.Metric, r.Weekly.Qualifier, strconv.Itoa(r.Weekly.MinAttendance), r.Weekly.TiePolicy,
+				r.Yearly.Metric, r.Yearly.Qualifier, strconv.Itoa(r.Yearly.MinAttendance), r.Yearly.TiePolicy,
+			})
+		}
+	case "seasons":
+		for _, se := range d.Seasons {
+			_ = cw.Write([]string{se.Name, se.StartDate, se.EndDate})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadDatasetCSV reads one table written by WriteCSV into an otherwise empty Dataset.
+// Columns are matched by header name, so their order doesn't matter. Rows that can't be
+// parsed are reported as RowErrors and left out; the error is only for unreadable input.
+func ReadDatasetCSV(r io.Reader, table string) (Dataset, []RowError, error) {
+	header, ok := datasetCSVHeaders[table]
+	if !ok {
+		return Dataset{}, nil, fmt.Errorf("unknown table %q", table)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return Dataset{}, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return Dataset{}, nil, errors.New("invalid CSV: missing header row")
+	}
+
+	col := map[string]int{}
+	for i, name := range records[0] {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range header {
+		if _, ok := col[name]; !ok && !optionalCSVColumns[name] {
+			return Dataset{}, nil, fmt.Errorf("invalid CSV: missing column %q", name)
+		}
+	}
+
+	d := Dataset{Version: DatasetVersion}
+	var rowErrs []RowError
+
+	for i, rec := range records[1:] {
+		row := i + 1
+		get := func(name string) string {
+			if j, ok := col[name]; ok && j < len(rec) {
+				return strings.TrimSpace(rec[j])
+			}
+			return ""
+		}
+		fail := func(msg string) { rowErrs = append(rowErrs, RowError{Table: table, Row: row, Message: msg}) }
+
+		active, err := parseCSVBool(get("is_active"))
+		if err != nil && table != "tiebreakers" && table != "rulesets" && table != "seasons" {
+			fail(err.Error())
+			continue
+		}
+
+		switch table {
+		case "players":
+			d.Players = append(d.Players, DatasetPlayer{Name: get("name"), IsActive: active})
+		case "titles":
+			d.Titles = append(d.Titles, DatasetTitle{Name: get("name"), IsActive: active})
+		case "games":
+			playedAt, err := time.Parse(time.RFC3339, get("played_at"))
+			if err != nil {
+				fail("played_at must be an RFC 3339 timestamp")
+				continue
+			}
+			results, err := parseCSVResults(get("results"))
+			if err != nil {
+				fail(err.Error())
+				continue
+			}
+			d.Games = append(d.Games, DatasetGame{
+				PlayedAt:     playedAt,
+				Title:        get("title"),
+				Mode:         get("mode"),
+				Participants: splitCSVList(get("participants")),
+				Winners:      splitCSVList(get("winners")),
+				Teams:        parseCSVTeams(get("teams")),
+				Results:      results,
+				Notes:        get("notes"),
+				IsActive:     active,
+			})
+		case "tiebreakers":
+			decidedAt, err := time.Parse(time.RFC3339, get("decided_at"))
+			if err != nil {
+				fail("decided_at must be an RFC 3339 timestamp")
+				continue
+			}
+			var playoff *DatasetGameRef
+			if v := get("playoff_played_at"); v != "" {
+				playedAt, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					fail("playoff_played_at must be an RFC 3339 timestamp")
+					continue
+				}
+				playoff = &DatasetGameRef{PlayedAt: playedAt, Title: get("playoff_title")}
+			}
+			d.Tiebreakers = append(d.Tiebreakers, DatasetTiebreaker{
+				Scope:     get("scope"),
+				ScopeKey:  get("scope_key"),
+				Tied:      splitCSVList(get("tied")),
+				Winner:    get("winner"),
+				Method:    get("method"),
+				DecidedAt: decidedAt,
+				Seed:      get("seed"),
+				Algorithm: get("algorithm"),
+
+				PlayoffGame: playoff,
+			})
+		case "rulesets":
+			rules := func(prefix string) (DatasetRules, error) {
+				r := DatasetRules{
+					Metric:    get(prefix + "_metric"),
+					Qualifier: get(prefix + "_qualifier"),
+					TiePolicy: get(prefix + "_tie_policy"),
+				}
+				if v := get(prefix + "_min_attendance"); v != "" {
+					n, err := strconv.Atoi(v)
+					if err != nil {
+						return r, fmt.Errorf("%s_min_attendance must be a whole number", prefix)
+					}
+					r.MinAttendance = n
+				}
+				return r, nil
+			}
+			weekly, err := rules("weekly")
+			if err != nil {
+				fail(err.Error())
+				continue
+			}
+			yearly, err := rules("yearly")
+			if err != nil {
+				fail(err.Error())
+				continue
+			}
+			d.Rulesets = append(d.Rulesets, DatasetRuleset{
+				Name:          get("name"),
+				EffectiveFrom: get("effective_from"),
+				Weekly:        weekly,
+				Yearly:        yearly,
+			})
+		case "seasons":
+			d.Seasons = append(d.Seasons, DatasetSeason{
+				Name:      get("name"),
+				StartDate: get("start_date"),
+				EndDate:   get("end_date"),
+			})
+		}
+	}
+
+	return d, rowErrs, nil
+}
+
+func formatCSVTeams(teams [][]string) string {
+	parts := make([]string, 0, len(teams))
+	for _, team := range teams {
+		parts = append(parts, strings.Join(team, csvListSep))
+	}
+	return strings.Join(parts, csvTeamSep)
+}
+
+func parseCSVTeams(s string) [][]string {
+	var out [][]string
+	for _, part := range strings.Split(s, csvTeamSep) {
+		if team := splitCSVList(part); len(team) > 0 {
+			out = append(out, team)
+		}
+	}
+	return out
+}
+
+func formatCSVResults(rs []DatasetResult) string {
+	parts := make([]string, 0, len(rs))
+	for _, r := range rs {
+		pos, score := "", ""
+		if r.Position > 0 {
+			pos = strconv.Itoa(r.Position)
+		}
+		if r.Score != nil {
+			score = strconv.Itoa(*r.Score)
+		}
+		parts = append(parts, r.Player+":"+pos+":"+score)
+	}
+	return strings.Join(parts, csvListSep)
+}
+
+// parseCSVResults reads a results cell written by formatCSVResults. Names may contain ":",
+// so the position and score are taken from the end.
+func parseCSVResults(s string) ([]DatasetResult, error) {
+	var out []DatasetResult
+	for _, item := range splitCSVList(s) {
+		rest, score, ok1 := cutLast(item, ":")
+		name, pos, ok2 := cutLast(rest, ":")
+		if !ok1 || !ok2 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("result %q must look like name:position:score", item)
+		}
+		r := DatasetResult{Player: strings.TrimSpace(name)}
+		if pos = strings.TrimSpace(pos); pos != "" {
+			n, err := strconv.Atoi(pos)
+			if err != nil {
+				return nil, fmt.Errorf("result %q has an invalid position", item)
+			}
+			r.Position = n
+		}
+		if score = strings.TrimSpace(score); score != "" {
+			n, err := strconv.Atoi(score)
+			if err != nil {
+				return nil, fmt.Errorf("result %q has an invalid score", item)
+			}
+			r.Score = &n
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// datasetResults resolves result player names with resolve, as the stores do for participants.
+func datasetResults(rs []DatasetResult, resolve func([]string) ([]int64, error)) ([]Result, error) {
+	var out []Result
+	for _, r := range rs {
+		id, err := resolve([]string{r.Player})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Result{PlayerID: id[0], Position: r.Position, Score: r.Score})
+	}
+	return out, nil
+}
+
+// datasetTeams resolves team member names with resolve.
+func datasetTeams(teams [][]string, resolve func([]string) ([]int64, error)) ([][]int64, error) {
+	var out [][]int64
+	for _, team := range teams {
+		ids, err := resolve(team)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ids)
+	}
+	return out, nil
+}
+
+// parseCSVBool reads an is_active cell; blank means true.
+func parseCSVBool(s string) (bool, error) {
+	if s == "" {
+		return true, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("is_active must be true or false, got %q", s)
+	}
+	return b, nil
+}
+
+func splitCSVList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, csvListSep) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// sortTiebreakers orders tiebreakers by scope, then key.
+func sortTiebreakers(tbs []Tiebreaker) {
+	sort.Slice(tbs, func(i, j int) bool {
+		if tbs[i].Scope != tbs[j].Scope {
+			return tbs[i].Scope < tbs[j].Scope
+		}
+		return tbs[i].ScopeKey < tbs[j].ScopeKey
+	})
+}
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleDataset() Dataset {
+	score := 42
+	players := []Player{{ID: 1, Name: "Alice", IsActive: true}, {ID: 2, Name: "Bob", IsActive: false}, {ID: 3, Name: "Cleo", IsActive: true}}
+	titles := []Title{{ID: 7, Name: "Coup", IsActive: true}}
+	games := []Game{{
+		ID: 3, PlayedAt: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), TitleID: 7,
+		ParticipantIDs: []int64{1, 2}, WinnerIDs: []int64{2}, Notes: "close, \"really\"", IsActive: true,
+		Results: []Result{{PlayerID: 2, Position: 1, Score: &score}, {PlayerID: 1, Position: 2}},
+	}, {
+		ID: 4, PlayedAt: time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC), TitleID: 7, Mode: ModeTeam,
+		ParticipantIDs: []int64{1, 2, 3}, WinnerIDs: []int64{1, 3}, Teams: [][]int64{{1, 3}, {2}}, IsActive: true,
+	}}
+	drawn := Tiebreaker{
+		Scope: "yearly", ScopeKey: "2026", TiedPlayerIDs: []int64{2, 1}, Method: "chance",
+		DecidedAt: time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC), Seed: "5eed", Algorithm: DrawAlgorithm,
+	}
+	drawn.WinnerID, _ = VerifyDraw(drawn)
+	tbs := []Tiebreaker{{
+		Scope: "weekly", ScopeKey: "2026-W02", TiedPlayerIDs: []int64{1, 2}, WinnerID: 1,
+		Method: "chance", DecidedAt: time.Date(2026, 1, 9, 17, 0, 0, 0, time.UTC),
+	}, drawn, {
+		Scope: "weekly", ScopeKey: "2026-W01", TiedPlayerIDs: []int64{1, 3}, WinnerID: 1, Method: MethodPlayoff,
+		DecidedAt: time.Date(2026, 1, 9, 18, 0, 0, 0, time.UTC), GameID: 4,
+	}}
+	rulesets := []Ruleset{{
+		ID: 1, Name: "2026 rules", EffectiveFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Weekly: PeriodRules{Metric: MetricWinRate, Qualifier: QualifyAll, MinAttendance: 2, TiePolicy: TieMostGames},
+		Yearly: DefaultRuleset().Yearly,
+	}}
+	seasons := []Season{{ID: 1, Name: "Winter", StartDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)}}
+	return NewDataset(players, titles, games, tbs, rulesets, seasons, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestNewDataset_UsesNames(t *testing.T) {
+	d := sampleDataset()
+
+	g := d.Games[0]
+	if g.Title != "Coup" || strings.Join(g.Participants, ",") != "Alice,Bob" || g.Winners[0] != "Bob" {
+		t.Errorf("game = %+v", g)
+	}
+	if tb := d.Tiebreakers[0]; tb.Winner != "Alice" || len(tb.Tied) != 2 {
+		t.Errorf("tiebreaker = %+v", tb)
+	}
+}
+
+func TestDataset_JSONRoundTrip(t *testing.T) {
+	d := sampleDataset()
+
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDatasetJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Players) != 3 || got.Players[1].IsActive || got.Games[0].Notes != d.Games[0].Notes {
+		t.Errorf("round trip = %+v", got)
+	}
+	if !got.Games[0].PlayedAt.Equal(d.Games[0].PlayedAt) {
+		t.Errorf("PlayedAt = %v, want %v", got.Games[0].PlayedAt, d.Games[0].PlayedAt)
+	}
+	if rs := got.Games[0].Results; len(rs) != 2 || rs[0].Player != "Bob" || *rs[0].Score != 42 || rs[1].Score != nil {
+		t.Errorf("results = %+v", rs)
+	}
+	if g := got.Games[1]; g.Mode != ModeTeam || len(g.Teams) != 2 || strings.Join(g.Teams[0], ",") != "Alice,Cleo" || g.Teams[1][0] != "Bob" {
+		t.Errorf("team game = %+v", g)
+	}
+	if len(got.Rulesets) != 1 || got.Rulesets[0] != d.Rulesets[0] || got.Rulesets[0].EffectiveFrom != "2026-01-01" {
+		t.Errorf("rulesets = %+v", got.Rulesets)
+	}
+	if len(got.Seasons) != 1 || got.Seasons[0] != (DatasetSeason{Name: "Winter", StartDate: "2026-01-05", EndDate: "2026-03-31"}) {
+		t.Errorf("seasons = %+v", got.Seasons)
+	}
+}
+
+func TestReadDatasetJSON_RejectsOtherVersions(t *testing.T) {
+	if _, err := ReadDatasetJSON(strings.NewReader(`{"version": 99}`)); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestDataset_CSVRoundTrip(t *testing.T) {
+	d := sampleDataset()
+
+	for _, table := range DatasetTables {
+		var buf bytes.Buffer
+		if err := d.WriteCSV(&buf, table); err != nil {
+			t.Fatalf("%s: %v", table, err)
+		}
+		got, rowErrs, err := ReadDatasetCSV(&buf, table)
+		if err != nil || len(rowErrs) != 0 {
+			t.Fatalf("%s: err = %v, rowErrs = %v", table, err, rowErrs)
+		}
+
+		switch table {
+		case "players":
+			if len(got.Players) != 3 || got.Players[1] != d.Players[1] {
+				t.Errorf("players = %+v", got.Players)
+			}
+		case "titles":
+			if len(got.Titles) != 1 || got.Titles[0] != d.Titles[0] {
+				t.Errorf("titles = %+v", got.Titles)
+			}
+		case "games":
+			g := got.Games[0]
+			if g.Notes != d.Games[0].Notes || strings.Join(g.Participants, ",") != "Alice,Bob" || !g.PlayedAt.Equal(d.Games[0].PlayedAt) {
+				t.Errorf("games = %+v", got.Games)
+			}
+			if rs := g.Results; len(rs) != 2 || rs[0].Player != "Bob" || rs[0].Position != 1 || *rs[0].Score != 42 || rs[1].Score != nil {
+				t.Errorf("results = %+v", rs)
+			}
+			if tg := got.Games[1]; tg.Mode != ModeTeam || len(tg.Teams) != 2 || strings.Join(tg.Teams[0], ",") != "Alice,Cleo" || tg.Teams[1][0] != "Bob" {
+				t.Errorf("team game = %+v", tg)
+			}
+		case "tiebreakers":
+			tb := got.Tiebreakers[0]
+			if tb.Winner != "Alice" || tb.ScopeKey != "2026-W02" || !tb.DecidedAt.Equal(d.Tiebreakers[0].DecidedAt) {
+				t.Errorf("tiebreakers = %+v", got.Tiebreakers)
+			}
+			if dt := got.Tiebreakers[1]; dt.Seed != "5eed" || dt.Algorithm != DrawAlgorithm || strings.Join(dt.Tied, ",") != "Bob,Alice" {
+				t.Errorf("drawn tiebreaker = %+v", dt)
+			}
+			if pt := got.Tiebreakers[2]; pt.PlayoffGame == nil || !pt.PlayoffGame.PlayedAt.Equal(d.Tiebreakers[2].PlayoffGame.PlayedAt) || pt.PlayoffGame.Title != "Coup" {
+				t.Errorf("play-off tiebreaker = %+v", pt)
+			}
+			if got.Tiebreakers[0].PlayoffGame != nil {
+				t.Errorf("chance tiebreaker has a play-off game: %+v", got.Tiebreakers[0].PlayoffGame)
+			}
+		case "rulesets":
+			if len(got.Rulesets) != 1 || got.Rulesets[0] != d.Rulesets[0] {
+				t.Errorf("rulesets = %+v", got.Rulesets)
+			}
+		case "seasons":
+			if len(got.Seasons) != 1 || got.Seasons[0] != d.Seasons[0] {
+				t.Errorf("seasons = %+v", got.Seasons)
+			}
+		}
+	}
+}
+
+func TestReadDatasetCSV_RowErrors(t *testing.T) {
+	in := "title,played_at,participants,winners,notes,is_active\n" +
+		"Coup,2026-01-05T12:00:00Z,Alice;Bob,Bob,,\n" +
+		"Coup,yesterday,Alice,Alice,,true\n" +
+		"Coup,2026-01-06T12:00:00Z,Alice,Alice,,maybe\n"
+
+	d, rowErrs, err := ReadDatasetCSV(strings.NewReader(in), "games")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Games) != 1 || !d.Games[0].IsActive {
+		t.Errorf("games = %+v, want the first row, active by default", d.Games)
+	}
+	if len(rowErrs) != 2 || rowErrs[0].Row != 2 || rowErrs[1].Row != 3 {
+		t.Errorf("rowErrs = %v, want rows 2 and 3", rowErrs)
+	}
+}
+
+func TestReadDatasetCSV_MissingColumn(t *testing.T) {
+	if _, _, err := ReadDatasetCSV(strings.NewReader("name\nAlice\n"), "players"); err == nil {
+		t.Error("expected error for missing is_active column")
+	}
+}
+
+func TestParseCSVResults(t *testing.T) {
+	got, err := parseCSVResults("Dr. No: the sequel:1:-5;Bob::7;Cy:3:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Player != "Dr. No: the sequel" || got[0].Position != 1 || *got[0].Score != -5 {
+		t.Errorf("first = %+v", got)
+	}
+	if got[1].Position != 0 || *got[1].Score != 7 || got[2].Position != 3 || got[2].Score != nil {
+		t.Errorf("results = %+v", got)
+	}
+
+	for _, bad := range []string{"Bob", "Bob:first:", ":1:2"} {
+		if _, err := parseCSVResults(bad); err == nil {
+			t.Errorf("parseCSVResults(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DrawAlgorithm names the draw below. It is stored with every drawn tiebreaker so the
+// draw can be re-run later even if a newer algorithm is added.
+const DrawAlgorithm = "hmac-sha256-v1"
+
+// NewDrawSeed returns 32 random bytes, hex encoded, to seed a draw.
+func NewDrawSeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewDrawSeed: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// DrawMessage is the HMAC message for one round of a draw: "scope|scope_key|n|round".
+func DrawMessage(scope, scopeKey string, n, round int) string {
+	return fmt.Sprintf("%s|%s|%d|%d", scope, scopeKey, n, round)
+}
+
+// DrawIndex picks an index in [0, n) from seed. Each round computes
+// HMAC-SHA256(key = seed, message = DrawMessage(...)) and reads the first 8 bytes as a
+// big-endian uint64; a value in the uneven tail above the largest multiple of n is
+// rejected and the next round is tried, so every index is equally likely.
+func DrawIndex(seed, scope, scopeKey string, n int) (int, error) {
+	if seed == "" {
+		return 0, errors.New("draw seed is empty")
+	}
+	if n < 1 {
+		return 0, errors.New("nothing to draw from")
+	}
+	for round := 0; ; round++ {
+		if r := DrawRoundFor(seed, scope, scopeKey, n, round); r.Accepted {
+			return r.Index, nil
+		}
+	}
+}
+
+// DrawRound is one round of a draw, spelled out so it can be checked by hand.
+type DrawRound struct {
+	Round    int
+	Message  string
+	MAC      string // hex
+	Value    uint64 // first 8 bytes of the MAC, big-endian
+	Accepted bool
+	Index    int // Value mod n when accepted
+}
+
+// DrawRoundFor computes one round of DrawIndex; n must be at least 1.
+func DrawRoundFor(seed, scope, scopeKey string, n, round int) DrawRound {
+	msg := DrawMessage(scope, scopeKey, n, round)
+	mac := hmac.New(sha256.New, []byte(seed))
+	mac.Write([]byte(msg))
+	sum := mac.Sum(nil)
+
+	r := DrawRound{Round: round, Message: msg, MAC: hex.EncodeToString(sum), Value: binary.BigEndian.Uint64(sum[:8])}
+	limit := math.MaxUint64 - math.MaxUint64%uint64(n)
+	if r.Value < limit {
+		r.Accepted = true
+		r.Index = int(r.Value % uint64(n))
+	}
+	return r
+}
+
+// DrawTiebreaker draws a winner uniformly from tied with a fresh seed. The tied order is
+// kept as given; the stored tiebreaker records the seed, the algorithm and that order, so
+// VerifyDraw can repeat the draw.
+func DrawTiebreaker(scope, scopeKey string, tied []int64) (Tiebreaker, error) {
+	seed, err := NewDrawSeed()
+	if err != nil {
+		return Tiebreaker{}, err
+	}
+	i, err := DrawIndex(seed, scope, scopeKey, len(tied))
+	if err != nil {
+		return Tiebreaker{}, err
+	}
+	return Tiebreaker{
+		Scope:         scope,
+		ScopeKey:      scopeKey,
+		TiedPlayerIDs: append([]int64(nil), tied...),
+		WinnerID:      tied[i],
+		Method:        MethodChance,
+		Seed:          seed,
+		Algorithm:     DrawAlgorithm,
+	}, nil
+}
+
+// VerifyDraw re-runs a drawn tiebreaker and returns the winner the draw gives, or an
+// error if tb wasn't drawn by the server or used an unknown algorithm.
+func VerifyDraw(tb Tiebreaker) (int64, error) {
+	if !tb.Drawn() {
+		return 0, errors.New("tiebreaker was not drawn by the server")
+	}
+	if tb.Algorithm != DrawAlgorithm {
+		return 0, fmt.Errorf("unknown draw algorithm %q", tb.Algorithm)
+	}
+	i, err := DrawIndex(tb.Seed, tb.Scope, tb.ScopeKey, len(tb.TiedPlayerIDs))
+	if err != nil {
+		return 0, err
+	}
+	return tb.TiedPlayerIDs[i], nil
+}
@@ -0,0 +1,89 @@
+package game
+
+import "testing"
+
+func TestDrawIndex_Deterministic(t *testing.T) {
+	const seed = "5eed"
+	a, err := DrawIndex(seed, "weekly", "2026-W07", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		if b, _ := DrawIndex(seed, "weekly", "2026-W07", 3); b != a {
+			t.Fatalf("same seed drew %d then %d", a, b)
+		}
+	}
+
+	if _, err := DrawIndex("", "weekly", "2026-W07", 3); err == nil {
+		t.Error("empty seed: expected error")
+	}
+	if _, err := DrawIndex(seed, "weekly", "2026-W07", 0); err == nil {
+		t.Error("nobody tied: expected error")
+	}
+}
+
+func TestDrawIndex_RoughlyUniform(t *testing.T) {
+	const n, draws = 3, 3000
+	counts := make([]int, n)
+	for i := range draws {
+		idx, err := DrawIndex(string(rune('a'+i%26))+string(rune(i)), "yearly", "2026", n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[idx]++
+	}
+	for i, c := range counts {
+		if c < draws/n*8/10 || c > draws/n*12/10 {
+			t.Errorf("index %d drawn %d times of %d, want about %d", i, c, draws, draws/n)
+		}
+	}
+}
+
+func TestDrawRoundFor_MatchesDrawIndex(t *testing.T) {
+	r := DrawRoundFor("5eed", "season", "2026-06-01..2026-08-31", 2, 0)
+	if r.Message != "season|2026-06-01..2026-08-31|2|0" {
+		t.Errorf("Message = %q", r.Message)
+	}
+	if len(r.MAC) != 64 {
+		t.Errorf("MAC = %q, want 64 hex digits", r.MAC)
+	}
+	idx, _ := DrawIndex("5eed", "season", "2026-06-01..2026-08-31", 2)
+	if !r.Accepted || r.Index != idx {
+		t.Errorf("round 0 = %+v, DrawIndex = %d", r, idx)
+	}
+}
+
+func TestDrawTiebreaker_Verifies(t *testing.T) {
+	tied := []int64{4, 7, 9}
+	tb, err := DrawTiebreaker("weekly", "2026-W07", tied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tb.Drawn() || tb.Algorithm != DrawAlgorithm || tb.Method != "chance" || len(tb.Seed) != 64 {
+		t.Fatalf("tiebreaker = %+v", tb)
+	}
+	if !containsID(tied, tb.WinnerID) {
+		t.Fatalf("winner %d is not one of %v", tb.WinnerID, tied)
+	}
+
+	got, err := VerifyDraw(tb)
+	if err != nil || got != tb.WinnerID {
+		t.Errorf("VerifyDraw = %d, %v; want %d", got, err, tb.WinnerID)
+	}
+
+	// Re-keying the draw to another week gives an independent result for the same seed.
+	moved := tb
+	moved.ScopeKey = "2026-W08"
+	if _, err := VerifyDraw(moved); err != nil {
+		t.Errorf("moved: %v", err)
+	}
+
+	if _, err := VerifyDraw(Tiebreaker{WinnerID: 4, TiedPlayerIDs: tied}); err == nil {
+		t.Error("hand-picked: expected error")
+	}
+	unknown := tb
+	unknown.Algorithm = "dice"
+	if _, err := VerifyDraw(unknown); err == nil {
+		t.Error("unknown algorithm: expected error")
+	}
+}
@@ -0,0 +1,99 @@
+package game
+
+import "sort"
+
+// HeadToHeadRecord is one player's record against one opponent.
+type HeadToHeadRecord struct {
+	Games  int // games both players took part in
+	Wins   int // games the player won with the opponent at the table
+	Losses int // games the opponent won with the player at the table
+}
+
+type HeadToHead struct {
+	PlayerIDs []int64 // every player seen, by games played desc then id asc
+	records   map[[2]int64]HeadToHeadRecord
+}
+
+// Record returns a's record against b. The zero value means they never shared a table.
+func (h HeadToHead) Record(a, b int64) HeadToHeadRecord {
+	return h.records[[2]int64{a, b}]
+}
+
+// headToHeadWins counts, for each of the tied players, the games they won with another of
+// them at the table as an opponent. Like ComputeHeadToHead, co-op games and teammates don't
+// count; each game counts once per winner however many tied opponents were there.
+func headToHeadWins(tied []int64, games []Game) map[int64]int {
+	wins := map[int64]int{}
+	for _, g := range games {
+		if g.GameMode() == ModeCoop {
+			continue
+		}
+		for _, a := range tied {
+			if !containsID(g.WinnerIDs, a) {
+				continue
+			}
+			for _, b := range tied {
+				if b == a || !containsID(g.ParticipantIDs, b) || (g.GameMode() == ModeTeam && g.TeamOf(a) == g.TeamOf(b)) {
+					continue
+				}
+				wins[a]++
+				break
+			}
+		}
+	}
+	return wins
+}
+
+// ComputeHeadToHead counts, for every pair of players, the active games they both played
+// and how many of those each one won. A titleID of 0 includes every title.
+//
+// Shared wins count for both players, so Wins + Losses can exceed Games. Only opponents
+// meet: co-op games are skipped, and teammates in a team game don't count as a pairing.
+func ComputeHeadToHead(games []Game, titleID int64) HeadToHead {
+	h := HeadToHead{records: map[[2]int64]HeadToHeadRecord{}}
+	played := map[int64]int{}
+
+	for _, g := range games {
+		if !g.IsActive || (titleID != 0 && g.TitleID != titleID) || g.GameMode() == ModeCoop {
+			continue
+		}
+
+		winners := map[int64]bool{}
+		for _, wid := range g.WinnerIDs {
+			winners[wid] = true
+		}
+
+		participants := uniqueIDs(g.ParticipantIDs)
+		for _, a := range participants {
+			played[a]++
+			for _, b := range participants {
+				if a == b || (g.GameMode() == ModeTeam && g.TeamOf(a) == g.TeamOf(b)) {
+					continue
+				}
+				key := [2]int64{a, b}
+				rec := h.records[key]
+				rec.Games++
+				if winners[a] {
+					rec.Wins++
+				}
+				if winners[b] {
+					rec.Losses++
+				}
+				h.records[key] = rec
+			}
+		}
+	}
+
+	for pid := range played {
+		h.PlayerIDs = append(h.PlayerIDs, pid)
+	}
+	sort.Slice(h.PlayerIDs, func(i, j int) bool {
+		a, b := h.PlayerIDs[i], h.PlayerIDs[j]
+		if played[a] != played[b] {
+			return played[a] > played[b]
+		}
+		return a < b
+	})
+
+	return h
+}
@@ -0,0 +1,79 @@
+package game
+
+import "testing"
+
+func TestComputeHeadToHead_Records(t *testing.T) {
+	games := []Game{
+		titleGame(1, day(2026, 1, 5), []int64{1, 2, 3}, []int64{1}),
+		titleGame(1, day(2026, 1, 6), []int64{1, 2}, []int64{2}),
+		titleGame(2, day(2026, 1, 7), []int64{1, 2}, []int64{1}),
+	}
+	h := ComputeHeadToHead(games, 0)
+
+	if got, want := h.Record(1, 2), (HeadToHeadRecord{Games: 3, Wins: 2, Losses: 1}); got != want {
+		t.Errorf("Record(1, 2) = %+v, want %+v", got, want)
+	}
+	if got, want := h.Record(2, 1), (HeadToHeadRecord{Games: 3, Wins: 1, Losses: 2}); got != want {
+		t.Errorf("Record(2, 1) = %+v, want %+v", got, want)
+	}
+	if got, want := h.Record(3, 1), (HeadToHeadRecord{Games: 1, Wins: 0, Losses: 1}); got != want {
+		t.Errorf("Record(3, 1) = %+v, want %+v", got, want)
+	}
+	if got := h.Record(1, 4); got != (HeadToHeadRecord{}) {
+		t.Errorf("Record(1, 4) = %+v, want zero", got)
+	}
+
+	want := []int64{1, 2, 3}
+	if len(h.PlayerIDs) != len(want) {
+		t.Fatalf("PlayerIDs = %v, want %v", h.PlayerIDs, want)
+	}
+	for i := range want {
+		if h.PlayerIDs[i] != want[i] {
+			t.Errorf("PlayerIDs = %v, want %v", h.PlayerIDs, want)
+			break
+		}
+	}
+}
+
+func TestComputeHeadToHead_TitleFilterAndInactive(t *testing.T) {
+	inactive := titleGame(1, day(2026, 1, 8), []int64{1, 2}, []int64{2})
+	inactive.IsActive = false
+	games := []Game{
+		titleGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		titleGame(2, day(2026, 1, 6), []int64{1, 2, 3}, []int64{2}),
+		inactive,
+	}
+	h := ComputeHeadToHead(games, 1)
+
+	if got, want := h.Record(1, 2), (HeadToHeadRecord{Games: 1, Wins: 1}); got != want {
+		t.Errorf("Record(1, 2) = %+v, want %+v", got, want)
+	}
+	if len(h.PlayerIDs) != 2 {
+		t.Errorf("PlayerIDs = %v, want players 1 and 2 only", h.PlayerIDs)
+	}
+}
+
+func TestComputeHeadToHead_SharedWinCountsForBoth(t *testing.T) {
+	h := ComputeHeadToHead([]Game{titleGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1, 2})}, 0)
+
+	if got, want := h.Record(1, 2), (HeadToHeadRecord{Games: 1, Wins: 1, Losses: 1}); got != want {
+		t.Errorf("Record(1, 2) = %+v, want %+v", got, want)
+	}
+}
+
+func TestComputeHeadToHead_TeamsAndCoop(t *testing.T) {
+	team := titleGame(1, day(2026, 1, 5), []int64{1, 2, 3, 4}, []int64{1, 2})
+	team.Mode = ModeTeam
+	team.Teams = [][]int64{{1, 2}, {3, 4}}
+	coop := titleGame(1, day(2026, 1, 6), []int64{1, 2, 3}, nil)
+	coop.Mode = ModeCoop
+
+	h := ComputeHeadToHead([]Game{team, coop}, 0)
+
+	if got := h.Record(1, 2); got != (HeadToHeadRecord{}) {
+		t.Errorf("teammates Record(1, 2) = %+v, want zero", got)
+	}
+	if got, want := h.Record(1, 3), (HeadToHeadRecord{Games: 1, Wins: 1}); got != want {
+		t.Errorf("opponents Record(1, 3) = %+v, want %+v (co-op game skipped)", got, want)
+	}
+}
@@ -21,19 +21,76 @@ type Game struct {
 	TitleID int64
 	Title   string // denormalized for reads (join)
 
+	Mode           string // ModeCompetitive (default), ModeTeam or ModeCoop
 	ParticipantIDs []int64
-	WinnerIDs      []int64
+	WinnerIDs      []int64   // team games: the winning team; co-op: everyone or no one
+	Teams          [][]int64 // team games only: every participant on exactly one team
+	Results        []Result  // optional placements/scores, at most one per participant
 	Notes          string
 
 	IsActive bool
 }
 
+// Game modes. Standings treat them all through WinnerIDs: each winner is credited with a
+// win and every participant with a game played. So a team win counts for each member of the
+// winning team, a co-op win for the whole table, and a co-op loss is a game played that
+// no one won.
+const (
+	ModeCompetitive = "competitive"
+	ModeTeam        = "team"
+	ModeCoop        = "coop"
+)
+
+// GameMode returns g's mode, treating an unset mode as competitive.
+func (g Game) GameMode() string {
+	if g.Mode == "" {
+		return ModeCompetitive
+	}
+	return g.Mode
+}
+
+// TeamOf returns the index in g.Teams of pid's team, or -1.
+func (g Game) TeamOf(pid int64) int {
+	for i, team := range g.Teams {
+		if containsID(team, pid) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Result is one participant's finish in a game. Either part may be left out.
+type Result struct {
+	PlayerID int64
+	Position int  // 1 = first; tied players share a position; 0 = not recorded
+	Score    *int // nil = not recorded
+}
+
 type Tiebreaker struct {
-	Scope    string // "weekly" | "yearly"
-	ScopeKey string // "2026-W07" | "2026"
+	Scope    string // "weekly" | "yearly" | "season"
+	ScopeKey string // "2026-W07" | "2026" | "2026-06-01..2026-08-31"
 
 	TiedPlayerIDs []int64
 	WinnerID      int64
-	Method        string // "chance"
+	Method        string // MethodChance or MethodPlayoff
 	DecidedAt     time.Time
+
+	// GameID is the logged play-off game that settled the tie, for MethodPlayoff.
+	GameID int64
+
+	// Seed and Algorithm are set when the server drew the winner; see DrawTiebreaker.
+	// A hand-picked winner leaves them empty.
+	Seed      string
+	Algorithm string
+}
+
+// Tiebreaker methods: how a stored tiebreaker was settled.
+const (
+	MethodChance  = "chance"  // a game of chance, picked by hand or drawn by the server
+	MethodPlayoff = "playoff" // the tied players played a logged play-off game (GameID)
+)
+
+// Drawn reports whether the server drew the winner, so the draw can be verified.
+func (tb Tiebreaker) Drawn() bool {
+	return tb.Seed != ""
 }
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultRecentGames is how many recent games a player profile lists.
+const DefaultRecentGames = 10
+
+type PlayerPeriodStats struct {
+	Games          int
+	Wins           int
+	WinRate        float64 // percent with 1 decimal
+	AttendanceDays int
+	WeeklyTitles   int
+	YearlyTitles   int
+}
+
+type PlayerYearSummary struct {
+	Year int
+	PlayerPeriodStats
+}
+
+type PlayerTitleRecord struct {
+	TitleID int64
+	Games   int
+	Wins    int
+	WinRate float64 // percent with 1 decimal
+}
+
+type PlayerProfile struct {
+	PlayerID int64
+	Career   PlayerPeriodStats
+	Years    []PlayerYearSummary // newest first
+
+	Titles          []PlayerTitleRecord // most played first, then title id
+	FavoriteTitleID *int64              // most played
+	BestTitleID     *int64              // best win rate with at least DefaultTitleMinGames plays and one win
+
+	LongestWinStreak int    // consecutive games won, in play order
+	Recent           []Game // newest first
+}
+
+// ComputePlayerProfile builds a player's career from the active games.
+//
+// Weekly and yearly titles come from ComputeHallOfChampions (under rulesets, with stored
+// tiebreakers applied via getTB), counting only weeks and years that ended before now. Weeks, years and
+// attendance days are judged in loc.
+func ComputePlayerProfile(
+	games []Game,
+	playerID int64,
+	loc *time.Location,
+	now time.Time,
+	rulesets []Ruleset,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) PlayerProfile {
+	pp := PlayerProfile{PlayerID: playerID}
+
+	active := make([]Game, 0, len(games))
+	for _, g := range games {
+		if g.IsActive {
+			active = append(active, g)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].PlayedAt.Equal(active[j].PlayedAt) {
+			return active[i].ID < active[j].ID
+		}
+		return active[i].PlayedAt.Before(active[j].PlayedAt)
+	})
+
+	years := map[int]*PlayerPeriodStats{}
+	yearOf := func(y int) *PlayerPeriodStats {
+		if years[y] == nil {
+			years[y] = &PlayerPeriodStats{}
+		}
+		return years[y]
+	}
+	days := map[string]bool{}
+	yearDays := map[int]map[string]bool{}
+	titles := map[int64]*PlayerTitleRecord{}
+	streak := 0
+
+	for _, g := range active {
+		if !containsID(g.ParticipantIDs, playerID) {
+			continue
+		}
+		local := g.PlayedAt.In(loc)
+		won := containsID(g.WinnerIDs, playerID)
+
+		ys := yearOf(local.Year())
+		ys.Games++
+		pp.Career.Games++
+		dateKey := local.Format("2006-01-02")
+		days[dateKey] = true
+		if yearDays[local.Year()] == nil {
+			yearDays[local.Year()] = map[string]bool{}
+		}
+		yearDays[local.Year()][dateKey] = true
+
+		tr := titles[g.TitleID]
+		if tr == nil {
+			tr = &PlayerTitleRecord{TitleID: g.TitleID}
+			titles[g.TitleID] = tr
+		}
+		tr.Games++
+
+		if won {
+			ys.Wins++
+			pp.Career.Wins++
+			tr.Wins++
+			streak++
+			pp.LongestWinStreak = max(pp.LongestWinStreak, streak)
+		} else {
+			streak = 0
+		}
+	}
+
+	// Weekly titles are credited to the week's ISO year.
+	won := func(c *PeriodChampion) bool {
+		return c != nil && !c.InProgress && c.WinnerID != nil && *c.WinnerID == playerID
+	}
+	for _, cy := range ComputeHallOfChampions(active, loc, now, rulesets, getTB).Years {
+		for i := range cy.Weeks {
+			if won(&cy.Weeks[i]) {
+				yearOf(cy.Year).WeeklyTitles++
+				pp.Career.WeeklyTitles++
+			}
+		}
+		if won(cy.Yearly) {
+			yearOf(cy.Year).YearlyTitles++
+			pp.Career.YearlyTitles++
+		}
+	}
+
+	pp.Career.AttendanceDays = len(days)
+	pp.Career.WinRate = percent(pp.Career.Wins, pp.Career.Games)
+	for y, ys := range years {
+		ys.AttendanceDays = len(yearDays[y])
+		ys.WinRate = percent(ys.Wins, ys.Games)
+		pp.Years = append(pp.Years, PlayerYearSummary{Year: y, PlayerPeriodStats: *ys})
+	}
+	sort.Slice(pp.Years, func(i, j int) bool { return pp.Years[i].Year > pp.Years[j].Year })
+
+	for _, tr := range titles {
+		tr.WinRate = percent(tr.Wins, tr.Games)
+		pp.Titles = append(pp.Titles, *tr)
+	}
+	sort.Slice(pp.Titles, func(i, j int) bool {
+		if pp.Titles[i].Games != pp.Titles[j].Games {
+			return pp.Titles[i].Games > pp.Titles[j].Games
+		}
+		return pp.Titles[i].TitleID < pp.Titles[j].TitleID
+	})
+	if len(pp.Titles) > 0 {
+		id := pp.Titles[0].TitleID
+		pp.FavoriteTitleID = &id
+	}
+	var best *PlayerTitleRecord
+	for i := range pp.Titles {
+		tr := &pp.Titles[i]
+		if tr.Games < DefaultTitleMinGames || tr.Wins == 0 {
+			continue
+		}
+		// Exact comparison by cross-multiplying; ties keep the more-played title.
+		if best == nil || tr.Wins*best.Games > best.Wins*tr.Games {
+			best = tr
+		}
+	}
+	if best != nil {
+		id := best.TitleID
+		pp.BestTitleID = &id
+	}
+
+	for i := len(active) - 1; i >= 0 && len(pp.Recent) < DefaultRecentGames; i-- {
+		if containsID(active[i].ParticipantIDs, playerID) {
+			pp.Recent = append(pp.Recent, active[i])
+		}
+	}
+
+	return pp
+}
+
+// percent returns n/d as a percentage with 1 decimal, or 0 when d is 0.
+func percent(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(d)*1000) / 10
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func profileGame(id int64, at time.Time, titleID int64, participants, winners []int64) Game {
+	return Game{ID: id, PlayedAt: at, TitleID: titleID, ParticipantIDs: participants, WinnerIDs: winners, IsActive: true}
+}
+
+var profileNow = time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func TestComputePlayerProfile_CareerAndYears(t *testing.T) {
+	games := []Game{
+		profileGame(1, day(2025, 3, 3), 1, []int64{1, 2}, []int64{1}),
+		profileGame(2, day(2025, 3, 3), 1, []int64{1, 2}, []int64{2}),
+		profileGame(3, day(2026, 1, 5), 2, []int64{1, 2}, []int64{1}),
+		profileGame(4, day(2026, 1, 6), 2, []int64{2, 3}, []int64{3}), // player 1 absent
+	}
+	pp := ComputePlayerProfile(games, 1, time.UTC, profileNow, nil, noTB)
+
+	if pp.Career.Games != 3 || pp.Career.Wins != 2 || pp.Career.AttendanceDays != 2 {
+		t.Errorf("career = %+v", pp.Career)
+	}
+	if pp.Career.WinRate != 66.7 {
+		t.Errorf("career win rate = %.1f, want 66.7", pp.Career.WinRate)
+	}
+	if len(pp.Years) != 2 || pp.Years[0].Year != 2026 || pp.Years[1].Games != 2 {
+		t.Errorf("years = %+v, want 2026 then 2025", pp.Years)
+	}
+	if len(pp.Recent) != 3 || pp.Recent[0].ID != 3 {
+		t.Errorf("recent = %+v, want newest (game 3) first", pp.Recent)
+	}
+}
+
+func TestComputePlayerProfile_Titles(t *testing.T) {
+	// Week 2026-W02 (Jan 5–9): player 1 wins 2, player 2 wins 1.
+	// Week 2026-W03 (Jan 12–16): tie between 1 and 2, resolved for 2.
+	games := []Game{
+		profileGame(1, day(2026, 1, 5), 1, []int64{1, 2}, []int64{1}),
+		profileGame(2, day(2026, 1, 6), 1, []int64{1, 2}, []int64{1}),
+		profileGame(3, day(2026, 1, 7), 1, []int64{1, 2}, []int64{2}),
+		profileGame(4, day(2026, 1, 12), 1, []int64{1, 2}, []int64{1}),
+		profileGame(5, day(2026, 1, 13), 1, []int64{1, 2}, []int64{2}),
+	}
+	getTB := tbFor(WeekScopeKey(2026, 3), 2, 1, 2)
+
+	p1 := ComputePlayerProfile(games, 1, time.UTC, profileNow, nil, getTB)
+	p2 := ComputePlayerProfile(games, 2, time.UTC, profileNow, nil, getTB)
+
+	if p1.Career.WeeklyTitles != 1 || p2.Career.WeeklyTitles != 1 {
+		t.Errorf("weekly titles = %d/%d, want 1/1", p1.Career.WeeklyTitles, p2.Career.WeeklyTitles)
+	}
+	// 2026: both qualify; player 1 has 3/5, player 2 has 2/5.
+	if p1.Career.YearlyTitles != 1 || p1.Years[0].YearlyTitles != 1 || p2.Career.YearlyTitles != 0 {
+		t.Errorf("yearly titles = %d/%d, want 1/0", p1.Career.YearlyTitles, p2.Career.YearlyTitles)
+	}
+}
+
+func TestComputePlayerProfile_UnfinishedPeriodsDontCount(t *testing.T) {
+	games := []Game{profileGame(1, day(2026, 1, 5), 1, []int64{1}, []int64{1})}
+
+	pp := ComputePlayerProfile(games, 1, time.UTC, day(2026, 1, 7), nil, noTB)
+	if pp.Career.WeeklyTitles != 0 || pp.Career.YearlyTitles != 0 {
+		t.Errorf("titles = %+v, want none while the week and year are in progress", pp.Career)
+	}
+}
+
+func TestComputePlayerProfile_FavoriteBestAndStreak(t *testing.T) {
+	games := []Game{
+		profileGame(1, day(2026, 1, 5), 1, []int64{1, 2}, []int64{2}),
+		profileGame(2, day(2026, 1, 6), 1, []int64{1, 2}, []int64{1}),
+		profileGame(3, day(2026, 1, 7), 1, []int64{1, 2}, []int64{1}),
+		profileGame(4, day(2026, 1, 8), 1, []int64{1, 2}, []int64{1}),
+		profileGame(5, day(2026, 1, 9), 2, []int64{1, 2}, []int64{2}),
+		profileGame(6, day(2026, 1, 12), 3, []int64{1, 2}, []int64{1}),
+		profileGame(7, day(2026, 1, 13), 3, []int64{1, 2}, []int64{1}),
+		profileGame(8, day(2026, 1, 14), 3, []int64{1, 2}, []int64{1}),
+	}
+	pp := ComputePlayerProfile(games, 1, time.UTC, profileNow, nil, noTB)
+
+	if pp.FavoriteTitleID == nil || *pp.FavoriteTitleID != 1 {
+		t.Errorf("favorite = %v, want 1", pp.FavoriteTitleID)
+	}
+	// Title 3 is 3/3; title 1 is 3/4.
+	if pp.BestTitleID == nil || *pp.BestTitleID != 3 {
+		t.Errorf("best = %v, want 3", pp.BestTitleID)
+	}
+	if pp.LongestWinStreak != 3 {
+		t.Errorf("longest streak = %d, want 3", pp.LongestWinStreak)
+	}
+}
+
+func TestComputePlayerProfile_NoGames(t *testing.T) {
+	pp := ComputePlayerProfile(nil, 1, time.UTC, profileNow, nil, noTB)
+	if pp.Career.Games != 0 || pp.FavoriteTitleID != nil || pp.BestTitleID != nil || len(pp.Years) != 0 {
+		t.Errorf("profile = %+v, want empty", pp)
+	}
+}
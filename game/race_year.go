@@ -1,6 +1,9 @@
 package game
 
-import "sort"
+import (
+	"sort"
+	"time"
+)
 
 type RaceMetric string
 
@@ -14,6 +17,7 @@ type RaceSeries struct {
 	Values   []float64 // aligned to Weeks
 }
 
+// YearRace is a race over a year's ISO weeks, or over a season's weeks (Year is 0).
 type YearRace struct {
 	Year   int
 	Weeks  []int
@@ -22,9 +26,14 @@ type YearRace struct {
 
 // ComputeYearRace builds cumulative weekly data for the given year.
 // v1 supports metric=wins only, and filters to Top N by final value.
+//
+// Games belong to the calendar year they were played in, in loc. Early-January days that
+// fall in the previous ISO year's last week count toward week 1, and late-December days
+// in the next ISO year's week 1 count toward this year's last week.
 func ComputeYearRace(
 	games []Game,
 	year int,
+	loc *time.Location,
 	metric RaceMetric,
 	topN int,
 	players []Player,
@@ -32,13 +41,64 @@ func ComputeYearRace(
 	// Group games by ISO week (only games in the target year)
 	byWeek := map[int][]Game{}
 	for _, g := range games {
-		if g.PlayedAt.Year() != year {
+		local := g.PlayedAt.In(loc)
+		if local.Year() != year {
+			continue
+		}
+		isoYear, w := local.ISOWeek()
+		switch {
+		case isoYear < year:
+			w = 1
+		case isoYear > year:
+			_, w = time.Date(year, 12, 28, 0, 0, 0, 0, loc).ISOWeek()
+		}
+		byWeek[w] = append(byWeek[w], g)
+	}
+
+	weeks, series := buildRace(byWeek, metric, topN, players)
+	if len(weeks) == 0 {
+		return YearRace{Year: year}
+	}
+
+	return YearRace{
+		Year:   year,
+		Weeks:  weeks,
+		Series: series,
+	}
+}
+
+// ComputeSeasonRace is ComputeYearRace over a season's active games in loc. Weeks count from the
+// season's first day: week 1 is its first seven days, week 2 the next seven, and so on.
+func ComputeSeasonRace(
+	games []Game,
+	season Season,
+	loc *time.Location,
+	metric RaceMetric,
+	topN int,
+	players []Player,
+) YearRace {
+	start, end := season.Bounds(loc)
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	byWeek := map[int][]Game{}
+	for _, g := range games {
+		if !g.IsActive || g.PlayedAt.Before(start) || !g.PlayedAt.Before(end) {
 			continue
 		}
-		_, w := g.PlayedAt.ISOWeek()
+		// Count calendar days in UTC so a DST change doesn't shorten a day.
+		local := g.PlayedAt.In(loc)
+		d := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		w := int(d.Sub(first).Hours())/(24*7) + 1
 		byWeek[w] = append(byWeek[w], g)
 	}
 
+	weeks, series := buildRace(byWeek, metric, topN, players)
+	return YearRace{Weeks: weeks, Series: series}
+}
+
+// buildRace accumulates metric for active players week by week, in week order, and keeps
+// the top N by final value. It returns nil slices when there are no weeks.
+func buildRace(byWeek map[int][]Game, metric RaceMetric, topN int, players []Player) ([]int, []RaceSeries) {
 	// Sorted week list
 	var weeks []int
 	for w := range byWeek {
@@ -85,7 +145,7 @@ func ComputeYearRace(
 
 	// No data
 	if len(weeks) == 0 {
-		return YearRace{Year: year}
+		return nil, nil
 	}
 
 	// Rank by final value and take top N
@@ -114,9 +174,5 @@ func ComputeYearRace(
 		out = append(out, *series[finals[i].id])
 	}
 
-	return YearRace{
-		Year:   year,
-		Weeks:  weeks,
-		Series: out,
-	}
+	return weeks, out
 }
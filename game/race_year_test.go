@@ -38,7 +38,7 @@ func nameFor(id int64) string {
 }
 
 func TestComputeYearRace_NoGames(t *testing.T) {
-	race := ComputeYearRace(nil, 2026, RaceMetricWins, 5, playerList(1, 2))
+	race := ComputeYearRace(nil, 2026, time.UTC, RaceMetricWins, 5, playerList(1, 2))
 	if len(race.Weeks) != 0 {
 		t.Errorf("Weeks = %v, want empty", race.Weeks)
 	}
@@ -55,7 +55,7 @@ func TestComputeYearRace_CumulativeWins(t *testing.T) {
 		raceGame(2026, 2, 2), // Bob wins week 2
 	}
 	players := playerList(1, 2)
-	race := ComputeYearRace(games, 2026, RaceMetricWins, 5, players)
+	race := ComputeYearRace(games, 2026, time.UTC, RaceMetricWins, 5, players)
 
 	if len(race.Weeks) != 2 {
 		t.Fatalf("Weeks = %v, want [1 2]", race.Weeks)
@@ -92,7 +92,7 @@ func TestComputeYearRace_TopNFiltering(t *testing.T) {
 		// players 4, 5, 6 have 0 wins
 	}
 	players := playerList(1, 2, 3, 4, 5, 6)
-	race := ComputeYearRace(games, 2026, RaceMetricWins, 3, players)
+	race := ComputeYearRace(games, 2026, time.UTC, RaceMetricWins, 3, players)
 
 	if len(race.Series) != 3 {
 		t.Fatalf("Series len = %d, want 3", len(race.Series))
@@ -114,7 +114,7 @@ func TestComputeYearRace_GamesFromOtherYearIgnored(t *testing.T) {
 		raceGame(2026, 1, 2), // correct year
 	}
 	players := playerList(1, 2)
-	race := ComputeYearRace(games, 2026, RaceMetricWins, 5, players)
+	race := ComputeYearRace(games, 2026, time.UTC, RaceMetricWins, 5, players)
 
 	seriesByName := map[string]RaceSeries{}
 	for _, s := range race.Series {
@@ -134,7 +134,7 @@ func TestComputeYearRace_InactivePlayersExcluded(t *testing.T) {
 		{ID: 1, Name: "Alice", IsActive: true},
 		{ID: 2, Name: "Bob", IsActive: false}, // inactive — should be excluded
 	}
-	race := ComputeYearRace(games, 2026, RaceMetricWins, 5, players)
+	race := ComputeYearRace(games, 2026, time.UTC, RaceMetricWins, 5, players)
 
 	for _, s := range race.Series {
 		if s.Name == "Bob" {
@@ -149,7 +149,7 @@ func TestComputeYearRace_WeeksAreSorted(t *testing.T) {
 		raceGame(2026, 1, 2),
 		raceGame(2026, 2, 1),
 	}
-	race := ComputeYearRace(games, 2026, RaceMetricWins, 5, playerList(1, 2))
+	race := ComputeYearRace(games, 2026, time.UTC, RaceMetricWins, 5, playerList(1, 2))
 
 	for i := 1; i < len(race.Weeks); i++ {
 		if race.Weeks[i] <= race.Weeks[i-1] {
@@ -157,3 +157,34 @@ func TestComputeYearRace_WeeksAreSorted(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeYearRace_ISOYearBoundaries(t *testing.T) {
+	at := func(month time.Month, d int) Game {
+		return Game{PlayedAt: time.Date(2027, month, d, 12, 0, 0, 0, time.UTC), WinnerIDs: []int64{1}, IsActive: true}
+	}
+	// Jan 1 2027 (Fri) is in ISO 2026-W53; Dec 31 2027 (Fri) is in ISO 2027-W52.
+	games := []Game{at(time.January, 1), at(time.January, 5), at(time.December, 31)}
+
+	race := ComputeYearRace(games, 2027, time.UTC, RaceMetricWins, 5, playerList(1))
+
+	want := []int{1, 52}
+	if len(race.Weeks) != len(want) || race.Weeks[0] != want[0] || race.Weeks[1] != want[1] {
+		t.Fatalf("Weeks = %v, want %v (Jan 1 folded into week 1)", race.Weeks, want)
+	}
+	if got := race.Series[0].Values; got[0] != 2 || got[1] != 3 {
+		t.Errorf("Values = %v, want [2 3]", got)
+	}
+}
+
+func TestComputeYearRace_LateDecemberInNextISOYear(t *testing.T) {
+	// Dec 31 2024 (Tue) is in ISO 2025-W01; it must not land in 2024's week 1.
+	games := []Game{
+		{PlayedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), WinnerIDs: []int64{1}, IsActive: true},
+		{PlayedAt: time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), WinnerIDs: []int64{1}, IsActive: true},
+	}
+	race := ComputeYearRace(games, 2024, time.UTC, RaceMetricWins, 5, playerList(1))
+
+	if len(race.Weeks) != 2 || race.Weeks[1] != 52 {
+		t.Errorf("Weeks = %v, want [1 52]", race.Weeks)
+	}
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultRating is the Elo rating every player starts with.
+	DefaultRating = 1500.0
+
+	// ratingK is the K-factor applied to each winner/loser pairing. A game is scored as
+	// one pairwise Elo match between every winner and every non-winner, so beating five
+	// opponents moves ratings further than beating one.
+	ratingK = 20.0
+)
+
+type RatingPoint struct {
+	GameID   int64
+	PlayedAt time.Time
+	Title    string
+	Won      bool
+	Delta    float64
+	Rating   float64 // rating after this game
+}
+
+type PlayerRating struct {
+	PlayerID int64
+	Rating   float64
+	Peak     float64
+	Games    int
+	Wins     int
+	History  []RatingPoint // oldest first
+}
+
+type Ratings struct {
+	Players []PlayerRating // sorted by rating desc, then id asc
+}
+
+// ComputeRatings replays active games in PlayedAt order and returns current Elo ratings
+// with each player's history.
+//
+// Each game is treated as a set of pairwise matches: every winner beats every participant
+// who did not win. Winners don't play each other, and games where everyone (or no one) won
+// carry no information and are skipped. All deltas in a game are computed from the
+// pre-game ratings, so the order of participants doesn't matter.
+func ComputeRatings(games []Game) Ratings {
+	ordered := make([]Game, 0, len(games))
+	for _, g := range games {
+		if g.IsActive {
+			ordered = append(ordered, g)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].PlayedAt.Equal(ordered[j].PlayedAt) {
+			return ordered[i].ID < ordered[j].ID
+		}
+		return ordered[i].PlayedAt.Before(ordered[j].PlayedAt)
+	})
+
+	byID := map[int64]*PlayerRating{}
+	get := func(pid int64) *PlayerRating {
+		pr := byID[pid]
+		if pr == nil {
+			pr = &PlayerRating{PlayerID: pid, Rating: DefaultRating, Peak: DefaultRating}
+			byID[pid] = pr
+		}
+		return pr
+	}
+
+	for _, g := range ordered {
+		winners := map[int64]bool{}
+		for _, wid := range g.WinnerIDs {
+			winners[wid] = true
+		}
+
+		var ws, ls []int64
+		for _, pid := range uniqueIDs(g.ParticipantIDs) {
+			if winners[pid] {
+				ws = append(ws, pid)
+			} else {
+				ls = append(ls, pid)
+			}
+		}
+		if len(ws) == 0 || len(ls) == 0 {
+			continue
+		}
+
+		deltas := map[int64]float64{}
+		for _, w := range ws {
+			for _, l := range ls {
+				d := ratingK * (1 - expectedScore(get(w).Rating, get(l).Rating))
+				deltas[w] += d
+				deltas[l] -= d
+			}
+		}
+
+		for pid, d := range deltas {
+			pr := get(pid)
+			pr.Rating += d
+			pr.Peak = math.Max(pr.Peak, pr.Rating)
+			pr.Games++
+			if winners[pid] {
+				pr.Wins++
+			}
+			pr.History = append(pr.History, RatingPoint{
+				GameID:   g.ID,
+				PlayedAt: g.PlayedAt,
+				Title:    g.Title,
+				Won:      winners[pid],
+				Delta:    d,
+				Rating:   pr.Rating,
+			})
+		}
+	}
+
+	out := Ratings{Players: make([]PlayerRating, 0, len(byID))}
+	for _, pr := range byID {
+		out.Players = append(out.Players, *pr)
+	}
+	sort.Slice(out.Players, func(i, j int) bool {
+		if out.Players[i].Rating != out.Players[j].Rating {
+			return out.Players[i].Rating > out.Players[j].Rating
+		}
+		return out.Players[i].PlayerID < out.Players[j].PlayerID
+	})
+
+	return out
+}
+
+// expectedScore is the Elo win probability of a player rated a against one rated b.
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// uniqueIDs drops duplicate IDs, keeping first-seen order.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func ratedGame(id int64, at time.Time, participants, winners []int64) Game {
+	return Game{
+		ID:             id,
+		PlayedAt:       at,
+		ParticipantIDs: participants,
+		WinnerIDs:      winners,
+		IsActive:       true,
+	}
+}
+
+func ratingOf(r Ratings, pid int64) (PlayerRating, bool) {
+	for _, p := range r.Players {
+		if p.PlayerID == pid {
+			return p, true
+		}
+	}
+	return PlayerRating{}, false
+}
+
+func TestComputeRatings_NoGames(t *testing.T) {
+	r := ComputeRatings(nil)
+	if len(r.Players) != 0 {
+		t.Errorf("Players len = %d, want 0", len(r.Players))
+	}
+}
+
+func TestComputeRatings_HeadsUp(t *testing.T) {
+	r := ComputeRatings([]Game{ratedGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1})})
+
+	p1, _ := ratingOf(r, 1)
+	p2, _ := ratingOf(r, 2)
+
+	// Equal ratings: expected score 0.5, so the winner gains K/2.
+	if math.Abs(p1.Rating-(DefaultRating+ratingK/2)) > 1e-9 {
+		t.Errorf("winner rating = %.3f, want %.3f", p1.Rating, DefaultRating+ratingK/2)
+	}
+	if math.Abs(p1.Rating+p2.Rating-2*DefaultRating) > 1e-9 {
+		t.Error("ratings should be zero-sum")
+	}
+	if r.Players[0].PlayerID != 1 {
+		t.Errorf("leader = %d, want 1", r.Players[0].PlayerID)
+	}
+}
+
+func TestComputeRatings_BiggerTableWorthMore(t *testing.T) {
+	big := ComputeRatings([]Game{ratedGame(1, day(2026, 1, 5), []int64{1, 2, 3, 4, 5, 6}, []int64{1})})
+	small := ComputeRatings([]Game{ratedGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1})})
+
+	bigWinner, _ := ratingOf(big, 1)
+	smallWinner, _ := ratingOf(small, 1)
+	if bigWinner.Rating <= smallWinner.Rating {
+		t.Errorf("6-player win (%.1f) should outweigh 2-player win (%.1f)", bigWinner.Rating, smallWinner.Rating)
+	}
+}
+
+func TestComputeRatings_UpsetMovesMore(t *testing.T) {
+	// Player 1 builds a lead, then loses to player 3 (new). Player 3 should gain more than K/2.
+	games := []Game{
+		ratedGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		ratedGame(2, day(2026, 1, 6), []int64{1, 2}, []int64{1}),
+		ratedGame(3, day(2026, 1, 7), []int64{1, 3}, []int64{3}),
+	}
+	r := ComputeRatings(games)
+
+	p3, _ := ratingOf(r, 3)
+	if p3.Rating-DefaultRating <= ratingK/2 {
+		t.Errorf("upset gain = %.3f, want > %.3f", p3.Rating-DefaultRating, ratingK/2)
+	}
+}
+
+func TestComputeRatings_ReplaysInPlayedAtOrder(t *testing.T) {
+	a := ratedGame(1, day(2026, 1, 6), []int64{1, 2}, []int64{2})
+	b := ratedGame(2, day(2026, 1, 5), []int64{1, 2}, []int64{1})
+
+	r1 := ComputeRatings([]Game{a, b})
+	r2 := ComputeRatings([]Game{b, a})
+
+	p1a, _ := ratingOf(r1, 1)
+	p1b, _ := ratingOf(r2, 1)
+	if p1a.Rating != p1b.Rating {
+		t.Errorf("ratings depend on input order: %.3f vs %.3f", p1a.Rating, p1b.Rating)
+	}
+	if p1a.History[0].GameID != 2 {
+		t.Errorf("first history point = game %d, want 2", p1a.History[0].GameID)
+	}
+}
+
+func TestComputeRatings_SkipsUninformativeAndInactive(t *testing.T) {
+	inactive := ratedGame(3, day(2026, 1, 7), []int64{1, 2}, []int64{1})
+	inactive.IsActive = false
+	games := []Game{
+		ratedGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1, 2}), // everyone won
+		ratedGame(2, day(2026, 1, 6), []int64{1, 2}, nil),           // no one won
+		inactive,
+	}
+	r := ComputeRatings(games)
+	if len(r.Players) != 0 {
+		t.Errorf("Players = %+v, want none rated", r.Players)
+	}
+}
+
+func TestComputeRatings_HistoryAndPeak(t *testing.T) {
+	games := []Game{
+		ratedGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		ratedGame(2, day(2026, 1, 6), []int64{1, 2}, []int64{2}),
+	}
+	r := ComputeRatings(games)
+
+	p1, _ := ratingOf(r, 1)
+	if p1.Games != 2 || p1.Wins != 1 || len(p1.History) != 2 {
+		t.Fatalf("p1 = %+v", p1)
+	}
+	if p1.Peak != p1.History[0].Rating {
+		t.Errorf("Peak = %.3f, want first-game rating %.3f", p1.Peak, p1.History[0].Rating)
+	}
+	if p1.History[1].Rating != p1.Rating {
+		t.Error("last history point should equal current rating")
+	}
+}
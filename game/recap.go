@@ -0,0 +1,181 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// A WeekRecap is the summary of a finished week, written once when the week closes (see
+// WeekClose) and kept as it was then, even if the week's games are edited later.
+type WeekRecap struct {
+	Year     int
+	Week     int
+	ScopeKey string // "2026-W07"
+
+	TotalGames int
+	Wins       map[int64]int // playerID -> wins
+	TopIDs     []int64       // the leaders under the weekly rules
+	WinnerID   *int64
+	DecidedBy  string // how a tie for the lead was settled; see Standings
+	// TieUnresolved is set when the week closed tied with no tiebreaker stored.
+	TieUnresolved bool
+
+	Titles  []RecapTitle  // most played first
+	Streaks []RecapStreak // longest first
+
+	ClosedAt   time.Time // WeekClose of the week
+	CreatedAt  time.Time
+	NotifiedAt *time.Time // when the recap was announced; nil until it has been
+}
+
+// RecapTitle is how often a title was played in the week.
+type RecapTitle struct {
+	TitleID int64
+	Title   string
+	Plays   int
+}
+
+// Streak kinds a recap calls out.
+const (
+	StreakGameWins     = "game_wins"     // consecutive games won, in play order
+	StreakWeeklyTitles = "weekly_titles" // consecutive weeks won, counting weeks with games
+)
+
+// A streak is notable once it is at least this long.
+const (
+	RecapMinWinStreak   = 3
+	RecapMinTitleStreak = 2
+)
+
+// RecapStreak is a streak still running when the week closed.
+type RecapStreak struct {
+	PlayerID int64
+	Kind     string // StreakGameWins or StreakWeeklyTitles
+	Length   int
+}
+
+// ComputeWeekRecap recaps ISO week `week` of `year` from games, which may span any period:
+// the week is judged by ComputeWeekStandings under the ruleset in force when it started,
+// and streaks are counted over the games played before it ended. Periods are judged in loc.
+// CreatedAt is left for the caller to set.
+func ComputeWeekRecap(
+	games []Game,
+	year, week int,
+	loc *time.Location,
+	rulesets []Ruleset,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) WeekRecap {
+	start, end := WeekBounds(year, week, loc)
+
+	var upToEnd, inWeek []Game
+	for _, g := range games {
+		if !g.IsActive || !g.PlayedAt.Before(end) {
+			continue
+		}
+		upToEnd = append(upToEnd, g)
+		if !g.PlayedAt.Before(start) {
+			inWeek = append(inWeek, g)
+		}
+	}
+
+	ws := ComputeWeekStandings(inWeek, year, week, loc, RulesetFor(rulesets, start).Weekly, getTB)
+	r := WeekRecap{
+		Year:          year,
+		Week:          week,
+		ScopeKey:      ws.ScopeKey,
+		TotalGames:    ws.TotalGames,
+		Wins:          ws.Wins,
+		TopIDs:        ws.TopIDs,
+		WinnerID:      ws.WinnerID,
+		DecidedBy:     ws.DecidedBy,
+		TieUnresolved: ws.TieUnresolved,
+		ClosedAt:      WeekClose(year, week, loc),
+	}
+
+	titles := map[int64]*RecapTitle{}
+	for _, g := range inWeek {
+		t := titles[g.TitleID]
+		if t == nil {
+			t = &RecapTitle{TitleID: g.TitleID, Title: g.Title}
+			titles[g.TitleID] = t
+		}
+		t.Plays++
+	}
+	for _, t := range titles {
+		r.Titles = append(r.Titles, *t)
+	}
+	sort.Slice(r.Titles, func(i, j int) bool {
+		a, b := r.Titles[i], r.Titles[j]
+		if a.Plays != b.Plays {
+			return a.Plays > b.Plays
+		}
+		return a.Title < b.Title
+	})
+
+	r.Streaks = recapStreaks(upToEnd, ws, loc, end, rulesets, getTB)
+	return r
+}
+
+// recapStreaks finds the notable streaks running at the end of the week ws: game-win runs
+// that reached into the week, and the winner's run of weekly titles.
+func recapStreaks(
+	games []Game,
+	ws WeekStandings,
+	loc *time.Location,
+	end time.Time,
+	rulesets []Ruleset,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) []RecapStreak {
+	var out []RecapStreak
+
+	ordered := append([]Game(nil), games...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].PlayedAt.Equal(ordered[j].PlayedAt) {
+			return ordered[i].ID < ordered[j].ID
+		}
+		return ordered[i].PlayedAt.Before(ordered[j].PlayedAt)
+	})
+	running := map[int64]int{}
+	for _, g := range ordered {
+		for _, pid := range g.ParticipantIDs {
+			if containsID(g.WinnerIDs, pid) {
+				running[pid]++
+			} else {
+				running[pid] = 0
+			}
+		}
+	}
+	// A run that is still going and includes a win this week reached into the week.
+	for pid, n := range running {
+		if n >= RecapMinWinStreak && ws.Wins[pid] > 0 {
+			out = append(out, RecapStreak{PlayerID: pid, Kind: StreakGameWins, Length: n})
+		}
+	}
+
+	if ws.WinnerID != nil {
+		n := 0
+	count:
+		for _, cy := range ComputeHallOfChampions(games, loc, end, rulesets, getTB).Years {
+			for _, c := range cy.Weeks {
+				if c.WinnerID == nil || *c.WinnerID != *ws.WinnerID {
+					break count
+				}
+				n++
+			}
+		}
+		if n >= RecapMinTitleStreak {
+			out = append(out, RecapStreak{PlayerID: *ws.WinnerID, Kind: StreakWeeklyTitles, Length: n})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Length != out[j].Length {
+			return out[i].Length > out[j].Length
+		}
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].PlayerID < out[j].PlayerID
+	})
+	return out
+}
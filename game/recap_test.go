@@ -0,0 +1,79 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func recapGame(id int64, at time.Time, titleID int64, title string, participants, winners []int64) Game {
+	g := profileGame(id, at, titleID, participants, winners)
+	g.Title = title
+	return g
+}
+
+func TestComputeWeekRecap(t *testing.T) {
+	deleted := recapGame(6, day(2026, 1, 15), 2, "Azul", []int64{1, 2}, []int64{2})
+	deleted.IsActive = false
+	games := []Game{
+		recapGame(1, day(2026, 1, 5), 1, "Bang", []int64{1, 2}, []int64{1}),  // 2026-W02
+		recapGame(2, day(2026, 1, 12), 2, "Azul", []int64{1, 2}, []int64{1}), // 2026-W03
+		recapGame(3, day(2026, 1, 13), 1, "Bang", []int64{1, 2}, []int64{1}),
+		recapGame(4, day(2026, 1, 14), 1, "Bang", []int64{2, 3}, []int64{3}),
+		recapGame(5, day(2026, 1, 19), 1, "Bang", []int64{1, 2}, []int64{2}), // 2026-W04, after the recap
+		deleted,
+	}
+
+	r := ComputeWeekRecap(games, 2026, 3, time.UTC, nil, noTB)
+
+	if r.ScopeKey != "2026-W03" || r.TotalGames != 3 || r.Wins[1] != 2 || r.Wins[3] != 1 || r.Wins[2] != 0 {
+		t.Errorf("recap = %+v, want 3 games, 2 wins for 1 and 1 for 3", r)
+	}
+	if r.WinnerID == nil || *r.WinnerID != 1 || r.TieUnresolved {
+		t.Errorf("winner = %v (unresolved %v), want 1", r.WinnerID, r.TieUnresolved)
+	}
+	if want := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC); !r.ClosedAt.Equal(want) {
+		t.Errorf("ClosedAt = %v, want %v", r.ClosedAt, want)
+	}
+	if len(r.Titles) != 2 || r.Titles[0] != (RecapTitle{TitleID: 1, Title: "Bang", Plays: 2}) || r.Titles[1] != (RecapTitle{TitleID: 2, Title: "Azul", Plays: 1}) {
+		t.Errorf("titles = %+v, want Bang x2 then Azul x1", r.Titles)
+	}
+
+	// Player 1 has won all three games since W02 and both weeks; player 3's single win and
+	// game 5, played after the week, don't count.
+	want := []RecapStreak{
+		{PlayerID: 1, Kind: StreakGameWins, Length: 3},
+		{PlayerID: 1, Kind: StreakWeeklyTitles, Length: 2},
+	}
+	if len(r.Streaks) != len(want) {
+		t.Fatalf("streaks = %+v, want %+v", r.Streaks, want)
+	}
+	for i := range want {
+		if r.Streaks[i] != want[i] {
+			t.Errorf("streak %d = %+v, want %+v", i, r.Streaks[i], want[i])
+		}
+	}
+}
+
+func TestComputeWeekRecap_UnresolvedTie(t *testing.T) {
+	games := []Game{
+		recapGame(1, day(2026, 1, 12), 1, "Bang", []int64{1, 2}, []int64{1}),
+		recapGame(2, day(2026, 1, 13), 1, "Bang", []int64{1, 2}, []int64{2}),
+	}
+
+	r := ComputeWeekRecap(games, 2026, 3, time.UTC, nil, noTB)
+	if !r.TieUnresolved || r.WinnerID != nil || len(r.TopIDs) != 2 || len(r.Streaks) != 0 {
+		t.Errorf("recap = %+v, want an unresolved tie between 1 and 2 and no streaks", r)
+	}
+
+	r = ComputeWeekRecap(games, 2026, 3, time.UTC, nil, tbFor("2026-W03", 2, 1, 2))
+	if r.TieUnresolved || r.WinnerID == nil || *r.WinnerID != 2 {
+		t.Errorf("recap = %+v, want 2 by tiebreaker", r)
+	}
+}
+
+func TestComputeWeekRecap_NoGames(t *testing.T) {
+	r := ComputeWeekRecap(nil, 2026, 3, time.UTC, nil, noTB)
+	if r.TotalGames != 0 || r.WinnerID != nil || r.TieUnresolved || len(r.Titles) != 0 || len(r.Streaks) != 0 {
+		t.Errorf("recap = %+v, want empty", r)
+	}
+}
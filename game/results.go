@@ -0,0 +1,49 @@
+package game
+
+import "math"
+
+// HasPlacements reports whether any participant of g has a recorded finishing position.
+func (g Game) HasPlacements() bool {
+	for _, r := range g.Results {
+		if r.Position > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FinishPercentile scores a finishing position among n players: 100 for first, 0 for last,
+// evenly spaced between. A one-player game counts as 100.
+func FinishPercentile(position, n int) float64 {
+	if n <= 1 {
+		return 100
+	}
+	return float64(n-position) / float64(n-1) * 100
+}
+
+// placementTotals sums FinishPercentile per player over the placed games.
+type placementTotals struct {
+	games map[int64]int
+	sum   map[int64]float64
+}
+
+func newPlacementTotals() placementTotals {
+	return placementTotals{games: map[int64]int{}, sum: map[int64]float64{}}
+}
+
+func (pt placementTotals) add(g Game) {
+	for _, r := range g.Results {
+		if r.Position > 0 {
+			pt.games[r.PlayerID]++
+			pt.sum[r.PlayerID] += FinishPercentile(r.Position, len(g.ParticipantIDs))
+		}
+	}
+}
+
+// avg is the player's mean percentile with 1 decimal, or 0 with no placed games.
+func (pt placementTotals) avg(pid int64) float64 {
+	if pt.games[pid] == 0 {
+		return 0
+	}
+	return math.Round(pt.sum[pid]/float64(pt.games[pid])*10) / 10
+}
@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestFinishPercentile(t *testing.T) {
+	cases := []struct {
+		position, n int
+		want        float64
+	}{
+		{1, 4, 100},
+		{4, 4, 0},
+		{2, 3, 50},
+		{1, 1, 100},
+	}
+	for _, tc := range cases {
+		if got := FinishPercentile(tc.position, tc.n); got != tc.want {
+			t.Errorf("FinishPercentile(%d, %d) = %v, want %v", tc.position, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestGame_HasPlacements(t *testing.T) {
+	score := 12
+	if (Game{Results: []Result{{PlayerID: 1, Score: &score}}}).HasPlacements() {
+		t.Error("scores alone are not placements")
+	}
+	if !(Game{Results: []Result{{PlayerID: 1, Position: 1}}}).HasPlacements() {
+		t.Error("expected placements")
+	}
+}
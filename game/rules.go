@@ -0,0 +1,242 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Ruleset decides who wins a week or a year. Rulesets are stored with the date they take
+// effect; each period is judged by the one in force on its first day (see RulesetFor).
+type Ruleset struct {
+	ID            int64
+	Name          string
+	EffectiveFrom time.Time // first day it applies, as a league-time date; zero for the built-in default
+
+	Weekly PeriodRules
+	Yearly PeriodRules
+}
+
+// PeriodRules are the winner rules for one kind of period.
+type PeriodRules struct {
+	Metric        string // MetricWins, MetricWinRate or MetricAvgFinish
+	Qualifier     string // QualifyAll or QualifyTopHalfAttendance
+	MinAttendance int    // days present needed to qualify, on top of Qualifier; 0 for none
+	TiePolicy     string // TieTiebreaker, TieMostGames or TieHeadToHead
+}
+
+// Metrics rank qualified players; the highest value leads.
+const (
+	MetricWins      = "wins"       // most wins
+	MetricWinRate   = "win_rate"   // wins ÷ games played
+	MetricAvgFinish = "avg_finish" // average finishing percentile over placed games
+)
+
+// Qualifier rules decide who may win. MinAttendance is applied after them.
+const (
+	QualifyAll               = "all"                 // everyone who played
+	QualifyTopHalfAttendance = "top_half_attendance" // top half by days present, ties at the cut included
+)
+
+// Tie policies decide what happens when several qualifiers share the best metric. Each
+// runs its automatic steps in order; a tie left after them goes to a stored tiebreaker.
+const (
+	TieTiebreaker = "tiebreaker"   // a stored tiebreaker decides, else the tie is unresolved
+	TieMostGames  = "most_games"   // most games played wins; a remaining tie goes to a stored tiebreaker
+	TieHeadToHead = "head_to_head" // head-to-head wins, then most games played, then a stored tiebreaker
+)
+
+// Automatic tie steps, as recorded in Standings.DecidedBy.
+const (
+	TieStepHeadToHead = "head_to_head" // most wins in games against the other tied players
+	TieStepMostGames  = "most_games"   // most games played in the period
+)
+
+// DefaultRuleset is the league's original rules, used for any period before the first
+// stored ruleset: most wins for the week; for the year, top half by attendance then best
+// win rate. Ties go to a stored tiebreaker.
+func DefaultRuleset() Ruleset {
+	return Ruleset{
+		Name:   "Default",
+		Weekly: PeriodRules{Metric: MetricWins, Qualifier: QualifyAll, TiePolicy: TieTiebreaker},
+		Yearly: PeriodRules{Metric: MetricWinRate, Qualifier: QualifyTopHalfAttendance, TiePolicy: TieTiebreaker},
+	}
+}
+
+// RulesetFor returns the ruleset in force at t: the one with the latest EffectiveFrom on or
+// before t, or DefaultRuleset if none has started. EffectiveFrom is a date, so t should be
+// the period's start in league time.
+func RulesetFor(rulesets []Ruleset, t time.Time) Ruleset {
+	rs := DefaultRuleset()
+	var from time.Time
+	for _, r := range rulesets {
+		if r.EffectiveFrom.After(t) || (!from.IsZero() && !r.EffectiveFrom.After(from)) {
+			continue
+		}
+		rs, from = r, r.EffectiveFrom
+	}
+	return rs
+}
+
+// Validate reports the first invalid setting, with a user-facing message.
+func (r PeriodRules) Validate() error {
+	switch r.Metric {
+	case MetricWins, MetricWinRate, MetricAvgFinish:
+	default:
+		return errors.New("Please choose a valid metric.")
+	}
+	switch r.Qualifier {
+	case QualifyAll, QualifyTopHalfAttendance:
+	default:
+		return errors.New("Please choose a valid qualifier rule.")
+	}
+	if r.MinAttendance < 0 {
+		return errors.New("Minimum attendance can't be negative.")
+	}
+	switch r.TiePolicy {
+	case TieTiebreaker, TieMostGames, TieHeadToHead:
+	default:
+		return errors.New("Please choose a valid tie policy.")
+	}
+	return nil
+}
+
+// Validate checks the name, date and both periods' rules, with user-facing messages.
+func (r Ruleset) Validate() error {
+	if r.Name == "" {
+		return errors.New("Please give the ruleset a name.")
+	}
+	if r.EffectiveFrom.IsZero() {
+		return errors.New("Please choose the date the ruleset takes effect.")
+	}
+	if err := r.Weekly.Validate(); err != nil {
+		return err
+	}
+	return r.Yearly.Validate()
+}
+
+// qualify marks the qualifiers in stats (sorted by attendance, highest first) and returns
+// their IDs in the same order.
+func (r PeriodRules) qualify(stats []PlayerYearStats) []int64 {
+	if len(stats) == 0 {
+		return nil
+	}
+	cut := 0
+	if r.Qualifier == QualifyTopHalfAttendance {
+		// The top half; for odd N, the larger half. Everyone tied at the cut is in.
+		cut = stats[(len(stats)+1)/2-1].Attendance
+	}
+	cut = max(cut, r.MinAttendance)
+
+	var ids []int64
+	for i := range stats {
+		if stats[i].Attendance >= cut {
+			stats[i].Qualified = true
+			ids = append(ids, stats[i].PlayerID)
+		}
+	}
+	return ids
+}
+
+// tieSteps returns the automatic steps r.TiePolicy runs on a tie, in order.
+func (r PeriodRules) tieSteps() []string {
+	switch r.TiePolicy {
+	case TieMostGames:
+		return []string{TieStepMostGames}
+	case TieHeadToHead:
+		return []string{TieStepHeadToHead, TieStepMostGames}
+	}
+	return nil
+}
+
+// breakTie runs the tie policy's automatic steps on tied, keeping at each step only the
+// players with the best count. It returns who is still tied, sorted by ID, and the step that
+// left a single leader ("" if none did). games are the period's games, for head-to-head.
+func (r PeriodRules) breakTie(tied []int64, stats []PlayerYearStats, games []Game) ([]int64, string) {
+	for _, step := range r.tieSteps() {
+		var count map[int64]int
+		switch step {
+		case TieStepHeadToHead:
+			count = headToHeadWins(tied, games)
+		case TieStepMostGames:
+			count = map[int64]int{}
+			for _, st := range stats {
+				count[st.PlayerID] = st.GamesPlayed
+			}
+		}
+
+		best := 0
+		for _, pid := range tied {
+			best = max(best, count[pid])
+		}
+		kept := make([]int64, 0, len(tied))
+		for _, pid := range tied {
+			if count[pid] == best {
+				kept = append(kept, pid)
+			}
+		}
+		tied = kept
+		if len(tied) == 1 {
+			return tied, step
+		}
+	}
+	return tied, ""
+}
+
+// leaders returns the qualified players sharing the best metric, sorted by ID. A player
+// with nothing to measure (no games for win rate, no placed games for average finish) can't
+// lead, and nobody leads on wins with zero.
+func (r PeriodRules) leaders(stats []PlayerYearStats) []int64 {
+	// a and b are compared exactly: win rates by cross-multiplying, average finishes as
+	// shown (1 decimal).
+	better := func(a, b PlayerYearStats) int {
+		switch r.Metric {
+		case MetricWinRate:
+			return a.Wins*b.GamesPlayed - b.Wins*a.GamesPlayed
+		case MetricAvgFinish:
+			switch {
+			case a.AvgPercentile > b.AvgPercentile:
+				return 1
+			case a.AvgPercentile < b.AvgPercentile:
+				return -1
+			}
+			return 0
+		default:
+			return a.Wins - b.Wins
+		}
+	}
+	measurable := func(st PlayerYearStats) bool {
+		switch r.Metric {
+		case MetricWinRate:
+			return st.GamesPlayed > 0
+		case MetricAvgFinish:
+			return st.PlacedGames > 0
+		default:
+			return st.Wins > 0
+		}
+	}
+
+	var top []PlayerYearStats
+	for _, st := range stats {
+		if !st.Qualified || !measurable(st) {
+			continue
+		}
+		if len(top) > 0 {
+			c := better(st, top[0])
+			if c < 0 {
+				continue
+			}
+			if c > 0 {
+				top = top[:0]
+			}
+		}
+		top = append(top, st)
+	}
+
+	ids := make([]int64, 0, len(top))
+	for _, st := range top {
+		ids = append(ids, st.PlayerID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
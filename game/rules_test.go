@@ -0,0 +1,209 @@
+package game
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRulesetFor(t *testing.T) {
+	jan := Ruleset{ID: 1, Name: "Jan", EffectiveFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	jul := Ruleset{ID: 2, Name: "Jul", EffectiveFrom: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)}
+	rulesets := []Ruleset{jul, jan} // order shouldn't matter
+
+	cases := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "Default"},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "Jan"},
+		{time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), "Jan"},
+		{time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), "Jul"},
+		{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), "Jul"},
+	}
+	for _, c := range cases {
+		if got := RulesetFor(rulesets, c.at).Name; got != c.want {
+			t.Errorf("RulesetFor(%s) = %q, want %q", c.at.Format("2006-01-02"), got, c.want)
+		}
+	}
+}
+
+func TestRuleset_Validate(t *testing.T) {
+	valid := DefaultRuleset()
+	valid.EffectiveFrom = day(2026, 1, 1)
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid ruleset: %v", err)
+	}
+
+	cases := map[string]func(r *Ruleset){
+		"no name":          func(r *Ruleset) { r.Name = "" },
+		"no date":          func(r *Ruleset) { r.EffectiveFrom = time.Time{} },
+		"bad metric":       func(r *Ruleset) { r.Weekly.Metric = "points" },
+		"bad qualifier":    func(r *Ruleset) { r.Yearly.Qualifier = "some" },
+		"negative minimum": func(r *Ruleset) { r.Yearly.MinAttendance = -1 },
+		"bad tie policy":   func(r *Ruleset) { r.Weekly.TiePolicy = "coin" },
+	}
+	for name, mutate := range cases {
+		r := valid
+		mutate(&r)
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestComputeWeekStandings_WinRateWithMinAttendance(t *testing.T) {
+	mon := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	tue := mon.AddDate(0, 0, 1)
+	games := []Game{
+		makeYearGame(mon, []int64{1, 2}, []int64{1}),
+		makeYearGame(mon, []int64{1, 2}, []int64{1}),
+		makeYearGame(mon, []int64{1, 2}, []int64{2}),
+		makeYearGame(tue, []int64{2, 3}, []int64{2}),
+		makeYearGame(tue, []int64{2, 3}, []int64{3}),
+	}
+
+	// Most wins: 1 and 2 tie on two wins each.
+	ws := ComputeWeekStandings(games, 2026, 2, time.UTC, defaultWeekly, noTB)
+	if !slices.Equal(ws.TopIDs, []int64{1, 2}) || !ws.TieUnresolved {
+		t.Errorf("wins: TopIDs = %v, unresolved = %v", ws.TopIDs, ws.TieUnresolved)
+	}
+
+	// Win rate: player 1 (2 of 3) beats 3 (1 of 2) and 2 (2 of 5).
+	rate := PeriodRules{Metric: MetricWinRate, Qualifier: QualifyAll, TiePolicy: TieTiebreaker}
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rate, noTB)
+	if ws.WinnerID == nil || *ws.WinnerID != 1 {
+		t.Errorf("win rate: winner = %v, want 1", ws.WinnerID)
+	}
+
+	// Requiring two days leaves only player 2.
+	rate.MinAttendance = 2
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rate, noTB)
+	if !slices.Equal(ws.Qualifiers, []int64{2}) || ws.WinnerID == nil || *ws.WinnerID != 2 {
+		t.Errorf("min attendance: qualifiers = %v, winner = %v, want [2] / 2", ws.Qualifiers, ws.WinnerID)
+	}
+}
+
+func TestComputeYearStandings_AvgFinishMetric(t *testing.T) {
+	placed := makeYearGame(day(2026, 3, 2), []int64{1, 2, 3}, []int64{3})
+	placed.Results = []Result{{PlayerID: 3, Position: 1}, {PlayerID: 1, Position: 2}, {PlayerID: 2, Position: 3}}
+	unplaced := makeYearGame(day(2026, 3, 3), []int64{1, 2, 3}, []int64{1})
+	unplaced2 := makeYearGame(day(2026, 3, 4), []int64{1, 2, 3}, []int64{1})
+
+	rules := PeriodRules{Metric: MetricAvgFinish, Qualifier: QualifyAll, TiePolicy: TieTiebreaker}
+	ys := ComputeYearStandings([]Game{placed, unplaced, unplaced2}, 2026, time.UTC, rules, noTB)
+	if ys.WinnerID == nil || *ys.WinnerID != 3 {
+		t.Errorf("winner = %v, want 3 (best average finish, despite fewer wins)", ys.WinnerID)
+	}
+
+	// Nobody with placements means nobody can lead.
+	ys = ComputeYearStandings([]Game{unplaced, unplaced2}, 2026, time.UTC, rules, noTB)
+	if len(ys.TopIDs) != 0 || ys.WinnerID != nil {
+		t.Errorf("no placements: TopIDs = %v, winner = %v", ys.TopIDs, ys.WinnerID)
+	}
+}
+
+func TestComputeWeekStandings_TieMostGames(t *testing.T) {
+	mon := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	games := []Game{
+		makeYearGame(mon, []int64{1, 2, 3}, []int64{1}),
+		makeYearGame(mon, []int64{1, 2}, []int64{2}),
+		makeYearGame(mon, []int64{1, 3}, []int64{3}),
+	}
+	rules := PeriodRules{Metric: MetricWins, Qualifier: QualifyAll, TiePolicy: TieMostGames}
+
+	ws := ComputeWeekStandings(games, 2026, 2, time.UTC, rules, noTB)
+	if ws.WinnerID == nil || *ws.WinnerID != 1 {
+		t.Errorf("winner = %v, want 1 (three games to two)", ws.WinnerID)
+	}
+
+	// 2 and 3 still tie on games played, so the stored tiebreaker decides.
+	games = games[1:]
+	games = append(games, makeYearGame(mon, []int64{2, 3}, nil))
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rules, tbFor(WeekScopeKey(2026, 2), 3, 2, 3))
+	if !slices.Equal(ws.TopIDs, []int64{2, 3}) || ws.WinnerID == nil || *ws.WinnerID != 3 {
+		t.Errorf("TopIDs = %v, winner = %v, want [2 3] / 3", ws.TopIDs, ws.WinnerID)
+	}
+}
+
+func TestComputeYearStandings_QualifyAll(t *testing.T) {
+	// Player 3 plays once and wins; under the default rules they don't qualify.
+	games := []Game{
+		makeYearGame(day(2026, 3, 2), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 3, 3), []int64{1, 2}, []int64{2}),
+		makeYearGame(day(2026, 3, 4), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 3, 5), []int64{3}, []int64{3}),
+	}
+
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
+	if ys.WinnerID == nil || *ys.WinnerID != 1 {
+		t.Errorf("default rules: winner = %v, want 1", ys.WinnerID)
+	}
+
+	all := defaultYearly
+	all.Qualifier = QualifyAll
+	ys = ComputeYearStandings(games, 2026, time.UTC, all, noTB)
+	if len(ys.Qualifiers) != 3 || ys.WinnerID == nil || *ys.WinnerID != 3 {
+		t.Errorf("everyone qualifies: qualifiers = %v, winner = %v, want 3 / 3", ys.Qualifiers, ys.WinnerID)
+	}
+}
+
+func TestComputeWeekStandings_TieHeadToHead(t *testing.T) {
+	mon := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	rules := PeriodRules{Metric: MetricWins, Qualifier: QualifyAll, TiePolicy: TieHeadToHead}
+
+	// 1 and 2 tie on two wins; 2 beat 1 at the same table, 1 only beat player 3.
+	games := []Game{
+		makeYearGame(mon, []int64{1, 3}, []int64{1}),
+		makeYearGame(mon, []int64{1, 3}, []int64{1}),
+		makeYearGame(mon, []int64{1, 2}, []int64{2}),
+		makeYearGame(mon, []int64{2, 3}, []int64{2}),
+	}
+	ws := ComputeWeekStandings(games, 2026, 2, time.UTC, rules, noTB)
+	if ws.WinnerID == nil || *ws.WinnerID != 2 || ws.DecidedBy != TieStepHeadToHead {
+		t.Errorf("winner = %v by %q, want 2 by head-to-head", ws.WinnerID, ws.DecidedBy)
+	}
+	if !slices.Equal(ws.TiedIDs, []int64{1, 2}) {
+		t.Errorf("TiedIDs = %v, want [1 2]", ws.TiedIDs)
+	}
+
+	// Even head-to-head (one win each); 1 played more games.
+	games = append(games, makeYearGame(mon, []int64{1, 2}, []int64{1}), makeYearGame(mon, []int64{2, 3}, []int64{2}))
+	games = append(games, makeYearGame(mon, []int64{1, 3}, []int64{3}))
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rules, noTB)
+	if ws.WinnerID == nil || *ws.WinnerID != 1 || ws.DecidedBy != TieStepMostGames {
+		t.Errorf("winner = %v by %q, want 1 by most games", ws.WinnerID, ws.DecidedBy)
+	}
+
+	// Still level after both steps: a stored play-off settles it.
+	games = append(games, makeYearGame(mon, []int64{2, 3}, []int64{3}))
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rules, noTB)
+	if !ws.TieUnresolved || !slices.Equal(ws.TopIDs, []int64{1, 2}) {
+		t.Fatalf("TopIDs = %v, unresolved = %v, want an unresolved tie between 1 and 2", ws.TopIDs, ws.TieUnresolved)
+	}
+	playoff := func(scope, scopeKey string) (Tiebreaker, bool, error) {
+		return Tiebreaker{Scope: scope, ScopeKey: scopeKey, TiedPlayerIDs: []int64{2, 1}, WinnerID: 2, Method: MethodPlayoff, GameID: 99}, true, nil
+	}
+	ws = ComputeWeekStandings(games, 2026, 2, time.UTC, rules, playoff)
+	if ws.WinnerID == nil || *ws.WinnerID != 2 || ws.DecidedBy != MethodPlayoff {
+		t.Errorf("winner = %v by %q, want 2 by play-off", ws.WinnerID, ws.DecidedBy)
+	}
+}
+
+func TestHeadToHeadWins_CountsEachGameOnce(t *testing.T) {
+	day := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	team := makeYearGame(day, []int64{1, 2, 3, 4}, []int64{1, 2})
+	team.Mode, team.Teams = ModeTeam, [][]int64{{1, 2}, {3, 4}}
+	coop := makeYearGame(day, []int64{1, 3}, []int64{1, 3})
+	coop.Mode = ModeCoop
+	games := []Game{
+		makeYearGame(day, []int64{1, 2, 3}, []int64{1}), // beat both tied opponents: one win
+		team, // 1 and 2 are teammates, so each beat only 3
+		coop,
+	}
+
+	wins := headToHeadWins([]int64{1, 2, 3}, games)
+	if wins[1] != 2 || wins[2] != 1 || wins[3] != 0 {
+		t.Errorf("wins = %v, want 1:2 2:1 3:0", wins)
+	}
+}
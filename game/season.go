@@ -0,0 +1,72 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// Season is a named stretch of league days, e.g. a summer league or Q1, with its own
+// standings, race and tiebreaker. Seasons may overlap each other and cross year boundaries.
+type Season struct {
+	ID        int64
+	Name      string
+	StartDate time.Time // first day, as a league-time date
+	EndDate   time.Time // last day (inclusive), as a league-time date
+}
+
+// SeasonStandings are the standings for one season; ScopeKey is the season's ScopeKey.
+type SeasonStandings struct {
+	Season Season
+	Standings
+}
+
+// Bounds returns the half-open interval [start, end) covering the season's days in loc,
+// from StartDate 00:00 to the day after EndDate 00:00.
+func (s Season) Bounds(loc *time.Location) (time.Time, time.Time) {
+	start := leagueDate(s.StartDate, loc)
+	return start, leagueDate(s.EndDate, loc).AddDate(0, 0, 1)
+}
+
+// ScopeKey is the season's tiebreaker key, its dates: "2026-06-01..2026-08-31". Keying by
+// date rather than ID keeps tiebreakers portable across exports; changing a season's dates
+// leaves its old tiebreaker behind.
+func (s Season) ScopeKey() string {
+	return RangeScopeKey(s.StartDate, s.EndDate)
+}
+
+// Validate checks the name and dates, with user-facing messages.
+func (s Season) Validate() error {
+	if s.Name == "" {
+		return errors.New("Please give the season a name.")
+	}
+	if s.StartDate.IsZero() || s.EndDate.IsZero() {
+		return errors.New("Please choose the season's first and last days.")
+	}
+	if s.EndDate.Before(s.StartDate) {
+		return errors.New("The last day must not be before the first day.")
+	}
+	return nil
+}
+
+// ComputeSeasonStandings ranks the season's active games under rules, usually the yearly
+// rules in force on its first day. Ties go to the stored "season" tiebreaker; see
+// ComputeRangeStandings for how the rules are applied.
+func ComputeSeasonStandings(
+	games []Game,
+	season Season,
+	loc *time.Location,
+	rules PeriodRules,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) SeasonStandings {
+	start, end := season.Bounds(loc)
+	return SeasonStandings{
+		Season:    season,
+		Standings: ComputeRangeStandings(games, start, end, loc, "season", season.ScopeKey(), rules, getTB),
+	}
+}
+
+// leagueDate returns midnight in loc on t's date in loc.
+func leagueDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// winterSeason runs from Dec 15, 2025 through Jan 15, 2026, across the new year.
+func winterSeason(loc *time.Location) Season {
+	return Season{
+		ID:        1,
+		Name:      "Winter",
+		StartDate: time.Date(2025, 12, 15, 0, 0, 0, 0, loc),
+		EndDate:   time.Date(2026, 1, 15, 0, 0, 0, 0, loc),
+	}
+}
+
+func TestSeason_BoundsIncludeLastDay(t *testing.T) {
+	loc := chicago(t)
+	start, end := winterSeason(loc).Bounds(loc)
+
+	if want := time.Date(2025, 12, 15, 0, 0, 0, 0, loc); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+	if want := time.Date(2026, 1, 16, 0, 0, 0, 0, loc); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+}
+
+func TestSeason_ScopeKey(t *testing.T) {
+	if got := winterSeason(time.UTC).ScopeKey(); got != "2025-12-15..2026-01-15" {
+		t.Errorf("ScopeKey = %q", got)
+	}
+}
+
+func TestSeason_Validate(t *testing.T) {
+	valid := winterSeason(time.UTC)
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid season: %v", err)
+	}
+
+	cases := map[string]func(se *Season){
+		"no name":       func(se *Season) { se.Name = "" },
+		"no start":      func(se *Season) { se.StartDate = time.Time{} },
+		"end before":    func(se *Season) { se.EndDate = se.StartDate.AddDate(0, 0, -1) },
+		"no end either": func(se *Season) { se.EndDate = time.Time{} },
+	}
+	for name, mutate := range cases {
+		se := valid
+		mutate(&se)
+		if err := se.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	oneDay := valid
+	oneDay.EndDate = oneDay.StartDate
+	if err := oneDay.Validate(); err != nil {
+		t.Errorf("one-day season: %v", err)
+	}
+}
+
+func TestComputeSeasonStandings_CrossesYearBoundary(t *testing.T) {
+	games := []Game{
+		makeYearGame(day(2025, 12, 12), []int64{1, 2}, []int64{1}), // before the season
+		makeYearGame(day(2025, 12, 15), []int64{1, 2}, []int64{2}),
+		makeYearGame(day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 1, 6), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 1, 15), []int64{1, 2}, []int64{2}), // last day counts
+		makeYearGame(day(2026, 1, 16), []int64{1, 2}, []int64{1}), // after the season
+	}
+	season := winterSeason(time.UTC)
+
+	ss := ComputeSeasonStandings(games, season, time.UTC, defaultYearly, noTB)
+	if ss.Scope != "season" || ss.ScopeKey != season.ScopeKey() {
+		t.Errorf("scope = %s/%s", ss.Scope, ss.ScopeKey)
+	}
+	if !slices.Equal(ss.TopIDs, []int64{1, 2}) || !ss.TieUnresolved {
+		t.Fatalf("TopIDs = %v, unresolved = %v, want an unresolved tie between 1 and 2", ss.TopIDs, ss.TieUnresolved)
+	}
+	for _, st := range ss.Stats {
+		if st.GamesPlayed != 4 {
+			t.Errorf("player %d played %d season games, want 4", st.PlayerID, st.GamesPlayed)
+		}
+	}
+
+	ss = ComputeSeasonStandings(games, season, time.UTC, defaultYearly, tbFor(season.ScopeKey(), 2, 1, 2))
+	if ss.WinnerID == nil || *ss.WinnerID != 2 {
+		t.Errorf("winner = %v, want 2 from the season tiebreaker", ss.WinnerID)
+	}
+}
+
+func TestComputeSeasonRace_WeeksFromSeasonStart(t *testing.T) {
+	games := []Game{
+		makeYearGame(day(2025, 12, 15), []int64{1}, []int64{1}), // week 1
+		makeYearGame(day(2025, 12, 21), []int64{1}, []int64{1}), // still week 1 (day 7)
+		makeYearGame(day(2026, 1, 2), []int64{2}, []int64{2}),   // week 3
+		makeYearGame(day(2026, 1, 20), []int64{2}, []int64{2}),  // after the season
+	}
+
+	race := ComputeSeasonRace(games, winterSeason(time.UTC), time.UTC, RaceMetricWins, 5, playerList(1, 2))
+	if !slices.Equal(race.Weeks, []int{1, 3}) {
+		t.Fatalf("Weeks = %v, want [1 3]", race.Weeks)
+	}
+	for _, se := range race.Series {
+		want := map[int64][]float64{1: {2, 2}, 2: {0, 1}}[se.PlayerID]
+		if !slices.Equal(se.Values, want) {
+			t.Errorf("player %d values = %v, want %v", se.PlayerID, se.Values, want)
+		}
+	}
+}
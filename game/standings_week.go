@@ -2,40 +2,48 @@ package game
 
 import (
 	"fmt"
-	"sort"
+	"time"
 )
 
 type WeekStandings struct {
-	Year     int
-	Week     int
-	ScopeKey string // "2026-W07"
+	Year int
+	Week int
 
 	TotalGames int
 	Wins       map[int64]int // playerID -> wins
-	TotalWins  int
+	TotalWins  int           // the most wins by any player
 
-	TopIDs        []int64 // tied leaders by wins (could be 1)
-	WinnerID      *int64
-	TieUnresolved bool
+	// Standings holds the ranked week under the weekly rules; ScopeKey is "2026-W07".
+	Standings
 }
 
 func WeekScopeKey(year, week int) string {
 	return fmt.Sprintf("%04d-W%02d", year, week)
 }
 
-// ComputeWeekStandings computes the standings for a given week.
+// ComputeWeekStandings computes the standings for a given week's games under rules, usually
+// RulesetFor(...).Weekly. The default rules pick the player with the most wins; a tie goes
+// to the stored weekly tiebreaker, else it is unresolved. See ComputeRangeStandings for
+// how the rules are applied.
+//
+// Every winner of a game is credited with a win, so team and co-op games follow the policy
+// described on ModeCompetitive; a co-op loss adds a game but no wins. Attendance days are
+// judged in loc, the league's time zone.
 func ComputeWeekStandings(
 	games []Game,
 	year, week int,
+	loc *time.Location,
+	rules PeriodRules,
 	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
 ) WeekStandings {
 	ws := WeekStandings{
-		Year:     year,
-		Week:     week,
-		ScopeKey: WeekScopeKey(year, week),
+		Year: year,
+		Week: week,
 
 		TotalGames: len(games),
 		Wins:       map[int64]int{},
+
+		Standings: rankStandings(games, loc, "weekly", WeekScopeKey(year, week), rules, getTB),
 	}
 
 	for _, g := range games {
@@ -43,41 +51,8 @@ func ComputeWeekStandings(
 			ws.Wins[wid]++
 		}
 	}
-
-	ws.TotalWins = 0
 	for _, w := range ws.Wins {
-		if w > ws.TotalWins {
-			ws.TotalWins = w
-		}
-	}
-	for pid, w := range ws.Wins {
-		if w == ws.TotalWins && ws.TotalWins > 0 {
-			ws.TopIDs = append(ws.TopIDs, pid)
-		}
-	}
-	sort.Slice(ws.TopIDs, func(i, j int) bool { return ws.TopIDs[i] < ws.TopIDs[j] })
-
-	if len(ws.TopIDs) == 1 {
-		ws.WinnerID = &ws.TopIDs[0]
-		return ws
-	}
-
-	if len(ws.TopIDs) > 1 {
-		// If we have a stored tiebreaker, apply it.
-		if getTB != nil {
-			tb, ok, err := getTB("weekly", ws.ScopeKey)
-			if err != nil {
-				panic(err)
-			}
-			if ok {
-				if containsID(ws.TopIDs, tb.WinnerID) {
-					wid := tb.WinnerID
-					ws.WinnerID = &wid
-					return ws
-				}
-			}
-		}
-		ws.TieUnresolved = true
+		ws.TotalWins = max(ws.TotalWins, w)
 	}
 
 	return ws
@@ -91,3 +66,16 @@ func containsID(xs []int64, v int64) bool {
 	}
 	return false
 }
+
+// sameIDs reports whether a and b hold the same IDs, in any order.
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		if !containsID(b, x) {
+			return false
+		}
+	}
+	return true
+}
@@ -5,12 +5,15 @@ import (
 	"time"
 )
 
+var defaultWeekly, defaultYearly = DefaultRuleset().Weekly, DefaultRuleset().Yearly
+
 func noTB(_, _ string) (Tiebreaker, bool, error) { return Tiebreaker{}, false, nil }
 
-func tbFor(scopeKey string, winnerID int64) func(string, string) (Tiebreaker, bool, error) {
+// tbFor stores a tiebreaker for scopeKey picking winnerID from tied.
+func tbFor(scopeKey string, winnerID int64, tied ...int64) func(string, string) (Tiebreaker, bool, error) {
 	return func(scope, key string) (Tiebreaker, bool, error) {
 		if key == scopeKey {
-			return Tiebreaker{WinnerID: winnerID}, true, nil
+			return Tiebreaker{TiedPlayerIDs: tied, WinnerID: winnerID}, true, nil
 		}
 		return Tiebreaker{}, false, nil
 	}
@@ -26,7 +29,7 @@ func makeGame(winners ...int64) Game {
 }
 
 func TestComputeWeekStandings_NoGames(t *testing.T) {
-	ws := ComputeWeekStandings(nil, 2026, 1, noTB)
+	ws := ComputeWeekStandings(nil, 2026, 1, time.UTC, defaultWeekly, noTB)
 
 	if ws.TotalGames != 0 {
 		t.Errorf("TotalGames = %d, want 0", ws.TotalGames)
@@ -45,7 +48,7 @@ func TestComputeWeekStandings_ClearWinner(t *testing.T) {
 		makeGame(1),
 		makeGame(2),
 	}
-	ws := ComputeWeekStandings(games, 2026, 1, noTB)
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, noTB)
 
 	if ws.TotalGames != 3 {
 		t.Errorf("TotalGames = %d, want 3", ws.TotalGames)
@@ -63,7 +66,7 @@ func TestComputeWeekStandings_ClearWinner(t *testing.T) {
 
 func TestComputeWeekStandings_TieNoTiebreaker(t *testing.T) {
 	games := []Game{makeGame(1), makeGame(2)}
-	ws := ComputeWeekStandings(games, 2026, 1, noTB)
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, noTB)
 
 	if ws.WinnerID != nil {
 		t.Errorf("WinnerID should be nil for unresolved tie, got %v", ws.WinnerID)
@@ -79,7 +82,7 @@ func TestComputeWeekStandings_TieNoTiebreaker(t *testing.T) {
 func TestComputeWeekStandings_TieResolvedByTiebreaker(t *testing.T) {
 	games := []Game{makeGame(1), makeGame(2)}
 	scopeKey := WeekScopeKey(2026, 1)
-	ws := ComputeWeekStandings(games, 2026, 1, tbFor(scopeKey, 2))
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, tbFor(scopeKey, 2, 1, 2))
 
 	if ws.WinnerID == nil || *ws.WinnerID != 2 {
 		t.Errorf("WinnerID = %v, want 2", ws.WinnerID)
@@ -93,7 +96,7 @@ func TestComputeWeekStandings_TiebreakerWrongPlayer(t *testing.T) {
 	// Tiebreaker names player 99 who is not in TopIDs — should be ignored.
 	games := []Game{makeGame(1), makeGame(2)}
 	scopeKey := WeekScopeKey(2026, 1)
-	ws := ComputeWeekStandings(games, 2026, 1, tbFor(scopeKey, 99))
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, tbFor(scopeKey, 99, 1, 2))
 
 	if ws.WinnerID != nil {
 		t.Errorf("WinnerID should be nil, got %v", ws.WinnerID)
@@ -103,13 +106,34 @@ func TestComputeWeekStandings_TiebreakerWrongPlayer(t *testing.T) {
 	}
 }
 
+func TestComputeWeekStandings_StaleTiebreaker(t *testing.T) {
+	// Decided while 1, 2 and 3 were tied; 3's win has since been deactivated, leaving
+	// only 1 and 2 level, so the stored decision no longer applies.
+	games := []Game{makeGame(1), makeGame(2)}
+	scopeKey := WeekScopeKey(2026, 1)
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, tbFor(scopeKey, 2, 1, 2, 3))
+
+	if ws.WinnerID != nil || !ws.TieUnresolved {
+		t.Errorf("winner = %v, unresolved = %v, want an unresolved tie", ws.WinnerID, ws.TieUnresolved)
+	}
+	if ws.StaleTiebreaker == nil || ws.StaleTiebreaker.WinnerID != 2 {
+		t.Errorf("StaleTiebreaker = %+v, want the stored decision for 2", ws.StaleTiebreaker)
+	}
+
+	// The same players in another order still match.
+	ws = ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, tbFor(scopeKey, 2, 2, 1))
+	if ws.WinnerID == nil || *ws.WinnerID != 2 || ws.StaleTiebreaker != nil {
+		t.Errorf("winner = %v, stale = %v, want 2 and not stale", ws.WinnerID, ws.StaleTiebreaker)
+	}
+}
+
 func TestComputeWeekStandings_MultipleWinnersPerGame(t *testing.T) {
 	// A co-op game where both players 1 and 2 win.
 	games := []Game{
 		{PlayedAt: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), WinnerIDs: []int64{1, 2}, IsActive: true},
 		{PlayedAt: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), WinnerIDs: []int64{1}, IsActive: true},
 	}
-	ws := ComputeWeekStandings(games, 2026, 1, noTB)
+	ws := ComputeWeekStandings(games, 2026, 1, time.UTC, defaultWeekly, noTB)
 
 	if ws.Wins[1] != 2 {
 		t.Errorf("player 1 wins = %d, want 2", ws.Wins[1])
@@ -123,7 +147,7 @@ func TestComputeWeekStandings_MultipleWinnersPerGame(t *testing.T) {
 }
 
 func TestComputeWeekStandings_ScopeKey(t *testing.T) {
-	ws := ComputeWeekStandings(nil, 2026, 7, noTB)
+	ws := ComputeWeekStandings(nil, 2026, 7, time.UTC, defaultWeekly, noTB)
 	if ws.ScopeKey != "2026-W07" {
 		t.Errorf("ScopeKey = %q, want %q", ws.ScopeKey, "2026-W07")
 	}
@@ -145,3 +169,19 @@ func TestWeekScopeKey(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeWeekStandings_CoopPolicy(t *testing.T) {
+	lost := Game{Mode: ModeCoop, ParticipantIDs: []int64{1, 2}, IsActive: true}
+	won := Game{Mode: ModeCoop, ParticipantIDs: []int64{1, 2}, WinnerIDs: []int64{1, 2}, IsActive: true}
+	comp := makeGame(1)
+	comp.ParticipantIDs = []int64{1, 2}
+
+	ws := ComputeWeekStandings([]Game{lost, won, comp}, 2026, 2, time.UTC, defaultWeekly, noTB)
+
+	if ws.TotalGames != 3 || ws.Wins[1] != 2 || ws.Wins[2] != 1 {
+		t.Errorf("games = %d, wins = %v; want 3 games, the co-op loss credited to no one", ws.TotalGames, ws.Wins)
+	}
+	if ws.WinnerID == nil || *ws.WinnerID != 1 {
+		t.Errorf("winner = %v, want 1", ws.WinnerID)
+	}
+}
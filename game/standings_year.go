@@ -14,50 +14,135 @@ type PlayerYearStats struct {
 	Wins        int
 	WinRate     float64
 	Qualified   bool
+
+	PlacedGames   int     // games with a recorded placement
+	AvgPercentile float64 // mean FinishPercentile over PlacedGames, 1 decimal
 }
 
-type YearStandings struct {
-	Year     int
+// Standings is a leaderboard ranked by a ruleset's period rules over some span of games.
+type Standings struct {
+	Scope    string // tiebreaker scope: "weekly", "yearly", "season", "alltime", "range"
 	ScopeKey string // "2026"
 
 	Stats []PlayerYearStats
 
 	Qualifiers []int64 // player IDs
-	TopIDs     []int64 // tied leaders
+	TiedIDs    []int64 // leaders on the metric alone, when more than one
+	TopIDs     []int64 // leaders still tied after the tie policy's automatic steps
 	WinnerID   *int64
 
+	// DecidedBy is how a tie for the lead was settled: an automatic TieStep, or the stored
+	// tiebreaker's Method. Empty if there was no tie or it is unresolved.
+	DecidedBy     string
 	TieUnresolved bool
+
+	// StaleTiebreaker is the stored tiebreaker for this period when it was decided among
+	// different tied players than TopIDs (a game was since logged, edited or deactivated).
+	// It no longer decides the winner and the tie needs deciding again.
+	StaleTiebreaker *Tiebreaker
+}
+
+type YearStandings struct {
+	Year int
+	Standings
+}
+
+// AllTimeScopeKey is the tiebreaker key for the all-time leaderboard.
+const AllTimeScopeKey = "all"
+
+// RangeScopeKey is the tiebreaker key for a date-range leaderboard, e.g. "2024-01-01..2025-12-31".
+// A zero from or to (an open-ended range) leaves that side empty.
+func RangeScopeKey(from, to time.Time) string {
+	day := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+	return day(from) + ".." + day(to)
 }
 
 func YearScopeKey(year int) string { return fmt.Sprintf("%d", year) }
 
-// ComputeYearStandings computes the standings for a given year.
-//
-// Spec implemented:
-// - Attendance = unique days attended (participated).
-// - Qualifiers = top 1/2 of attendees (by attendance).
-// - Winner = highest win rate (wins/games played) among qualifiers.
-// - Any tie for winner is resolved by chance (stored tiebreaker), else unresolved.
+// ComputeYearStandings computes the standings for a given calendar year in loc, the
+// league's time zone, under rules (usually RulesetFor(...).Yearly). See
+// ComputeRangeStandings for how the rules are applied.
 func ComputeYearStandings(
 	games []Game,
 	year int,
+	loc *time.Location,
+	rules PeriodRules,
 	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
 ) YearStandings {
-	ys := YearStandings{
-		Year:     year,
-		ScopeKey: YearScopeKey(year),
+	start, end := YearBounds(year, loc)
+	return YearStandings{
+		Year:      year,
+		Standings: ComputeRangeStandings(games, start, end, loc, "yearly", YearScopeKey(year), rules, getTB),
+	}
+}
+
+// ComputeRangeStandings ranks the active games played in [start, end) under rules.
+// A zero start or end leaves that side unbounded.
+//
+// Spec implemented:
+// - Attendance = unique days attended (participated).
+// - Qualifiers = everyone, or the top 1/2 of attendees by attendance (rules.Qualifier),
+// and at least rules.MinAttendance days.
+// - Winner = best rules.Metric among qualifiers: most wins, highest win rate
+// (wins/games played) or best average finish.
+// - A tie for winner is first narrowed by the tie policy's automatic steps (rules.TiePolicy:
+// head-to-head wins, then most games played); any remaining tie is resolved by the stored
+// tiebreaker for scope/scopeKey (chance or a play-off game), else unresolved.
+//
+// With the default yearly rules this is the league's original year: top half by attendance,
+// then the best win rate.
+//
+// Team and co-op games follow the policy described on ModeCompetitive: a co-op loss counts
+// as a game played (and attendance) for everyone at the table, with no one winning.
+//
+// Placements, where recorded, are summarized as each player's average finishing percentile;
+// they only affect the winner under MetricAvgFinish.
+//
+// Attendance days are judged in loc, the league's time zone.
+func ComputeRangeStandings(
+	games []Game,
+	start, end time.Time,
+	loc *time.Location,
+	scope, scopeKey string,
+	rules PeriodRules,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) Standings {
+	// Only consider active games in the requested range.
+	inRange := make([]Game, 0, len(games))
+	for _, g := range games {
+		if !g.IsActive || (!start.IsZero() && g.PlayedAt.Before(start)) || (!end.IsZero() && !g.PlayedAt.Before(end)) {
+			continue
+		}
+		inRange = append(inRange, g)
+	}
+	return rankStandings(inRange, loc, scope, scopeKey, rules, getTB)
+}
+
+// rankStandings computes every player's stats over games and picks the winner under rules.
+func rankStandings(
+	games []Game,
+	loc *time.Location,
+	scope, scopeKey string,
+	rules PeriodRules,
+	getTB func(scope, scopeKey string) (Tiebreaker, bool, error),
+) Standings {
+	ys := Standings{
+		Scope:    scope,
+		ScopeKey: scopeKey,
 	}
 
 	attendedDays := map[int64]map[string]bool{} // playerID -> dateKey -> true
 	playedCount := map[int64]int{}
 	winsCount := map[int64]int{}
+	placements := newPlacementTotals()
 
-	// Only consider games in the requested year (in local time).
 	for _, g := range games {
-		local := g.PlayedAt.In(time.Local)
-		if local.Year() != year {
-			continue
-		}
+		local := g.PlayedAt.In(loc)
 
 		dateKey := local.Format("2006-01-02")
 		for _, pid := range g.ParticipantIDs {
@@ -70,6 +155,7 @@ func ComputeYearStandings(
 		for _, wid := range g.WinnerIDs {
 			winsCount[wid]++
 		}
+		placements.add(g)
 	}
 
 	// Build stats for anyone who appeared in any map.
@@ -90,6 +176,9 @@ func ComputeYearStandings(
 			GamesPlayed: gp,
 			Wins:        wins,
 			WinRate:     math.Round(wr*1000) / 10, // percent with 1 decimal (e.g., 66.7)
+
+			PlacedGames:   placements.games[pid],
+			AvgPercentile: placements.avg(pid),
 		})
 	}
 
@@ -104,94 +193,32 @@ func ComputeYearStandings(
 		return ys.Stats[i].PlayerID < ys.Stats[j].PlayerID
 	})
 
-	// Qualifiers = top half by attendance.
-	// If the cutoff is tied, include all tied at the cutoff attendance.
-	if len(ys.Stats) == 0 {
-		return ys
-	}
-
-	n := len(ys.Stats)
-	half := (n + 1) / 2 // the top half; for odd N, includes the larger half.
-
-	cutAttendance := ys.Stats[half-1].Attendance
-	for i := range ys.Stats {
-		if ys.Stats[i].Attendance >= cutAttendance {
-			ys.Stats[i].Qualified = true
-			ys.Qualifiers = append(ys.Qualifiers, ys.Stats[i].PlayerID)
-		} else {
-			break // because sorted by attendance desc
-		}
-	}
-
-	// Determine leader(s) by WIN RATE among qualifiers.
-	bestRate := -1.0
-	for _, pid := range ys.Qualifiers {
-		gp := playedCount[pid]
-		if gp == 0 {
-			continue
-		}
-		rate := float64(winsCount[pid]) / float64(gp)
-		if rate > bestRate {
-			bestRate = rate
-		}
-	}
-
-	// If nobody has any games played (weird but possible), there is no winner.
-	if bestRate < 0 {
-		return ys
-	}
-
-	for _, pid := range ys.Qualifiers {
-		gp := playedCount[pid]
-		if gp == 0 {
-			continue
-		}
-		//rate := float64(winsCount[pid]) / float64(gp)
-
-		// Use exact equality on the rational comparison by cross-multiplying to avoid float issues.
-		// rate == bestRate  <=>  wins/gp == bestWins/bestGP,
-		// but we didn't store the bestWins/bestGP. So we do a tolerant float compare OR recompute via cross-multiply by scanning again:
-		// We'll do cross-multiply with the bestRate represented as wins/gp by re-finding a "best representative".
-	}
-
-	// Re-find the representative best (wins, gp) pair, then use cross-multiply to find ties exactly.
-	var bestWins, bestGP int
-	for _, pid := range ys.Qualifiers {
-		gp := playedCount[pid]
-		if gp == 0 {
-			continue
-		}
-		w := winsCount[pid]
-		if float64(w)/float64(gp) == bestRate {
-			bestWins, bestGP = w, gp
-			break
-		}
-	}
-	for _, pid := range ys.Qualifiers {
-		gp := playedCount[pid]
-		if gp == 0 {
-			continue
-		}
-		w := winsCount[pid]
-		if w*bestGP == bestWins*gp {
-			ys.TopIDs = append(ys.TopIDs, pid)
-		}
+	ys.Qualifiers = rules.qualify(ys.Stats)
+	ys.TopIDs = rules.leaders(ys.Stats)
+	if len(ys.TopIDs) > 1 {
+		ys.TiedIDs = ys.TopIDs
+		ys.TopIDs, ys.DecidedBy = rules.breakTie(ys.TiedIDs, ys.Stats, games)
 	}
-	sort.Slice(ys.TopIDs, func(i, j int) bool { return ys.TopIDs[i] < ys.TopIDs[j] })
 
 	if len(ys.TopIDs) == 1 {
 		ys.WinnerID = &ys.TopIDs[0]
 		return ys
 	}
 
-	// Tie: resolve via stored the "game of chance" tiebreaker if present.
+	// Tie: resolve via the stored tiebreaker (chance or play-off) if present and it was
+	// decided among exactly these players.
 	if len(ys.TopIDs) > 1 {
 		if getTB != nil {
-			tb, ok, err := getTB("yearly", ys.ScopeKey)
-			if err == nil && ok && containsID(ys.TopIDs, tb.WinnerID) {
-				wid := tb.WinnerID
-				ys.WinnerID = &wid
-				return ys
+			tb, ok, err := getTB(ys.Scope, ys.ScopeKey)
+			if err == nil && ok {
+				if !sameIDs(tb.TiedPlayerIDs, ys.TopIDs) {
+					ys.StaleTiebreaker = &tb
+				} else if containsID(ys.TopIDs, tb.WinnerID) {
+					wid := tb.WinnerID
+					ys.WinnerID = &wid
+					ys.DecidedBy = tb.Method
+					return ys
+				}
 			}
 			// If err != nil, we *don't* panic; we just leave it unresolved.
 		}
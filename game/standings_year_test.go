@@ -20,7 +20,7 @@ func day(year int, month time.Month, d int) time.Time {
 }
 
 func TestComputeYearStandings_NoGames(t *testing.T) {
-	ys := ComputeYearStandings(nil, 2026, nil)
+	ys := ComputeYearStandings(nil, 2026, time.UTC, defaultYearly, nil)
 
 	if len(ys.Stats) != 0 {
 		t.Errorf("Stats len = %d, want 0", len(ys.Stats))
@@ -37,7 +37,7 @@ func TestComputeYearStandings_ClearWinner(t *testing.T) {
 		makeYearGame(day(2026, 1, 6), []int64{1}, []int64{1}),
 		makeYearGame(day(2026, 1, 7), []int64{1}, []int64{1}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	if ys.WinnerID == nil || *ys.WinnerID != 1 {
 		t.Errorf("WinnerID = %v, want 1", ys.WinnerID)
@@ -55,7 +55,7 @@ func TestComputeYearStandings_QualificationCutoff(t *testing.T) {
 		makeYearGame(day(2026, 1, 7), []int64{1, 2}, []int64{2}),
 		makeYearGame(day(2026, 1, 8), []int64{1}, []int64{1}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	qualSet := map[int64]bool{}
 	for _, pid := range ys.Qualifiers {
@@ -75,7 +75,7 @@ func TestComputeYearStandings_AttendanceTiePullsAllIn(t *testing.T) {
 		makeYearGame(day(2026, 1, 5), []int64{1, 2, 3}, []int64{1}),
 		makeYearGame(day(2026, 1, 6), []int64{1, 2, 3}, []int64{2}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	if len(ys.Qualifiers) != 3 {
 		t.Errorf("all 3 players should qualify when attendance is tied, got %v", ys.Qualifiers)
@@ -93,7 +93,7 @@ func TestComputeYearStandings_WinRateBeatsRawWins(t *testing.T) {
 		makeYearGame(day(2026, 1, 6), []int64{1}, nil),           // p1 plays Jan 6, no winner
 		makeYearGame(day(2026, 1, 6), []int64{2}, []int64{2}),    // p2 plays Jan 6, p2 wins
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	if ys.WinnerID == nil || *ys.WinnerID != 2 {
 		t.Errorf("WinnerID = %v, want 2 (better win rate)", ys.WinnerID)
@@ -106,7 +106,7 @@ func TestComputeYearStandings_TieNoTiebreaker(t *testing.T) {
 		makeYearGame(day(2026, 1, 5), []int64{1, 2}, []int64{1}),
 		makeYearGame(day(2026, 1, 6), []int64{1, 2}, []int64{2}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	if ys.WinnerID != nil {
 		t.Errorf("WinnerID should be nil for unresolved tie, got %v", ys.WinnerID)
@@ -125,7 +125,7 @@ func TestComputeYearStandings_TieResolvedByTiebreaker(t *testing.T) {
 		makeYearGame(day(2026, 1, 6), []int64{1, 2}, []int64{2}),
 	}
 	scopeKey := YearScopeKey(2026)
-	ys := ComputeYearStandings(games, 2026, tbFor(scopeKey, 1))
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, tbFor(scopeKey, 1, 1, 2))
 
 	if ys.WinnerID == nil || *ys.WinnerID != 1 {
 		t.Errorf("WinnerID = %v, want 1", ys.WinnerID)
@@ -148,7 +148,7 @@ func TestComputeYearStandings_CrossMultiplyTieDetection(t *testing.T) {
 		makeYearGame(day(2026, 1, 6), []int64{2}, []int64{}),
 		makeYearGame(day(2026, 1, 6), []int64{2}, []int64{}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	if len(ys.TopIDs) != 2 {
 		t.Errorf("TopIDs = %v, want both players tied (2/3 == 4/6)", ys.TopIDs)
@@ -160,7 +160,7 @@ func TestComputeYearStandings_GamesFromOtherYearIgnored(t *testing.T) {
 		makeYearGame(day(2025, 12, 31), []int64{1}, []int64{1}), // wrong year
 		makeYearGame(day(2026, 1, 5), []int64{2}, []int64{2}),
 	}
-	ys := ComputeYearStandings(games, 2026, noTB)
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
 
 	for _, s := range ys.Stats {
 		if s.PlayerID == 1 {
@@ -187,3 +187,124 @@ func TestYearScopeKey(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeYearStandings_UsesLeagueTimezone(t *testing.T) {
+	loc := chicago(t)
+
+	// 11pm on New Year's Eve in Chicago is already next year in UTC.
+	nye := makeYearGame(time.Date(2026, 12, 31, 23, 0, 0, 0, loc).UTC(), []int64{1}, []int64{1})
+
+	if ys := ComputeYearStandings([]Game{nye}, 2026, loc, defaultYearly, noTB); len(ys.Stats) != 1 {
+		t.Errorf("2026 in Chicago: %d players, want 1", len(ys.Stats))
+	}
+	if ys := ComputeYearStandings([]Game{nye}, 2027, loc, defaultYearly, noTB); len(ys.Stats) != 0 {
+		t.Errorf("2027 in Chicago: %d players, want 0", len(ys.Stats))
+	}
+}
+
+func TestComputeRangeStandings_AllTime(t *testing.T) {
+	games := []Game{
+		makeYearGame(day(2024, 5, 1), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2025, 5, 1), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 5, 1), []int64{1, 2}, []int64{2}),
+	}
+	inactive := makeYearGame(day(2026, 5, 2), []int64{1, 2}, []int64{2})
+	inactive.IsActive = false
+	games = append(games, inactive)
+
+	st := ComputeRangeStandings(games, time.Time{}, time.Time{}, time.UTC, "alltime", AllTimeScopeKey, defaultYearly, noTB)
+
+	if st.Scope != "alltime" || st.ScopeKey != AllTimeScopeKey {
+		t.Errorf("scope = %s/%s", st.Scope, st.ScopeKey)
+	}
+	if st.WinnerID == nil || *st.WinnerID != 1 {
+		t.Fatalf("winner = %v, want 1 (2/3 vs 1/3)", st.WinnerID)
+	}
+	if st.Stats[0].GamesPlayed != 3 {
+		t.Errorf("games played = %d, want 3 (inactive game ignored)", st.Stats[0].GamesPlayed)
+	}
+}
+
+func TestComputeRangeStandings_HalfOpenRange(t *testing.T) {
+	games := []Game{
+		makeYearGame(day(2025, 12, 31), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 1, 1), []int64{1, 2}, []int64{2}),
+		makeYearGame(day(2026, 1, 31), []int64{1, 2}, []int64{2}),
+		makeYearGame(day(2026, 2, 1), []int64{1, 2}, []int64{1}),
+	}
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	st := ComputeRangeStandings(games, from, to, time.UTC, "range", RangeScopeKey(from, to), defaultYearly, noTB)
+
+	if st.WinnerID == nil || *st.WinnerID != 2 {
+		t.Fatalf("winner = %v, want 2", st.WinnerID)
+	}
+	if st.Stats[0].GamesPlayed != 2 {
+		t.Errorf("games played = %d, want 2", st.Stats[0].GamesPlayed)
+	}
+}
+
+func TestComputeRangeStandings_TieUsesScopeKey(t *testing.T) {
+	games := []Game{
+		makeYearGame(day(2026, 1, 1), []int64{1, 2}, []int64{1}),
+		makeYearGame(day(2026, 1, 2), []int64{1, 2}, []int64{2}),
+	}
+	st := ComputeRangeStandings(games, time.Time{}, time.Time{}, time.UTC, "alltime", AllTimeScopeKey, defaultYearly, tbFor(AllTimeScopeKey, 2, 1, 2))
+	if st.WinnerID == nil || *st.WinnerID != 2 || st.TieUnresolved {
+		t.Errorf("winner = %v unresolved = %v, want 2 via tiebreaker", st.WinnerID, st.TieUnresolved)
+	}
+}
+
+func TestRangeScopeKey(t *testing.T) {
+	got := RangeScopeKey(day(2024, 1, 1), day(2025, 12, 31))
+	if got != "2024-01-01..2025-12-31" {
+		t.Errorf("RangeScopeKey = %q", got)
+	}
+	if got := RangeScopeKey(time.Time{}, day(2025, 12, 31)); got != "..2025-12-31" {
+		t.Errorf("open-ended RangeScopeKey = %q", got)
+	}
+}
+
+func TestComputeYearStandings_AvgPercentile(t *testing.T) {
+	placed := makeYearGame(day(2026, 3, 2), []int64{1, 2, 3}, []int64{1})
+	placed.Results = []Result{{PlayerID: 1, Position: 1}, {PlayerID: 2, Position: 2}, {PlayerID: 3, Position: 3}}
+	placed2 := makeYearGame(day(2026, 3, 3), []int64{1, 2}, []int64{2})
+	placed2.Results = []Result{{PlayerID: 2, Position: 1}, {PlayerID: 1, Position: 2}}
+	unplaced := makeYearGame(day(2026, 3, 4), []int64{1, 2}, []int64{1})
+
+	ys := ComputeYearStandings([]Game{placed, placed2, unplaced}, 2026, time.UTC, defaultYearly, noTB)
+
+	want := map[int64]struct {
+		placed int
+		avg    float64
+	}{1: {2, 50}, 2: {2, 75}, 3: {1, 0}}
+	for _, st := range ys.Stats {
+		w := want[st.PlayerID]
+		if st.PlacedGames != w.placed || st.AvgPercentile != w.avg {
+			t.Errorf("player %d: placed %d avg %.1f, want %d / %.1f", st.PlayerID, st.PlacedGames, st.AvgPercentile, w.placed, w.avg)
+		}
+	}
+	if ys.WinnerID == nil || *ys.WinnerID != 1 {
+		t.Errorf("winner = %v, want 1 (placements don't change the winner)", ys.WinnerID)
+	}
+}
+
+func TestComputeYearStandings_CoopLossCountsAsPlayed(t *testing.T) {
+	coop := makeYearGame(day(2026, 4, 6), []int64{1, 2}, nil)
+	coop.Mode = ModeCoop
+	games := []Game{
+		coop,
+		makeYearGame(day(2026, 4, 7), []int64{1, 2}, []int64{1}),
+	}
+	ys := ComputeYearStandings(games, 2026, time.UTC, defaultYearly, noTB)
+
+	for _, st := range ys.Stats {
+		if st.GamesPlayed != 2 || st.Attendance != 2 {
+			t.Errorf("player %d: played %d, attended %d; want 2 and 2", st.PlayerID, st.GamesPlayed, st.Attendance)
+		}
+	}
+	if ys.WinnerID == nil || *ys.WinnerID != 1 || ys.Stats[0].WinRate != 50 {
+		t.Errorf("winner = %v, stats = %+v; want player 1 at 50%%", ys.WinnerID, ys.Stats)
+	}
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultTitleMinGames is the minimum number of plays of a title before a player's
+// win rate on it counts for the leaderboard and the specialist.
+const DefaultTitleMinGames = 3
+
+type TitlePlayerStats struct {
+	PlayerID  int64
+	Played    int
+	Wins      int
+	WinRate   float64 // percent with 1 decimal
+	Qualified bool    // Played >= MinGames
+}
+
+type TitlePeriodCount struct {
+	Period string // "2026-01"
+	Games  int
+}
+
+type TitleStats struct {
+	TitleID  int64
+	MinGames int
+
+	TimesPlayed int
+	AvgPlayers  float64 // 1 decimal
+	FirstPlayed time.Time
+	LastPlayed  time.Time
+
+	Players      []TitlePlayerStats // qualified first, then win rate desc, wins desc, id asc
+	SpecialistID *int64             // best win rate among qualified players with at least one win
+
+	Frequency []TitlePeriodCount // per month from first to last play, empty months included
+}
+
+// ComputeTitleStats aggregates the active games of one title. Months are bucketed in loc.
+func ComputeTitleStats(games []Game, titleID int64, minGames int, loc *time.Location) TitleStats {
+	if minGames < 1 {
+		minGames = 1
+	}
+	ts := TitleStats{TitleID: titleID, MinGames: minGames}
+
+	played := map[int64]int{}
+	wins := map[int64]int{}
+	perMonth := map[string]int{}
+	seats := 0
+
+	for _, g := range games {
+		if !g.IsActive || g.TitleID != titleID {
+			continue
+		}
+
+		ts.TimesPlayed++
+		if ts.FirstPlayed.IsZero() || g.PlayedAt.Before(ts.FirstPlayed) {
+			ts.FirstPlayed = g.PlayedAt
+		}
+		if g.PlayedAt.After(ts.LastPlayed) {
+			ts.LastPlayed = g.PlayedAt
+		}
+		perMonth[g.PlayedAt.In(loc).Format("2006-01")]++
+
+		participants := uniqueIDs(g.ParticipantIDs)
+		seats += len(participants)
+		for _, pid := range participants {
+			played[pid]++
+		}
+		for _, wid := range uniqueIDs(g.WinnerIDs) {
+			wins[wid]++
+		}
+	}
+
+	if ts.TimesPlayed == 0 {
+		return ts
+	}
+	ts.AvgPlayers = math.Round(float64(seats)/float64(ts.TimesPlayed)*10) / 10
+
+	for pid, n := range played {
+		ts.Players = append(ts.Players, TitlePlayerStats{
+			PlayerID:  pid,
+			Played:    n,
+			Wins:      wins[pid],
+			WinRate:   math.Round(float64(wins[pid])/float64(n)*1000) / 10,
+			Qualified: n >= minGames,
+		})
+	}
+	sort.Slice(ts.Players, func(i, j int) bool {
+		a, b := ts.Players[i], ts.Players[j]
+		if a.Qualified != b.Qualified {
+			return a.Qualified
+		}
+		// Compare exact rates by cross-multiplying, as ComputeYearStandings does.
+		if a.Wins*b.Played != b.Wins*a.Played {
+			return a.Wins*b.Played > b.Wins*a.Played
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.PlayerID < b.PlayerID
+	})
+
+	if best := ts.Players[0]; best.Qualified && best.Wins > 0 {
+		id := best.PlayerID
+		ts.SpecialistID = &id
+	}
+
+	// Fill every month between the first and last play so gaps show up as zeros.
+	first, last := ts.FirstPlayed.In(loc), ts.LastPlayed.In(loc)
+	start := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := start; !m.After(end); m = m.AddDate(0, 1, 0) {
+		key := m.Format("2006-01")
+		ts.Frequency = append(ts.Frequency, TitlePeriodCount{Period: key, Games: perMonth[key]})
+	}
+
+	return ts
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func titleGame(titleID int64, at time.Time, participants, winners []int64) Game {
+	return Game{
+		PlayedAt:       at,
+		TitleID:        titleID,
+		ParticipantIDs: participants,
+		WinnerIDs:      winners,
+		IsActive:       true,
+	}
+}
+
+func TestComputeTitleStats_NoGames(t *testing.T) {
+	ts := ComputeTitleStats([]Game{titleGame(2, day(2026, 1, 5), []int64{1}, []int64{1})}, 1, 3, time.UTC)
+
+	if ts.TimesPlayed != 0 || ts.SpecialistID != nil || len(ts.Frequency) != 0 {
+		t.Errorf("stats = %+v, want empty", ts)
+	}
+}
+
+func TestComputeTitleStats_Totals(t *testing.T) {
+	inactive := titleGame(1, day(2026, 1, 9), []int64{1, 2, 3, 4, 5}, []int64{5})
+	inactive.IsActive = false
+	games := []Game{
+		titleGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		titleGame(1, day(2026, 1, 6), []int64{1, 2, 3}, []int64{2}),
+		titleGame(2, day(2026, 1, 7), []int64{1, 2, 3, 4}, []int64{4}),
+		inactive,
+	}
+	ts := ComputeTitleStats(games, 1, 1, time.UTC)
+
+	if ts.TimesPlayed != 2 {
+		t.Errorf("TimesPlayed = %d, want 2", ts.TimesPlayed)
+	}
+	if ts.AvgPlayers != 2.5 {
+		t.Errorf("AvgPlayers = %.1f, want 2.5", ts.AvgPlayers)
+	}
+	if !ts.FirstPlayed.Equal(day(2026, 1, 5)) || !ts.LastPlayed.Equal(day(2026, 1, 6)) {
+		t.Errorf("first/last = %v / %v", ts.FirstPlayed, ts.LastPlayed)
+	}
+	if len(ts.Players) != 3 {
+		t.Errorf("Players len = %d, want 3", len(ts.Players))
+	}
+}
+
+func TestComputeTitleStats_SpecialistRespectsMinGames(t *testing.T) {
+	// Player 3 wins their only game (100%) but hasn't played enough.
+	// Player 1 is 2/3; player 2 is 1/3.
+	games := []Game{
+		titleGame(1, day(2026, 1, 5), []int64{1, 2}, []int64{1}),
+		titleGame(1, day(2026, 1, 6), []int64{1, 2}, []int64{1}),
+		titleGame(1, day(2026, 1, 7), []int64{1, 2, 3}, []int64{2, 3}),
+	}
+	ts := ComputeTitleStats(games, 1, 3, time.UTC)
+
+	if ts.SpecialistID == nil || *ts.SpecialistID != 1 {
+		t.Fatalf("SpecialistID = %v, want 1", ts.SpecialistID)
+	}
+	if ts.Players[0].PlayerID != 1 || ts.Players[len(ts.Players)-1].PlayerID != 3 {
+		t.Errorf("order = %+v, want qualified player 1 first and unqualified 3 last", ts.Players)
+	}
+	if ts.Players[0].WinRate != 66.7 {
+		t.Errorf("WinRate = %.1f, want 66.7", ts.Players[0].WinRate)
+	}
+}
+
+func TestComputeTitleStats_NoSpecialistWithoutWins(t *testing.T) {
+	games := []Game{titleGame(1, day(2026, 1, 5), []int64{1, 2}, nil)}
+	ts := ComputeTitleStats(games, 1, 1, time.UTC)
+	if ts.SpecialistID != nil {
+		t.Errorf("SpecialistID = %d, want nil", *ts.SpecialistID)
+	}
+}
+
+func TestComputeTitleStats_FrequencyFillsGaps(t *testing.T) {
+	games := []Game{
+		titleGame(1, day(2026, 1, 5), []int64{1}, []int64{1}),
+		titleGame(1, day(2026, 1, 6), []int64{1}, []int64{1}),
+		titleGame(1, day(2026, 3, 2), []int64{1}, []int64{1}),
+	}
+	ts := ComputeTitleStats(games, 1, 1, time.UTC)
+
+	want := []TitlePeriodCount{{"2026-01", 2}, {"2026-02", 0}, {"2026-03", 1}}
+	if len(ts.Frequency) != len(want) {
+		t.Fatalf("Frequency = %+v, want %+v", ts.Frequency, want)
+	}
+	for i := range want {
+		if ts.Frequency[i] != want[i] {
+			t.Errorf("Frequency[%d] = %+v, want %+v", i, ts.Frequency[i], want[i])
+		}
+	}
+}
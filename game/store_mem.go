@@ -3,7 +3,10 @@ package game
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,24 +14,58 @@ import (
 type MemoryStore struct {
 	mu sync.Mutex
 
-	nextGameID   int64
-	nextPlayerID int64
-	nextTitleID  int64
+	loc *time.Location // league time zone for week/year queries
 
-	games   []Game
-	players []Player
-	titles  []Title
+	nextGameID    int64
+	nextPlayerID  int64
+	nextTitleID   int64
+	nextRulesetID int64
+	nextSeasonID  int64
+
+	games    []Game
+	players  []Player
+	titles   []Title
+	rulesets []Ruleset
+	seasons  []Season
 
 	tiebreakers map[string]Tiebreaker // key = scope + "|" + scopeKey
+	tbHistory   []Tiebreaker          // every decision, oldest first
+
+	audit       []AuditEntry // oldest first
+	nextAuditID int64
+
+	users      []User
+	nextUserID int64
+	sessions   map[string]Session // key = TokenHash
+
+	apiTokens      []APIToken
+	nextAPITokenID int64
+
+	webhooks       []Webhook
+	nextWebhookID  int64
+	deliveries     []WebhookDelivery // oldest first
+	nextDeliveryID int64
+
+	recaps map[string]WeekRecap // key = WeekScopeKey
 }
 
 //goland:noinspection GoUnusedExportedFunction
-func NewMemoryStore() *MemoryStore {
+func NewMemoryStore(loc *time.Location) *MemoryStore {
 	s := &MemoryStore{
-		nextGameID:   1,
-		nextPlayerID: 1,
-		nextTitleID:  1,
-		tiebreakers:  map[string]Tiebreaker{},
+		loc:            loc,
+		nextGameID:     1,
+		nextPlayerID:   1,
+		nextTitleID:    1,
+		nextRulesetID:  1,
+		nextSeasonID:   1,
+		nextAuditID:    1,
+		nextUserID:     1,
+		nextAPITokenID: 1,
+		nextWebhookID:  1,
+		nextDeliveryID: 1,
+		tiebreakers:    map[string]Tiebreaker{},
+		sessions:       map[string]Session{},
+		recaps:         map[string]WeekRecap{},
 	}
 
 	// Seed with the historical hardcoded lists.
@@ -57,8 +94,48 @@ func (s *MemoryStore) AddGame(_ context.Context, g Game) (Game, error) {
 	if !g.IsActive {
 		g.IsActive = true
 	}
+	g.Mode = g.GameMode()
+	g.PlayedAt = g.PlayedAt.In(s.loc)
 	s.games = append(s.games, g)
-	return g, nil
+	return s.withTitle(g), nil
+}
+
+// withTitle sets g.Title to the current name of its title, as a join would.
+func (s *MemoryStore) withTitle(g Game) Game {
+	g.Title = ""
+	for _, t := range s.titles {
+		if t.ID == g.TitleID {
+			g.Title = t.Name
+			break
+		}
+	}
+	return g
+}
+
+// gamesCopy returns the stored games with their current title names.
+func (s *MemoryStore) gamesCopy() []Game {
+	out := make([]Game, len(s.games))
+	for i, g := range s.games {
+		out[i] = s.withTitle(g)
+	}
+	return out
+}
+
+// UpdateGame replaces the editable fields of an existing game, keeping its ID and active flag.
+func (s *MemoryStore) UpdateGame(_ context.Context, g Game) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.games {
+		if s.games[i].ID == g.ID {
+			g.IsActive = s.games[i].IsActive
+			g.Mode = g.GameMode()
+			g.PlayedAt = g.PlayedAt.In(s.loc)
+			s.games[i] = g
+			return nil
+		}
+	}
+	return errors.New("game not found")
 }
 
 func (s *MemoryStore) DeleteGame(_ context.Context, id int64) error {
@@ -91,8 +168,7 @@ func (s *MemoryStore) RecentGames(_ context.Context, limit int) ([]Game, error)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	out := make([]Game, len(s.games))
-	copy(out, s.games)
+	out := s.gamesCopy()
 
 	sort.Slice(out, func(i, j int) bool {
 		if out[i].IsActive != out[j].IsActive {
@@ -110,34 +186,63 @@ func (s *MemoryStore) RecentGames(_ context.Context, limit int) ([]Game, error)
 	return out, nil
 }
 
-func (s *MemoryStore) GetWeek(_ context.Context, year, week int) ([]Game, error) {
+// ListGames returns every game (active or not) in play order.
+func (s *MemoryStore) ListGames(_ context.Context) ([]Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := s.gamesCopy()
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].PlayedAt.Equal(out[j].PlayedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].PlayedAt.Before(out[j].PlayedAt)
+	})
+	return out, nil
+}
+
+// GetGame returns the game with id, active or not, or false if there is none.
+func (s *MemoryStore) GetGame(_ context.Context, id int64) (Game, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	out := make([]Game, 0, len(s.games))
 	for _, g := range s.games {
-		if g.PlayedAt.Year() == year && g.PlayedAt.Weekday() == time.Weekday(week) {
-			out = append(out, g)
+		if g.ID == id {
+			return s.withTitle(g), true, nil
 		}
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].PlayedAt.Before(out[j].PlayedAt) })
+	return Game{}, false, nil
+}
 
-	return out, nil
+func (s *MemoryStore) GetWeek(_ context.Context, year, week int) ([]Game, error) {
+	start, end := WeekBounds(year, week, s.loc)
+	return s.activeBetween(start, end), nil
 }
 
 func (s *MemoryStore) GetYear(_ context.Context, year int) ([]Game, error) {
+	start, end := YearBounds(year, s.loc)
+	return s.activeBetween(start, end), nil
+}
+
+// activeBetween returns active games played in [start, end), in play order.
+func (s *MemoryStore) activeBetween(start, end time.Time) []Game {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	out := make([]Game, 0, len(s.games))
 	for _, g := range s.games {
-		if g.PlayedAt.Year() == year {
-			out = append(out, g)
+		if g.IsActive && !g.PlayedAt.Before(start) && g.PlayedAt.Before(end) {
+			out = append(out, s.withTitle(g))
 		}
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].PlayedAt.Before(out[j].PlayedAt) })
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PlayedAt.Equal(out[j].PlayedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].PlayedAt.Before(out[j].PlayedAt)
+	})
 
-	return out, nil
+	return out
 }
 
 // ============================
@@ -165,6 +270,9 @@ func (s *MemoryStore) AddPlayer(_ context.Context, name string) (Player, error)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.playerNameTaken(name, 0) {
+		return Player{}, errors.New("a player with that name already exists")
+	}
 	p := Player{ID: s.nextPlayerID, Name: name, IsActive: true}
 	s.nextPlayerID++
 	s.players = append(s.players, p)
@@ -177,6 +285,9 @@ func (s *MemoryStore) UpdatePlayer(_ context.Context, id int64, name string) err
 
 	for i := range s.players {
 		if s.players[i].ID == id {
+			if s.playerNameTaken(name, id) {
+				return errors.New("a player with that name already exists")
+			}
 			s.players[i].Name = name
 			return nil
 		}
@@ -184,6 +295,16 @@ func (s *MemoryStore) UpdatePlayer(_ context.Context, id int64, name string) err
 	return errors.New("player not found")
 }
 
+// playerNameTaken reports whether a player other than except is called name.
+func (s *MemoryStore) playerNameTaken(name string, except int64) bool {
+	for _, p := range s.players {
+		if p.Name == name && p.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *MemoryStore) SetPlayerActive(_ context.Context, id int64, active bool) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -197,13 +318,25 @@ func (s *MemoryStore) SetPlayerActive(_ context.Context, id int64, active bool)
 	return errors.New("player not found")
 }
 
+// DeletePlayer removes a player no game refers to, unlinking any user account that plays
+// as them.
 func (s *MemoryStore) DeletePlayer(_ context.Context, id int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for _, g := range s.games {
+		if slices.Contains(g.ParticipantIDs, id) || slices.Contains(g.WinnerIDs, id) {
+			return errors.New("player is referenced by a game")
+		}
+	}
 	for i := range s.players {
 		if s.players[i].ID == id {
 			s.players = append(s.players[:i], s.players[i+1:]...)
+			for j := range s.users {
+				if p := s.users[j].PlayerID; p != nil && *p == id {
+					s.users[j].PlayerID = nil
+				}
+			}
 			return nil
 		}
 	}
@@ -235,6 +368,9 @@ func (s *MemoryStore) AddTitle(_ context.Context, name string) (Title, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.titleNameTaken(name, 0) {
+		return Title{}, errors.New("a title with that name already exists")
+	}
 	t := Title{ID: s.nextTitleID, Name: name, IsActive: true}
 	s.nextTitleID++
 	s.titles = append(s.titles, t)
@@ -247,6 +383,9 @@ func (s *MemoryStore) UpdateTitle(_ context.Context, id int64, name string) erro
 
 	for i := range s.titles {
 		if s.titles[i].ID == id {
+			if s.titleNameTaken(name, id) {
+				return errors.New("a title with that name already exists")
+			}
 			s.titles[i].Name = name
 			return nil
 		}
@@ -254,6 +393,16 @@ func (s *MemoryStore) UpdateTitle(_ context.Context, id int64, name string) erro
 	return errors.New("title not found")
 }
 
+// titleNameTaken reports whether a title other than except is called name.
+func (s *MemoryStore) titleNameTaken(name string, except int64) bool {
+	for _, t := range s.titles {
+		if t.Name == name && t.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *MemoryStore) SetTitleActive(_ context.Context, id int64, active bool) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -267,10 +416,16 @@ func (s *MemoryStore) SetTitleActive(_ context.Context, id int64, active bool) e
 	return errors.New("title not found")
 }
 
+// DeleteTitle removes a title no game refers to.
 func (s *MemoryStore) DeleteTitle(_ context.Context, id int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for _, g := range s.games {
+		if g.TitleID == id {
+			return errors.New("title is referenced by a game")
+		}
+	}
 	for i := range s.titles {
 		if s.titles[i].ID == id {
 			s.titles = append(s.titles[:i], s.titles[i+1:]...)
@@ -289,13 +444,12 @@ func IsWeekdayLocal(t time.Time) bool {
 	return wd >= time.Monday && wd <= time.Friday
 }
 
+// GetTiebreaker returns the decision for a period. Like PostgresStore, it reports a missing
+// one with ok false and a nil error.
 func (s *MemoryStore) GetTiebreaker(_ context.Context, scope, scopeKey string) (Tiebreaker, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	tb, ok := s.tiebreakers[tbKey(scope, scopeKey)]
-	if !ok {
-		return Tiebreaker{}, ok, errors.New("tiebreaker not found")
-	}
 	return tb, ok, nil
 }
 
@@ -303,5 +457,801 @@ func (s *MemoryStore) SetTiebreaker(_ context.Context, tb Tiebreaker) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.tiebreakers[tbKey(tb.Scope, tb.ScopeKey)] = tb
+	s.tbHistory = append(s.tbHistory, tb)
+	return nil
+}
+
+// ListTiebreakerHistory returns every decision recorded for scope/scopeKey, latest first.
+func (s *MemoryStore) ListTiebreakerHistory(_ context.Context, scope, scopeKey string) ([]Tiebreaker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Tiebreaker
+	for i := len(s.tbHistory) - 1; i >= 0; i-- {
+		if tb := s.tbHistory[i]; tb.Scope == scope && tb.ScopeKey == scopeKey {
+			out = append(out, tb)
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) ListTiebreakers(_ context.Context) ([]Tiebreaker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Tiebreaker, 0, len(s.tiebreakers))
+	for _, tb := range s.tiebreakers {
+		out = append(out, tb)
+	}
+	sortTiebreakers(out)
+	return out, nil
+}
+
+// ============================
+// Rulesets
+// ============================
+
+// ListRulesets returns the stored rulesets, oldest EffectiveFrom first.
+func (s *MemoryStore) ListRulesets(_ context.Context) ([]Ruleset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Ruleset, len(s.rulesets))
+	copy(out, s.rulesets)
+	sort.Slice(out, func(i, j int) bool { return out[i].EffectiveFrom.Before(out[j].EffectiveFrom) })
+	return out, nil
+}
+
+// AddRuleset stores r; EffectiveFrom is kept as a date (midnight in the league time zone),
+// and no two rulesets may start on the same date.
+func (s *MemoryStore) AddRuleset(_ context.Context, r Ruleset) (Ruleset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from := r.EffectiveFrom.In(s.loc)
+	r.EffectiveFrom = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, s.loc)
+	for _, existing := range s.rulesets {
+		if existing.EffectiveFrom.Equal(r.EffectiveFrom) {
+			return Ruleset{}, errors.New("a ruleset already starts on that date")
+		}
+	}
+	r.ID = s.nextRulesetID
+	s.nextRulesetID++
+	s.rulesets = append(s.rulesets, r)
+	return r, nil
+}
+
+func (s *MemoryStore) DeleteRuleset(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.rulesets {
+		if s.rulesets[i].ID == id {
+			s.rulesets = append(s.rulesets[:i], s.rulesets[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("ruleset not found")
+}
+
+// ============================
+// Seasons
+// ============================
+
+// ListSeasons returns the stored seasons, latest StartDate first.
+func (s *MemoryStore) ListSeasons(_ context.Context) ([]Season, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Season, len(s.seasons))
+	copy(out, s.seasons)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartDate.After(out[j].StartDate) })
+	return out, nil
+}
+
+// AddSeason stores se with its dates kept as dates (midnight in the league time zone).
+// Season names are unique.
+func (s *MemoryStore) AddSeason(_ context.Context, se Season) (Season, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.seasons {
+		if existing.Name == se.Name {
+			return Season{}, errors.New("a season with that name already exists")
+		}
+	}
+	se.StartDate = leagueDate(se.StartDate, s.loc)
+	se.EndDate = leagueDate(se.EndDate, s.loc)
+	if se.EndDate.Before(se.StartDate) {
+		return Season{}, errors.New("a season can't end before it starts")
+	}
+	se.ID = s.nextSeasonID
+	s.nextSeasonID++
+	s.seasons = append(s.seasons, se)
+	return se, nil
+}
+
+func (s *MemoryStore) DeleteSeason(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.seasons {
+		if s.seasons[i].ID == id {
+			s.seasons = append(s.seasons[:i], s.seasons[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("season not found")
+}
+
+// ============================
+// Audit
+// ============================
+
+// AppendAudit records e, stamping its ID and, if unset, its time.
+func (s *MemoryStore) AppendAudit(_ context.Context, e AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.ID = s.nextAuditID
+	s.nextAuditID++
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	s.audit = append(s.audit, e)
+	return nil
+}
+
+// ListAudit returns the entries matching f, latest first.
+func (s *MemoryStore) ListAudit(_ context.Context, f AuditFilter) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []AuditEntry
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+		if f.Match(s.audit[i]) {
+			out = append(out, s.audit[i])
+		}
+	}
+	return out, nil
+}
+
+// ============================
+// Users and sessions
+// ============================
+
+// ListUsers returns every user by user name.
+func (s *MemoryStore) ListUsers(_ context.Context) ([]User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]User, len(s.users))
+	copy(out, s.users)
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Username) < strings.ToLower(out[j].Username) })
+	return out, nil
+}
+
+func (s *MemoryStore) GetUser(_ context.Context, id int64) (User, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, true, nil
+		}
+	}
+	return User{}, false, nil
+}
+
+// GetUserByUsername looks a user up by name, ignoring case.
+func (s *MemoryStore) GetUserByUsername(_ context.Context, username string) (User, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, username) {
+			return u, true, nil
+		}
+	}
+	return User{}, false, nil
+}
+
+// AddUser stores u with a new ID. User names are unique, ignoring case.
+func (s *MemoryStore) AddUser(_ context.Context, u User) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.users {
+		if strings.EqualFold(e.Username, u.Username) {
+			return User{}, errors.New("user name taken")
+		}
+	}
+	u.ID = s.nextUserID
+	s.nextUserID++
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	s.users = append(s.users, u)
+	return u, nil
+}
+
+// UpdateUser replaces everything but the ID, user name and creation time.
+func (s *MemoryStore) UpdateUser(_ context.Context, u User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.users {
+		if s.users[i].ID == u.ID {
+			s.users[i].PasswordHash = u.PasswordHash
+			s.users[i].Role = u.Role
+			s.users[i].PlayerID = u.PlayerID
+			s.users[i].IsActive = u.IsActive
+			return nil
+		}
+	}
+	return errors.New("user not found")
+}
+
+// DeleteUser removes a user and their sessions.
+func (s *MemoryStore) DeleteUser(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.users {
+		if s.users[i].ID == id {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+			s.deleteUserSessions(id)
+			return nil
+		}
+	}
+	return errors.New("user not found")
+}
+
+func (s *MemoryStore) AddSession(_ context.Context, sess Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[sess.TokenHash]; ok {
+		return errors.New("session already exists")
+	}
+	if sess.CreatedAt.IsZero() {
+		sess.CreatedAt = time.Now()
+	}
+	s.sessions[sess.TokenHash] = sess
+	return nil
+}
+
+// GetSession returns the unexpired session with tokenHash.
+func (s *MemoryStore) GetSession(_ context.Context, tokenHash string) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[tokenHash]
+	if !ok || !time.Now().Before(sess.ExpiresAt) {
+		return Session{}, false, nil
+	}
+	return sess, true, nil
+}
+
+// DeleteSession removes one session, along with any that have expired.
+func (s *MemoryStore) DeleteSession(_ context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, sess := range s.sessions {
+		if k == tokenHash || !now.Before(sess.ExpiresAt) {
+			delete(s.sessions, k)
+		}
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere.
+func (s *MemoryStore) DeleteUserSessions(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteUserSessions(userID)
 	return nil
 }
+
+// deleteUserSessions is DeleteUserSessions for callers holding s.mu.
+func (s *MemoryStore) deleteUserSessions(userID int64) {
+	for k, sess := range s.sessions {
+		if sess.UserID == userID {
+			delete(s.sessions, k)
+		}
+	}
+}
+
+// ============================
+// API tokens
+// ============================
+
+// ListAPITokens returns every token, newest first, revoked ones included.
+func (s *MemoryStore) ListAPITokens(_ context.Context) ([]APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]APIToken, 0, len(s.apiTokens))
+	for i := len(s.apiTokens) - 1; i >= 0; i-- {
+		out = append(out, s.apiTokens[i])
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) AddAPIToken(_ context.Context, t APIToken) (APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.apiTokens {
+		if e.TokenHash == t.TokenHash {
+			return APIToken{}, errors.New("token already exists")
+		}
+	}
+	t.ID = s.nextAPITokenID
+	s.nextAPITokenID++
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	s.apiTokens = append(s.apiTokens, t)
+	return t, nil
+}
+
+// GetAPITokenByHash returns the token with tokenHash, revoked or not.
+func (s *MemoryStore) GetAPITokenByHash(_ context.Context, tokenHash string) (APIToken, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.apiTokens {
+		if t.TokenHash == tokenHash {
+			return t, true, nil
+		}
+	}
+	return APIToken{}, false, nil
+}
+
+// RevokeAPIToken stops a token working. Revoking it again keeps the first revocation time.
+func (s *MemoryStore) RevokeAPIToken(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.apiTokens {
+		if s.apiTokens[i].ID == id {
+			if s.apiTokens[i].RevokedAt == nil {
+				now := time.Now()
+				s.apiTokens[i].RevokedAt = &now
+			}
+			return nil
+		}
+	}
+	return errors.New("token not found")
+}
+
+// TouchAPIToken records that a token was used at at.
+func (s *MemoryStore) TouchAPIToken(_ context.Context, id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.apiTokens {
+		if s.apiTokens[i].ID == id {
+			s.apiTokens[i].LastUsedAt = &at
+			return nil
+		}
+	}
+	return errors.New("token not found")
+}
+
+// ============================
+// Webhooks
+// ============================
+
+// ListWebhooks returns every webhook by name.
+func (s *MemoryStore) ListWebhooks(_ context.Context) ([]Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Webhook, 0, len(s.webhooks))
+	for _, h := range s.webhooks {
+		h.Events = slices.Clone(h.Events)
+		out = append(out, h)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out, nil
+}
+
+func (s *MemoryStore) AddWebhook(_ context.Context, h Webhook) (Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h.ID = s.nextWebhookID
+	s.nextWebhookID++
+	h.Events = slices.Clone(h.Events)
+	if h.Events == nil {
+		h.Events = []string{}
+	}
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = time.Now()
+	}
+	s.webhooks = append(s.webhooks, h)
+	h.Events = slices.Clone(h.Events)
+	return h, nil
+}
+
+func (s *MemoryStore) SetWebhookActive(_ context.Context, id int64, active bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.webhooks {
+		if s.webhooks[i].ID == id {
+			s.webhooks[i].IsActive = active
+			return nil
+		}
+	}
+	return errors.New("webhook not found")
+}
+
+// DeleteWebhook removes a webhook and its deliveries.
+func (s *MemoryStore) DeleteWebhook(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.webhooks, func(h Webhook) bool { return h.ID == id })
+	if i < 0 {
+		return errors.New("webhook not found")
+	}
+	s.webhooks = slices.Delete(s.webhooks, i, i+1)
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d WebhookDelivery) bool { return d.WebhookID == id })
+	return nil
+}
+
+// AddWebhookDelivery queues d; it is pending and due now unless d says otherwise. If the
+// webhook already has a delivery with d's Key, that one is returned and nothing is queued.
+func (s *MemoryStore) AddWebhookDelivery(_ context.Context, d WebhookDelivery) (WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.webhooks, func(h Webhook) bool { return h.ID == d.WebhookID }) {
+		return WebhookDelivery{}, errors.New("webhook not found")
+	}
+	if d.Key != "" {
+		i := slices.IndexFunc(s.deliveries, func(e WebhookDelivery) bool { return e.WebhookID == d.WebhookID && e.Key == d.Key })
+		if i >= 0 {
+			e := s.deliveries[i]
+			e.Payload = slices.Clone(e.Payload)
+			return e, nil
+		}
+	}
+	d.ID = s.nextDeliveryID
+	s.nextDeliveryID++
+	d.Payload = slices.Clone(d.Payload)
+	if d.Status == "" {
+		d.Status = DeliveryPending
+	}
+	d.CreatedAt = time.Now()
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = d.CreatedAt
+	}
+	s.deliveries = append(s.deliveries, d)
+	return d, nil
+}
+
+// UpdateWebhookDelivery saves d's status, attempts, next attempt time, last status and
+// error, and delivery time.
+func (s *MemoryStore) UpdateWebhookDelivery(_ context.Context, d WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.deliveries {
+		if e := &s.deliveries[i]; e.ID == d.ID {
+			e.Status = d.Status
+			e.Attempts = d.Attempts
+			e.NextAttemptAt = d.NextAttemptAt
+			e.LastStatus = d.LastStatus
+			e.LastError = d.LastError
+			e.DeliveredAt = d.DeliveredAt
+			return nil
+		}
+	}
+	return errors.New("delivery not found")
+}
+
+// ClaimWebhookDeliveries claims up to limit pending deliveries due at now, the longest due
+// first, by moving their next attempt to now+lease, and returns them oldest first.
+func (s *MemoryStore) ClaimWebhookDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*WebhookDelivery
+	for i := range s.deliveries {
+		if d := &s.deliveries[i]; d.Status == DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	out := make([]WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = now.Add(lease)
+		c := *d
+		c.Payload = slices.Clone(c.Payload)
+		out = append(out, c)
+	}
+	sortDeliveries(out)
+	return out, nil
+}
+
+// ListWebhookDeliveries returns the latest deliveries first, for one webhook or (webhookID
+// 0) all of them; limit 0 means no limit.
+func (s *MemoryStore) ListWebhookDeliveries(_ context.Context, webhookID int64, limit int) ([]WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []WebhookDelivery
+	for i := len(s.deliveries) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		if d := s.deliveries[i]; webhookID == 0 || d.WebhookID == webhookID {
+			d.Payload = slices.Clone(d.Payload)
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// ============================
+// Week recaps
+// ============================
+
+func (s *MemoryStore) GetWeekRecap(_ context.Context, year, week int) (WeekRecap, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.recaps[WeekScopeKey(year, week)]
+	return r, ok, nil
+}
+
+// LastWeekRecap returns the recap of the latest week that has one, or false if none has.
+func (s *MemoryStore) LastWeekRecap(_ context.Context) (WeekRecap, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last WeekRecap
+	found := false
+	for _, r := range s.recaps {
+		if !found || r.Year > last.Year || r.Year == last.Year && r.Week > last.Week {
+			last, found = r, true
+		}
+	}
+	return last, found, nil
+}
+
+// AddWeekRecap stores r unless its week already has a recap, and returns the week's recap
+// and whether it is r. The first recap written for a week is the one that stays.
+func (s *MemoryStore) AddWeekRecap(_ context.Context, r WeekRecap) (WeekRecap, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := WeekScopeKey(r.Year, r.Week)
+	if existing, ok := s.recaps[key]; ok {
+		return existing, false, nil
+	}
+	r.NotifiedAt = nil
+	s.recaps[key] = r
+	return r, true, nil
+}
+
+// MarkWeekRecapNotified records that the recap of the week was handed to every notifier at at.
+func (s *MemoryStore) MarkWeekRecapNotified(_ context.Context, year, week int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := WeekScopeKey(year, week)
+	r, ok := s.recaps[key]
+	if !ok {
+		return errors.New("recap not found")
+	}
+	r.NotifiedAt = &at
+	s.recaps[key] = r
+	return nil
+}
+
+// ============================
+// Import
+// ============================
+
+// ImportDataset adds d's missing players and titles, appends its games and upserts its
+// tiebreakers, rulesets (by start date) and seasons (by name). Names are resolved before anything changes, so a failed import leaves the
+// store untouched.
+func (s *MemoryStore) ImportDataset(_ context.Context, d Dataset) (ImportSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sum ImportSummary
+
+	playerIDs := map[string]int64{}
+	for _, p := range s.players {
+		playerIDs[p.Name] = p.ID
+	}
+	titleIDs := map[string]int64{}
+	for _, t := range s.titles {
+		titleIDs[t.Name] = t.ID
+	}
+
+	// Reserve IDs for new names so games and tiebreakers can be resolved up front.
+	var newPlayers []Player
+	nextPlayerID := s.nextPlayerID
+	for _, p := range d.Players {
+		if _, ok := playerIDs[p.Name]; !ok {
+			playerIDs[p.Name] = nextPlayerID
+			newPlayers = append(newPlayers, Player{ID: nextPlayerID, Name: p.Name, IsActive: p.IsActive})
+			nextPlayerID++
+		}
+	}
+	var newTitles []Title
+	nextTitleID := s.nextTitleID
+	for _, t := range d.Titles {
+		if _, ok := titleIDs[t.Name]; !ok {
+			titleIDs[t.Name] = nextTitleID
+			newTitles = append(newTitles, Title{ID: nextTitleID, Name: t.Name, IsActive: t.IsActive})
+			nextTitleID++
+		}
+	}
+
+	resolve := func(names []string) ([]int64, error) {
+		ids := make([]int64, 0, len(names))
+		for _, n := range names {
+			id, ok := playerIDs[n]
+			if !ok {
+				return nil, fmt.Errorf("unknown player %q", n)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	var newGames []Game
+	nextGameID := s.nextGameID
+	for _, dg := range d.Games {
+		titleID, ok := titleIDs[dg.Title]
+		if !ok {
+			return ImportSummary{}, fmt.Errorf("unknown title %q", dg.Title)
+		}
+		participants, err := resolve(dg.Participants)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		winners, err := resolve(dg.Winners)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		results, err := datasetResults(dg.Results, resolve)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		teams, err := datasetTeams(dg.Teams, resolve)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		newGames = append(newGames, Game{
+			ID:             nextGameID,
+			PlayedAt:       dg.PlayedAt.In(s.loc),
+			TitleID:        titleID,
+			Title:          dg.Title,
+			Mode:           Game{Mode: dg.Mode}.GameMode(),
+			ParticipantIDs: participants,
+			WinnerIDs:      winners,
+			Teams:          teams,
+			Results:        results,
+			Notes:          dg.Notes,
+			IsActive:       dg.IsActive,
+		})
+		nextGameID++
+	}
+
+	var newTBs []Tiebreaker
+	for _, dt := range d.Tiebreakers {
+		tied, err := resolve(dt.Tied)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		winner, err := resolve([]string{dt.Winner})
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		var gameID int64
+		if ref := dt.PlayoffGame; ref != nil {
+			for _, games := range [][]Game{s.games, newGames} {
+				for _, g := range games {
+					if gameID == 0 && g.PlayedAt.Equal(ref.PlayedAt) && g.TitleID == titleIDs[ref.Title] {
+						gameID = g.ID
+					}
+				}
+			}
+			if gameID == 0 {
+				return ImportSummary{}, fmt.Errorf("unknown play-off game %s at %s", ref.Title, ref.PlayedAt.Format(time.RFC3339))
+			}
+		}
+		newTBs = append(newTBs, Tiebreaker{
+			Scope:         dt.Scope,
+			ScopeKey:      dt.ScopeKey,
+			TiedPlayerIDs: tied,
+			WinnerID:      winner[0],
+			Method:        dt.Method,
+			DecidedAt:     dt.DecidedAt,
+			Seed:          dt.Seed,
+			Algorithm:     dt.Algorithm,
+			GameID:        gameID,
+		})
+	}
+
+	var newRulesets []Ruleset
+	for _, dr := range d.Rulesets {
+		rs, err := dr.Ruleset(s.loc)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		newRulesets = append(newRulesets, rs)
+	}
+	var newSeasons []Season
+	for _, ds := range d.Seasons {
+		se, err := ds.Season(s.loc)
+		if err != nil {
+			return ImportSummary{}, err
+		}
+		newSeasons = append(newSeasons, se)
+	}
+
+	s.players = append(s.players, newPlayers...)
+	s.nextPlayerID = nextPlayerID
+	s.titles = append(s.titles, newTitles...)
+	s.nextTitleID = nextTitleID
+	s.games = append(s.games, newGames...)
+	s.nextGameID = nextGameID
+	for _, tb := range newTBs {
+		s.tiebreakers[tbKey(tb.Scope, tb.ScopeKey)] = tb
+		s.tbHistory = append(s.tbHistory, tb)
+	}
+	for _, rs := range newRulesets {
+		s.upsertRuleset(rs)
+	}
+	for _, se := range newSeasons {
+		s.upsertSeason(se)
+	}
+
+	sum.PlayersAdded = len(newPlayers)
+	sum.TitlesAdded = len(newTitles)
+	sum.GamesAdded = len(newGames)
+	sum.TiebreakersSet = len(newTBs)
+	sum.RulesetsSet = len(newRulesets)
+	sum.SeasonsSet = len(newSeasons)
+	return sum, nil
+}
+
+// upsertRuleset replaces the ruleset starting on r's date, keeping its ID, or adds r.
+// Callers hold s.mu.
+func (s *MemoryStore) upsertRuleset(r Ruleset) {
+	for i := range s.rulesets {
+		if s.rulesets[i].EffectiveFrom.Equal(r.EffectiveFrom) {
+			r.ID = s.rulesets[i].ID
+			s.rulesets[i] = r
+			return
+		}
+	}
+	r.ID = s.nextRulesetID
+	s.nextRulesetID++
+	s.rulesets = append(s.rulesets, r)
+}
+
+// upsertSeason replaces the dates of the season named se.Name, or adds se. Callers hold s.mu.
+func (s *MemoryStore) upsertSeason(se Season) {
+	for i := range s.seasons {
+		if s.seasons[i].Name == se.Name {
+			s.seasons[i].StartDate, s.seasons[i].EndDate = se.StartDate, se.EndDate
+			return
+		}
+	}
+	se.ID = s.nextSeasonID
+	s.nextSeasonID++
+	s.seasons = append(s.seasons, se)
+}
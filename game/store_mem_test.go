@@ -2,6 +2,7 @@ package game
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 )
@@ -10,10 +11,20 @@ var ctx = context.Background()
 
 func newStore() *MemoryStore {
 	return &MemoryStore{
-		nextGameID:   1,
-		nextPlayerID: 1,
-		nextTitleID:  1,
-		tiebreakers:  map[string]Tiebreaker{},
+		loc:            time.UTC,
+		nextGameID:     1,
+		nextPlayerID:   1,
+		nextTitleID:    1,
+		nextRulesetID:  1,
+		nextSeasonID:   1,
+		nextAuditID:    1,
+		nextUserID:     1,
+		nextAPITokenID: 1,
+		nextWebhookID:  1,
+		nextDeliveryID: 1,
+		tiebreakers:    map[string]Tiebreaker{},
+		sessions:       map[string]Session{},
+		recaps:         map[string]WeekRecap{},
 	}
 }
 
@@ -240,6 +251,46 @@ func TestMemoryStore_SetGameActive(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_UpdateGame(t *testing.T) {
+	s := newStore()
+	g, _ := s.AddGame(ctx, Game{
+		PlayedAt:       time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC),
+		TitleID:        1,
+		ParticipantIDs: []int64{1, 2},
+		WinnerIDs:      []int64{1},
+	})
+	_ = s.SetGameActive(ctx, g.ID, false)
+
+	err := s.UpdateGame(ctx, Game{
+		ID:             g.ID,
+		PlayedAt:       time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC),
+		TitleID:        2,
+		ParticipantIDs: []int64{1, 2},
+		WinnerIDs:      []int64{2},
+		Notes:          "fixed winner",
+		IsActive:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	games, _ := s.RecentGames(ctx, 10)
+	got := games[0]
+	if got.TitleID != 2 || got.WinnerIDs[0] != 2 || got.Notes != "fixed winner" {
+		t.Errorf("game not updated: %+v", got)
+	}
+	if got.IsActive {
+		t.Error("UpdateGame should not change the active flag")
+	}
+}
+
+func TestMemoryStore_UpdateGame_NotFound(t *testing.T) {
+	s := newStore()
+	if err := s.UpdateGame(ctx, Game{ID: 99}); err == nil {
+		t.Error("expected error updating a missing game")
+	}
+}
+
 func TestMemoryStore_DeleteGame(t *testing.T) {
 	s := newStore()
 	g, _ := s.AddGame(ctx, Game{PlayedAt: time.Now()})
@@ -289,10 +340,13 @@ func TestMemoryStore_SetAndGetTiebreaker(t *testing.T) {
 
 func TestMemoryStore_GetTiebreaker_Missing(t *testing.T) {
 	s := newStore()
-	_, ok, _ := s.GetTiebreaker(ctx, "weekly", "2026-W99")
+	_, ok, err := s.GetTiebreaker(ctx, "weekly", "2026-W99")
 	if ok {
 		t.Error("expected not found for missing tiebreaker")
 	}
+	if err != nil {
+		t.Errorf("err = %v, want nil (missing is not an error, as in PostgresStore)", err)
+	}
 }
 
 func TestMemoryStore_SetTiebreaker_Overwrites(t *testing.T) {
@@ -305,3 +359,410 @@ func TestMemoryStore_SetTiebreaker_Overwrites(t *testing.T) {
 		t.Errorf("WinnerID = %d, want 2 (overwritten)", got.WinnerID)
 	}
 }
+
+func TestMemoryStore_ListTiebreakerHistory(t *testing.T) {
+	s := newStore()
+	_ = s.SetTiebreaker(ctx, Tiebreaker{Scope: "weekly", ScopeKey: "2026-W01", WinnerID: 1})
+	_ = s.SetTiebreaker(ctx, Tiebreaker{Scope: "weekly", ScopeKey: "2026-W02", WinnerID: 3})
+	_ = s.SetTiebreaker(ctx, Tiebreaker{Scope: "weekly", ScopeKey: "2026-W01", WinnerID: 2})
+
+	got, err := s.ListTiebreakerHistory(ctx, "weekly", "2026-W01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].WinnerID != 2 || got[1].WinnerID != 1 {
+		t.Errorf("history = %+v, want winners 2 then 1", got)
+	}
+}
+
+// ============================
+// Audit
+// ============================
+
+func TestMemoryStore_ListAudit_Filters(t *testing.T) {
+	s := newStore()
+	for _, e := range []AuditEntry{
+		{At: day(2026, 1, 5), Actor: "alice", Action: AuditCreate, Entity: AuditGame, EntityID: "1"},
+		{At: day(2026, 1, 6), Actor: "Bob", Action: AuditUpdate, Entity: AuditGame, EntityID: "1"},
+		{At: day(2026, 1, 7), Actor: "alice", Action: AuditUpdate, Entity: AuditPlayer, EntityID: "1"},
+	} {
+		if err := s.AppendAudit(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name string
+		f    AuditFilter
+		want []int64 // IDs, latest first
+	}{
+		{"all", AuditFilter{}, []int64{3, 2, 1}},
+		{"actor ignores case", AuditFilter{Actor: "bob"}, []int64{2}},
+		{"entity and id", AuditFilter{Entity: AuditGame, EntityID: "1"}, []int64{2, 1}},
+		{"action", AuditFilter{Action: AuditUpdate}, []int64{3, 2}},
+		{"dates, to exclusive", AuditFilter{From: day(2026, 1, 6), To: day(2026, 1, 7)}, []int64{2}},
+		{"limit keeps the latest", AuditFilter{Limit: 1}, []int64{3}},
+	}
+	for _, c := range cases {
+		got, err := s.ListAudit(ctx, c.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, e := range got {
+			ids = append(ids, e.ID)
+		}
+		if !slices.Equal(ids, c.want) {
+			t.Errorf("%s: IDs = %v, want %v", c.name, ids, c.want)
+		}
+	}
+}
+
+// ============================
+// Import
+// ============================
+
+func TestMemoryStore_ImportDataset(t *testing.T) {
+	s := newStore()
+	_, _ = s.AddPlayer(ctx, "Alice")
+
+	d := Dataset{
+		Players: []DatasetPlayer{{Name: "Alice", IsActive: true}, {Name: "Bob", IsActive: false}},
+		Titles:  []DatasetTitle{{Name: "Coup", IsActive: true}},
+		Games: []DatasetGame{{
+			PlayedAt: day(2026, 1, 5), Title: "Coup",
+			Participants: []string{"Alice", "Bob"}, Winners: []string{"Bob"}, IsActive: true,
+			Results: []DatasetResult{{Player: "Bob", Position: 1}, {Player: "Alice", Position: 2}},
+		}},
+		Tiebreakers: []DatasetTiebreaker{{Scope: "yearly", ScopeKey: "2026", Tied: []string{"Alice", "Bob"}, Winner: "Alice"}},
+	}
+	sum, err := s.ImportDataset(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.PlayersAdded != 1 || sum.TitlesAdded != 1 || sum.GamesAdded != 1 || sum.TiebreakersSet != 1 {
+		t.Errorf("summary = %+v", sum)
+	}
+
+	games, _ := s.ListGames(ctx)
+	if len(games) != 1 || games[0].WinnerIDs[0] != 2 || games[0].TitleID != 1 {
+		t.Errorf("games = %+v", games)
+	}
+	if rs := games[0].Results; len(rs) != 2 || rs[0] != (Result{PlayerID: 2, Position: 1}) {
+		t.Errorf("results = %+v", rs)
+	}
+	if games[0].Mode != ModeCompetitive {
+		t.Errorf("mode = %q, want %q", games[0].Mode, ModeCompetitive)
+	}
+	tb, ok, _ := s.GetTiebreaker(ctx, "yearly", "2026")
+	if !ok || tb.WinnerID != 1 {
+		t.Errorf("tiebreaker = %+v, %v", tb, ok)
+	}
+}
+
+func TestMemoryStore_ImportDataset_PlayoffGame(t *testing.T) {
+	s := newStore()
+	d := Dataset{
+		Players: []DatasetPlayer{{Name: "Alice", IsActive: true}, {Name: "Bob", IsActive: true}},
+		Titles:  []DatasetTitle{{Name: "Coup", IsActive: true}},
+		Games: []DatasetGame{
+			{PlayedAt: day(2026, 1, 5), Title: "Coup", Participants: []string{"Alice", "Bob"}, Winners: []string{"Bob"}, IsActive: true},
+			{PlayedAt: day(2026, 1, 12), Title: "Coup", Participants: []string{"Alice", "Bob"}, Winners: []string{"Alice"}, IsActive: true},
+		},
+		Tiebreakers: []DatasetTiebreaker{{
+			Scope: "weekly", ScopeKey: "2026-W02", Tied: []string{"Alice", "Bob"}, Winner: "Alice", Method: MethodPlayoff,
+			PlayoffGame: &DatasetGameRef{PlayedAt: day(2026, 1, 12), Title: "Coup"},
+		}},
+	}
+	if _, err := s.ImportDataset(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	tb, ok, _ := s.GetTiebreaker(ctx, "weekly", "2026-W02")
+	if !ok || tb.GameID != 2 || tb.Method != MethodPlayoff {
+		t.Errorf("tiebreaker = %+v, want play-off game 2", tb)
+	}
+
+	d.Tiebreakers[0].PlayoffGame.Title = "Bang"
+	if _, err := s.ImportDataset(ctx, Dataset{Tiebreakers: d.Tiebreakers}); err == nil {
+		t.Error("unknown play-off game: expected error")
+	}
+}
+
+func TestMemoryStore_ImportDataset_Teams(t *testing.T) {
+	s := newStore()
+	d := Dataset{
+		Players: []DatasetPlayer{{Name: "Alice", IsActive: true}, {Name: "Bob", IsActive: true}, {Name: "Cleo", IsActive: true}},
+		Titles:  []DatasetTitle{{Name: "Codenames", IsActive: true}},
+		Games: []DatasetGame{{
+			PlayedAt: day(2026, 1, 5), Title: "Codenames", Mode: ModeTeam,
+			Participants: []string{"Alice", "Bob", "Cleo"}, Winners: []string{"Bob"},
+			Teams: [][]string{{"Alice", "Cleo"}, {"Bob"}}, IsActive: true,
+		}},
+	}
+	if _, err := s.ImportDataset(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	games, _ := s.ListGames(ctx)
+	if len(games) != 1 || games[0].Mode != ModeTeam || games[0].TeamOf(3) != 0 || games[0].TeamOf(2) != 1 {
+		t.Errorf("games = %+v", games)
+	}
+}
+
+func TestMemoryStore_ImportDataset_UnknownNameChangesNothing(t *testing.T) {
+	s := newStore()
+
+	d := Dataset{
+		Players: []DatasetPlayer{{Name: "Alice", IsActive: true}},
+		Titles:  []DatasetTitle{{Name: "Coup", IsActive: true}},
+		Games:   []DatasetGame{{PlayedAt: day(2026, 1, 5), Title: "Coup", Participants: []string{"Zed"}, Winners: []string{"Zed"}}},
+	}
+	if _, err := s.ImportDataset(ctx, d); err == nil {
+		t.Fatal("expected error for unknown player")
+	}
+
+	players, _ := s.ListPlayers(ctx)
+	titles, _ := s.ListTitles(ctx)
+	if len(players) != 0 || len(titles) != 0 {
+		t.Errorf("store changed by failed import: %d players, %d titles", len(players), len(titles))
+	}
+}
+
+func TestMemoryStore_ImportDataset_UpsertsRulesetsByDate(t *testing.T) {
+	s := newStore()
+	if _, err := s.AddRuleset(ctx, Ruleset{Name: "Old", EffectiveFrom: day(2026, 1, 1), Weekly: defaultWeekly, Yearly: defaultYearly}); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := DatasetRules{Metric: MetricWinRate, Qualifier: QualifyAll, TiePolicy: TieTiebreaker}
+	d := Dataset{Rulesets: []DatasetRuleset{
+		{Name: "New", EffectiveFrom: "2026-01-01", Weekly: rules, Yearly: rules},
+		{Name: "Later", EffectiveFrom: "2026-07-01", Weekly: rules, Yearly: rules},
+	}}
+	sum, err := s.ImportDataset(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.RulesetsSet != 2 {
+		t.Errorf("RulesetsSet = %d, want 2", sum.RulesetsSet)
+	}
+
+	got, _ := s.ListRulesets(ctx)
+	if len(got) != 2 || got[0].ID != 1 || got[0].Name != "New" || got[0].Weekly.Metric != MetricWinRate || got[1].Name != "Later" {
+		t.Errorf("rulesets = %+v", got)
+	}
+}
+
+// ============================
+// Rulesets
+// ============================
+
+func TestMemoryStore_Rulesets(t *testing.T) {
+	s := newStore()
+
+	later, err := s.AddRuleset(ctx, Ruleset{Name: "Later", EffectiveFrom: day(2026, 7, 1), Weekly: defaultWeekly, Yearly: defaultYearly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddRuleset(ctx, Ruleset{Name: "Earlier", EffectiveFrom: day(2026, 1, 1), Weekly: defaultWeekly, Yearly: defaultYearly}); err != nil {
+		t.Fatal(err)
+	}
+	if !later.EffectiveFrom.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("EffectiveFrom = %v, want midnight", later.EffectiveFrom)
+	}
+	if _, err := s.AddRuleset(ctx, Ruleset{Name: "Same day", EffectiveFrom: day(2026, 7, 1)}); err == nil {
+		t.Error("expected error for a second ruleset on the same date")
+	}
+
+	got, _ := s.ListRulesets(ctx)
+	if len(got) != 2 || got[0].Name != "Earlier" || got[1].Name != "Later" {
+		t.Errorf("rulesets = %+v, want oldest first", got)
+	}
+
+	if err := s.DeleteRuleset(ctx, later.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteRuleset(ctx, later.ID); err == nil {
+		t.Error("expected error deleting a missing ruleset")
+	}
+	if got, _ := s.ListRulesets(ctx); len(got) != 1 {
+		t.Errorf("rulesets after delete = %+v", got)
+	}
+}
+
+// ============================
+// Seasons
+// ============================
+
+func TestMemoryStore_Seasons(t *testing.T) {
+	s := newStore()
+
+	summer, err := s.AddSeason(ctx, Season{Name: "Summer", StartDate: day(2026, 6, 1), EndDate: day(2026, 8, 31)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddSeason(ctx, Season{Name: "Q1", StartDate: day(2026, 1, 1), EndDate: day(2026, 3, 31)}); err != nil {
+		t.Fatal(err)
+	}
+	if !summer.EndDate.Equal(time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("EndDate = %v, want midnight", summer.EndDate)
+	}
+	if _, err := s.AddSeason(ctx, Season{Name: "Summer", StartDate: day(2027, 6, 1), EndDate: day(2027, 8, 31)}); err == nil {
+		t.Error("expected error for a duplicate season name")
+	}
+
+	got, _ := s.ListSeasons(ctx)
+	if len(got) != 2 || got[0].Name != "Summer" || got[1].Name != "Q1" {
+		t.Errorf("seasons = %+v, want latest first", got)
+	}
+
+	if err := s.DeleteSeason(ctx, summer.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteSeason(ctx, summer.ID); err == nil {
+		t.Error("expected error deleting a missing season")
+	}
+}
+
+func TestMemoryStore_ImportDataset_UpsertsSeasonsByName(t *testing.T) {
+	s := newStore()
+	if _, err := s.AddSeason(ctx, Season{Name: "Summer", StartDate: day(2026, 6, 1), EndDate: day(2026, 8, 31)}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Dataset{Seasons: []DatasetSeason{
+		{Name: "Summer", StartDate: "2026-06-15", EndDate: "2026-09-15"},
+		{Name: "Q1", StartDate: "2026-01-01", EndDate: "2026-03-31"},
+	}}
+	sum, err := s.ImportDataset(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.SeasonsSet != 2 {
+		t.Errorf("SeasonsSet = %d, want 2", sum.SeasonsSet)
+	}
+
+	got, _ := s.ListSeasons(ctx)
+	if len(got) != 2 || got[0].ID != 1 || got[0].ScopeKey() != "2026-06-15..2026-09-15" {
+		t.Errorf("seasons = %+v", got)
+	}
+}
+
+// ============================
+// Week / year queries
+// ============================
+
+func TestMemoryStore_GetWeekAndYear_UseLeagueTimezone(t *testing.T) {
+	loc := chicago(t)
+	s := NewMemoryStore(loc)
+
+	add := func(at time.Time) {
+		_, _ = s.AddGame(ctx, Game{PlayedAt: at, ParticipantIDs: []int64{1}, WinnerIDs: []int64{1}})
+	}
+	add(time.Date(2026, 1, 4, 23, 30, 0, 0, loc))       // Sunday night: 2026-W01 locally, W02 in UTC
+	add(time.Date(2026, 1, 5, 9, 0, 0, 0, loc))         // Monday: 2026-W02
+	add(time.Date(2025, 12, 31, 23, 0, 0, 0, loc))      // 2025 locally, 2026 in UTC; ISO 2026-W01
+	add(time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC)) // 16:00 NYE locally
+
+	w1, _ := s.GetWeek(ctx, 2026, 1)
+	if len(w1) != 2 {
+		t.Errorf("2026-W01 has %d games, want 2 (Dec 31 and Sunday night)", len(w1))
+	}
+	w2, _ := s.GetWeek(ctx, 2026, 2)
+	if len(w2) != 1 {
+		t.Errorf("2026-W02 has %d games, want 1", len(w2))
+	}
+
+	y2025, _ := s.GetYear(ctx, 2025)
+	y2026, _ := s.GetYear(ctx, 2026)
+	if len(y2025) != 1 || len(y2026) != 3 {
+		t.Errorf("2025/2026 have %d/%d games, want 1/3", len(y2025), len(y2026))
+	}
+	if y2026[0].PlayedAt.Location() != loc {
+		t.Errorf("returned times are in %s, want league time", y2026[0].PlayedAt.Location())
+	}
+}
+
+func TestMemoryStore_GetWeek_SkipsInactive(t *testing.T) {
+	s := newStore()
+	g, _ := s.AddGame(ctx, Game{PlayedAt: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)})
+	_ = s.SetGameActive(ctx, g.ID, false)
+
+	games, _ := s.GetWeek(ctx, 2026, 2)
+	if len(games) != 0 {
+		t.Errorf("GetWeek returned %d games, want inactive game excluded", len(games))
+	}
+}
+
+func TestMemoryStore_UsersAndSessions(t *testing.T) {
+	s := newStore()
+
+	alice, err := s.AddUser(ctx, User{Username: "Alice", Role: RoleAdmin, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddUser(ctx, User{Username: "alice", Role: RoleViewer}); err == nil {
+		t.Error("AddUser accepted a name differing only in case")
+	}
+	if u, ok, _ := s.GetUserByUsername(ctx, "ALICE"); !ok || u.ID != alice.ID {
+		t.Errorf("GetUserByUsername(ALICE) = %+v, %v; want alice", u, ok)
+	}
+
+	now := time.Now()
+	for _, sess := range []Session{
+		{TokenHash: "live", UserID: alice.ID, ExpiresAt: now.Add(time.Hour)},
+		{TokenHash: "old", UserID: alice.ID, ExpiresAt: now.Add(-time.Hour)},
+	} {
+		if err := s.AddSession(ctx, sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok, _ := s.GetSession(ctx, "live"); !ok {
+		t.Error("live session not found")
+	}
+	if _, ok, _ := s.GetSession(ctx, "old"); ok {
+		t.Error("expired session found")
+	}
+
+	if err := s.DeleteUser(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.GetSession(ctx, "live"); ok {
+		t.Error("session outlived its user")
+	}
+	if _, ok, _ := s.GetUser(ctx, alice.ID); ok {
+		t.Error("deleted user found")
+	}
+}
+
+func TestMemoryStore_APITokens(t *testing.T) {
+	s := newStore()
+
+	bot, err := s.AddAPIToken(ctx, APIToken{Name: "bot", TokenHash: "h1", Scope: ScopeRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddAPIToken(ctx, APIToken{Name: "sheet", TokenHash: "h2", Scope: ScopeWriteGames}); err != nil {
+		t.Fatal(err)
+	}
+	if tokens, _ := s.ListAPITokens(ctx); len(tokens) != 2 || tokens[0].Name != "sheet" {
+		t.Errorf("ListAPITokens = %+v, want newest first", tokens)
+	}
+
+	used := day(2026, 1, 5)
+	if err := s.TouchAPIToken(ctx, bot.ID, used); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RevokeAPIToken(ctx, bot.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, _ := s.GetAPITokenByHash(ctx, "h1")
+	if !ok || got.LastUsedAt == nil || !got.LastUsedAt.Equal(used) || got.Active() {
+		t.Errorf("token = %+v, want last used %v and revoked", got, used)
+	}
+	revokedAt := *got.RevokedAt
+	_ = s.RevokeAPIToken(ctx, bot.ID)
+	if got, _, _ := s.GetAPITokenByHash(ctx, "h1"); !got.RevokedAt.Equal(revokedAt) {
+		t.Error("revoking again moved the revocation time")
+	}
+}
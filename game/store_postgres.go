@@ -1,10 +1,12 @@
 package game
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,12 +16,14 @@ import (
 type PostgresStore struct {
 	db  *pgxpool.Pool
 	now func() time.Time
+	loc *time.Location // league time zone for week/year queries and returned times
 }
 
-func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+func NewPostgresStore(db *pgxpool.Pool, loc *time.Location) *PostgresStore {
 	return &PostgresStore{
 		db:  db,
 		now: time.Now,
+		loc: loc,
 	}
 }
 
@@ -27,34 +31,108 @@ func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
 // Games
 // ============================
 
+// gameColumns is the select list scanGames reads, from "app.games g JOIN app.titles t".
+const gameColumns = `g.id, g.played_at, g.title_id, t.name, g.mode, g.participant_ids, g.winner_ids, g.teams, g.results, g.notes, g.is_active`
+
+// scanGames reads every row of a gameColumns query, with times in the league time zone.
+func (s *PostgresStore) scanGames(rows pgx.Rows, capacity int) ([]Game, error) {
+	out := make([]Game, 0, capacity)
+	for rows.Next() {
+		var g Game
+		var teams, results []byte
+		if err := rows.Scan(&g.ID, &g.PlayedAt, &g.TitleID, &g.Title, &g.Mode, &g.ParticipantIDs, &g.WinnerIDs, &teams, &results, &g.Notes, &g.IsActive); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		if err := json.Unmarshal(teams, &g.Teams); err != nil {
+			return nil, fmt.Errorf("teams: %w", err)
+		}
+		if err := json.Unmarshal(results, &g.Results); err != nil {
+			return nil, fmt.Errorf("results: %w", err)
+		}
+		g.PlayedAt = g.PlayedAt.In(s.loc)
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return out, nil
+}
+
 func (s *PostgresStore) AddGame(ctx context.Context, g Game) (Game, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := s.db.QueryRow(ctx,
-		`INSERT INTO app.games (title_id, played_at, participant_ids, winner_ids, notes)
-		 VALUES ($1, $2, $3, $4, $5)
-		 RETURNING id`,
+	teams, results, err := marshalGameJSON(g)
+	if err != nil {
+		return Game{}, fmt.Errorf("AddGame: %w", err)
+	}
+
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO app.games (title_id, played_at, mode, participant_ids, winner_ids, teams, results, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING id, is_active, (SELECT name FROM app.titles WHERE id = title_id)`,
 		g.TitleID,
 		g.PlayedAt,
+		g.GameMode(),
 		g.ParticipantIDs,
 		g.WinnerIDs,
+		teams,
+		results,
 		g.Notes,
-	).Scan(&g.ID)
+	).Scan(&g.ID, &g.IsActive, &g.Title)
 	if err != nil {
 		return Game{}, fmt.Errorf("AddGame: %w", err)
 	}
+	g.Mode = g.GameMode()
+	g.PlayedAt = g.PlayedAt.In(s.loc)
 	return g, nil
 }
 
+// UpdateGame replaces the editable fields of an existing game, keeping its ID and active flag.
+func (s *PostgresStore) UpdateGame(ctx context.Context, g Game) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	teams, results, err := marshalGameJSON(g)
+	if err != nil {
+		return fmt.Errorf("UpdateGame: %w", err)
+	}
+
+	tag, err := s.db.Exec(ctx,
+		`UPDATE app.games
+		    SET title_id = $2, played_at = $3, mode = $4, participant_ids = $5, winner_ids = $6,
+		        teams = $7, results = $8, notes = $9
+		  WHERE id = $1`,
+		g.ID,
+		g.TitleID,
+		g.PlayedAt,
+		g.GameMode(),
+		g.ParticipantIDs,
+		g.WinnerIDs,
+		teams,
+		results,
+		g.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateGame: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("game not found")
+	}
+	return nil
+}
+
 func (s *PostgresStore) DeleteGame(ctx context.Context, id int64) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `DELETE FROM app.games WHERE id = $1`, id)
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.games WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("DeleteGame: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("game not found")
+	}
 	return nil
 }
 
@@ -62,10 +140,13 @@ func (s *PostgresStore) SetGameActive(ctx context.Context, id int64, active bool
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `UPDATE app.games SET is_active = $2 WHERE id = $1`, id, active)
+	tag, err := s.db.Exec(ctx, `UPDATE app.games SET is_active = $2 WHERE id = $1`, id, active)
 	if err != nil {
 		return fmt.Errorf("SetGameActive: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("game not found")
+	}
 	return nil
 }
 
@@ -73,7 +154,7 @@ func (s *PostgresStore) RecentGames(ctx context.Context, limit int) ([]Game, err
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	q := `SELECT g.id, g.played_at, g.title_id, t.name, g.participant_ids, g.winner_ids, g.notes, g.is_active
+	q := `SELECT ` + gameColumns + `
 		  FROM app.games g
 		  JOIN app.titles t ON t.id = g.title_id
 		 ORDER BY g.is_active DESC, g.played_at DESC, g.id DESC`
@@ -90,46 +171,82 @@ func (s *PostgresStore) RecentGames(ctx context.Context, limit int) ([]Game, err
 	}
 	defer rows.Close()
 
-	out := make([]Game, 0, max(0, limit))
-	for rows.Next() {
-		var g Game
-		if err := rows.Scan(&g.ID, &g.PlayedAt, &g.TitleID, &g.Title, &g.ParticipantIDs, &g.WinnerIDs, &g.Notes, &g.IsActive); err != nil {
-			return nil, fmt.Errorf("RecentGames scan: %w", err)
-		}
-		out = append(out, g)
+	out, err := s.scanGames(rows, max(0, limit))
+	if err != nil {
+		return nil, fmt.Errorf("RecentGames: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("RecentGames rows: %w", err)
+	return out, nil
+}
+
+// ListGames returns every game (active or not) in play order.
+func (s *PostgresStore) ListGames(ctx context.Context) ([]Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + gameColumns + `
+		  FROM app.games g
+		  JOIN app.titles t ON t.id = g.title_id
+		 ORDER BY g.played_at, g.id`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("ListGames query: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := s.scanGames(rows, 100)
+	if err != nil {
+		return nil, fmt.Errorf("ListGames: %w", err)
 	}
 	return out, nil
 }
+
+// GetGame returns the game with id, active or not, or false if there is none.
+func (s *PostgresStore) GetGame(ctx context.Context, id int64) (Game, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + gameColumns + `
+		  FROM app.games g
+		  JOIN app.titles t ON t.id = g.title_id
+		 WHERE g.id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return Game{}, false, fmt.Errorf("GetGame query: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := s.scanGames(rows, 1)
+	if err != nil {
+		return Game{}, false, fmt.Errorf("GetGame: %w", err)
+	}
+	if len(out) == 0 {
+		return Game{}, false, nil
+	}
+	return out[0], true, nil
+}
 func (s *PostgresStore) GetWeek(ctx context.Context, year, week int) ([]Game, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	q := `SELECT g.id, g.played_at, g.title_id, t.name, g.participant_ids, g.winner_ids, g.notes, g.is_active
+	q := `SELECT ` + gameColumns + `
 		  FROM app.games g
 		  JOIN app.titles t ON t.id = g.title_id
-		 WHERE EXTRACT(ISOYEAR FROM g.played_at) = $1 AND EXTRACT(WEEK FROM g.played_at) = $2
+		 WHERE g.played_at >= $1 AND g.played_at < $2
 		   AND g.is_active = true
 		 ORDER BY g.played_at, g.id`
 
-	rows, err := s.db.Query(ctx, q, year, week)
+	start, end := WeekBounds(year, week, s.loc)
+	rows, err := s.db.Query(ctx, q, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("GetWeek query: %w", err)
 	}
 	defer rows.Close()
 
-	out := make([]Game, 0, 100)
-	for rows.Next() {
-		var g Game
-		if err := rows.Scan(&g.ID, &g.PlayedAt, &g.TitleID, &g.Title, &g.ParticipantIDs, &g.WinnerIDs, &g.Notes, &g.IsActive); err != nil {
-			return nil, fmt.Errorf("GetWeek scan: %w", err)
-		}
-		out = append(out, g)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("GetWeek rows: %w", err)
+	out, err := s.scanGames(rows, 100)
+	if err != nil {
+		return nil, fmt.Errorf("GetWeek: %w", err)
 	}
 
 	return out, nil
@@ -139,29 +256,23 @@ func (s *PostgresStore) GetYear(ctx context.Context, year int) ([]Game, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	q := `SELECT g.id, g.played_at, g.title_id, t.name, g.participant_ids, g.winner_ids, g.notes, g.is_active
+	q := `SELECT ` + gameColumns + `
 		  FROM app.games g
 		  JOIN app.titles t ON t.id = g.title_id
-		 WHERE EXTRACT(YEAR FROM g.played_at) = $1
+		 WHERE g.played_at >= $1 AND g.played_at < $2
 		   AND g.is_active = true
 		 ORDER BY g.played_at, g.id`
 
-	rows, err := s.db.Query(ctx, q, year)
+	start, end := YearBounds(year, s.loc)
+	rows, err := s.db.Query(ctx, q, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("GetYear query: %w", err)
 	}
 	defer rows.Close()
 
-	out := make([]Game, 0, 100)
-	for rows.Next() {
-		var g Game
-		if err := rows.Scan(&g.ID, &g.PlayedAt, &g.TitleID, &g.Title, &g.ParticipantIDs, &g.WinnerIDs, &g.Notes, &g.IsActive); err != nil {
-			return nil, fmt.Errorf("GetYear scan: %w", err)
-		}
-		out = append(out, g)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("GetYear rows: %w", err)
+	out, err := s.scanGames(rows, 100)
+	if err != nil {
+		return nil, fmt.Errorf("GetYear: %w", err)
 	}
 
 	return out, nil
@@ -213,10 +324,13 @@ func (s *PostgresStore) UpdatePlayer(ctx context.Context, id int64, name string)
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `UPDATE app.players SET name=$2 WHERE id=$1`, id, name)
+	tag, err := s.db.Exec(ctx, `UPDATE app.players SET name=$2 WHERE id=$1`, id, name)
 	if err != nil {
 		return fmt.Errorf("UpdatePlayer: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("player not found")
+	}
 
 	return nil
 }
@@ -225,10 +339,13 @@ func (s *PostgresStore) SetPlayerActive(ctx context.Context, id int64, active bo
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `UPDATE app.players SET is_active = $2 WHERE id = $1`, id, active)
+	tag, err := s.db.Exec(ctx, `UPDATE app.players SET is_active = $2 WHERE id = $1`, id, active)
 	if err != nil {
 		return fmt.Errorf("SetPlayerActive: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("player not found")
+	}
 
 	return nil
 }
@@ -252,10 +369,13 @@ func (s *PostgresStore) DeletePlayer(ctx context.Context, id int64) error {
 		return fmt.Errorf("player is referenced by a game")
 	}
 
-	_, err = s.db.Exec(ctx, `DELETE FROM app.players WHERE id=$1`, id)
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.players WHERE id=$1`, id)
 	if err != nil {
 		return fmt.Errorf("DeletePlayer: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("player not found")
+	}
 
 	return nil
 }
@@ -306,10 +426,13 @@ func (s *PostgresStore) UpdateTitle(ctx context.Context, id int64, name string)
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `UPDATE app.titles SET name=$2 WHERE id=$1`, id, name)
+	tag, err := s.db.Exec(ctx, `UPDATE app.titles SET name=$2 WHERE id=$1`, id, name)
 	if err != nil {
 		return fmt.Errorf("UpdateTitle: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("title not found")
+	}
 
 	return nil
 }
@@ -318,10 +441,13 @@ func (s *PostgresStore) SetTitleActive(ctx context.Context, id int64, active boo
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `UPDATE app.titles SET is_active = $2 WHERE id = $1`, id, active)
+	tag, err := s.db.Exec(ctx, `UPDATE app.titles SET is_active = $2 WHERE id = $1`, id, active)
 	if err != nil {
 		return fmt.Errorf("SetTitleActive: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("title not found")
+	}
 
 	return nil
 }
@@ -330,10 +456,23 @@ func (s *PostgresStore) DeleteTitle(ctx context.Context, id int64) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := s.db.Exec(ctx, `DELETE FROM app.titles WHERE id=$1`, id)
+	// Prevent deleting a title referenced by any game.
+	var exists bool
+	err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM app.games WHERE title_id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("DeleteTitle: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("title is referenced by a game")
+	}
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.titles WHERE id=$1`, id)
 	if err != nil {
 		return fmt.Errorf("DeleteTitle: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("title not found")
+	}
 
 	return nil
 }
@@ -374,17 +513,1132 @@ func (s *PostgresStore) SetTiebreaker(ctx context.Context, tb Tiebreaker) error
 		return fmt.Errorf("SetTiebreaker marshal: %w", err)
 	}
 
-	_, err = s.db.Exec(ctx,
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetTiebreaker begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := setTiebreakerTx(ctx, tx, tb.Scope, tb.ScopeKey, b); err != nil {
+		return fmt.Errorf("SetTiebreaker: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SetTiebreaker commit: %w", err)
+	}
+	return nil
+}
+
+// setTiebreakerTx upserts the current decision for scope/scopeKey and appends it to the
+// history.
+func setTiebreakerTx(ctx context.Context, tx pgx.Tx, scope, scopeKey string, data []byte) error {
+	_, err := tx.Exec(ctx,
 		`INSERT INTO app.tiebreakers (scope, scope_key, data)
 		 VALUES ($1, $2, $3)
 		 ON CONFLICT (scope, scope_key)
 		 DO UPDATE SET data = EXCLUDED.data`,
-		tb.Scope, tb.ScopeKey, b,
+		scope, scopeKey, data,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO app.tiebreaker_history (scope, scope_key, data) VALUES ($1, $2, $3)`,
+		scope, scopeKey, data,
 	)
+	return err
+}
+
+// ListTiebreakerHistory returns every decision recorded for scope/scopeKey, latest first.
+func (s *PostgresStore) ListTiebreakerHistory(ctx context.Context, scope, scopeKey string) ([]Tiebreaker, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
 
+	rows, err := s.db.Query(ctx,
+		`SELECT data FROM app.tiebreaker_history
+		 WHERE scope = $1 AND scope_key = $2
+		 ORDER BY id DESC`,
+		scope, scopeKey,
+	)
 	if err != nil {
-		return fmt.Errorf("SetTiebreaker: %w", err)
+		return nil, fmt.Errorf("ListTiebreakerHistory query: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Tiebreaker
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("ListTiebreakerHistory scan: %w", err)
+		}
+		var tb Tiebreaker
+		if err := json.Unmarshal(raw, &tb); err != nil {
+			return nil, fmt.Errorf("ListTiebreakerHistory unmarshal: %w", err)
+		}
+		out = append(out, tb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTiebreakerHistory rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) ListTiebreakers(ctx context.Context) ([]Tiebreaker, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, `SELECT data FROM app.tiebreakers ORDER BY scope, scope_key`)
+	if err != nil {
+		return nil, fmt.Errorf("ListTiebreakers query: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Tiebreaker
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("ListTiebreakers scan: %w", err)
+		}
+		var tb Tiebreaker
+		if err := json.Unmarshal(raw, &tb); err != nil {
+			return nil, fmt.Errorf("ListTiebreakers unmarshal: %w", err)
+		}
+		out = append(out, tb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTiebreakers rows: %w", err)
+	}
+	return out, nil
+}
+
+// ============================
+// Rulesets
+// ============================
+
+// ListRulesets returns the stored rulesets, oldest EffectiveFrom first. EffectiveFrom is
+// midnight of the stored date in the league time zone.
+func (s *PostgresStore) ListRulesets(ctx context.Context) ([]Ruleset, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, name, to_char(effective_from, 'YYYY-MM-DD'), weekly, yearly
+		 FROM app.rulesets ORDER BY effective_from`)
+	if err != nil {
+		return nil, fmt.Errorf("ListRulesets: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Ruleset
+	for rows.Next() {
+		var r Ruleset
+		var from string
+		var weekly, yearly []byte
+		if err := rows.Scan(&r.ID, &r.Name, &from, &weekly, &yearly); err != nil {
+			return nil, fmt.Errorf("ListRulesets scan: %w", err)
+		}
+		if r.EffectiveFrom, err = time.ParseInLocation("2006-01-02", from, s.loc); err != nil {
+			return nil, fmt.Errorf("ListRulesets date: %w", err)
+		}
+		if err := json.Unmarshal(weekly, &r.Weekly); err != nil {
+			return nil, fmt.Errorf("ListRulesets unmarshal: %w", err)
+		}
+		if err := json.Unmarshal(yearly, &r.Yearly); err != nil {
+			return nil, fmt.Errorf("ListRulesets unmarshal: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListRulesets rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) AddRuleset(ctx context.Context, r Ruleset) (Ruleset, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	weekly, err := json.Marshal(r.Weekly)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset marshal: %w", err)
+	}
+	yearly, err := json.Marshal(r.Yearly)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset marshal: %w", err)
 	}
 
+	from := r.EffectiveFrom.In(s.loc)
+	r.EffectiveFrom = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, s.loc)
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO app.rulesets (name, effective_from, weekly, yearly)
+		 VALUES ($1, $2::date, $3, $4) RETURNING id`,
+		r.Name, r.EffectiveFrom.Format("2006-01-02"), weekly, yearly,
+	).Scan(&r.ID)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset: %w", err)
+	}
+
+	return r, nil
+}
+
+func (s *PostgresStore) DeleteRuleset(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.rulesets WHERE id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteRuleset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("ruleset not found")
+	}
+
+	return nil
+}
+
+// ============================
+// Audit
+// ============================
+
+// AppendAudit records e; the database stamps its ID and, if unset, its time.
+func (s *PostgresStore) AppendAudit(ctx context.Context, e AuditEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var at *time.Time
+	if !e.At.IsZero() {
+		at = &e.At
+	}
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO app.audit_log (at, actor, action, entity, entity_id, before, after)
+		 VALUES (COALESCE($1, now()), $2, $3, $4, $5, $6, $7)`,
+		at, e.Actor, e.Action, e.Entity, e.EntityID, e.Before, e.After,
+	)
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
 	return nil
 }
+
+// ListAudit returns the entries matching f, latest first.
+func (s *PostgresStore) ListAudit(ctx context.Context, f AuditFilter) ([]AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var from, to *time.Time
+	if !f.From.IsZero() {
+		from = &f.From
+	}
+	if !f.To.IsZero() {
+		to = &f.To
+	}
+	var limit *int
+	if f.Limit > 0 {
+		limit = &f.Limit
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, at, actor, action, entity, entity_id, before, after
+		 FROM app.audit_log
+		 WHERE ($1 = '' OR lower(actor) = lower($1))
+		   AND ($2 = '' OR action = $2)
+		   AND ($3 = '' OR entity = $3)
+		   AND ($4 = '' OR entity_id = $4)
+		   AND ($5::timestamptz IS NULL OR at >= $5)
+		   AND ($6::timestamptz IS NULL OR at < $6)
+		 ORDER BY id DESC
+		 LIMIT $7`,
+		f.Actor, f.Action, f.Entity, f.EntityID, from, to, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListAudit query: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.At, &e.Actor, &e.Action, &e.Entity, &e.EntityID, &e.Before, &e.After); err != nil {
+			return nil, fmt.Errorf("ListAudit scan: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAudit rows: %w", err)
+	}
+	return out, nil
+}
+
+// ============================
+// Seasons
+// ============================
+
+// ListSeasons returns the stored seasons, latest start date first.
+func (s *PostgresStore) ListSeasons(ctx context.Context) ([]Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, name, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD')
+		 FROM app.seasons ORDER BY start_date DESC, id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListSeasons: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Season
+	for rows.Next() {
+		var se Season
+		var start, end string
+		if err := rows.Scan(&se.ID, &se.Name, &start, &end); err != nil {
+			return nil, fmt.Errorf("ListSeasons scan: %w", err)
+		}
+		if se.StartDate, err = time.ParseInLocation("2006-01-02", start, s.loc); err != nil {
+			return nil, fmt.Errorf("ListSeasons date: %w", err)
+		}
+		if se.EndDate, err = time.ParseInLocation("2006-01-02", end, s.loc); err != nil {
+			return nil, fmt.Errorf("ListSeasons date: %w", err)
+		}
+		out = append(out, se)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListSeasons rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) AddSeason(ctx context.Context, se Season) (Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	se.StartDate = leagueDate(se.StartDate, s.loc)
+	se.EndDate = leagueDate(se.EndDate, s.loc)
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO app.seasons (name, start_date, end_date)
+		 VALUES ($1, $2::date, $3::date) RETURNING id`,
+		se.Name, se.StartDate.Format("2006-01-02"), se.EndDate.Format("2006-01-02"),
+	).Scan(&se.ID)
+	if err != nil {
+		return Season{}, fmt.Errorf("AddSeason: %w", err)
+	}
+
+	return se, nil
+}
+
+func (s *PostgresStore) DeleteSeason(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.seasons WHERE id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteSeason: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("season not found")
+	}
+
+	return nil
+}
+
+// ============================
+// Users and sessions
+// ============================
+
+const userColumns = `id, username, password_hash, role, player_id, is_active, created_at`
+
+func scanUser(row pgx.Row) (User, error) {
+	var u User
+	err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.PlayerID, &u.IsActive, &u.CreatedAt)
+	return u, err
+}
+
+// ListUsers returns every user by user name.
+func (s *PostgresStore) ListUsers(ctx context.Context) ([]User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, `SELECT `+userColumns+` FROM app.users ORDER BY lower(username)`)
+	if err != nil {
+		return nil, fmt.Errorf("ListUsers: %w", err)
+	}
+	defer rows.Close()
+
+	var out []User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListUsers scan: %w", err)
+		}
+		out = append(out, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListUsers rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) GetUser(ctx context.Context, id int64) (User, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	u, err := scanUser(s.db.QueryRow(ctx, `SELECT `+userColumns+` FROM app.users WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, false, nil
+	}
+	if err != nil {
+		return User{}, false, fmt.Errorf("GetUser: %w", err)
+	}
+	return u, true, nil
+}
+
+// GetUserByUsername looks a user up by name, ignoring case.
+func (s *PostgresStore) GetUserByUsername(ctx context.Context, username string) (User, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	u, err := scanUser(s.db.QueryRow(ctx, `SELECT `+userColumns+` FROM app.users WHERE lower(username) = lower($1)`, username))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, false, nil
+	}
+	if err != nil {
+		return User{}, false, fmt.Errorf("GetUserByUsername: %w", err)
+	}
+	return u, true, nil
+}
+
+func (s *PostgresStore) AddUser(ctx context.Context, u User) (User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := scanUser(s.db.QueryRow(ctx,
+		`INSERT INTO app.users (username, password_hash, role, player_id, is_active)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+userColumns,
+		u.Username, u.PasswordHash, u.Role, u.PlayerID, u.IsActive,
+	))
+	if err != nil {
+		return User{}, fmt.Errorf("AddUser: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateUser replaces everything but the ID, user name and creation time.
+func (s *PostgresStore) UpdateUser(ctx context.Context, u User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx,
+		`UPDATE app.users SET password_hash = $2, role = $3, player_id = $4, is_active = $5 WHERE id = $1`,
+		u.ID, u.PasswordHash, u.Role, u.PlayerID, u.IsActive,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateUser: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// DeleteUser removes a user; their sessions go with them.
+func (s *PostgresStore) DeleteUser(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteUser: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (s *PostgresStore) AddSession(ctx context.Context, sess Session) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO app.sessions (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`,
+		sess.TokenHash, sess.UserID, sess.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("AddSession: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns the unexpired session with tokenHash.
+func (s *PostgresStore) GetSession(ctx context.Context, tokenHash string) (Session, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var sess Session
+	err := s.db.QueryRow(ctx,
+		`SELECT token_hash, user_id, created_at, expires_at FROM app.sessions
+		 WHERE token_hash = $1 AND expires_at > now()`, tokenHash,
+	).Scan(&sess.TokenHash, &sess.UserID, &sess.CreatedAt, &sess.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, false, nil
+	}
+	if err != nil {
+		return Session{}, false, fmt.Errorf("GetSession: %w", err)
+	}
+	return sess, true, nil
+}
+
+// DeleteSession removes one session, along with any that have expired.
+func (s *PostgresStore) DeleteSession(ctx context.Context, tokenHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.Exec(ctx, `DELETE FROM app.sessions WHERE token_hash = $1 OR expires_at <= now()`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("DeleteSession: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere.
+func (s *PostgresStore) DeleteUserSessions(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.Exec(ctx, `DELETE FROM app.sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("DeleteUserSessions: %w", err)
+	}
+	return nil
+}
+
+// ============================
+// API tokens
+// ============================
+
+const apiTokenColumns = `id, name, token_hash, scope, created_by, created_at, last_used_at, revoked_at`
+
+func scanAPIToken(row pgx.Row) (APIToken, error) {
+	var t APIToken
+	err := row.Scan(&t.ID, &t.Name, &t.TokenHash, &t.Scope, &t.CreatedBy, &t.CreatedAt, &t.LastUsedAt, &t.RevokedAt)
+	return t, err
+}
+
+// ListAPITokens returns every token, newest first, revoked ones included.
+func (s *PostgresStore) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, `SELECT `+apiTokenColumns+` FROM app.api_tokens ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPITokens: %w", err)
+	}
+	defer rows.Close()
+
+	var out []APIToken
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListAPITokens scan: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAPITokens rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) AddAPIToken(ctx context.Context, t APIToken) (APIToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := scanAPIToken(s.db.QueryRow(ctx,
+		`INSERT INTO app.api_tokens (name, token_hash, scope, created_by)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+apiTokenColumns,
+		t.Name, t.TokenHash, t.Scope, t.CreatedBy,
+	))
+	if err != nil {
+		return APIToken{}, fmt.Errorf("AddAPIToken: %w", err)
+	}
+	return out, nil
+}
+
+// GetAPITokenByHash returns the token with tokenHash, revoked or not.
+func (s *PostgresStore) GetAPITokenByHash(ctx context.Context, tokenHash string) (APIToken, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	t, err := scanAPIToken(s.db.QueryRow(ctx, `SELECT `+apiTokenColumns+` FROM app.api_tokens WHERE token_hash = $1`, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return APIToken{}, false, nil
+	}
+	if err != nil {
+		return APIToken{}, false, fmt.Errorf("GetAPITokenByHash: %w", err)
+	}
+	return t, true, nil
+}
+
+// RevokeAPIToken stops a token working. Revoking it again keeps the first revocation time.
+func (s *PostgresStore) RevokeAPIToken(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `UPDATE app.api_tokens SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("RevokeAPIToken: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// TouchAPIToken records that a token was used at at.
+func (s *PostgresStore) TouchAPIToken(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `UPDATE app.api_tokens SET last_used_at = $2 WHERE id = $1`, id, at)
+	if err != nil {
+		return fmt.Errorf("TouchAPIToken: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// ============================
+// Webhooks
+// ============================
+
+const webhookColumns = `id, name, url, secret, events, is_active, created_by, created_at`
+
+func scanWebhook(row pgx.Row) (Webhook, error) {
+	var h Webhook
+	err := row.Scan(&h.ID, &h.Name, &h.URL, &h.Secret, &h.Events, &h.IsActive, &h.CreatedBy, &h.CreatedAt)
+	return h, err
+}
+
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, next_attempt_at, last_status, last_error, created_at, delivered_at,
+	COALESCE(dedupe_key, '')`
+
+func scanDelivery(row pgx.Row) (WebhookDelivery, error) {
+	var d WebhookDelivery
+	var payload string
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt, &d.Key)
+	d.Payload = []byte(payload)
+	return d, err
+}
+
+// ListWebhooks returns every webhook by name.
+func (s *PostgresStore) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, `SELECT `+webhookColumns+` FROM app.webhooks ORDER BY lower(name), id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Webhook
+	for rows.Next() {
+		h, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListWebhooks scan: %w", err)
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListWebhooks rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) AddWebhook(ctx context.Context, h Webhook) (Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if h.Events == nil {
+		h.Events = []string{}
+	}
+	out, err := scanWebhook(s.db.QueryRow(ctx,
+		`INSERT INTO app.webhooks (name, url, secret, events, is_active, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+webhookColumns,
+		h.Name, h.URL, h.Secret, h.Events, h.IsActive, h.CreatedBy,
+	))
+	if err != nil {
+		return Webhook{}, fmt.Errorf("AddWebhook: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresStore) SetWebhookActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `UPDATE app.webhooks SET is_active = $2 WHERE id = $1`, id, active)
+	if err != nil {
+		return fmt.Errorf("SetWebhookActive: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// DeleteWebhook removes a webhook; its deliveries go with it (ON DELETE CASCADE).
+func (s *PostgresStore) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM app.webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// AddWebhookDelivery queues d; it is pending and due now unless d says otherwise. If the
+// webhook already has a delivery with d's Key, that one is returned and nothing is queued.
+func (s *PostgresStore) AddWebhookDelivery(ctx context.Context, d WebhookDelivery) (WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if d.Status == "" {
+		d.Status = DeliveryPending
+	}
+	var next *time.Time
+	if !d.NextAttemptAt.IsZero() {
+		next = &d.NextAttemptAt
+	}
+	out, err := scanDelivery(s.db.QueryRow(ctx,
+		`INSERT INTO app.webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_at, last_status, last_error, dedupe_key)
+		 SELECT id, $2, $3, $4, $5, COALESCE($6, now()), $7, $8, NULLIF($9, '') FROM app.webhooks WHERE id = $1
+		 ON CONFLICT (webhook_id, dedupe_key) DO NOTHING
+		 RETURNING `+deliveryColumns,
+		d.WebhookID, d.Event, string(d.Payload), d.Status, d.Attempts, next, d.LastStatus, d.LastError, d.Key,
+	))
+	if errors.Is(err, pgx.ErrNoRows) && d.Key != "" {
+		out, err = scanDelivery(s.db.QueryRow(ctx,
+			`SELECT `+deliveryColumns+` FROM app.webhook_deliveries WHERE webhook_id = $1 AND dedupe_key = $2`,
+			d.WebhookID, d.Key,
+		))
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return WebhookDelivery{}, errors.New("webhook not found")
+	}
+	if err != nil {
+		return WebhookDelivery{}, fmt.Errorf("AddWebhookDelivery: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateWebhookDelivery saves d's status, attempts, next attempt time, last status and
+// error, and delivery time.
+func (s *PostgresStore) UpdateWebhookDelivery(ctx context.Context, d WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx,
+		`UPDATE app.webhook_deliveries
+		 SET status = $2, attempts = $3, next_attempt_at = $4, last_status = $5, last_error = $6, delivered_at = $7
+		 WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatus, d.LastError, d.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateWebhookDelivery: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("delivery not found")
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries claims up to limit pending deliveries due at now, the longest due
+// first, by moving their next attempt to now+lease, and returns them oldest first. Rows
+// another worker is claiming are skipped, so no two workers get the same delivery.
+func (s *PostgresStore) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	out, err := s.queryDeliveries(ctx, "ClaimWebhookDeliveries",
+		`UPDATE app.webhook_deliveries SET next_attempt_at = $2
+		 WHERE id IN (SELECT id FROM app.webhook_deliveries
+		              WHERE status = 'pending' AND next_attempt_at <= $1
+		              ORDER BY next_attempt_at, id
+		              LIMIT $3
+		              FOR UPDATE SKIP LOCKED)
+		 RETURNING `+deliveryColumns,
+		now, now.Add(lease), pgLimit(limit))
+	sortDeliveries(out)
+	return out, err
+}
+
+// sortDeliveries puts ds oldest first. RETURNING comes back in no particular order.
+func sortDeliveries(ds []WebhookDelivery) {
+	slices.SortFunc(ds, func(a, b WebhookDelivery) int { return cmp.Compare(a.ID, b.ID) })
+}
+
+// ListWebhookDeliveries returns the latest deliveries first, for one webhook or (webhookID
+// 0) all of them; limit 0 means no limit.
+func (s *PostgresStore) ListWebhookDeliveries(ctx context.Context, webhookID int64, limit int) ([]WebhookDelivery, error) {
+	return s.queryDeliveries(ctx, "ListWebhookDeliveries",
+		`SELECT `+deliveryColumns+` FROM app.webhook_deliveries
+		 WHERE $1::bigint = 0 OR webhook_id = $1
+		 ORDER BY id DESC
+		 LIMIT $2`,
+		webhookID, pgLimit(limit))
+}
+
+// pgLimit is a LIMIT argument where 0 means no limit (NULL).
+func pgLimit(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+func (s *PostgresStore) queryDeliveries(ctx context.Context, op, q string, args ...any) ([]WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var out []WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return out, nil
+}
+
+// ============================
+// Week recaps
+// ============================
+
+func (s *PostgresStore) GetWeekRecap(ctx context.Context, year, week int) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r, err := scanWeekRecap(s.db.QueryRow(ctx,
+		`SELECT data, notified_at FROM app.week_recaps WHERE year = $1 AND week = $2`,
+		year, week,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return WeekRecap{}, false, nil
+	}
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("GetWeekRecap: %w", err)
+	}
+	return r, true, nil
+}
+
+// LastWeekRecap returns the recap of the latest week that has one, or false if none has.
+func (s *PostgresStore) LastWeekRecap(ctx context.Context) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r, err := scanWeekRecap(s.db.QueryRow(ctx,
+		`SELECT data, notified_at FROM app.week_recaps ORDER BY year DESC, week DESC LIMIT 1`,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return WeekRecap{}, false, nil
+	}
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("LastWeekRecap: %w", err)
+	}
+	return r, true, nil
+}
+
+func scanWeekRecap(row pgx.Row) (WeekRecap, error) {
+	var raw []byte
+	var notifiedAt *time.Time
+	if err := row.Scan(&raw, &notifiedAt); err != nil {
+		return WeekRecap{}, err
+	}
+	var r WeekRecap
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return WeekRecap{}, fmt.Errorf("unmarshal: %w", err)
+	}
+	r.NotifiedAt = notifiedAt
+	return r, nil
+}
+
+// AddWeekRecap stores r unless its week already has a recap, and returns the week's recap
+// and whether it is r. The first recap written for a week is the one that stays.
+func (s *PostgresStore) AddWeekRecap(ctx context.Context, r WeekRecap) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.NotifiedAt = nil
+	b, err := json.Marshal(r)
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("AddWeekRecap marshal: %w", err)
+	}
+
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO app.week_recaps (year, week, data, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (year, week) DO NOTHING`,
+		r.Year, r.Week, b, r.CreatedAt,
+	)
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("AddWeekRecap: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return r, true, nil
+	}
+	existing, _, err := s.GetWeekRecap(ctx, r.Year, r.Week)
+	return existing, false, err
+}
+
+// MarkWeekRecapNotified records that the recap of the week was handed to every notifier at at.
+func (s *PostgresStore) MarkWeekRecapNotified(ctx context.Context, year, week int, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := s.db.Exec(ctx,
+		`UPDATE app.week_recaps SET notified_at = $3 WHERE year = $1 AND week = $2`,
+		year, week, at,
+	)
+	if err != nil {
+		return fmt.Errorf("MarkWeekRecapNotified: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("recap not found")
+	}
+	return nil
+}
+
+// ============================
+// Import
+// ============================
+
+// ImportDataset adds d's missing players and titles, appends its games and upserts its
+// tiebreakers, rulesets (by start date) and seasons (by name) in a single transaction.
+func (s *PostgresStore) ImportDataset(ctx context.Context, d Dataset) (ImportSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var sum ImportSummary
+
+	for _, p := range d.Players {
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO app.players (name, is_active) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING`,
+			p.Name, p.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset players: %w", err)
+		}
+		sum.PlayersAdded += int(tag.RowsAffected())
+	}
+	for _, t := range d.Titles {
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO app.titles (name, is_active) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING`,
+			t.Name, t.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset titles: %w", err)
+		}
+		sum.TitlesAdded += int(tag.RowsAffected())
+	}
+
+	playerIDs, err := nameIDs(ctx, tx, `SELECT id, name FROM app.players`)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset players: %w", err)
+	}
+	titleIDs, err := nameIDs(ctx, tx, `SELECT id, name FROM app.titles`)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset titles: %w", err)
+	}
+	resolve := func(names []string) ([]int64, error) {
+		ids := make([]int64, 0, len(names))
+		for _, n := range names {
+			id, ok := playerIDs[n]
+			if !ok {
+				return nil, fmt.Errorf("unknown player %q", n)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	for _, g := range d.Games {
+		titleID, ok := titleIDs[g.Title]
+		if !ok {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: unknown title %q", g.Title)
+		}
+		participants, err := resolve(g.Participants)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		winners, err := resolve(g.Winners)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		rs, err := datasetResults(g.Results, resolve)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		ts, err := datasetTeams(g.Teams, resolve)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		stored := Game{Mode: g.Mode, Teams: ts, Results: rs}
+		teams, results, err := marshalGameJSON(stored)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO app.games (title_id, played_at, mode, participant_ids, winner_ids, teams, results, notes, is_active)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			titleID, g.PlayedAt, stored.GameMode(), participants, winners, teams, results, g.Notes, g.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		sum.GamesAdded++
+	}
+
+	for _, dt := range d.Tiebreakers {
+		tied, err := resolve(dt.Tied)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		winner, err := resolve([]string{dt.Winner})
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		var gameID int64
+		if ref := dt.PlayoffGame; ref != nil {
+			err := tx.QueryRow(ctx,
+				`SELECT g.id FROM app.games g JOIN app.titles t ON t.id = g.title_id
+				 WHERE g.played_at = $1 AND t.name = $2
+				 ORDER BY g.id LIMIT 1`,
+				ref.PlayedAt, ref.Title).Scan(&gameID)
+			if err != nil {
+				return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: play-off game %s at %s: %w", ref.Title, ref.PlayedAt.Format(time.RFC3339), err)
+			}
+		}
+		b, err := json.Marshal(Tiebreaker{
+			Scope:         dt.Scope,
+			ScopeKey:      dt.ScopeKey,
+			TiedPlayerIDs: tied,
+			WinnerID:      winner[0],
+			Method:        dt.Method,
+			DecidedAt:     dt.DecidedAt,
+			Seed:          dt.Seed,
+			Algorithm:     dt.Algorithm,
+			GameID:        gameID,
+		})
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers marshal: %w", err)
+		}
+		if err := setTiebreakerTx(ctx, tx, dt.Scope, dt.ScopeKey, b); err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		sum.TiebreakersSet++
+	}
+
+	for _, dr := range d.Rulesets {
+		rs, err := dr.Ruleset(s.loc)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets: %w", err)
+		}
+		weekly, err := json.Marshal(rs.Weekly)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets marshal: %w", err)
+		}
+		yearly, err := json.Marshal(rs.Yearly)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets marshal: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO app.rulesets (name, effective_from, weekly, yearly)
+			 VALUES ($1, $2::date, $3, $4)
+			 ON CONFLICT (effective_from)
+			 DO UPDATE SET name = EXCLUDED.name, weekly = EXCLUDED.weekly, yearly = EXCLUDED.yearly`,
+			rs.Name, dr.EffectiveFrom, weekly, yearly)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets: %w", err)
+		}
+		sum.RulesetsSet++
+	}
+
+	for _, ds := range d.Seasons {
+		if _, err := ds.Season(s.loc); err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset seasons: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO app.seasons (name, start_date, end_date)
+			 VALUES ($1, $2::date, $3::date)
+			 ON CONFLICT (name)
+			 DO UPDATE SET start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date`,
+			ds.Name, ds.StartDate, ds.EndDate)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset seasons: %w", err)
+		}
+		sum.SeasonsSet++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset commit: %w", err)
+	}
+	return sum, nil
+}
+
+// marshalGameJSON encodes g's teams and results for their jsonb columns; nil becomes [].
+func marshalGameJSON(g Game) (teams, results []byte, err error) {
+	if g.Teams == nil {
+		g.Teams = [][]int64{}
+	}
+	if g.Results == nil {
+		g.Results = []Result{}
+	}
+	if teams, err = json.Marshal(g.Teams); err != nil {
+		return nil, nil, err
+	}
+	if results, err = json.Marshal(g.Results); err != nil {
+		return nil, nil, err
+	}
+	return teams, results, nil
+}
+
+// nameIDs runs q, which must select (id, name), and maps each name to its ID.
+func nameIDs(ctx context.Context, tx pgx.Tx, q string) (map[string]int64, error) {
+	rows, err := tx.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[string]int64{}
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		out[name] = id
+	}
+	return out, rows.Err()
+}
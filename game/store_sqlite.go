@@ -0,0 +1,1698 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLiteStore keeps everything in a single SQLite file (see db.OpenSQLite), for deployments
+// without a Postgres server. It behaves like PostgresStore.
+//
+// Times are stored as UTC text in sqliteTimeFormat, so range filters compare them as strings;
+// ID lists, teams and results are stored as JSON.
+type SQLiteStore struct {
+	db  *sql.DB
+	now func() time.Time
+	loc *time.Location // league time zone for week/year queries and returned times
+}
+
+func NewSQLiteStore(db *sql.DB, loc *time.Location) *SQLiteStore {
+	return &SQLiteStore{
+		db:  db,
+		now: time.Now,
+		loc: loc,
+	}
+}
+
+// Ping checks the database file can be reached, for the readiness probe.
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// sqliteTimeFormat matches the strftime('%Y-%m-%dT%H:%M:%fZ') column defaults: fixed-width
+// with milliseconds, so every stored time sorts in time order.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000Z"
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// parseSQLiteTime reads a stored time.
+func parseSQLiteTime(v string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+// parseSQLiteNullTime is parseSQLiteTime for nullable columns.
+func parseSQLiteNullTime(v sql.NullString, loc *time.Location) (*time.Time, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	t, err := parseSQLiteTime(v.String, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// sqliteIDs encodes an ID list for its JSON column; nil becomes [].
+func sqliteIDs(ids []int64) (string, error) {
+	if ids == nil {
+		ids = []int64{}
+	}
+	b, err := json.Marshal(ids)
+	return string(b), err
+}
+
+// sqliteJSON stores a JSON snapshot as text, keeping nil as NULL.
+func sqliteJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+// sqliteFound returns "<noun> not found" when res changed no rows.
+func sqliteFound(res sql.Result, noun string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New(noun + " not found")
+	}
+	return nil
+}
+
+// ============================
+// Games
+// ============================
+
+// sqliteGameColumns is the select list scanGames reads, from "games g JOIN titles t".
+const sqliteGameColumns = `g.id, g.played_at, g.title_id, t.name, g.mode, g.participant_ids, g.winner_ids, g.teams, g.results, g.notes, g.is_active`
+
+// scanGames reads every row of a sqliteGameColumns query, with times in the league time zone.
+func (s *SQLiteStore) scanGames(rows *sql.Rows, capacity int) ([]Game, error) {
+	out := make([]Game, 0, capacity)
+	for rows.Next() {
+		var g Game
+		var playedAt string
+		var participants, winners, teams, results []byte
+		if err := rows.Scan(&g.ID, &playedAt, &g.TitleID, &g.Title, &g.Mode, &participants, &winners, &teams, &results, &g.Notes, &g.IsActive); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		var err error
+		if g.PlayedAt, err = parseSQLiteTime(playedAt, s.loc); err != nil {
+			return nil, fmt.Errorf("played_at: %w", err)
+		}
+		if err := json.Unmarshal(participants, &g.ParticipantIDs); err != nil {
+			return nil, fmt.Errorf("participant_ids: %w", err)
+		}
+		if err := json.Unmarshal(winners, &g.WinnerIDs); err != nil {
+			return nil, fmt.Errorf("winner_ids: %w", err)
+		}
+		if err := json.Unmarshal(teams, &g.Teams); err != nil {
+			return nil, fmt.Errorf("teams: %w", err)
+		}
+		if err := json.Unmarshal(results, &g.Results); err != nil {
+			return nil, fmt.Errorf("results: %w", err)
+		}
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return out, nil
+}
+
+// sqliteGameArgs returns g's ID lists, teams and results as their stored JSON.
+func sqliteGameArgs(g Game) (participants, winners, teams, results string, err error) {
+	if participants, err = sqliteIDs(g.ParticipantIDs); err != nil {
+		return "", "", "", "", err
+	}
+	if winners, err = sqliteIDs(g.WinnerIDs); err != nil {
+		return "", "", "", "", err
+	}
+	t, r, err := marshalGameJSON(g)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	return participants, winners, string(t), string(r), nil
+}
+
+func (s *SQLiteStore) AddGame(ctx context.Context, g Game) (Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	participants, winners, teams, results, err := sqliteGameArgs(g)
+	if err != nil {
+		return Game{}, fmt.Errorf("AddGame: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO games (title_id, played_at, mode, participant_ids, winner_ids, teams, results, notes)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 RETURNING id, is_active, (SELECT name FROM titles WHERE id = title_id)`,
+		g.TitleID,
+		sqliteTime(g.PlayedAt),
+		g.GameMode(),
+		participants,
+		winners,
+		teams,
+		results,
+		g.Notes,
+	).Scan(&g.ID, &g.IsActive, &g.Title)
+	if err != nil {
+		return Game{}, fmt.Errorf("AddGame: %w", err)
+	}
+	g.Mode = g.GameMode()
+	g.PlayedAt = g.PlayedAt.In(s.loc)
+	return g, nil
+}
+
+// UpdateGame replaces the editable fields of an existing game, keeping its ID and active flag.
+func (s *SQLiteStore) UpdateGame(ctx context.Context, g Game) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	participants, winners, teams, results, err := sqliteGameArgs(g)
+	if err != nil {
+		return fmt.Errorf("UpdateGame: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE games
+		    SET title_id = ?2, played_at = ?3, mode = ?4, participant_ids = ?5, winner_ids = ?6,
+		        teams = ?7, results = ?8, notes = ?9
+		  WHERE id = ?1`,
+		g.ID,
+		g.TitleID,
+		sqliteTime(g.PlayedAt),
+		g.GameMode(),
+		participants,
+		winners,
+		teams,
+		results,
+		g.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateGame: %w", err)
+	}
+	return sqliteFound(res, "game")
+}
+
+func (s *SQLiteStore) DeleteGame(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM games WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteGame: %w", err)
+	}
+	return sqliteFound(res, "game")
+}
+
+func (s *SQLiteStore) SetGameActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE games SET is_active = ?2 WHERE id = ?1`, id, active)
+	if err != nil {
+		return fmt.Errorf("SetGameActive: %w", err)
+	}
+	return sqliteFound(res, "game")
+}
+
+func (s *SQLiteStore) RecentGames(ctx context.Context, limit int) ([]Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + sqliteGameColumns + `
+		  FROM games g
+		  JOIN titles t ON t.id = g.title_id
+		 ORDER BY g.is_active DESC, g.played_at DESC, g.id DESC`
+
+	var args []any
+	if limit > 0 {
+		q += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("RecentGames query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out, err := s.scanGames(rows, max(0, limit))
+	if err != nil {
+		return nil, fmt.Errorf("RecentGames: %w", err)
+	}
+	return out, nil
+}
+
+// ListGames returns every game (active or not) in play order.
+func (s *SQLiteStore) ListGames(ctx context.Context) ([]Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + sqliteGameColumns + `
+		  FROM games g
+		  JOIN titles t ON t.id = g.title_id
+		 ORDER BY g.played_at, g.id`
+
+	rows, err := s.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("ListGames query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out, err := s.scanGames(rows, 100)
+	if err != nil {
+		return nil, fmt.Errorf("ListGames: %w", err)
+	}
+	return out, nil
+}
+
+// GetGame returns the game with id, active or not, or false if there is none.
+func (s *SQLiteStore) GetGame(ctx context.Context, id int64) (Game, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + sqliteGameColumns + `
+		  FROM games g
+		  JOIN titles t ON t.id = g.title_id
+		 WHERE g.id = ?`
+
+	rows, err := s.db.QueryContext(ctx, q, id)
+	if err != nil {
+		return Game{}, false, fmt.Errorf("GetGame query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out, err := s.scanGames(rows, 1)
+	if err != nil {
+		return Game{}, false, fmt.Errorf("GetGame: %w", err)
+	}
+	if len(out) == 0 {
+		return Game{}, false, nil
+	}
+	return out[0], true, nil
+}
+
+// GetWeek returns the active games of ISO week year/week in the league time zone.
+func (s *SQLiteStore) GetWeek(ctx context.Context, year, week int) ([]Game, error) {
+	start, end := WeekBounds(year, week, s.loc)
+	out, err := s.gamesBetween(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("GetWeek: %w", err)
+	}
+	return out, nil
+}
+
+// GetYear returns the active games of a calendar year in the league time zone.
+func (s *SQLiteStore) GetYear(ctx context.Context, year int) ([]Game, error) {
+	start, end := YearBounds(year, s.loc)
+	out, err := s.gamesBetween(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("GetYear: %w", err)
+	}
+	return out, nil
+}
+
+// gamesBetween returns the active games played in [start, end), in play order.
+func (s *SQLiteStore) gamesBetween(ctx context.Context, start, end time.Time) ([]Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := `SELECT ` + sqliteGameColumns + `
+		  FROM games g
+		  JOIN titles t ON t.id = g.title_id
+		 WHERE g.played_at >= ? AND g.played_at < ?
+		   AND g.is_active = 1
+		 ORDER BY g.played_at, g.id`
+
+	rows, err := s.db.QueryContext(ctx, q, sqliteTime(start), sqliteTime(end))
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return s.scanGames(rows, 100)
+}
+
+// ============================
+// Players
+// ============================
+
+func (s *SQLiteStore) ListPlayers(ctx context.Context) ([]Player, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, is_active FROM players ORDER BY is_active DESC, name`)
+	if err != nil {
+		return nil, fmt.Errorf("ListPlayers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Player
+	for rows.Next() {
+		var p Player
+		if err := rows.Scan(&p.ID, &p.Name, &p.IsActive); err != nil {
+			return nil, fmt.Errorf("ListPlayers scan: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListPlayers rows: %w", err)
+	}
+
+	return out, nil
+}
+
+func (s *SQLiteStore) AddPlayer(ctx context.Context, name string) (Player, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var p Player
+	err := s.db.QueryRowContext(ctx, `INSERT INTO players (name) VALUES (?) RETURNING id, name, is_active`, name).Scan(&p.ID, &p.Name, &p.IsActive)
+	if err != nil {
+		return Player{}, fmt.Errorf("AddPlayer: %w", err)
+	}
+
+	return p, nil
+}
+
+func (s *SQLiteStore) UpdatePlayer(ctx context.Context, id int64, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE players SET name = ?2 WHERE id = ?1`, id, name)
+	if err != nil {
+		return fmt.Errorf("UpdatePlayer: %w", err)
+	}
+	return sqliteFound(res, "player")
+}
+
+func (s *SQLiteStore) SetPlayerActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE players SET is_active = ?2 WHERE id = ?1`, id, active)
+	if err != nil {
+		return fmt.Errorf("SetPlayerActive: %w", err)
+	}
+	return sqliteFound(res, "player")
+}
+
+func (s *SQLiteStore) DeletePlayer(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Prevent deleting a player referenced by any game.
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM games
+			WHERE EXISTS (SELECT 1 FROM json_each(participant_ids) WHERE value = ?1)
+			   OR EXISTS (SELECT 1 FROM json_each(winner_ids) WHERE value = ?1)
+		)`, id,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("DeletePlayer: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("player is referenced by a game")
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM players WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeletePlayer: %w", err)
+	}
+	return sqliteFound(res, "player")
+}
+
+// ============================
+// Titles
+// ============================
+
+func (s *SQLiteStore) ListTitles(ctx context.Context) ([]Title, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, is_active FROM titles ORDER BY is_active DESC, name`)
+	if err != nil {
+		return nil, fmt.Errorf("ListTitles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Title
+	for rows.Next() {
+		var t Title
+		if err := rows.Scan(&t.ID, &t.Name, &t.IsActive); err != nil {
+			return nil, fmt.Errorf("ListTitles scan: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTitles rows: %w", err)
+	}
+
+	return out, nil
+}
+
+func (s *SQLiteStore) AddTitle(ctx context.Context, name string) (Title, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var t Title
+	err := s.db.QueryRowContext(ctx, `INSERT INTO titles (name) VALUES (?) RETURNING id, name, is_active`, name).Scan(&t.ID, &t.Name, &t.IsActive)
+	if err != nil {
+		return Title{}, fmt.Errorf("AddTitle: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *SQLiteStore) UpdateTitle(ctx context.Context, id int64, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE titles SET name = ?2 WHERE id = ?1`, id, name)
+	if err != nil {
+		return fmt.Errorf("UpdateTitle: %w", err)
+	}
+	return sqliteFound(res, "title")
+}
+
+func (s *SQLiteStore) SetTitleActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE titles SET is_active = ?2 WHERE id = ?1`, id, active)
+	if err != nil {
+		return fmt.Errorf("SetTitleActive: %w", err)
+	}
+	return sqliteFound(res, "title")
+}
+
+func (s *SQLiteStore) DeleteTitle(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Prevent deleting a title referenced by any game.
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM games WHERE title_id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("DeleteTitle: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("title is referenced by a game")
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM titles WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteTitle: %w", err)
+	}
+	return sqliteFound(res, "title")
+}
+
+// ============================
+// Tiebreakers
+// ============================
+
+func (s *SQLiteStore) GetTiebreaker(ctx context.Context, scope, scopeKey string) (Tiebreaker, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var raw []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM tiebreakers WHERE scope = ? AND scope_key = ?`,
+		scope, scopeKey,
+	).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Tiebreaker{}, false, nil
+		}
+		return Tiebreaker{}, false, fmt.Errorf("GetTiebreaker: %w", err)
+	}
+
+	var tb Tiebreaker
+	if err := json.Unmarshal(raw, &tb); err != nil {
+		return Tiebreaker{}, false, fmt.Errorf("GetTiebreaker unmarshal: %w", err)
+	}
+	return tb, true, nil
+}
+
+func (s *SQLiteStore) SetTiebreaker(ctx context.Context, tb Tiebreaker) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	b, err := json.Marshal(tb)
+	if err != nil {
+		return fmt.Errorf("SetTiebreaker marshal: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("SetTiebreaker begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := sqliteSetTiebreakerTx(ctx, tx, tb.Scope, tb.ScopeKey, b); err != nil {
+		return fmt.Errorf("SetTiebreaker: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("SetTiebreaker commit: %w", err)
+	}
+	return nil
+}
+
+// sqliteSetTiebreakerTx upserts the current decision for scope/scopeKey and appends it to
+// the history.
+func sqliteSetTiebreakerTx(ctx context.Context, tx *sql.Tx, scope, scopeKey string, data []byte) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO tiebreakers (scope, scope_key, data)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT (scope, scope_key)
+		 DO UPDATE SET data = excluded.data`,
+		scope, scopeKey, string(data),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO tiebreaker_history (scope, scope_key, data) VALUES (?, ?, ?)`,
+		scope, scopeKey, string(data),
+	)
+	return err
+}
+
+// ListTiebreakerHistory returns every decision recorded for scope/scopeKey, latest first.
+func (s *SQLiteStore) ListTiebreakerHistory(ctx context.Context, scope, scopeKey string) ([]Tiebreaker, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT data FROM tiebreaker_history
+		 WHERE scope = ? AND scope_key = ?
+		 ORDER BY id DESC`,
+		scope, scopeKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListTiebreakerHistory query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out, err := scanTiebreakers(rows)
+	if err != nil {
+		return nil, fmt.Errorf("ListTiebreakerHistory: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) ListTiebreakers(ctx context.Context) ([]Tiebreaker, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM tiebreakers ORDER BY scope, scope_key`)
+	if err != nil {
+		return nil, fmt.Errorf("ListTiebreakers query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out, err := scanTiebreakers(rows)
+	if err != nil {
+		return nil, fmt.Errorf("ListTiebreakers: %w", err)
+	}
+	return out, nil
+}
+
+// scanTiebreakers decodes every row of a "SELECT data" tiebreaker query.
+func scanTiebreakers(rows *sql.Rows) ([]Tiebreaker, error) {
+	var out []Tiebreaker
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		var tb Tiebreaker
+		if err := json.Unmarshal(raw, &tb); err != nil {
+			return nil, fmt.Errorf("unmarshal: %w", err)
+		}
+		out = append(out, tb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return out, nil
+}
+
+// ============================
+// Rulesets
+// ============================
+
+// ListRulesets returns the stored rulesets, oldest EffectiveFrom first. EffectiveFrom is
+// midnight of the stored date in the league time zone.
+func (s *SQLiteStore) ListRulesets(ctx context.Context) ([]Ruleset, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, effective_from, weekly, yearly
+		 FROM rulesets ORDER BY effective_from`)
+	if err != nil {
+		return nil, fmt.Errorf("ListRulesets: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Ruleset
+	for rows.Next() {
+		var r Ruleset
+		var from string
+		var weekly, yearly []byte
+		if err := rows.Scan(&r.ID, &r.Name, &from, &weekly, &yearly); err != nil {
+			return nil, fmt.Errorf("ListRulesets scan: %w", err)
+		}
+		if r.EffectiveFrom, err = time.ParseInLocation("2006-01-02", from, s.loc); err != nil {
+			return nil, fmt.Errorf("ListRulesets date: %w", err)
+		}
+		if err := json.Unmarshal(weekly, &r.Weekly); err != nil {
+			return nil, fmt.Errorf("ListRulesets unmarshal: %w", err)
+		}
+		if err := json.Unmarshal(yearly, &r.Yearly); err != nil {
+			return nil, fmt.Errorf("ListRulesets unmarshal: %w", err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListRulesets rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) AddRuleset(ctx context.Context, r Ruleset) (Ruleset, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	weekly, err := json.Marshal(r.Weekly)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset marshal: %w", err)
+	}
+	yearly, err := json.Marshal(r.Yearly)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset marshal: %w", err)
+	}
+
+	r.EffectiveFrom = leagueDate(r.EffectiveFrom, s.loc)
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO rulesets (name, effective_from, weekly, yearly)
+		 VALUES (?, ?, ?, ?) RETURNING id`,
+		r.Name, r.EffectiveFrom.Format("2006-01-02"), string(weekly), string(yearly),
+	).Scan(&r.ID)
+	if err != nil {
+		return Ruleset{}, fmt.Errorf("AddRuleset: %w", err)
+	}
+
+	return r, nil
+}
+
+func (s *SQLiteStore) DeleteRuleset(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM rulesets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteRuleset: %w", err)
+	}
+	return sqliteFound(res, "ruleset")
+}
+
+// ============================
+// Audit
+// ============================
+
+// AppendAudit records e; the database stamps its ID, and its time if unset.
+func (s *SQLiteStore) AppendAudit(ctx context.Context, e AuditEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	at := e.At
+	if at.IsZero() {
+		at = s.now()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_log (at, actor, action, entity, entity_id, before, after)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		sqliteTime(at), e.Actor, e.Action, e.Entity, e.EntityID, sqliteJSON(e.Before), sqliteJSON(e.After),
+	)
+	if err != nil {
+		return fmt.Errorf("AppendAudit: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns the entries matching f, latest first.
+func (s *SQLiteStore) ListAudit(ctx context.Context, f AuditFilter) ([]AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var from, to string
+	if !f.From.IsZero() {
+		from = sqliteTime(f.From)
+	}
+	if !f.To.IsZero() {
+		to = sqliteTime(f.To)
+	}
+	limit := -1 // no limit
+	if f.Limit > 0 {
+		limit = f.Limit
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, at, actor, action, entity, entity_id, before, after
+		 FROM audit_log
+		 WHERE (?1 = '' OR lower(actor) = lower(?1))
+		   AND (?2 = '' OR action = ?2)
+		   AND (?3 = '' OR entity = ?3)
+		   AND (?4 = '' OR entity_id = ?4)
+		   AND (?5 = '' OR at >= ?5)
+		   AND (?6 = '' OR at < ?6)
+		 ORDER BY id DESC
+		 LIMIT ?7`,
+		f.Actor, f.Action, f.Entity, f.EntityID, from, to, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListAudit query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var at string
+		if err := rows.Scan(&e.ID, &at, &e.Actor, &e.Action, &e.Entity, &e.EntityID, &e.Before, &e.After); err != nil {
+			return nil, fmt.Errorf("ListAudit scan: %w", err)
+		}
+		if e.At, err = parseSQLiteTime(at, s.loc); err != nil {
+			return nil, fmt.Errorf("ListAudit at: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAudit rows: %w", err)
+	}
+	return out, nil
+}
+
+// ============================
+// Seasons
+// ============================
+
+// ListSeasons returns the stored seasons, latest start date first.
+func (s *SQLiteStore) ListSeasons(ctx context.Context) ([]Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, start_date, end_date
+		 FROM seasons ORDER BY start_date DESC, id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListSeasons: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Season
+	for rows.Next() {
+		var se Season
+		var start, end string
+		if err := rows.Scan(&se.ID, &se.Name, &start, &end); err != nil {
+			return nil, fmt.Errorf("ListSeasons scan: %w", err)
+		}
+		if se.StartDate, err = time.ParseInLocation("2006-01-02", start, s.loc); err != nil {
+			return nil, fmt.Errorf("ListSeasons date: %w", err)
+		}
+		if se.EndDate, err = time.ParseInLocation("2006-01-02", end, s.loc); err != nil {
+			return nil, fmt.Errorf("ListSeasons date: %w", err)
+		}
+		out = append(out, se)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListSeasons rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) AddSeason(ctx context.Context, se Season) (Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	se.StartDate = leagueDate(se.StartDate, s.loc)
+	se.EndDate = leagueDate(se.EndDate, s.loc)
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO seasons (name, start_date, end_date)
+		 VALUES (?, ?, ?) RETURNING id`,
+		se.Name, se.StartDate.Format("2006-01-02"), se.EndDate.Format("2006-01-02"),
+	).Scan(&se.ID)
+	if err != nil {
+		return Season{}, fmt.Errorf("AddSeason: %w", err)
+	}
+
+	return se, nil
+}
+
+func (s *SQLiteStore) DeleteSeason(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM seasons WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteSeason: %w", err)
+	}
+	return sqliteFound(res, "season")
+}
+
+// ============================
+// Users and sessions
+// ============================
+
+func (s *SQLiteStore) scanUser(row interface{ Scan(...any) error }) (User, error) {
+	var u User
+	var createdAt string
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.PlayerID, &u.IsActive, &createdAt); err != nil {
+		return User{}, err
+	}
+	var err error
+	u.CreatedAt, err = parseSQLiteTime(createdAt, s.loc)
+	return u, err
+}
+
+// ListUsers returns every user by user name.
+func (s *SQLiteStore) ListUsers(ctx context.Context) ([]User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+userColumns+` FROM users ORDER BY lower(username)`)
+	if err != nil {
+		return nil, fmt.Errorf("ListUsers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []User
+	for rows.Next() {
+		u, err := s.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListUsers scan: %w", err)
+		}
+		out = append(out, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListUsers rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) GetUser(ctx context.Context, id int64) (User, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	u, err := s.scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, false, nil
+	}
+	if err != nil {
+		return User{}, false, fmt.Errorf("GetUser: %w", err)
+	}
+	return u, true, nil
+}
+
+// GetUserByUsername looks a user up by name, ignoring case.
+func (s *SQLiteStore) GetUserByUsername(ctx context.Context, username string) (User, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	u, err := s.scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE lower(username) = lower(?)`, username))
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, false, nil
+	}
+	if err != nil {
+		return User{}, false, fmt.Errorf("GetUserByUsername: %w", err)
+	}
+	return u, true, nil
+}
+
+func (s *SQLiteStore) AddUser(ctx context.Context, u User) (User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := s.scanUser(s.db.QueryRowContext(ctx,
+		`INSERT INTO users (username, password_hash, role, player_id, is_active, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 RETURNING `+userColumns,
+		u.Username, u.PasswordHash, u.Role, u.PlayerID, u.IsActive, sqliteTime(s.now()),
+	))
+	if err != nil {
+		return User{}, fmt.Errorf("AddUser: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateUser replaces everything but the ID, user name and creation time.
+func (s *SQLiteStore) UpdateUser(ctx context.Context, u User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = ?2, role = ?3, player_id = ?4, is_active = ?5 WHERE id = ?1`,
+		u.ID, u.PasswordHash, u.Role, u.PlayerID, u.IsActive,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateUser: %w", err)
+	}
+	return sqliteFound(res, "user")
+}
+
+// DeleteUser removes a user; their sessions go with them.
+func (s *SQLiteStore) DeleteUser(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteUser: %w", err)
+	}
+	return sqliteFound(res, "user")
+}
+
+func (s *SQLiteStore) AddSession(ctx context.Context, sess Session) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO sessions (token_hash, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		sess.TokenHash, sess.UserID, sqliteTime(s.now()), sqliteTime(sess.ExpiresAt),
+	)
+	if err != nil {
+		return fmt.Errorf("AddSession: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns the unexpired session with tokenHash.
+func (s *SQLiteStore) GetSession(ctx context.Context, tokenHash string) (Session, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var sess Session
+	var createdAt, expiresAt string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT token_hash, user_id, created_at, expires_at FROM sessions
+		 WHERE token_hash = ? AND expires_at > ?`, tokenHash, sqliteTime(s.now()),
+	).Scan(&sess.TokenHash, &sess.UserID, &createdAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, false, nil
+	}
+	if err != nil {
+		return Session{}, false, fmt.Errorf("GetSession: %w", err)
+	}
+	if sess.CreatedAt, err = parseSQLiteTime(createdAt, s.loc); err != nil {
+		return Session{}, false, fmt.Errorf("GetSession: %w", err)
+	}
+	if sess.ExpiresAt, err = parseSQLiteTime(expiresAt, s.loc); err != nil {
+		return Session{}, false, fmt.Errorf("GetSession: %w", err)
+	}
+	return sess, true, nil
+}
+
+// DeleteSession removes one session, along with any that have expired.
+func (s *SQLiteStore) DeleteSession(ctx context.Context, tokenHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ? OR expires_at <= ?`, tokenHash, sqliteTime(s.now()))
+	if err != nil {
+		return fmt.Errorf("DeleteSession: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere.
+func (s *SQLiteStore) DeleteUserSessions(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("DeleteUserSessions: %w", err)
+	}
+	return nil
+}
+
+// ============================
+// API tokens
+// ============================
+
+func (s *SQLiteStore) scanAPIToken(row interface{ Scan(...any) error }) (APIToken, error) {
+	var t APIToken
+	var createdAt string
+	var lastUsedAt, revokedAt sql.NullString
+	if err := row.Scan(&t.ID, &t.Name, &t.TokenHash, &t.Scope, &t.CreatedBy, &createdAt, &lastUsedAt, &revokedAt); err != nil {
+		return APIToken{}, err
+	}
+	var err error
+	if t.CreatedAt, err = parseSQLiteTime(createdAt, s.loc); err != nil {
+		return APIToken{}, err
+	}
+	if t.LastUsedAt, err = parseSQLiteNullTime(lastUsedAt, s.loc); err != nil {
+		return APIToken{}, err
+	}
+	if t.RevokedAt, err = parseSQLiteNullTime(revokedAt, s.loc); err != nil {
+		return APIToken{}, err
+	}
+	return t, nil
+}
+
+// ListAPITokens returns every token, newest first, revoked ones included.
+func (s *SQLiteStore) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPITokens: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []APIToken
+	for rows.Next() {
+		t, err := s.scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListAPITokens scan: %w", err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAPITokens rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) AddAPIToken(ctx context.Context, t APIToken) (APIToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := s.scanAPIToken(s.db.QueryRowContext(ctx,
+		`INSERT INTO api_tokens (name, token_hash, scope, created_by, created_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 RETURNING `+apiTokenColumns,
+		t.Name, t.TokenHash, t.Scope, t.CreatedBy, sqliteTime(s.now()),
+	))
+	if err != nil {
+		return APIToken{}, fmt.Errorf("AddAPIToken: %w", err)
+	}
+	return out, nil
+}
+
+// GetAPITokenByHash returns the token with tokenHash, revoked or not.
+func (s *SQLiteStore) GetAPITokenByHash(ctx context.Context, tokenHash string) (APIToken, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	t, err := s.scanAPIToken(s.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIToken{}, false, nil
+	}
+	if err != nil {
+		return APIToken{}, false, fmt.Errorf("GetAPITokenByHash: %w", err)
+	}
+	return t, true, nil
+}
+
+// RevokeAPIToken stops a token working. Revoking it again keeps the first revocation time.
+func (s *SQLiteStore) RevokeAPIToken(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, ?2) WHERE id = ?1`, id, sqliteTime(s.now()))
+	if err != nil {
+		return fmt.Errorf("RevokeAPIToken: %w", err)
+	}
+	return sqliteFound(res, "token")
+}
+
+// TouchAPIToken records that a token was used at at.
+func (s *SQLiteStore) TouchAPIToken(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ?2 WHERE id = ?1`, id, sqliteTime(at))
+	if err != nil {
+		return fmt.Errorf("TouchAPIToken: %w", err)
+	}
+	return sqliteFound(res, "token")
+}
+
+// ============================
+// Webhooks
+// ============================
+
+func (s *SQLiteStore) scanWebhook(row interface{ Scan(...any) error }) (Webhook, error) {
+	var h Webhook
+	var events, createdAt string
+	if err := row.Scan(&h.ID, &h.Name, &h.URL, &h.Secret, &events, &h.IsActive, &h.CreatedBy, &createdAt); err != nil {
+		return Webhook{}, err
+	}
+	if err := json.Unmarshal([]byte(events), &h.Events); err != nil {
+		return Webhook{}, err
+	}
+	var err error
+	if h.CreatedAt, err = parseSQLiteTime(createdAt, s.loc); err != nil {
+		return Webhook{}, err
+	}
+	return h, nil
+}
+
+func (s *SQLiteStore) scanDelivery(row interface{ Scan(...any) error }) (WebhookDelivery, error) {
+	var d WebhookDelivery
+	var payload, nextAttemptAt, createdAt string
+	var deliveredAt sql.NullString
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &nextAttemptAt,
+		&d.LastStatus, &d.LastError, &createdAt, &deliveredAt, &d.Key); err != nil {
+		return WebhookDelivery{}, err
+	}
+	d.Payload = []byte(payload)
+	var err error
+	if d.NextAttemptAt, err = parseSQLiteTime(nextAttemptAt, s.loc); err != nil {
+		return WebhookDelivery{}, err
+	}
+	if d.CreatedAt, err = parseSQLiteTime(createdAt, s.loc); err != nil {
+		return WebhookDelivery{}, err
+	}
+	if d.DeliveredAt, err = parseSQLiteNullTime(deliveredAt, s.loc); err != nil {
+		return WebhookDelivery{}, err
+	}
+	return d, nil
+}
+
+// ListWebhooks returns every webhook by name.
+func (s *SQLiteStore) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY lower(name), id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhooks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Webhook
+	for rows.Next() {
+		h, err := s.scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListWebhooks scan: %w", err)
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListWebhooks rows: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) AddWebhook(ctx context.Context, h Webhook) (Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if h.Events == nil {
+		h.Events = []string{}
+	}
+	events, err := json.Marshal(h.Events)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("AddWebhook: %w", err)
+	}
+	out, err := s.scanWebhook(s.db.QueryRowContext(ctx,
+		`INSERT INTO webhooks (name, url, secret, events, is_active, created_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 RETURNING `+webhookColumns,
+		h.Name, h.URL, h.Secret, string(events), h.IsActive, h.CreatedBy, sqliteTime(s.now()),
+	))
+	if err != nil {
+		return Webhook{}, fmt.Errorf("AddWebhook: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SQLiteStore) SetWebhookActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE webhooks SET is_active = ?2 WHERE id = ?1`, id, active)
+	if err != nil {
+		return fmt.Errorf("SetWebhookActive: %w", err)
+	}
+	return sqliteFound(res, "webhook")
+}
+
+// DeleteWebhook removes a webhook; its deliveries go with it (ON DELETE CASCADE).
+func (s *SQLiteStore) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+	return sqliteFound(res, "webhook")
+}
+
+// AddWebhookDelivery queues d; it is pending and due now unless d says otherwise. If the
+// webhook already has a delivery with d's Key, that one is returned and nothing is queued.
+func (s *SQLiteStore) AddWebhookDelivery(ctx context.Context, d WebhookDelivery) (WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if d.Status == "" {
+		d.Status = DeliveryPending
+	}
+	now := s.now()
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = now
+	}
+	out, err := s.scanDelivery(s.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_at, last_status, last_error, created_at, dedupe_key)
+		 SELECT id, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, NULLIF(?10, '') FROM webhooks WHERE id = ?1
+		 ON CONFLICT (webhook_id, dedupe_key) DO NOTHING
+		 RETURNING `+deliveryColumns,
+		d.WebhookID, d.Event, string(d.Payload), d.Status, d.Attempts, sqliteTime(d.NextAttemptAt),
+		d.LastStatus, d.LastError, sqliteTime(now), d.Key,
+	))
+	if errors.Is(err, sql.ErrNoRows) && d.Key != "" {
+		out, err = s.scanDelivery(s.db.QueryRowContext(ctx,
+			`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE webhook_id = ?1 AND dedupe_key = ?2`,
+			d.WebhookID, d.Key,
+		))
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return WebhookDelivery{}, errors.New("webhook not found")
+	}
+	if err != nil {
+		return WebhookDelivery{}, fmt.Errorf("AddWebhookDelivery: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateWebhookDelivery saves d's status, attempts, next attempt time, last status and
+// error, and delivery time.
+func (s *SQLiteStore) UpdateWebhookDelivery(ctx context.Context, d WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var deliveredAt any
+	if d.DeliveredAt != nil {
+		deliveredAt = sqliteTime(*d.DeliveredAt)
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = ?2, attempts = ?3, next_attempt_at = ?4, last_status = ?5, last_error = ?6, delivered_at = ?7
+		 WHERE id = ?1`,
+		d.ID, d.Status, d.Attempts, sqliteTime(d.NextAttemptAt), d.LastStatus, d.LastError, deliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateWebhookDelivery: %w", err)
+	}
+	return sqliteFound(res, "delivery")
+}
+
+// ClaimWebhookDeliveries claims up to limit pending deliveries due at now, the longest due
+// first, by moving their next attempt to now+lease, and returns them oldest first. SQLite
+// runs one write at a time, so no two workers get the same delivery.
+func (s *SQLiteStore) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	out, err := s.queryDeliveries(ctx, "ClaimWebhookDeliveries",
+		`UPDATE webhook_deliveries SET next_attempt_at = ?2
+		 WHERE id IN (SELECT id FROM webhook_deliveries
+		              WHERE status = 'pending' AND next_attempt_at <= ?1
+		              ORDER BY next_attempt_at, id
+		              LIMIT ?3)
+		 RETURNING `+deliveryColumns,
+		sqliteTime(now), sqliteTime(now.Add(lease)), sqliteLimit(limit))
+	sortDeliveries(out)
+	return out, err
+}
+
+// ListWebhookDeliveries returns the latest deliveries first, for one webhook or (webhookID
+// 0) all of them; limit 0 means no limit.
+func (s *SQLiteStore) ListWebhookDeliveries(ctx context.Context, webhookID int64, limit int) ([]WebhookDelivery, error) {
+	return s.queryDeliveries(ctx, "ListWebhookDeliveries",
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries
+		 WHERE ?1 = 0 OR webhook_id = ?1
+		 ORDER BY id DESC
+		 LIMIT ?2`,
+		webhookID, sqliteLimit(limit))
+}
+
+// sqliteLimit is a LIMIT argument where 0 means no limit (SQLite's -1).
+func sqliteLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+func (s *SQLiteStore) queryDeliveries(ctx context.Context, op, q string, args ...any) ([]WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []WebhookDelivery
+	for rows.Next() {
+		d, err := s.scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return out, nil
+}
+
+// ============================
+// Week recaps
+// ============================
+
+func (s *SQLiteStore) GetWeekRecap(ctx context.Context, year, week int) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r, err := s.scanWeekRecap(s.db.QueryRowContext(ctx,
+		`SELECT data, notified_at FROM week_recaps WHERE year = ? AND week = ?`,
+		year, week,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WeekRecap{}, false, nil
+	}
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("GetWeekRecap: %w", err)
+	}
+	return r, true, nil
+}
+
+// LastWeekRecap returns the recap of the latest week that has one, or false if none has.
+func (s *SQLiteStore) LastWeekRecap(ctx context.Context) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r, err := s.scanWeekRecap(s.db.QueryRowContext(ctx,
+		`SELECT data, notified_at FROM week_recaps ORDER BY year DESC, week DESC LIMIT 1`,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WeekRecap{}, false, nil
+	}
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("LastWeekRecap: %w", err)
+	}
+	return r, true, nil
+}
+
+func (s *SQLiteStore) scanWeekRecap(row *sql.Row) (WeekRecap, error) {
+	var raw []byte
+	var notifiedAt sql.NullString
+	if err := row.Scan(&raw, &notifiedAt); err != nil {
+		return WeekRecap{}, err
+	}
+	var r WeekRecap
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return WeekRecap{}, fmt.Errorf("unmarshal: %w", err)
+	}
+	var err error
+	if r.NotifiedAt, err = parseSQLiteNullTime(notifiedAt, s.loc); err != nil {
+		return WeekRecap{}, err
+	}
+	return r, nil
+}
+
+// AddWeekRecap stores r unless its week already has a recap, and returns the week's recap
+// and whether it is r. The first recap written for a week is the one that stays.
+func (s *SQLiteStore) AddWeekRecap(ctx context.Context, r WeekRecap) (WeekRecap, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.NotifiedAt = nil
+	b, err := json.Marshal(r)
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("AddWeekRecap marshal: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO week_recaps (year, week, data, created_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT (year, week) DO NOTHING`,
+		r.Year, r.Week, string(b), sqliteTime(r.CreatedAt),
+	)
+	if err != nil {
+		return WeekRecap{}, false, fmt.Errorf("AddWeekRecap: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 1 {
+		return r, true, nil
+	}
+	existing, _, err := s.GetWeekRecap(ctx, r.Year, r.Week)
+	return existing, false, err
+}
+
+// MarkWeekRecapNotified records that the recap of the week was handed to every notifier at at.
+func (s *SQLiteStore) MarkWeekRecapNotified(ctx context.Context, year, week int, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE week_recaps SET notified_at = ? WHERE year = ? AND week = ?`,
+		sqliteTime(at), year, week,
+	)
+	if err != nil {
+		return fmt.Errorf("MarkWeekRecapNotified: %w", err)
+	}
+	return sqliteFound(res, "recap")
+}
+
+// ============================
+// Import
+// ============================
+
+// ImportDataset adds d's missing players and titles, appends its games and upserts its
+// tiebreakers, rulesets (by start date) and seasons (by name) in a single transaction.
+func (s *SQLiteStore) ImportDataset(ctx context.Context, d Dataset) (ImportSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var sum ImportSummary
+
+	for _, p := range d.Players {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO players (name, is_active) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`,
+			p.Name, p.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset players: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset players: %w", err)
+		}
+		sum.PlayersAdded += int(n)
+	}
+	for _, t := range d.Titles {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO titles (name, is_active) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`,
+			t.Name, t.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset titles: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset titles: %w", err)
+		}
+		sum.TitlesAdded += int(n)
+	}
+
+	playerIDs, err := sqliteNameIDs(ctx, tx, `SELECT id, name FROM players`)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset players: %w", err)
+	}
+	titleIDs, err := sqliteNameIDs(ctx, tx, `SELECT id, name FROM titles`)
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset titles: %w", err)
+	}
+	resolve := func(names []string) ([]int64, error) {
+		ids := make([]int64, 0, len(names))
+		for _, n := range names {
+			id, ok := playerIDs[n]
+			if !ok {
+				return nil, fmt.Errorf("unknown player %q", n)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	for _, g := range d.Games {
+		titleID, ok := titleIDs[g.Title]
+		if !ok {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: unknown title %q", g.Title)
+		}
+		participantIDs, err := resolve(g.Participants)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		winnerIDs, err := resolve(g.Winners)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		rs, err := datasetResults(g.Results, resolve)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		ts, err := datasetTeams(g.Teams, resolve)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		stored := Game{Mode: g.Mode, ParticipantIDs: participantIDs, WinnerIDs: winnerIDs, Teams: ts, Results: rs}
+		participants, winners, teams, results, err := sqliteGameArgs(stored)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO games (title_id, played_at, mode, participant_ids, winner_ids, teams, results, notes, is_active)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			titleID, sqliteTime(g.PlayedAt), stored.GameMode(), participants, winners, teams, results, g.Notes, g.IsActive)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset games: %w", err)
+		}
+		sum.GamesAdded++
+	}
+
+	for _, dt := range d.Tiebreakers {
+		tied, err := resolve(dt.Tied)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		winner, err := resolve([]string{dt.Winner})
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		var gameID int64
+		if ref := dt.PlayoffGame; ref != nil {
+			err := tx.QueryRowContext(ctx,
+				`SELECT g.id FROM games g JOIN titles t ON t.id = g.title_id
+				 WHERE g.played_at = ? AND t.name = ?
+				 ORDER BY g.id LIMIT 1`,
+				sqliteTime(ref.PlayedAt), ref.Title).Scan(&gameID)
+			if err != nil {
+				return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: play-off game %s at %s: %w", ref.Title, ref.PlayedAt.Format(time.RFC3339), err)
+			}
+		}
+		b, err := json.Marshal(Tiebreaker{
+			Scope:         dt.Scope,
+			ScopeKey:      dt.ScopeKey,
+			TiedPlayerIDs: tied,
+			WinnerID:      winner[0],
+			Method:        dt.Method,
+			DecidedAt:     dt.DecidedAt,
+			Seed:          dt.Seed,
+			Algorithm:     dt.Algorithm,
+			GameID:        gameID,
+		})
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers marshal: %w", err)
+		}
+		if err := sqliteSetTiebreakerTx(ctx, tx, dt.Scope, dt.ScopeKey, b); err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset tiebreakers: %w", err)
+		}
+		sum.TiebreakersSet++
+	}
+
+	for _, dr := range d.Rulesets {
+		rs, err := dr.Ruleset(s.loc)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets: %w", err)
+		}
+		weekly, err := json.Marshal(rs.Weekly)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets marshal: %w", err)
+		}
+		yearly, err := json.Marshal(rs.Yearly)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets marshal: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO rulesets (name, effective_from, weekly, yearly)
+			 VALUES (?, ?, ?, ?)
+			 ON CONFLICT (effective_from)
+			 DO UPDATE SET name = excluded.name, weekly = excluded.weekly, yearly = excluded.yearly`,
+			rs.Name, rs.EffectiveFrom.Format("2006-01-02"), string(weekly), string(yearly))
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset rulesets: %w", err)
+		}
+		sum.RulesetsSet++
+	}
+
+	for _, ds := range d.Seasons {
+		se, err := ds.Season(s.loc)
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset seasons: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO seasons (name, start_date, end_date)
+			 VALUES (?, ?, ?)
+			 ON CONFLICT (name)
+			 DO UPDATE SET start_date = excluded.start_date, end_date = excluded.end_date`,
+			se.Name, se.StartDate.Format("2006-01-02"), se.EndDate.Format("2006-01-02"))
+		if err != nil {
+			return ImportSummary{}, fmt.Errorf("ImportDataset seasons: %w", err)
+		}
+		sum.SeasonsSet++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportSummary{}, fmt.Errorf("ImportDataset commit: %w", err)
+	}
+	return sum, nil
+}
+
+// sqliteNameIDs runs q, which must select (id, name), and maps each name to its ID.
+func sqliteNameIDs(ctx context.Context, tx *sql.Tx, q string) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := map[string]int64{}
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		out[name] = id
+	}
+	return out, rows.Err()
+}
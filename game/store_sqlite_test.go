@@ -0,0 +1,254 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/db"
+)
+
+// newSQLiteStore returns a store over a freshly migrated database file, which comes with the
+// seeded players and titles.
+func newSQLiteStore(t *testing.T, loc *time.Location) *SQLiteStore {
+	t.Helper()
+	sqlDB, err := db.OpenSQLite(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if _, err := db.MigrateSQLite(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	return NewSQLiteStore(sqlDB, loc)
+}
+
+func TestSQLiteStore_GamesRoundTrip(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+	title, _ := s.AddTitle(ctx, "Hanabi")
+	a, _ := s.AddPlayer(ctx, "Alice")
+	b, _ := s.AddPlayer(ctx, "Bob")
+
+	in := Game{
+		PlayedAt:       time.Date(2026, 2, 3, 12, 30, 0, 0, time.UTC),
+		TitleID:        title.ID,
+		Mode:           ModeTeam,
+		ParticipantIDs: []int64{a.ID, b.ID},
+		WinnerIDs:      []int64{a.ID},
+		Teams:          [][]int64{{a.ID}, {b.ID}},
+		Notes:          "close one",
+	}
+	g, err := s.AddGame(ctx, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	games, err := s.RecentGames(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("len = %d, want 1", len(games))
+	}
+	got := games[0]
+	if got.ID != g.ID || got.Title != "Hanabi" || got.Mode != ModeTeam || got.Notes != "close one" || !got.IsActive {
+		t.Errorf("game = %+v", got)
+	}
+	if !got.PlayedAt.Equal(in.PlayedAt) {
+		t.Errorf("PlayedAt = %v, want %v", got.PlayedAt, in.PlayedAt)
+	}
+	if len(got.ParticipantIDs) != 2 || len(got.WinnerIDs) != 1 || len(got.Teams) != 2 {
+		t.Errorf("ids = %v / %v / %v", got.ParticipantIDs, got.WinnerIDs, got.Teams)
+	}
+
+	if err := s.UpdateGame(ctx, Game{ID: 999, TitleID: title.ID, PlayedAt: in.PlayedAt}); err == nil {
+		t.Error("UpdateGame of a missing game: want error")
+	}
+}
+
+func TestSQLiteStore_GetWeek_LeagueTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := newSQLiteStore(t, loc)
+	title, _ := s.AddTitle(ctx, "Hanabi")
+
+	// Sunday night in Chicago is already Monday in UTC: the game belongs to the earlier week.
+	late := time.Date(2026, 2, 8, 21, 0, 0, 0, loc) // ISO 2026-W06
+	early := time.Date(2026, 2, 9, 9, 0, 0, 0, loc) // ISO 2026-W07
+	for _, at := range []time.Time{late, early} {
+		if _, err := s.AddGame(ctx, Game{PlayedAt: at, TitleID: title.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w6, err := s.GetWeek(ctx, 2026, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w6) != 1 || !w6[0].PlayedAt.Equal(late) {
+		t.Errorf("week 6 = %+v, want only the Sunday game", w6)
+	}
+	if w6[0].PlayedAt.Location() != loc {
+		t.Errorf("PlayedAt location = %v, want %v", w6[0].PlayedAt.Location(), loc)
+	}
+	w7, _ := s.GetWeek(ctx, 2026, 7)
+	if len(w7) != 1 || !w7[0].PlayedAt.Equal(early) {
+		t.Errorf("week 7 = %+v, want only the Monday game", w7)
+	}
+
+	_ = s.SetGameActive(ctx, w7[0].ID, false)
+	if w7, _ = s.GetWeek(ctx, 2026, 7); len(w7) != 0 {
+		t.Errorf("week 7 = %+v, want inactive games left out", w7)
+	}
+}
+
+func TestSQLiteStore_DeletePlayer_Referenced(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+	title, _ := s.AddTitle(ctx, "Hanabi")
+	a, _ := s.AddPlayer(ctx, "Alice")
+	b, _ := s.AddPlayer(ctx, "Bob")
+	c, _ := s.AddPlayer(ctx, "Carol")
+	_, _ = s.AddGame(ctx, Game{PlayedAt: day(2026, 1, 5), TitleID: title.ID, ParticipantIDs: []int64{a.ID}, WinnerIDs: []int64{b.ID}})
+
+	for _, p := range []Player{a, b} {
+		if err := s.DeletePlayer(ctx, p.ID); err == nil || err.Error() != "player is referenced by a game" {
+			t.Errorf("DeletePlayer(%s) = %v, want referenced error", p.Name, err)
+		}
+	}
+	if err := s.DeletePlayer(ctx, c.ID); err != nil {
+		t.Fatalf("DeletePlayer(Carol) = %v", err)
+	}
+	players, _ := s.ListPlayers(ctx)
+	for _, p := range players {
+		if p.ID == c.ID {
+			t.Error("Carol still listed after delete")
+		}
+	}
+}
+
+func TestSQLiteStore_Tiebreakers(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+	if _, ok, err := s.GetTiebreaker(ctx, "weekly", "2026-W01"); ok || err != nil {
+		t.Fatalf("missing tiebreaker = %v, %v; want false, nil", ok, err)
+	}
+
+	_ = s.SetTiebreaker(ctx, Tiebreaker{Scope: "weekly", ScopeKey: "2026-W01", WinnerID: 1})
+	_ = s.SetTiebreaker(ctx, Tiebreaker{Scope: "weekly", ScopeKey: "2026-W01", WinnerID: 2})
+
+	tb, ok, err := s.GetTiebreaker(ctx, "weekly", "2026-W01")
+	if err != nil || !ok || tb.WinnerID != 2 {
+		t.Errorf("tiebreaker = %+v, %v, %v; want winner 2", tb, ok, err)
+	}
+	hist, _ := s.ListTiebreakerHistory(ctx, "weekly", "2026-W01")
+	if len(hist) != 2 || hist[0].WinnerID != 2 || hist[1].WinnerID != 1 {
+		t.Errorf("history = %+v, want latest first", hist)
+	}
+}
+
+func TestSQLiteStore_UsersSessionsAndTokens(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+	u, err := s.AddUser(ctx, User{Username: "Alice", PasswordHash: "x", Role: RoleAdmin, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddUser(ctx, User{Username: "alice", PasswordHash: "x", Role: RoleViewer}); err == nil {
+		t.Error("duplicate user name (ignoring case): want error")
+	}
+	got, ok, _ := s.GetUserByUsername(ctx, "ALICE")
+	if !ok || got.ID != u.ID || got.PlayerID != nil || got.CreatedAt.IsZero() {
+		t.Errorf("GetUserByUsername = %+v, %v", got, ok)
+	}
+
+	now := time.Now()
+	_ = s.AddSession(ctx, Session{TokenHash: "live", UserID: u.ID, ExpiresAt: now.Add(time.Hour)})
+	_ = s.AddSession(ctx, Session{TokenHash: "old", UserID: u.ID, ExpiresAt: now.Add(-time.Hour)})
+	if _, ok, _ := s.GetSession(ctx, "live"); !ok {
+		t.Error("live session not found")
+	}
+	if _, ok, _ := s.GetSession(ctx, "old"); ok {
+		t.Error("expired session found")
+	}
+	_ = s.DeleteUser(ctx, u.ID)
+	if _, ok, _ := s.GetSession(ctx, "live"); ok {
+		t.Error("session outlived its user")
+	}
+
+	tok, err := s.AddAPIToken(ctx, APIToken{Name: "bot", TokenHash: "h", Scope: ScopeRead, CreatedBy: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.TouchAPIToken(ctx, tok.ID, now)
+	if err := s.RevokeAPIToken(ctx, tok.ID); err != nil {
+		t.Fatal(err)
+	}
+	got2, ok, _ := s.GetAPITokenByHash(ctx, "h")
+	if !ok || got2.LastUsedAt == nil || got2.RevokedAt == nil || got2.Active() {
+		t.Errorf("token = %+v", got2)
+	}
+	if err := s.RevokeAPIToken(ctx, 999); err == nil {
+		t.Error("RevokeAPIToken of a missing token: want error")
+	}
+}
+
+func TestSQLiteStore_ImportDataset(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+
+	d := Dataset{
+		Players: []DatasetPlayer{{Name: "Alice", IsActive: true}, {Name: "ESMITH", IsActive: true}},
+		Titles:  []DatasetTitle{{Name: "Coup", IsActive: true}},
+		Games: []DatasetGame{{
+			PlayedAt: day(2026, 1, 5), Title: "Coup",
+			Participants: []string{"Alice", "ESMITH"}, Winners: []string{"ESMITH"}, IsActive: true,
+			Results: []DatasetResult{{Player: "ESMITH", Position: 1}, {Player: "Alice", Position: 2}},
+		}},
+		Tiebreakers: []DatasetTiebreaker{{
+			Scope: "weekly", ScopeKey: "2026-W02", Tied: []string{"Alice", "ESMITH"}, Winner: "Alice",
+			Method: MethodPlayoff, PlayoffGame: &DatasetGameRef{PlayedAt: day(2026, 1, 5), Title: "Coup"},
+		}},
+		Seasons: []DatasetSeason{{Name: "Spring", StartDate: "2026-03-01", EndDate: "2026-05-31"}},
+	}
+	sum, err := s.ImportDataset(ctx, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ESMITH and Coup are seeded by the first migration.
+	if sum.PlayersAdded != 1 || sum.TitlesAdded != 0 || sum.GamesAdded != 1 || sum.TiebreakersSet != 1 || sum.SeasonsSet != 1 {
+		t.Errorf("summary = %+v", sum)
+	}
+
+	games, _ := s.ListGames(ctx)
+	if len(games) != 1 || len(games[0].Results) != 2 {
+		t.Fatalf("games = %+v", games)
+	}
+	tb, ok, _ := s.GetTiebreaker(ctx, "weekly", "2026-W02")
+	if !ok || tb.GameID != games[0].ID {
+		t.Errorf("tiebreaker = %+v, %v; want play-off game %d", tb, ok, games[0].ID)
+	}
+
+	// A dataset naming an unknown player changes nothing.
+	if _, err := s.ImportDataset(ctx, Dataset{
+		Players:     []DatasetPlayer{{Name: "Zed", IsActive: true}},
+		Seasons:     []DatasetSeason{{Name: "Summer", StartDate: "2026-06-01", EndDate: "2026-08-31"}},
+		Tiebreakers: []DatasetTiebreaker{{Scope: "yearly", ScopeKey: "2026", Tied: []string{"Nobody"}, Winner: "Nobody"}},
+	}); err == nil {
+		t.Fatal("want error for unknown player")
+	}
+	seasons, _ := s.ListSeasons(ctx)
+	if len(seasons) != 1 {
+		t.Errorf("seasons = %+v, want the failed import rolled back", seasons)
+	}
+}
+
+func TestSQLiteStore_TimesMatchColumnDefaults(t *testing.T) {
+	s := newSQLiteStore(t, time.UTC)
+	var def string
+	if err := s.db.QueryRowContext(ctx, `SELECT strftime('%Y-%m-%dT%H:%M:%fZ', 'now')`).Scan(&def); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 2, 3, 12, 30, 0, 123456789, time.UTC)
+	if got := sqliteTime(at); len(got) != len(def) || got != "2026-02-03T12:30:00.123Z" {
+		t.Errorf("sqliteTime = %q, want the width of the column default %q", got, def)
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLeagueTimezone is used when LEAGUE_TZ is not set.
+const DefaultLeagueTimezone = "America/Chicago"
+
+// LoadLeagueLocation loads the IANA time zone that weeks, years and weekdays are judged in.
+// An empty name means DefaultLeagueTimezone.
+func LoadLeagueLocation(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultLeagueTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("league timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// WeekBounds returns the half-open interval [start, end) covering ISO week `week` of ISO
+// year `year`, from Monday 00:00 to the following Monday 00:00 in loc.
+func WeekBounds(year, week int, loc *time.Location) (time.Time, time.Time) {
+	// Jan 4 is always in ISO week 1; step back to that week's Monday.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	start := jan4.AddDate(0, 0, -offset+(week-1)*7)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// WeekClose is when ISO week `week` of ISO year `year` is over for the league: the end of
+// its Friday (Saturday 00:00) in loc, as only Monday to Friday games count.
+func WeekClose(year, week int, loc *time.Location) time.Time {
+	start, _ := WeekBounds(year, week, loc)
+	return start.AddDate(0, 0, 5)
+}
+
+// LastClosedWeek returns the ISO year and week of the most recent week to have closed (see
+// WeekClose) by now, judged in loc.
+func LastClosedWeek(now time.Time, loc *time.Location) (int, int) {
+	now = now.In(loc)
+	year, week := now.ISOWeek()
+	if now.Before(WeekClose(year, week, loc)) {
+		year, week = now.AddDate(0, 0, -7).ISOWeek()
+	}
+	return year, week
+}
+
+// NextWeekClose returns the first WeekClose after now, judged in loc.
+func NextWeekClose(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	year, week := now.ISOWeek()
+	if c := WeekClose(year, week, loc); now.Before(c) {
+		return c
+	}
+	year, week = now.AddDate(0, 0, 7).ISOWeek()
+	return WeekClose(year, week, loc)
+}
+
+// YearBounds returns the half-open interval [start, end) covering calendar year `year` in loc.
+func YearBounds(year int, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(1, 0, 0)
+}
@@ -0,0 +1,126 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func chicago(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := LoadLeagueLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestLoadLeagueLocation(t *testing.T) {
+	loc, err := LoadLeagueLocation("")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	if loc.String() != DefaultLeagueTimezone {
+		t.Errorf("default = %s, want %s", loc, DefaultLeagueTimezone)
+	}
+	if _, err := LoadLeagueLocation("Not/AZone"); err == nil {
+		t.Error("expected error for unknown zone")
+	}
+}
+
+func TestWeekBounds(t *testing.T) {
+	loc := chicago(t)
+
+	cases := []struct {
+		name       string
+		year, week int
+		wantStart  string
+	}{
+		{"2026 W01 starts in 2025", 2026, 1, "2025-12-29"},
+		{"2026 W02", 2026, 2, "2026-01-05"},
+		{"2020 has 53 weeks", 2020, 53, "2020-12-28"},
+		{"2021 W01 starts in 2021", 2021, 1, "2021-01-04"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := WeekBounds(tc.year, tc.week, loc)
+			if got := start.Format("2006-01-02 15:04 MST"); got != tc.wantStart+" 00:00 CST" {
+				t.Errorf("start = %s, want %s 00:00 CST", got, tc.wantStart)
+			}
+			if end.Sub(start) != 7*24*time.Hour {
+				t.Errorf("end - start = %s, want 168h", end.Sub(start))
+			}
+			if y, w := start.ISOWeek(); y != tc.year || w != tc.week {
+				t.Errorf("start is ISO %d-W%02d, want %d-W%02d", y, w, tc.year, tc.week)
+			}
+		})
+	}
+}
+
+func TestWeekBounds_LateNightStaysInLeagueWeek(t *testing.T) {
+	loc := chicago(t)
+
+	// Sunday 2026-01-04 23:30 in Chicago is already Monday (W02) in UTC.
+	lateSunday := time.Date(2026, 1, 4, 23, 30, 0, 0, loc)
+	start, end := WeekBounds(2026, 1, loc)
+	if lateSunday.Before(start) || !lateSunday.Before(end) {
+		t.Errorf("%s should fall in 2026-W01 [%s, %s)", lateSunday, start, end)
+	}
+	if _, w := lateSunday.UTC().ISOWeek(); w != 2 {
+		t.Fatalf("test premise: UTC week = %d, want 2", w)
+	}
+}
+
+func TestWeekClose(t *testing.T) {
+	loc := chicago(t)
+
+	// 2026-W02 runs Monday Jan 5 to Friday Jan 9; it closes at Saturday midnight in Chicago.
+	got := WeekClose(2026, 2, loc)
+	if want := time.Date(2026, 1, 10, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("WeekClose = %s, want %s", got, want)
+	}
+	if got.Weekday() != time.Saturday {
+		t.Errorf("WeekClose weekday = %s, want Saturday", got.Weekday())
+	}
+}
+
+func TestLastClosedWeekAndNextWeekClose(t *testing.T) {
+	loc := chicago(t)
+
+	cases := []struct {
+		now       time.Time
+		wantYear  int
+		wantWeek  int
+		wantClose time.Time
+	}{
+		// Friday night of 2026-W02: the week before is the last closed.
+		{time.Date(2026, 1, 9, 23, 59, 0, 0, loc), 2026, 1, time.Date(2026, 1, 10, 0, 0, 0, 0, loc)},
+		// Saturday midnight closes W02.
+		{time.Date(2026, 1, 10, 0, 0, 0, 0, loc), 2026, 2, time.Date(2026, 1, 17, 0, 0, 0, 0, loc)},
+		// Sunday is still after W02's close.
+		{time.Date(2026, 1, 11, 12, 0, 0, 0, loc), 2026, 2, time.Date(2026, 1, 17, 0, 0, 0, 0, loc)},
+		// Monday of 2026-W01 looks back across the ISO year to 2025-W52.
+		{time.Date(2025, 12, 29, 9, 0, 0, 0, loc), 2025, 52, time.Date(2026, 1, 3, 0, 0, 0, 0, loc)},
+	}
+	for _, c := range cases {
+		if y, w := LastClosedWeek(c.now, loc); y != c.wantYear || w != c.wantWeek {
+			t.Errorf("LastClosedWeek(%s) = %d-W%02d, want %d-W%02d", c.now, y, w, c.wantYear, c.wantWeek)
+		}
+		if got := NextWeekClose(c.now.UTC(), loc); !got.Equal(c.wantClose) {
+			t.Errorf("NextWeekClose(%s) = %s, want %s", c.now, got, c.wantClose)
+		}
+	}
+}
+
+func TestYearBounds(t *testing.T) {
+	loc := chicago(t)
+
+	start, end := YearBounds(2026, loc)
+	// New Year's Eve at 11pm in Chicago is already next year in UTC.
+	nye := time.Date(2026, 12, 31, 23, 0, 0, 0, loc)
+	if nye.Before(start) || !nye.Before(end) {
+		t.Errorf("%s should fall in 2026 [%s, %s)", nye, start, end)
+	}
+	if nye.UTC().Year() != 2027 {
+		t.Fatalf("test premise: UTC year = %d, want 2027", nye.UTC().Year())
+	}
+}
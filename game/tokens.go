@@ -0,0 +1,76 @@
+package game
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIToken is a named credential for scripts and bots, sent as "Authorization: Bearer ...".
+// Only a hash of the token is stored; the token itself is shown once, when it is issued.
+type APIToken struct {
+	ID         int64
+	Name       string // what it's for, e.g. "chat bot"
+	TokenHash  string // HashAPIToken of the token
+	Scope      string // ScopeRead or ScopeWriteGames
+	CreatedBy  string // user name of the admin who issued it
+	CreatedAt  time.Time
+	LastUsedAt *time.Time // nil until first used
+	RevokedAt  *time.Time // nil while the token works
+}
+
+// API token scopes.
+const (
+	ScopeRead       = "read"        // everything a viewer can read
+	ScopeWriteGames = "write:games" // also log and edit games, like a recorder
+)
+
+// APITokenScopes lists the scopes, narrowest first.
+var APITokenScopes = []string{ScopeRead, ScopeWriteGames}
+
+// Role is the user role whose access the token's scope grants, or "" for an unknown scope.
+func (t APIToken) Role() string {
+	switch t.Scope {
+	case ScopeRead:
+		return RoleViewer
+	case ScopeWriteGames:
+		return RoleRecorder
+	}
+	return ""
+}
+
+// Active reports whether the token can still be used.
+func (t APIToken) Active() bool {
+	return t.RevokedAt == nil
+}
+
+// Validate checks the fields a token needs before it is stored; messages are user-facing.
+func (t APIToken) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("Please name the token after what will use it.")
+	}
+	if t.Role() == "" {
+		return errors.New("Please choose a valid scope.")
+	}
+	return nil
+}
+
+// apiTokenPrefix marks API tokens so they are easy to spot in scripts and secret scanners.
+const apiTokenPrefix = "mog_"
+
+// NewAPIToken returns a random token to hand to a script.
+func NewAPIToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewAPIToken: %w", err)
+	}
+	return apiTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIToken is the stored form of an API token.
+func HashAPIToken(token string) string {
+	return hashToken(token)
+}
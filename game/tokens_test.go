@@ -0,0 +1,35 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAPIToken_RoleAndValidate(t *testing.T) {
+	if r := (APIToken{Scope: ScopeRead}).Role(); r != RoleViewer {
+		t.Errorf("read scope role = %q, want viewer", r)
+	}
+	if r := (APIToken{Scope: ScopeWriteGames}).Role(); r != RoleRecorder {
+		t.Errorf("write:games scope role = %q, want recorder", r)
+	}
+	if err := (APIToken{Name: "bot", Scope: "admin"}).Validate(); err == nil {
+		t.Error("Validate accepted an unknown scope")
+	}
+	if err := (APIToken{Name: " ", Scope: ScopeRead}).Validate(); err == nil {
+		t.Error("Validate accepted a blank name")
+	}
+}
+
+func TestNewAPIToken(t *testing.T) {
+	a, err := NewAPIToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewAPIToken()
+	if !strings.HasPrefix(a, "mog_") || a == b {
+		t.Errorf("tokens %q and %q: want distinct mog_ tokens", a, b)
+	}
+	if HashAPIToken(a) == a || HashAPIToken(a) != HashAPIToken(a) {
+		t.Error("HashAPIToken should be a stable hash, not the token")
+	}
+}
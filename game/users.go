@@ -0,0 +1,151 @@
+package game
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User is a person who can sign in. PlayerID optionally links the account to the Player
+// it plays as.
+type User struct {
+	ID           int64
+	Username     string // unique, case-insensitive
+	PasswordHash string // see HashPassword
+	Role         string // RoleViewer, RoleRecorder or RoleAdmin
+	PlayerID     *int64
+	IsActive     bool // inactive users can't sign in
+	CreatedAt    time.Time
+}
+
+// Roles, least to most trusted. Each role can do everything the ones before it can.
+const (
+	RoleViewer   = "viewer"   // browse standings, stats and history
+	RoleRecorder = "recorder" // also log and edit games
+	RoleAdmin    = "admin"    // also manage players, titles, tiebreakers, rules, seasons, imports and users
+)
+
+// Roles lists the roles in order of trust.
+var Roles = []string{RoleViewer, RoleRecorder, RoleAdmin}
+
+// RoleAllows reports whether role has at least the access of need. Unknown roles allow nothing.
+func RoleAllows(role, need string) bool {
+	have, want := roleRank(role), roleRank(need)
+	return have >= 0 && want >= 0 && have >= want
+}
+
+func roleRank(role string) int {
+	for i, r := range Roles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate checks the fields a user needs before it is stored; messages are user-facing.
+func (u User) Validate() error {
+	if strings.TrimSpace(u.Username) == "" {
+		return errors.New("Please enter a user name.")
+	}
+	if strings.ContainsAny(u.Username, " \t\r\n:") {
+		return errors.New("User names can't contain spaces or colons.")
+	}
+	if roleRank(u.Role) < 0 {
+		return errors.New("Please choose a valid role.")
+	}
+	return nil
+}
+
+// MinPasswordLength is the shortest password HashPassword accepts.
+const MinPasswordLength = 8
+
+// Passwords are hashed with PBKDF2-HMAC-SHA256 and stored as
+// "pbkdf2-sha256$<iterations>$<salt>$<key>" (salt and key base64, unpadded).
+const (
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 600_000
+	passwordSaltLen    = 16
+	passwordKeyLen     = 32
+)
+
+// HashPassword returns a salted hash of password for User.PasswordHash.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("Passwords must be at least %d characters.", MinPasswordLength)
+	}
+	salt := make([]byte, passwordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("HashPassword: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLen)
+	if err != nil {
+		return "", fmt.Errorf("HashPassword: %w", err)
+	}
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%s$%d$%s$%s", passwordScheme, passwordIterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// CheckPassword reports whether password matches a hash from HashPassword.
+func CheckPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter < 1 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// Session is a signed-in browser. Only a hash of the cookie token is stored, so a leaked
+// sessions table can't be used to sign in.
+type Session struct {
+	TokenHash string // HashSessionToken of the cookie value
+	UserID    int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// NewSessionToken returns a random cookie value for a new session.
+func NewSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewSessionToken: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashSessionToken is the stored form of a session cookie value.
+func HashSessionToken(token string) string {
+	return hashToken(token)
+}
+
+// hashToken is the SHA-256 of a random token, in hex. The tokens are long and random, so a
+// plain hash is enough; unlike passwords they don't need a slow one.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role, need string
+		want       bool
+	}{
+		{RoleAdmin, RoleViewer, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleRecorder, RoleViewer, true},
+		{RoleRecorder, RoleAdmin, false},
+		{RoleViewer, RoleRecorder, false},
+		{"owner", RoleViewer, false},
+		{RoleAdmin, "owner", false},
+	}
+	for _, tc := range cases {
+		if got := RoleAllows(tc.role, tc.need); got != tc.want {
+			t.Errorf("RoleAllows(%q, %q) = %v, want %v", tc.role, tc.need, got, tc.want)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$") || strings.Contains(hash, "correct horse") {
+		t.Errorf("hash = %q, want a pbkdf2-sha256 hash without the password", hash)
+	}
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("CheckPassword rejected the right password")
+	}
+	if CheckPassword(hash, "correct horsE") {
+		t.Error("CheckPassword accepted the wrong password")
+	}
+	if again, _ := HashPassword("correct horse"); again == hash {
+		t.Error("two hashes of one password are equal; want a fresh salt each time")
+	}
+
+	if _, err := HashPassword("short"); err == nil {
+		t.Error("HashPassword accepted a password under MinPasswordLength")
+	}
+	for _, bad := range []string{"", "plain", "pbkdf2-sha256$x$y$z", "md5$1$AA$AA"} {
+		if CheckPassword(bad, "correct horse") {
+			t.Errorf("CheckPassword(%q) = true, want false", bad)
+		}
+	}
+}
+
+func TestUserValidate(t *testing.T) {
+	ok := User{Username: "alice", Role: RoleViewer}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	for _, u := range []User{
+		{Username: " ", Role: RoleViewer},
+		{Username: "al ice", Role: RoleViewer},
+		{Username: "al:ice", Role: RoleViewer},
+		{Username: "alice", Role: "owner"},
+	} {
+		if err := u.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", u)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook events, named "<thing>.<what happened>".
+const (
+	WebhookGameLogged        = "game.logged"         // a game was added
+	WebhookWeekWinnerDecided = "week.winner_decided" // a tiebreaker settled a week
+	WebhookTieNeedsBreaking  = "tie.needs_breaking"  // a finished week or year is tied
+	WebhookWeekRecap         = "week.recap"          // a week closed and its recap was written
+)
+
+// WebhookEvents lists the events a webhook can subscribe to.
+var WebhookEvents = []string{WebhookGameLogged, WebhookWeekWinnerDecided, WebhookTieNeedsBreaking, WebhookWeekRecap}
+
+// Webhook is an outgoing subscription: every event it wants is POSTed to URL as JSON,
+// signed with Secret (see SignWebhook).
+type Webhook struct {
+	ID        int64
+	Name      string   // what it posts to, e.g. "league chat"
+	URL       string   // http or https endpoint
+	Secret    string   // HMAC key shared with the receiver
+	Events    []string // subset of WebhookEvents
+	IsActive  bool
+	CreatedBy string // user name of the admin who added it
+	CreatedAt time.Time
+}
+
+// Wants reports whether the webhook should receive event.
+func (h Webhook) Wants(event string) bool {
+	return h.IsActive && slices.Contains(h.Events, event)
+}
+
+// Validate checks the fields a webhook needs before it is stored; messages are user-facing.
+func (h Webhook) Validate() error {
+	if strings.TrimSpace(h.Name) == "" {
+		return errors.New("Please name the webhook after where it posts.")
+	}
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("Please enter an http:// or https:// URL.")
+	}
+	if len(h.Events) == 0 {
+		return errors.New("Please choose at least one event.")
+	}
+	for _, e := range h.Events {
+		if !slices.Contains(WebhookEvents, e) {
+			return fmt.Errorf("Unknown event %q.", e)
+		}
+	}
+	return nil
+}
+
+// webhookSecretPrefix marks webhook secrets so they are easy to tell from API tokens.
+const webhookSecretPrefix = "whsec_"
+
+// NewWebhookSecret returns a random signing secret for a new webhook.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewWebhookSecret: %w", err)
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SignWebhook is the X-MOG-Signature header for body: "sha256=" and the hex HMAC-SHA256
+// of the exact request body under secret.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"   // waiting for its first or next attempt
+	DeliveryDelivered = "delivered" // the receiver answered 2xx
+	DeliveryFailed    = "failed"    // gave up after WebhookMaxAttempts
+)
+
+// WebhookDelivery is one event queued for one webhook, and the log of trying to send it.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	Event         string
+	Payload       []byte // JSON body, sent byte for byte on every attempt
+	Status        string // DeliveryPending, DeliveryDelivered or DeliveryFailed
+	Attempts      int
+	NextAttemptAt time.Time // when a pending delivery is due
+	LastStatus    int       // HTTP status of the last attempt; 0 if it got no response
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time // nil until delivered
+	// Key names what the delivery announces, e.g. "tie.needs_breaking:2026-W07:[1 2]". A
+	// webhook gets at most one delivery per key; deliveries without one are never deduplicated.
+	Key string
+}
+
+// WebhookMaxAttempts is how many times a delivery is tried before it is marked failed.
+const WebhookMaxAttempts = 6
+
+// WebhookBackoff is the wait after failed attempt n (1-based): 30s, doubling each time,
+// so six attempts span about 15 minutes.
+func WebhookBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	return 30 * time.Second << (attempt - 1)
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhook_ValidateAndWants(t *testing.T) {
+	ok := Webhook{Name: "chat", URL: "https://chat.example/hook", Events: []string{WebhookGameLogged}, IsActive: true}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	for name, h := range map[string]Webhook{
+		"blank name":    {Name: " ", URL: ok.URL, Events: ok.Events},
+		"ftp url":       {Name: "x", URL: "ftp://chat.example", Events: ok.Events},
+		"relative url":  {Name: "x", URL: "/hook", Events: ok.Events},
+		"no events":     {Name: "x", URL: ok.URL},
+		"unknown event": {Name: "x", URL: ok.URL, Events: []string{"game.deleted"}},
+	} {
+		if err := h.Validate(); err == nil {
+			t.Errorf("%s: Validate accepted %+v", name, h)
+		}
+	}
+
+	if !ok.Wants(WebhookGameLogged) || ok.Wants(WebhookTieNeedsBreaking) {
+		t.Error("Wants should follow the event filter")
+	}
+	ok.IsActive = false
+	if ok.Wants(WebhookGameLogged) {
+		t.Error("an inactive webhook wants nothing")
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '{"event":"game.logged"}' | openssl dgst -sha256 -hmac whsec_test
+	got := SignWebhook("whsec_test", []byte(`{"event":"game.logged"}`))
+	if want := "sha256=6e1d56676fca961086a5b5f4fd0cdc83cc019a645550ab58c0d20df1b2179802"; got != want {
+		t.Fatalf("signature = %q, want %q", got, want)
+	}
+	if got == SignWebhook("whsec_other", []byte(`{"event":"game.logged"}`)) {
+		t.Error("signature ignores the secret")
+	}
+
+	a, err := NewWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := NewWebhookSecret(); !strings.HasPrefix(a, "whsec_") || a == b {
+		t.Errorf("secrets %q and %q: want distinct whsec_ secrets", a, b)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, w := range want {
+		if got := WebhookBackoff(i + 1); got != w {
+			t.Errorf("WebhookBackoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
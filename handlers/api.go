@@ -0,0 +1,1167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// The /api/v1 surface mirrors the HTML pages for scripts and bots.
+// Responses are JSON; failures use apiErrorBody with the HTTP status repeated in the body.
+
+const maxAPIBodyBytes = 1 << 20
+
+type apiErrorBody struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Status  int           `json:"status"`
+	Message string        `json:"message"`
+	Rows    []apiRowError `json:"rows,omitempty"` // per-row import problems
+}
+
+type apiRowError struct {
+	Table   string `json:"table"`
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type apiImportSummary struct {
+	PlayersAdded   int `json:"players_added"`
+	TitlesAdded    int `json:"titles_added"`
+	GamesAdded     int `json:"games_added"`
+	GamesSkipped   int `json:"games_skipped"`
+	TiebreakersSet int `json:"tiebreakers_set"`
+	RulesetsSet    int `json:"rulesets_set"`
+	SeasonsSet     int `json:"seasons_set"`
+}
+
+type apiGame struct {
+	ID             int64       `json:"id"`
+	PlayedAt       time.Time   `json:"played_at"`
+	TitleID        int64       `json:"title_id"`
+	Title          string      `json:"title"`
+	Mode           string      `json:"mode"` // "competitive" | "team" | "coop"
+	ParticipantIDs []int64     `json:"participant_ids"`
+	WinnerIDs      []int64     `json:"winner_ids"`
+	Teams          [][]int64   `json:"teams"`
+	Results        []apiResult `json:"results"`
+	Notes          string      `json:"notes"`
+	IsActive       bool        `json:"is_active"`
+}
+
+// apiResult is one participant's placement and/or score; omitted parts weren't recorded.
+type apiResult struct {
+	PlayerID int64 `json:"player_id"`
+	Position int   `json:"position,omitempty"`
+	Score    *int  `json:"score,omitempty"`
+}
+
+// apiGameRequest is the body for creating or editing a game.
+// played_at accepts "2006-01-02T15:04" (as the forms send) or RFC 3339.
+type apiGameRequest struct {
+	TitleID        int64       `json:"title_id"`
+	PlayedAt       string      `json:"played_at"`
+	Mode           string      `json:"mode"` // optional, defaults to "competitive"
+	ParticipantIDs []int64     `json:"participant_ids"`
+	WinnerIDs      []int64     `json:"winner_ids"` // co-op: everyone, or [] if the table lost
+	Teams          [][]int64   `json:"teams"`      // team games only
+	Results        []apiResult `json:"results"`    // optional
+	Notes          string      `json:"notes"`
+}
+
+type apiPlayer struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type apiTitle struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type apiWeekStandings struct {
+	Year            int            `json:"year"`
+	Week            int            `json:"week"`
+	ScopeKey        string         `json:"scope_key"`
+	TotalGames      int            `json:"total_games"`
+	Wins            map[int64]int  `json:"wins"`
+	TotalWins       int            `json:"total_wins"`
+	TopIDs          []int64        `json:"top_ids"`
+	WinnerID        *int64         `json:"winner_id"`
+	TieUnresolved   bool           `json:"tie_unresolved"`
+	DecidedBy       string         `json:"decided_by,omitempty"`
+	TiebreakerStale bool           `json:"tiebreaker_stale"`
+	Tiebreaker      *apiTiebreaker `json:"tiebreaker"`
+	Ruleset         apiPeriodRules `json:"ruleset"`
+}
+
+type apiWeekRecap struct {
+	Year          int              `json:"year"`
+	Week          int              `json:"week"`
+	ScopeKey      string           `json:"scope_key"`
+	Text          string           `json:"text"`
+	TotalGames    int              `json:"total_games"`
+	Wins          map[int64]int    `json:"wins"`
+	TopIDs        []int64          `json:"top_ids"`
+	WinnerID      *int64           `json:"winner_id"`
+	TieUnresolved bool             `json:"tie_unresolved"`
+	DecidedBy     string           `json:"decided_by,omitempty"`
+	Titles        []apiRecapTitle  `json:"titles"`
+	Streaks       []apiRecapStreak `json:"streaks"`
+	ClosedAt      time.Time        `json:"closed_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type apiRecapTitle struct {
+	TitleID int64  `json:"title_id"`
+	Title   string `json:"title"`
+	Plays   int    `json:"plays"`
+}
+
+type apiRecapStreak struct {
+	PlayerID int64  `json:"player_id"`
+	Kind     string `json:"kind"` // "game_wins" | "weekly_titles"
+	Length   int    `json:"length"`
+}
+
+type apiPlayerYearStats struct {
+	PlayerID    int64   `json:"player_id"`
+	Attendance  int     `json:"attendance"`
+	GamesPlayed int     `json:"games_played"`
+	Wins        int     `json:"wins"`
+	WinRate     float64 `json:"win_rate"` // percent, one decimal
+	Qualified   bool    `json:"qualified"`
+
+	PlacedGames   int     `json:"placed_games"`
+	AvgPercentile float64 `json:"avg_percentile"` // average finishing percentile, one decimal
+}
+
+type apiYearStandings struct {
+	Year            int                  `json:"year"`
+	ScopeKey        string               `json:"scope_key"`
+	Stats           []apiPlayerYearStats `json:"stats"`
+	Qualifiers      []int64              `json:"qualifiers"`
+	TopIDs          []int64              `json:"top_ids"`
+	WinnerID        *int64               `json:"winner_id"`
+	TieUnresolved   bool                 `json:"tie_unresolved"`
+	DecidedBy       string               `json:"decided_by,omitempty"`
+	TiebreakerStale bool                 `json:"tiebreaker_stale"`
+	Tiebreaker      *apiTiebreaker       `json:"tiebreaker"`
+	Ruleset         apiPeriodRules       `json:"ruleset"`
+}
+
+// apiPeriodRules is the ruleset that decided a week or year, or one period of a stored ruleset.
+type apiPeriodRules struct {
+	Name          string `json:"name,omitempty"`
+	Metric        string `json:"metric"`
+	Qualifier     string `json:"qualifier"`
+	MinAttendance int    `json:"min_attendance"`
+	TiePolicy     string `json:"tie_policy"`
+}
+
+type apiRuleset struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	EffectiveFrom string         `json:"effective_from"` // YYYY-MM-DD in league time
+	Weekly        apiPeriodRules `json:"weekly"`
+	Yearly        apiPeriodRules `json:"yearly"`
+}
+
+type apiSeason struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD in league time
+	EndDate   string `json:"end_date"`   // inclusive
+}
+
+type apiSeasonStandings struct {
+	Season          apiSeason            `json:"season"`
+	ScopeKey        string               `json:"scope_key"`
+	Stats           []apiPlayerYearStats `json:"stats"`
+	Qualifiers      []int64              `json:"qualifiers"`
+	TopIDs          []int64              `json:"top_ids"`
+	WinnerID        *int64               `json:"winner_id"`
+	TieUnresolved   bool                 `json:"tie_unresolved"`
+	DecidedBy       string               `json:"decided_by,omitempty"`
+	TiebreakerStale bool                 `json:"tiebreaker_stale"`
+	Tiebreaker      *apiTiebreaker       `json:"tiebreaker"`
+	Ruleset         apiPeriodRules       `json:"ruleset"`
+}
+
+type apiRaceSeries struct {
+	PlayerID int64     `json:"player_id"`
+	Name     string    `json:"name"`
+	Values   []float64 `json:"values"`
+}
+
+type apiYearRace struct {
+	Year   int             `json:"year"`
+	Metric string          `json:"metric"`
+	Weeks  []int           `json:"weeks"`
+	Series []apiRaceSeries `json:"series"`
+}
+
+type apiTiebreaker struct {
+	Scope         string    `json:"scope"`
+	ScopeKey      string    `json:"scope_key"`
+	TiedPlayerIDs []int64   `json:"tied_player_ids"`
+	WinnerID      int64     `json:"winner_id"`
+	Method        string    `json:"method"`
+	DecidedAt     time.Time `json:"decided_at"`
+	Seed          string    `json:"seed,omitempty"`
+	Algorithm     string    `json:"algorithm,omitempty"`
+	GameID        int64     `json:"game_id,omitempty"`
+}
+
+type apiAuditEntry struct {
+	ID       int64           `json:"id"`
+	At       time.Time       `json:"at"`
+	Actor    string          `json:"actor"`
+	Action   string          `json:"action"`
+	Entity   string          `json:"entity"`
+	EntityID string          `json:"entity_id"`
+	Before   json.RawMessage `json:"before"` // null when the entity didn't exist
+	After    json.RawMessage `json:"after"`  // null when it was deleted
+}
+
+// apiDrawCheck is the result of re-running a server-drawn tiebreaker.
+type apiDrawCheck struct {
+	Tiebreaker    *apiTiebreaker `json:"tiebreaker"`
+	DrawnWinnerID int64          `json:"drawn_winner_id"`
+	Verified      bool           `json:"verified"`
+}
+
+// apiTiebreakRequest names the winner, sets Draw for the server to draw one, or names the
+// play-off game that settled the tie.
+type apiTiebreakRequest struct {
+	WinnerID int64 `json:"winner_id"`
+	Draw     bool  `json:"draw"`
+	GameID   int64 `json:"game_id"`
+}
+
+func (req apiTiebreakRequest) choice() tiebreakChoice {
+	return tiebreakChoice{WinnerID: req.WinnerID, Draw: req.Draw, GameID: req.GameID}
+}
+
+func toAPIGame(g game.Game) apiGame {
+	return apiGame{
+		ID:             g.ID,
+		PlayedAt:       g.PlayedAt,
+		TitleID:        g.TitleID,
+		Title:          g.Title,
+		Mode:           g.GameMode(),
+		ParticipantIDs: nonNilIDs(g.ParticipantIDs),
+		WinnerIDs:      nonNilIDs(g.WinnerIDs),
+		Teams:          nonNilTeams(g.Teams),
+		Results:        toAPIResults(g.Results),
+		Notes:          g.Notes,
+		IsActive:       g.IsActive,
+	}
+}
+
+func nonNilTeams(teams [][]int64) [][]int64 {
+	out := make([][]int64, 0, len(teams))
+	for _, team := range teams {
+		out = append(out, nonNilIDs(team))
+	}
+	return out
+}
+
+func toAPIResults(rs []game.Result) []apiResult {
+	out := make([]apiResult, 0, len(rs))
+	for _, r := range rs {
+		out = append(out, apiResult{PlayerID: r.PlayerID, Position: r.Position, Score: r.Score})
+	}
+	return out
+}
+
+func toAPIPeriodRules(name string, r game.PeriodRules) apiPeriodRules {
+	return apiPeriodRules{
+		Name:          name,
+		Metric:        r.Metric,
+		Qualifier:     r.Qualifier,
+		MinAttendance: r.MinAttendance,
+		TiePolicy:     r.TiePolicy,
+	}
+}
+
+func toAPIStats(stats []game.PlayerYearStats) []apiPlayerYearStats {
+	out := make([]apiPlayerYearStats, 0, len(stats))
+	for _, st := range stats {
+		out = append(out, apiPlayerYearStats{
+			PlayerID:    st.PlayerID,
+			Attendance:  st.Attendance,
+			GamesPlayed: st.GamesPlayed,
+			Wins:        st.Wins,
+			WinRate:     st.WinRate,
+			Qualified:   st.Qualified,
+
+			PlacedGames:   st.PlacedGames,
+			AvgPercentile: st.AvgPercentile,
+		})
+	}
+	return out
+}
+
+func toAPISeason(se game.Season) apiSeason {
+	return apiSeason{
+		ID:        se.ID,
+		Name:      se.Name,
+		StartDate: se.StartDate.Format("2006-01-02"),
+		EndDate:   se.EndDate.Format("2006-01-02"),
+	}
+}
+
+func toAPITiebreaker(tb game.Tiebreaker) *apiTiebreaker {
+	return &apiTiebreaker{
+		Scope:         tb.Scope,
+		ScopeKey:      tb.ScopeKey,
+		TiedPlayerIDs: nonNilIDs(tb.TiedPlayerIDs),
+		WinnerID:      tb.WinnerID,
+		Method:        tb.Method,
+		DecidedAt:     tb.DecidedAt,
+		Seed:          tb.Seed,
+		Algorithm:     tb.Algorithm,
+		GameID:        tb.GameID,
+	}
+}
+
+// toAPIWeekRecap converts r; text is its summary for chat (see recapText).
+func toAPIWeekRecap(r game.WeekRecap, text string) apiWeekRecap {
+	out := apiWeekRecap{
+		Year:          r.Year,
+		Week:          r.Week,
+		ScopeKey:      r.ScopeKey,
+		Text:          text,
+		TotalGames:    r.TotalGames,
+		Wins:          r.Wins,
+		TopIDs:        nonNilIDs(r.TopIDs),
+		WinnerID:      r.WinnerID,
+		TieUnresolved: r.TieUnresolved,
+		DecidedBy:     r.DecidedBy,
+		Titles:        make([]apiRecapTitle, 0, len(r.Titles)),
+		Streaks:       make([]apiRecapStreak, 0, len(r.Streaks)),
+		ClosedAt:      r.ClosedAt,
+		CreatedAt:     r.CreatedAt,
+	}
+	if out.Wins == nil {
+		out.Wins = map[int64]int{}
+	}
+	for _, t := range r.Titles {
+		out.Titles = append(out.Titles, apiRecapTitle{TitleID: t.TitleID, Title: t.Title, Plays: t.Plays})
+	}
+	for _, st := range r.Streaks {
+		out.Streaks = append(out.Streaks, apiRecapStreak{PlayerID: st.PlayerID, Kind: st.Kind, Length: st.Length})
+	}
+	return out
+}
+
+// nonNilIDs keeps empty ID lists as [] rather than null in responses.
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, apiErrorBody{Error: apiError{Status: status, Message: msg}})
+}
+
+// decodeJSON reads a single JSON object into dst, rejecting unknown fields and trailing data.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return errors.New("invalid JSON body: " + err.Error())
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.New("invalid JSON body: expected a single object")
+	}
+	return nil
+}
+
+func (s *Server) registerAPIRoutes(mux *http.ServeMux) {
+	viewer, recorder, admin := s.role(game.RoleViewer), s.role(game.RoleRecorder), s.role(game.RoleAdmin)
+
+	mux.HandleFunc("GET /api/v1/games", viewer(s.handleAPIGames))
+	mux.HandleFunc("POST /api/v1/games", recorder(s.handleAPIAddGame))
+	mux.HandleFunc("PUT /api/v1/games/{id}", recorder(s.handleAPIUpdateGame))
+
+	mux.HandleFunc("GET /api/v1/players", viewer(s.handleAPIPlayers))
+	mux.HandleFunc("GET /api/v1/titles", viewer(s.handleAPITitles))
+
+	mux.HandleFunc("GET /api/v1/weeks/{year}/{week}", viewer(s.handleAPIWeek))
+	mux.HandleFunc("GET /api/v1/weeks/{year}/{week}/recap", viewer(s.handleAPIWeekRecap))
+	mux.HandleFunc("POST /api/v1/weeks/{year}/{week}/tiebreak", admin(s.handleAPIWeekTiebreak))
+	mux.HandleFunc("GET /api/v1/years/{year}", viewer(s.handleAPIYear))
+	mux.HandleFunc("POST /api/v1/years/{year}/tiebreak", admin(s.handleAPIYearTiebreak))
+	mux.HandleFunc("GET /api/v1/years/{year}/race", viewer(s.handleAPIYearRace))
+
+	mux.HandleFunc("GET /api/v1/seasons", viewer(s.handleAPISeasons))
+	mux.HandleFunc("POST /api/v1/seasons", admin(s.handleAPIAddSeason))
+	mux.HandleFunc("GET /api/v1/seasons/{id}", viewer(s.handleAPISeason))
+	mux.HandleFunc("POST /api/v1/seasons/{id}/tiebreak", admin(s.handleAPISeasonTiebreak))
+
+	mux.HandleFunc("GET /api/v1/tiebreakers/{scope}/{key}", viewer(s.handleAPITiebreaker))
+	mux.HandleFunc("GET /api/v1/tiebreakers/{scope}/{key}/history", viewer(s.handleAPITiebreakerHistory))
+	mux.HandleFunc("GET /api/v1/tiebreakers/{scope}/{key}/verify", viewer(s.handleAPITiebreakerVerify))
+
+	mux.HandleFunc("GET /api/v1/rulesets", viewer(s.handleAPIRulesets))
+	mux.HandleFunc("POST /api/v1/rulesets", admin(s.handleAPIAddRuleset))
+
+	mux.HandleFunc("GET /api/v1/audit", viewer(s.handleAPIAudit))
+
+	mux.HandleFunc("GET /api/v1/export", viewer(s.handleAPIExport))
+	mux.HandleFunc("POST /api/v1/import", admin(s.handleAPIImport))
+
+	// Unknown GETs under /api/ get a JSON 404 rather than the HTML home page.
+	mux.HandleFunc("GET /api/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusNotFound, "not found")
+	})
+}
+
+// ============================
+// Games
+// ============================
+
+func (s *Server) handleAPIGames(w http.ResponseWriter, r *http.Request) {
+	limit := 25
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	games, err := s.store.RecentGames(r.Context(), limit)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiGame, 0, len(games))
+	for _, g := range games {
+		out = append(out, toAPIGame(g))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPIAddGame(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.decodeAPIGame(w, r, 0)
+	if !ok {
+		return
+	}
+
+	g, err := s.store.AddGame(r.Context(), g)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Unable to save game.")
+		return
+	}
+	g.IsActive = true
+	s.publishGame(r.Context(), EventGameAdded, g)
+	writeJSON(w, http.StatusCreated, toAPIGame(g))
+}
+
+func (s *Server) handleAPIUpdateGame(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	old, found, err := s.store.GetGame(r.Context(), id)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Unable to load game.")
+		return
+	}
+	if !found {
+		writeJSONError(w, http.StatusNotFound, "game not found")
+		return
+	}
+	g, ok := s.decodeAPIGame(w, r, old.TitleID)
+	if !ok {
+		return
+	}
+
+	g.ID = id
+	if err := s.store.UpdateGame(r.Context(), g); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Unable to update game.")
+		return
+	}
+	s.publishGame(r.Context(), EventGameUpdated, g, old.PlayedAt)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeAPIGame decodes and validates a game body with the same rules as the home page form.
+// When updating a game, currentTitleID is its stored title, which it may keep even if inactive.
+// On failure it writes the error response and returns false.
+func (s *Server) decodeAPIGame(w http.ResponseWriter, r *http.Request, currentTitleID int64) (game.Game, bool) {
+	var req apiGameRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return game.Game{}, false
+	}
+
+	titles, err := s.store.ListTitles(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Unable to load title list.")
+		return game.Game{}, false
+	}
+
+	var results []game.Result
+	for _, r := range req.Results {
+		results = append(results, game.Result{PlayerID: r.PlayerID, Position: r.Position, Score: r.Score})
+	}
+
+	g, err := validateGame(gameInput{
+		TitleID:        req.TitleID,
+		PlayedAt:       req.PlayedAt,
+		Mode:           req.Mode,
+		ParticipantIDs: req.ParticipantIDs,
+		WinnerIDs:      req.WinnerIDs,
+		Teams:          req.Teams,
+		Results:        results,
+		Notes:          req.Notes,
+	}, editableTitles(titles, currentTitleID), s.loc)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return game.Game{}, false
+	}
+
+	for _, t := range titles {
+		if t.ID == g.TitleID {
+			g.Title = t.Name
+		}
+	}
+	return g, true
+}
+
+// ============================
+// Players / Titles
+// ============================
+
+func (s *Server) handleAPIPlayers(w http.ResponseWriter, r *http.Request) {
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiPlayer, 0, len(players))
+	for _, p := range players {
+		out = append(out, apiPlayer{ID: p.ID, Name: p.Name, IsActive: p.IsActive})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPITitles(w http.ResponseWriter, r *http.Request) {
+	titles, err := s.store.ListTitles(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiTitle, 0, len(titles))
+	for _, t := range titles {
+		out = append(out, apiTitle{ID: t.ID, Name: t.Name, IsActive: t.IsActive})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// ============================
+// Standings
+// ============================
+
+func (s *Server) handleAPIWeek(w http.ResponseWriter, r *http.Request) {
+	year, ok1 := pathInt(r, "year")
+	week, ok2 := pathInt(r, "week")
+	if !ok1 || !ok2 || week < 1 || week > 53 {
+		writeJSONError(w, http.StatusNotFound, "unknown week")
+		return
+	}
+
+	out, err := s.apiWeek(r.Context(), year, week)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleAPIWeekRecap returns the recap written when the week closed; 404 until then.
+func (s *Server) handleAPIWeekRecap(w http.ResponseWriter, r *http.Request) {
+	year, ok1 := pathInt(r, "year")
+	week, ok2 := pathInt(r, "week")
+	if !ok1 || !ok2 || week < 1 || week > 53 {
+		writeJSONError(w, http.StatusNotFound, "unknown week")
+		return
+	}
+	ctx := r.Context()
+
+	recap, ok, err := s.store.GetWeekRecap(ctx, year, week)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "no recap for this week")
+		return
+	}
+	pMap, err := s.playerMap(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIWeekRecap(recap, recapText(recap, pMap)))
+}
+
+// apiWeek computes a week's standings in their API shape; webhook payloads use it too.
+func (s *Server) apiWeek(ctx context.Context, year, week int) (apiWeekStandings, error) {
+	games, err := s.store.GetWeek(ctx, year, week)
+	if err != nil {
+		return apiWeekStandings{}, err
+	}
+
+	start, _ := game.WeekBounds(year, week, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		return apiWeekStandings{}, err
+	}
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	ws := game.ComputeWeekStandings(games, year, week, s.loc, rules.Weekly, getTB)
+
+	out := apiWeekStandings{
+		Year:            ws.Year,
+		Week:            ws.Week,
+		ScopeKey:        ws.ScopeKey,
+		TotalGames:      ws.TotalGames,
+		Wins:            ws.Wins,
+		TotalWins:       ws.TotalWins,
+		TopIDs:          nonNilIDs(ws.TopIDs),
+		WinnerID:        ws.WinnerID,
+		TieUnresolved:   ws.TieUnresolved,
+		DecidedBy:       ws.DecidedBy,
+		TiebreakerStale: ws.StaleTiebreaker != nil,
+		Ruleset:         toAPIPeriodRules(rules.Name, rules.Weekly),
+	}
+	tb, ok, err := s.store.GetTiebreaker(ctx, "weekly", ws.ScopeKey)
+	if err != nil {
+		return apiWeekStandings{}, err
+	}
+	if ok {
+		out.Tiebreaker = toAPITiebreaker(tb)
+	}
+	return out, nil
+}
+
+func (s *Server) handleAPIYear(w http.ResponseWriter, r *http.Request) {
+	year, ok := pathInt(r, "year")
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown year")
+		return
+	}
+
+	out, err := s.apiYear(r.Context(), year)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// apiYear computes a year's standings in their API shape; webhook payloads use it too.
+func (s *Server) apiYear(ctx context.Context, year int) (apiYearStandings, error) {
+	games, err := s.store.GetYear(ctx, year)
+	if err != nil {
+		return apiYearStandings{}, err
+	}
+
+	start, _ := game.YearBounds(year, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		return apiYearStandings{}, err
+	}
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	ys := game.ComputeYearStandings(games, year, s.loc, rules.Yearly, getTB)
+
+	out := apiYearStandings{
+		Year:            ys.Year,
+		ScopeKey:        ys.ScopeKey,
+		Stats:           toAPIStats(ys.Stats),
+		Qualifiers:      nonNilIDs(ys.Qualifiers),
+		TopIDs:          nonNilIDs(ys.TopIDs),
+		WinnerID:        ys.WinnerID,
+		TieUnresolved:   ys.TieUnresolved,
+		DecidedBy:       ys.DecidedBy,
+		TiebreakerStale: ys.StaleTiebreaker != nil,
+		Ruleset:         toAPIPeriodRules(rules.Name, rules.Yearly),
+	}
+	tb, ok, err := s.store.GetTiebreaker(ctx, "yearly", ys.ScopeKey)
+	if err != nil {
+		return apiYearStandings{}, err
+	}
+	if ok {
+		out.Tiebreaker = toAPITiebreaker(tb)
+	}
+	return out, nil
+}
+
+func (s *Server) handleAPIYearRace(w http.ResponseWriter, r *http.Request) {
+	year, ok := pathInt(r, "year")
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown year")
+		return
+	}
+
+	topN := 5
+	if v := r.URL.Query().Get("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeJSONError(w, http.StatusBadRequest, "top must be a positive integer")
+			return
+		}
+		topN = n
+	}
+
+	games, err := s.store.GetYear(r.Context(), year)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	race := game.ComputeYearRace(games, year, s.loc, game.RaceMetricWins, topN, players)
+
+	out := apiYearRace{
+		Year:   race.Year,
+		Metric: string(game.RaceMetricWins),
+		Weeks:  race.Weeks,
+		Series: make([]apiRaceSeries, 0, len(race.Series)),
+	}
+	if out.Weeks == nil {
+		out.Weeks = []int{}
+	}
+	for _, se := range race.Series {
+		out.Series = append(out.Series, apiRaceSeries{PlayerID: se.PlayerID, Name: se.Name, Values: se.Values})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// ============================
+// Tiebreakers
+// ============================
+
+func (s *Server) handleAPITiebreaker(w http.ResponseWriter, r *http.Request) {
+	scope := r.PathValue("scope")
+	key := r.PathValue("key")
+	if scope != "weekly" && scope != "yearly" && scope != "season" {
+		writeJSONError(w, http.StatusNotFound, "scope must be weekly, yearly or season")
+		return
+	}
+
+	tb, ok, err := s.store.GetTiebreaker(r.Context(), scope, key)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "tiebreaker not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITiebreaker(tb))
+}
+
+// handleAPITiebreakerHistory lists every decision recorded for a period, latest first.
+func (s *Server) handleAPITiebreakerHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := s.store.ListTiebreakerHistory(r.Context(), r.PathValue("scope"), r.PathValue("key"))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(history) == 0 {
+		writeJSONError(w, http.StatusNotFound, "tiebreaker not found")
+		return
+	}
+
+	out := make([]*apiTiebreaker, 0, len(history))
+	for _, tb := range history {
+		out = append(out, toAPITiebreaker(tb))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPITiebreakerVerify(w http.ResponseWriter, r *http.Request) {
+	tb, ok, err := s.store.GetTiebreaker(r.Context(), r.PathValue("scope"), r.PathValue("key"))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "tiebreaker not found")
+		return
+	}
+
+	drawnID, err := game.VerifyDraw(tb)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, apiDrawCheck{
+		Tiebreaker:    toAPITiebreaker(tb),
+		DrawnWinnerID: drawnID,
+		Verified:      drawnID == tb.WinnerID,
+	})
+}
+
+func (s *Server) handleAPIWeekTiebreak(w http.ResponseWriter, r *http.Request) {
+	year, ok1 := pathInt(r, "year")
+	week, ok2 := pathInt(r, "week")
+	if !ok1 || !ok2 || week < 1 || week > 53 {
+		writeJSONError(w, http.StatusNotFound, "unknown week")
+		return
+	}
+
+	var req apiTiebreakRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.setWeekTiebreaker(r.Context(), year, week, req.choice()); err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	tb, _, err := s.store.GetTiebreaker(r.Context(), "weekly", game.WeekScopeKey(year, week))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITiebreaker(tb))
+}
+
+func (s *Server) handleAPIYearTiebreak(w http.ResponseWriter, r *http.Request) {
+	year, ok := pathInt(r, "year")
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown year")
+		return
+	}
+
+	var req apiTiebreakRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.setYearTiebreaker(r.Context(), year, req.choice()); err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	tb, _, err := s.store.GetTiebreaker(r.Context(), "yearly", game.YearScopeKey(year))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITiebreaker(tb))
+}
+
+// ============================
+// Seasons
+// ============================
+
+func (s *Server) handleAPISeasons(w http.ResponseWriter, r *http.Request) {
+	seasons, err := s.store.ListSeasons(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiSeason, 0, len(seasons))
+	for _, se := range seasons {
+		out = append(out, toAPISeason(se))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPIAddSeason(w http.ResponseWriter, r *http.Request) {
+	var req apiSeason
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	se, err := game.DatasetSeason{Name: strings.TrimSpace(req.Name), StartDate: req.StartDate, EndDate: req.EndDate}.Season(s.loc)
+	if err == nil {
+		err = se.Validate()
+	}
+	if err == nil {
+		se, err = s.addSeason(r.Context(), se)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAPISeason(se))
+}
+
+// pathAPISeason resolves the {id} path value to a stored season, writing a JSON 404 if there is none.
+func (s *Server) pathAPISeason(w http.ResponseWriter, r *http.Request) (game.Season, bool) {
+	id, err := pathInt64(r, "id")
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, "unknown season")
+		return game.Season{}, false
+	}
+	se, ok, err := s.season(r.Context(), id)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return game.Season{}, false
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown season")
+		return game.Season{}, false
+	}
+	return se, true
+}
+
+func (s *Server) handleAPISeason(w http.ResponseWriter, r *http.Request) {
+	se, ok := s.pathAPISeason(w, r)
+	if !ok {
+		return
+	}
+
+	ss, rules, err := s.seasonStandings(r.Context(), se)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := apiSeasonStandings{
+		Season:          toAPISeason(se),
+		ScopeKey:        ss.ScopeKey,
+		Stats:           toAPIStats(ss.Stats),
+		Qualifiers:      nonNilIDs(ss.Qualifiers),
+		TopIDs:          nonNilIDs(ss.TopIDs),
+		WinnerID:        ss.WinnerID,
+		TieUnresolved:   ss.TieUnresolved,
+		DecidedBy:       ss.DecidedBy,
+		TiebreakerStale: ss.StaleTiebreaker != nil,
+		Ruleset:         toAPIPeriodRules(rules.Name, rules.Yearly),
+	}
+	tb, ok, err := s.store.GetTiebreaker(r.Context(), "season", ss.ScopeKey)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if ok {
+		out.Tiebreaker = toAPITiebreaker(tb)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPISeasonTiebreak(w http.ResponseWriter, r *http.Request) {
+	se, ok := s.pathAPISeason(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiTiebreakRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.setSeasonTiebreaker(r.Context(), se, req.choice()); err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	tb, _, err := s.store.GetTiebreaker(r.Context(), "season", se.ScopeKey())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITiebreaker(tb))
+}
+
+// ============================
+// Rulesets
+// ============================
+
+func (s *Server) handleAPIRulesets(w http.ResponseWriter, r *http.Request) {
+	rulesets, err := s.store.ListRulesets(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiRuleset, 0, len(rulesets))
+	for _, rs := range rulesets {
+		out = append(out, toAPIRuleset(rs, s.loc))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPIAddRuleset(w http.ResponseWriter, r *http.Request) {
+	var req apiRuleset
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	period := func(p apiPeriodRules) game.PeriodRules {
+		return game.PeriodRules{Metric: p.Metric, Qualifier: p.Qualifier, MinAttendance: p.MinAttendance, TiePolicy: p.TiePolicy}
+	}
+	rs := game.Ruleset{Name: strings.TrimSpace(req.Name), Weekly: period(req.Weekly), Yearly: period(req.Yearly)}
+	if req.EffectiveFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", req.EffectiveFrom, s.loc)
+		if err != nil {
+			writeJSONError(w, http.StatusUnprocessableEntity, "effective_from must look like 2026-01-31.")
+			return
+		}
+		rs.EffectiveFrom = from
+	}
+	if err := rs.Validate(); err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	rs, err := s.addRuleset(r.Context(), rs)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAPIRuleset(rs, s.loc))
+}
+
+func toAPIRuleset(rs game.Ruleset, loc *time.Location) apiRuleset {
+	return apiRuleset{
+		ID:            rs.ID,
+		Name:          rs.Name,
+		EffectiveFrom: rs.EffectiveFrom.In(loc).Format("2006-01-02"),
+		Weekly:        toAPIPeriodRules("", rs.Weekly),
+		Yearly:        toAPIPeriodRules("", rs.Yearly),
+	}
+}
+
+// ============================
+// Audit
+// ============================
+
+// handleAPIAudit lists audit entries, latest first, filtered like the audit page.
+func (s *Server) handleAPIAudit(w http.ResponseWriter, r *http.Request) {
+	log, ok := s.auditLog()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "this server doesn't keep an audit log")
+		return
+	}
+	f, _, err := s.parseAuditFilter(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	entries, err := log.ListAudit(r.Context(), f)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := make([]apiAuditEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, apiAuditEntry{
+			ID:       e.ID,
+			At:       e.At,
+			Actor:    e.Actor,
+			Action:   e.Action,
+			Entity:   e.Entity,
+			EntityID: e.EntityID,
+			Before:   e.Before, // a nil snapshot encodes as null
+			After:    e.After,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// ============================
+// Export / import
+// ============================
+
+func (s *Server) handleAPIExport(w http.ResponseWriter, r *http.Request) {
+	d, err := game.LoadDataset(r.Context(), s.store, time.Now().UTC())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// handleAPIImport takes a dataset in the export format. Any row error rejects the whole import
+// with 422 and the problems listed in error.rows.
+func (s *Server) handleAPIImport(w http.ResponseWriter, r *http.Request) {
+	d, err := game.ReadDatasetJSON(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sum, rowErrs, err := s.importDataset(r.Context(), d)
+	if len(rowErrs) > 0 {
+		body := apiErrorBody{Error: apiError{
+			Status:  http.StatusUnprocessableEntity,
+			Message: "Nothing was imported: some rows have errors.",
+		}}
+		for _, re := range rowErrs {
+			body.Error.Rows = append(body.Error.Rows, apiRowError{Table: re.Table, Row: re.Row, Message: re.Message})
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, body)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Unable to import dataset.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIImportSummary(sum))
+}
+
+func toAPIImportSummary(sum game.ImportSummary) apiImportSummary {
+	return apiImportSummary{
+		PlayersAdded:   sum.PlayersAdded,
+		TitlesAdded:    sum.TitlesAdded,
+		GamesAdded:     sum.GamesAdded,
+		GamesSkipped:   sum.GamesSkipped,
+		TiebreakersSet: sum.TiebreakersSet,
+		RulesetsSet:    sum.RulesetsSet,
+		SeasonsSet:     sum.SeasonsSet,
+	}
+}
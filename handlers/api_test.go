@@ -0,0 +1,814 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// newAPITestServer serves the JSON API over a seeded MemoryStore (no templates needed).
+func newAPITestServer() http.Handler {
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC}
+	return apiTestHandler(s, "admin", game.RoleAdmin)
+}
+
+// apiTestHandler serves s's API to a new user with role, signed in by session cookie.
+// An empty username serves it to an anonymous client.
+func apiTestHandler(s *Server, username, role string) http.Handler {
+	mux := http.NewServeMux()
+	s.registerAPIRoutes(mux)
+	if username == "" {
+		return mux
+	}
+
+	ctx := context.Background()
+	u, err := s.store.AddUser(ctx, game.User{Username: username, Role: role, IsActive: true})
+	if err != nil {
+		panic(err)
+	}
+	token := "token-" + username
+	sess := game.Session{TokenHash: game.HashSessionToken(token), UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.store.AddSession(ctx, sess); err != nil {
+		panic(err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: sessionCookie, Value: token})
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func doJSON(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func decodeAPIError(t *testing.T, w *httptest.ResponseRecorder) apiError {
+	t.Helper()
+	var body apiErrorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body is not JSON: %v (%s)", err, w.Body.String())
+	}
+	if body.Error.Status != w.Code {
+		t.Errorf("error.status = %d, want %d", body.Error.Status, w.Code)
+	}
+	return body.Error
+}
+
+func TestAPI_AddGame(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (%s)", w.Code, w.Body.String())
+	}
+
+	var g apiGame
+	if err := json.Unmarshal(w.Body.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+	if g.ID == 0 || g.Title != "Bang" || !g.IsActive {
+		t.Errorf("created game = %+v", g)
+	}
+}
+
+func TestAPI_AddGame_Results(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2],
+		  "results":[{"player_id":1,"position":2,"score":10},{"player_id":2,"position":1,"score":31}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (%s)", w.Code, w.Body.String())
+	}
+	var g apiGame
+	if err := json.Unmarshal(w.Body.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Results) != 2 || g.Results[0].PlayerID != 2 || *g.Results[0].Score != 31 {
+		t.Errorf("results = %+v, want player 2 first", g.Results)
+	}
+
+	w = doJSON(t, h, "GET", "/api/v1/years/2026", "")
+	var ys apiYearStandings
+	if err := json.Unmarshal(w.Body.Bytes(), &ys); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range ys.Stats {
+		if st.PlacedGames != 1 || (st.PlayerID == 2) != (st.AvgPercentile == 100) {
+			t.Errorf("stats = %+v", st)
+		}
+	}
+}
+
+func TestAPI_AddGame_Modes(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","mode":"coop","participant_ids":[1,2],"winner_ids":[]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("coop loss: status = %d, want 201 (%s)", w.Code, w.Body.String())
+	}
+	var g apiGame
+	if err := json.Unmarshal(w.Body.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Mode != "coop" || len(g.WinnerIDs) != 0 {
+		t.Errorf("coop game = %+v", g)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","mode":"team","participant_ids":[1,2,3],"winner_ids":[1],"teams":[[1,2],[3]]}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("partial team win: status = %d, want 422", w.Code)
+	}
+	if e := decodeAPIError(t, w); e.Message != "Winners must be exactly one whole team." {
+		t.Errorf("message = %q", e.Message)
+	}
+}
+
+func TestAPI_AddGame_ValidationMatchesForm(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1],"winner_ids":[2]}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", w.Code)
+	}
+	if e := decodeAPIError(t, w); e.Message != "Winners must also be selected as participants." {
+		t.Errorf("message = %q", e.Message)
+	}
+}
+
+func TestAPI_AddGame_BadJSON(t *testing.T) {
+	h := newAPITestServer()
+
+	for _, body := range []string{`{"title_id":`, `{"title":"Coup"}`, `{} {}`} {
+		w := doJSON(t, h, "POST", "/api/v1/games", body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %q: status = %d, want 400", body, w.Code)
+		}
+		decodeAPIError(t, w)
+	}
+}
+
+func TestAPI_UpdateGameKeepsInactiveTitle(t *testing.T) {
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC}
+	h := apiTestHandler(s, "admin", game.RoleAdmin)
+	addTestGames(t, h, `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`)
+	if err := s.store.SetTitleActive(context.Background(), 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The game can still be edited on its own title, but not moved to another inactive one.
+	w := doJSON(t, h, "PUT", "/api/v1/games/1",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("edit on deactivated title: status = %d, want 204 (%s)", w.Code, w.Body.String())
+	}
+	if err := s.store.SetTitleActive(context.Background(), 2, false); err != nil {
+		t.Fatal(err)
+	}
+	w = doJSON(t, h, "PUT", "/api/v1/games/1",
+		`{"title_id":2,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("move to another deactivated title: status = %d, want 422", w.Code)
+	}
+	w = doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("new game on deactivated title: status = %d, want 422", w.Code)
+	}
+}
+
+func TestAPI_WeekStandingsAndTiebreak(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-06T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := doJSON(t, h, "GET", "/api/v1/years/2026", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var ys apiYearStandings
+	if err := json.Unmarshal(w.Body.Bytes(), &ys); err != nil {
+		t.Fatal(err)
+	}
+	if !ys.TieUnresolved || len(ys.TopIDs) != 2 {
+		t.Fatalf("year standings = %+v, want unresolved two-way tie", ys)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/years/2026/tiebreak", `{"winner_id":99}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("bad winner: status = %d, want 422", w.Code)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/years/2026/tiebreak", `{"winner_id":2}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = doJSON(t, h, "GET", "/api/v1/tiebreakers/yearly/2026", "")
+	var tb apiTiebreaker
+	if err := json.Unmarshal(w.Body.Bytes(), &tb); err != nil {
+		t.Fatal(err)
+	}
+	if tb.WinnerID != 2 || tb.Method != "chance" {
+		t.Errorf("tiebreaker = %+v", tb)
+	}
+}
+
+// brokenTiebreakerStore fails every tiebreaker lookup, as a database that is down would.
+type brokenTiebreakerStore struct{ Store }
+
+func (brokenTiebreakerStore) GetTiebreaker(context.Context, string, string) (game.Tiebreaker, bool, error) {
+	return game.Tiebreaker{}, false, errors.New("connection refused")
+}
+
+func TestAPI_TiebreakerStoreErrorIsNotNotFound(t *testing.T) {
+	s := &Server{store: brokenTiebreakerStore{game.NewMemoryStore(time.UTC)}, loc: time.UTC}
+	h := apiTestHandler(s, "admin", game.RoleAdmin)
+
+	for _, path := range []string{
+		"/api/v1/tiebreakers/weekly/2026-W02", "/api/v1/tiebreakers/weekly/2026-W02/verify",
+		"/api/v1/weeks/2026/2", "/api/v1/years/2026",
+	} {
+		if w := doJSON(t, h, "GET", path, ""); w.Code != http.StatusInternalServerError {
+			t.Errorf("GET %s: status = %d, want 500", path, w.Code)
+		}
+	}
+}
+
+func TestAPI_UpdateUnknownGameIsNotFound(t *testing.T) {
+	h := newAPITestServer()
+	w := doJSON(t, h, "PUT", "/api/v1/games/42", `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown game: status = %d (%s), want 404", w.Code, w.Body.String())
+	}
+}
+
+func TestAPI_DrawnTiebreakVerifies(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2,3],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-06T12:00","participant_ids":[1,2,3],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2026-01-07T12:00","participant_ids":[1,2,3],"winner_ids":[3]}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"draw":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("draw: status = %d (%s)", w.Code, w.Body.String())
+	}
+	var tb apiTiebreaker
+	if err := json.Unmarshal(w.Body.Bytes(), &tb); err != nil {
+		t.Fatal(err)
+	}
+	if tb.Seed == "" || tb.Algorithm != game.DrawAlgorithm || len(tb.TiedPlayerIDs) != 3 || !containsInt64(tb.TiedPlayerIDs, tb.WinnerID) {
+		t.Fatalf("drawn tiebreaker = %+v", tb)
+	}
+
+	w = doJSON(t, h, "GET", "/api/v1/tiebreakers/weekly/2026-W02/verify", "")
+	var check apiDrawCheck
+	if err := json.Unmarshal(w.Body.Bytes(), &check); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !check.Verified || check.DrawnWinnerID != tb.WinnerID {
+		t.Errorf("verify: status = %d, check = %+v", w.Code, check)
+	}
+
+	// A hand-picked winner has nothing to verify.
+	w = doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"winner_id":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("pick: status = %d (%s)", w.Code, w.Body.String())
+	}
+	if w = doJSON(t, h, "GET", "/api/v1/tiebreakers/weekly/2026-W02/verify", ""); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("verify hand-picked: status = %d, want 422", w.Code)
+	}
+}
+
+func TestAPI_PlayoffTiebreak(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2,3],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-06T12:00","participant_ids":[1,2,3],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2026-01-12T12:00","participant_ids":[1,3],"winner_ids":[1]}`,   // game 3: 2 missing
+		`{"title_id":1,"played_at":"2026-01-12T13:00","participant_ids":[1,2,3],"winner_ids":[2]}`, // game 4: the play-off
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"game_id":3}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("play-off without every tied player: status = %d, want 422", w.Code)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"game_id":4}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("play-off: status = %d (%s)", w.Code, w.Body.String())
+	}
+	var tb apiTiebreaker
+	if err := json.Unmarshal(w.Body.Bytes(), &tb); err != nil {
+		t.Fatal(err)
+	}
+	if tb.Method != game.MethodPlayoff || tb.GameID != 4 || tb.WinnerID != 2 {
+		t.Errorf("tiebreaker = %+v, want play-off game 4 won by 2", tb)
+	}
+
+	var ws apiWeekStandings
+	if err := json.Unmarshal(doJSON(t, h, "GET", "/api/v1/weeks/2026/2", "").Body.Bytes(), &ws); err != nil {
+		t.Fatal(err)
+	}
+	if ws.WinnerID == nil || *ws.WinnerID != 2 || ws.TieUnresolved {
+		t.Errorf("week standings = %+v, want 2 by play-off", ws)
+	}
+	if ws.DecidedBy != game.MethodPlayoff {
+		t.Errorf("decided_by = %q, want playoff", ws.DecidedBy)
+	}
+}
+
+func TestAPI_StaleTiebreakAndHistory(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2,3],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-06T12:00","participant_ids":[1,2,3],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2026-01-07T12:00","participant_ids":[1,2,3],"winner_ids":[3]}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+	week := func() apiWeekStandings {
+		t.Helper()
+		var ws apiWeekStandings
+		if err := json.Unmarshal(doJSON(t, h, "GET", "/api/v1/weeks/2026/2", "").Body.Bytes(), &ws); err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+
+	if w := doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"winner_id":3}`); w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+	if ws := week(); ws.WinnerID == nil || *ws.WinnerID != 3 || ws.TiebreakerStale {
+		t.Fatalf("week = %+v, want 3 by tiebreaker", ws)
+	}
+
+	// A co-op win for 1 and 2 leaves only them tied: the decision among three is stale.
+	if w := doJSON(t, h, "POST", "/api/v1/games", `{"title_id":1,"played_at":"2026-01-08T12:00","mode":"coop","participant_ids":[1,2],"winner_ids":[1,2]}`); w.Code != http.StatusCreated {
+		t.Fatalf("co-op game: %d %s", w.Code, w.Body.String())
+	}
+	if ws := week(); ws.WinnerID != nil || !ws.TieUnresolved || !ws.TiebreakerStale {
+		t.Fatalf("week = %+v, want an unresolved tie with a stale tiebreaker", ws)
+	}
+
+	if w := doJSON(t, h, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"winner_id":2}`); w.Code != http.StatusOK {
+		t.Fatalf("re-decide: status = %d (%s)", w.Code, w.Body.String())
+	}
+	if ws := week(); ws.WinnerID == nil || *ws.WinnerID != 2 || ws.TiebreakerStale {
+		t.Errorf("week = %+v, want 2 by the new tiebreaker", ws)
+	}
+
+	var history []apiTiebreaker
+	w := doJSON(t, h, "GET", "/api/v1/tiebreakers/weekly/2026-W02/history", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("history: %d %s", w.Code, w.Body.String())
+	}
+	if len(history) != 2 || history[0].WinnerID != 2 || history[1].WinnerID != 3 || len(history[1].TiedPlayerIDs) != 3 {
+		t.Errorf("history = %+v, want the decision for 2 then the one for 3 among three", history)
+	}
+
+	if w := doJSON(t, h, "GET", "/api/v1/tiebreakers/weekly/2026-W03/history", ""); w.Code != http.StatusNotFound {
+		t.Errorf("history of an undecided week: status = %d, want 404", w.Code)
+	}
+}
+
+func TestAPI_AuditLogRecordsChanges(t *testing.T) {
+	st := game.NewMemoryStore(time.UTC)
+	s := &Server{store: NewAuditStore(st, st, time.UTC), loc: time.UTC}
+	h := apiTestHandler(s, "alice", game.RoleRecorder)
+
+	w := doJSON(t, h, "POST", "/api/v1/games", `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add game: %d %s", w.Code, w.Body.String())
+	}
+	w = doJSON(t, h, "PUT", "/api/v1/games/1", `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("update game: %d %s", w.Code, w.Body.String())
+	}
+
+	var entries []apiAuditEntry
+	w = doJSON(t, h, "GET", "/api/v1/audit?entity=game&entity_id=1", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("audit: %d %s", w.Code, w.Body.String())
+	}
+	if len(entries) != 2 || entries[0].Action != game.AuditUpdate || entries[1].Action != game.AuditCreate {
+		t.Fatalf("entries = %+v, want the update then the create", entries)
+	}
+	if entries[0].Actor != "alice" {
+		t.Errorf("actor = %q, want alice", entries[0].Actor)
+	}
+	var before, after apiGame
+	if err := json.Unmarshal(entries[0].Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(entries[0].After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(before.WinnerIDs, []int64{1}) || !slices.Equal(after.WinnerIDs, []int64{2}) {
+		t.Errorf("winners before/after = %v/%v, want [1]/[2]", before.WinnerIDs, after.WinnerIDs)
+	}
+	if string(entries[1].Before) != "null" {
+		t.Errorf("create before = %s, want null", entries[1].Before)
+	}
+
+	if changes := auditChanges(entries[0].Before, entries[0].After); len(changes) != 1 || changes[0].Field != "winner_ids" {
+		t.Errorf("changes = %+v, want only winner_ids", changes)
+	}
+
+	if w := doJSON(t, h, "GET", "/api/v1/audit?action=rename", ""); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unknown action: status = %d, want 422", w.Code)
+	}
+}
+
+// brokenAuditLog can't append entries, as an audit table that is locked or full.
+type brokenAuditLog struct{ AuditLog }
+
+func (brokenAuditLog) AppendAudit(context.Context, game.AuditEntry) error {
+	return errors.New("disk full")
+}
+
+func TestAPI_AuditFailureFailsTheChange(t *testing.T) {
+	st := game.NewMemoryStore(time.UTC)
+	s := &Server{store: st, loc: time.UTC}
+	h := apiTestHandler(s, "alice", game.RoleRecorder)
+	s.store = NewAuditStore(st, brokenAuditLog{st}, time.UTC)
+
+	w := doJSON(t, h, "POST", "/api/v1/games", `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("add game: %d %s, want 500 when the audit entry can't be written", w.Code, w.Body.String())
+	}
+}
+
+func TestAPI_RulesetChangesWeekWinner(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,3],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-05T13:00","participant_ids":[1,3],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-06T12:00","participant_ids":[1,3],"winner_ids":[3]}`,
+		`{"title_id":1,"played_at":"2026-01-06T13:00","participant_ids":[2,3],"winner_ids":[2]}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	week := func() apiWeekStandings {
+		t.Helper()
+		var ws apiWeekStandings
+		w := doJSON(t, h, "GET", "/api/v1/weeks/2026/2", "")
+		if err := json.Unmarshal(w.Body.Bytes(), &ws); err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+	if ws := week(); ws.WinnerID == nil || *ws.WinnerID != 1 || ws.Ruleset.Name != "Default" {
+		t.Fatalf("default rules: winner = %v, ruleset = %+v, want player 1", ws.WinnerID, ws.Ruleset)
+	}
+
+	w := doJSON(t, h, "POST", "/api/v1/rulesets", `{"name":"Rates","effective_from":"2026-01-01",
+		"weekly":{"metric":"win_rate","qualifier":"all","tie_policy":"tiebreaker"},
+		"yearly":{"metric":"win_rate","qualifier":"top_half_attendance","tie_policy":"tiebreaker"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add ruleset: status = %d (%s)", w.Code, w.Body.String())
+	}
+	if ws := week(); ws.WinnerID == nil || *ws.WinnerID != 2 || ws.Ruleset.Name != "Rates" || ws.Ruleset.Metric != "win_rate" {
+		t.Errorf("win rate rules: winner = %v, ruleset = %+v, want player 2", ws.WinnerID, ws.Ruleset)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/rulesets", `{"name":"Again","effective_from":"2026-01-01",
+		"weekly":{"metric":"wins","qualifier":"all","tie_policy":"tiebreaker"},
+		"yearly":{"metric":"wins","qualifier":"all","tie_policy":"tiebreaker"}}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("same date: status = %d, want 422", w.Code)
+	}
+	w = doJSON(t, h, "POST", "/api/v1/rulesets", `{"name":"Bad","effective_from":"2026-02-01",
+		"weekly":{"metric":"points","qualifier":"all","tie_policy":"tiebreaker"},
+		"yearly":{"metric":"wins","qualifier":"all","tie_policy":"tiebreaker"}}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("bad metric: status = %d, want 422", w.Code)
+	}
+
+	var list []apiRuleset
+	if err := json.Unmarshal(doJSON(t, h, "GET", "/api/v1/rulesets", "").Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].EffectiveFrom != "2026-01-01" || list[0].Weekly.Metric != "win_rate" {
+		t.Errorf("rulesets = %+v", list)
+	}
+}
+
+func TestAPI_SeasonStandingsAndTiebreak(t *testing.T) {
+	h := newAPITestServer()
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2025-12-29T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2026-02-02T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("seed game: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := doJSON(t, h, "POST", "/api/v1/seasons", `{"name":"Winter","start_date":"2025-12-15","end_date":"2026-01-31"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add season: status = %d (%s)", w.Code, w.Body.String())
+	}
+	var se apiSeason
+	if err := json.Unmarshal(w.Body.Bytes(), &se); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{
+		`{"name":"winter","start_date":"2026-12-15","end_date":"2027-01-31"}`,
+		`{"name":"Backwards","start_date":"2026-02-01","end_date":"2026-01-01"}`,
+		`{"name":"Bad","start_date":"soon","end_date":"2026-01-01"}`,
+	} {
+		if w := doJSON(t, h, "POST", "/api/v1/seasons", body); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want 422", body, w.Code)
+		}
+	}
+
+	path := "/api/v1/seasons/" + strconv.FormatInt(se.ID, 10)
+	var ss apiSeasonStandings
+	if err := json.Unmarshal(doJSON(t, h, "GET", path, "").Body.Bytes(), &ss); err != nil {
+		t.Fatal(err)
+	}
+	if !ss.TieUnresolved || len(ss.TopIDs) != 2 || ss.ScopeKey != "2025-12-15..2026-01-31" {
+		t.Fatalf("season standings = %+v, want an unresolved two-way tie", ss)
+	}
+
+	w = doJSON(t, h, "POST", path+"/tiebreak", `{"winner_id":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+	w = doJSON(t, h, "GET", "/api/v1/tiebreakers/season/"+ss.ScopeKey, "")
+	var tb apiTiebreaker
+	if err := json.Unmarshal(w.Body.Bytes(), &tb); err != nil {
+		t.Fatal(err)
+	}
+	if tb.Scope != "season" || tb.WinnerID != 1 {
+		t.Errorf("tiebreaker = %+v", tb)
+	}
+
+	if w := doJSON(t, h, "GET", "/api/v1/seasons/99", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown season: status = %d, want 404", w.Code)
+	}
+}
+
+func TestAPI_UnknownRouteIsJSON(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "GET", "/api/v1/nope", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	decodeAPIError(t, w)
+
+	w = doJSON(t, h, "GET", "/api/v1/tiebreakers/weekly/2026-W01", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing tiebreaker: status = %d, want 404", w.Code)
+	}
+}
+
+func TestAPI_ImportRejectsWholeDatasetOnRowErrors(t *testing.T) {
+	h := newAPITestServer()
+
+	body := `{"version":1,"exported_at":"2026-02-01T00:00:00Z",
+		"players":[{"name":"Newcomer","is_active":true}],
+		"titles":[],
+		"games":[
+			{"played_at":"2026-01-05T12:00:00-06:00","title":"Bang","participants":["Newcomer","ESMITH"],"winners":["Newcomer"],"notes":"","is_active":true},
+			{"played_at":"2026-01-10T12:00:00-06:00","title":"Bang","participants":["ESMITH"],"winners":["ESMITH"],"notes":"","is_active":true},
+			{"played_at":"2026-01-06T12:00:00-06:00","title":"Nope","participants":["Ghost"],"winners":["Ghost"],"notes":"","is_active":true}
+		],
+		"tiebreakers":[]}`
+	w := doJSON(t, h, "POST", "/api/v1/import", body)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422 (%s)", w.Code, w.Body.String())
+	}
+	e := decodeAPIError(t, w)
+	if len(e.Rows) != 2 || e.Rows[0].Row != 2 || e.Rows[1].Row != 3 {
+		t.Errorf("rows = %+v, want games rows 2 (weekend) and 3 (unknown title)", e.Rows)
+	}
+	if e.Rows[0].Message != "Only weekday games are allowed (Mon–Fri)." {
+		t.Errorf("row 2 message = %q, want the form's weekday message", e.Rows[0].Message)
+	}
+
+	w = doJSON(t, h, "GET", "/api/v1/players", "")
+	if strings.Contains(w.Body.String(), "Newcomer") {
+		t.Error("failed import should not have added players")
+	}
+}
+
+func TestAPI_ImportRejectsDrawThatDoesNotVerify(t *testing.T) {
+	h := newAPITestServer()
+
+	tied := []string{"ESMITH", "Newcomer"}
+	i, err := game.DrawIndex("5eed", "yearly", "2025", len(tied))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := func(winner string) string {
+		return `{"version":1,"exported_at":"2026-02-01T00:00:00Z",
+			"players":[{"name":"Newcomer","is_active":true}],"titles":[],"games":[],
+			"tiebreakers":[{"scope":"yearly","scope_key":"2025","tied":["ESMITH","Newcomer"],"winner":"` + winner + `",
+				"method":"chance","decided_at":"2025-12-31T17:00:00Z","seed":"5eed","algorithm":"` + game.DrawAlgorithm + `"}]}`
+	}
+
+	w := doJSON(t, h, "POST", "/api/v1/import", body(tied[1-i]))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("tampered: status = %d, want 422 (%s)", w.Code, w.Body.String())
+	}
+	if e := decodeAPIError(t, w); len(e.Rows) != 1 || e.Rows[0].Table != "tiebreakers" {
+		t.Errorf("rows = %+v, want the tiebreaker row", e.Rows)
+	}
+
+	if w := doJSON(t, h, "POST", "/api/v1/import", body(tied[i])); w.Code != http.StatusOK {
+		t.Errorf("genuine: status = %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestAPI_ExportImportRoundTripSkipsExisting(t *testing.T) {
+	h := newAPITestServer()
+
+	w := doJSON(t, h, "POST", "/api/v1/games",
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add status = %d", w.Code)
+	}
+
+	w = doJSON(t, h, "GET", "/api/v1/export", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("export status = %d", w.Code)
+	}
+
+	w = doJSON(t, h, "POST", "/api/v1/import", w.Body.String())
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d (%s)", w.Code, w.Body.String())
+	}
+	var sum apiImportSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum.GamesAdded != 0 || sum.GamesSkipped != 1 || sum.PlayersAdded != 0 {
+		t.Errorf("summary = %+v, want the one game skipped and nothing added", sum)
+	}
+}
+
+func TestAPI_RolesAndSignIn(t *testing.T) {
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC}
+	anon := apiTestHandler(s, "", "")
+	viewer := apiTestHandler(s, "vera", game.RoleViewer)
+	recorder := apiTestHandler(s, "rick", game.RoleRecorder)
+	const newGame = `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`
+
+	w := doJSON(t, anon, "GET", "/api/v1/games", "")
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("anonymous: status = %d, want 401 with a Basic challenge", w.Code)
+	}
+	if w := doJSON(t, viewer, "GET", "/api/v1/games", ""); w.Code != http.StatusOK {
+		t.Errorf("viewer read: status = %d, want 200", w.Code)
+	}
+	if w := doJSON(t, viewer, "POST", "/api/v1/games", newGame); w.Code != http.StatusForbidden {
+		t.Errorf("viewer logs a game: status = %d, want 403", w.Code)
+	}
+	if w := doJSON(t, recorder, "POST", "/api/v1/games", newGame); w.Code != http.StatusCreated {
+		t.Errorf("recorder logs a game: status = %d, want 201 (%s)", w.Code, w.Body.String())
+	}
+	if w := doJSON(t, recorder, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"winner_id":1}`); w.Code != http.StatusForbidden {
+		t.Errorf("recorder decides a tiebreak: status = %d, want 403", w.Code)
+	}
+
+	// Scripts can sign in with HTTP Basic account credentials.
+	hash, err := game.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	bob, err := s.store.AddUser(ctx, game.User{Username: "Bob", PasswordHash: hash, Role: game.RoleViewer, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	basic := func(user, pass string) int {
+		r := httptest.NewRequest("GET", "/api/v1/players", nil)
+		r.SetBasicAuth(user, pass)
+		w := httptest.NewRecorder()
+		anon.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := basic("bob", "correct horse"); code != http.StatusOK {
+		t.Errorf("basic auth: status = %d, want 200", code)
+	}
+	if code := basic("bob", "wrong horse"); code != http.StatusUnauthorized {
+		t.Errorf("basic auth, wrong password: status = %d, want 401", code)
+	}
+
+	bob.IsActive = false
+	if err := s.store.UpdateUser(ctx, bob); err != nil {
+		t.Fatal(err)
+	}
+	if code := basic("bob", "correct horse"); code != http.StatusUnauthorized {
+		t.Errorf("basic auth, inactive user: status = %d, want 401", code)
+	}
+}
+
+func TestAPI_BearerTokens(t *testing.T) {
+	st := game.NewMemoryStore(time.UTC)
+	s := &Server{store: NewAuditStore(st, st, time.UTC), loc: time.UTC}
+	admin := apiTestHandler(s, "admin", game.RoleAdmin)
+	anon := apiTestHandler(s, "", "")
+
+	issue := func(name, scope string) string {
+		t.Helper()
+		ctx := withUser(context.Background(), game.User{Username: "admin", Role: game.RoleAdmin})
+		token, err := s.issueAPIToken(ctx, TokenForm{Name: name, Scope: scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	bearer := func(token, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		anon.ServeHTTP(w, r)
+		return w
+	}
+	read, write := issue("dashboard", game.ScopeRead), issue("chat bot", game.ScopeWriteGames)
+	const newGame = `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`
+
+	if w := bearer(read, "GET", "/api/v1/games", ""); w.Code != http.StatusOK {
+		t.Errorf("read token GET: status = %d, want 200", w.Code)
+	}
+	if w := bearer(read, "POST", "/api/v1/games", newGame); w.Code != http.StatusForbidden {
+		t.Errorf("read token POST game: status = %d, want 403", w.Code)
+	}
+	if w := bearer(write, "POST", "/api/v1/games", newGame); w.Code != http.StatusCreated {
+		t.Errorf("write token POST game: status = %d, want 201 (%s)", w.Code, w.Body.String())
+	}
+	if w := bearer(write, "POST", "/api/v1/import", `{}`); w.Code != http.StatusForbidden {
+		t.Errorf("write token import: status = %d, want 403", w.Code)
+	}
+	if w := bearer("mog_nope", "GET", "/api/v1/games", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("unknown token: status = %d, want 401", w.Code)
+	}
+
+	var entries []apiAuditEntry
+	w := doJSON(t, admin, "GET", "/api/v1/audit?entity=game", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil || len(entries) != 1 || entries[0].Actor != "token:chat bot" {
+		t.Errorf("game audit = %+v, want one entry by token:chat bot", entries)
+	}
+
+	tokens, _ := st.ListAPITokens(context.Background())
+	for _, tok := range tokens {
+		if tok.LastUsedAt == nil {
+			t.Errorf("%s: last used not recorded", tok.Name)
+		}
+		if tok.CreatedBy != "admin" {
+			t.Errorf("%s: created by %q, want admin", tok.Name, tok.CreatedBy)
+		}
+		if tok.Name == "dashboard" {
+			if err := s.store.RevokeAPIToken(context.Background(), tok.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if w := bearer(read, "GET", "/api/v1/games", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("revoked token: status = %d, want 401", w.Code)
+	}
+}
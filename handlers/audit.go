@@ -0,0 +1,682 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+type actorKey struct{}
+
+// WithActor returns ctx carrying the name of whoever is making the request, for the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or "anonymous".
+func ActorFrom(ctx context.Context) string {
+	if a, ok := ctx.Value(actorKey{}).(string); ok && a != "" {
+		return a
+	}
+	return "anonymous"
+}
+
+// AuditStore is a Store that records every successful change in an AuditLog: who made it
+// (see WithActor), what changed, and the entity before and after as JSON. Reads pass
+// straight through. It is itself an AuditLog, so the audit page can read the log back.
+type AuditStore struct {
+	Store
+	log AuditLog
+	loc *time.Location // for ruleset dates in snapshots
+}
+
+// NewAuditStore wraps store so its changes are recorded in log. loc is the league time zone.
+func NewAuditStore(store Store, log AuditLog, loc *time.Location) *AuditStore {
+	return &AuditStore{Store: store, log: log, loc: loc}
+}
+
+func (a *AuditStore) AppendAudit(ctx context.Context, e game.AuditEntry) error {
+	return a.log.AppendAudit(ctx, e)
+}
+
+func (a *AuditStore) ListAudit(ctx context.Context, f game.AuditFilter) ([]game.AuditEntry, error) {
+	return a.log.ListAudit(ctx, f)
+}
+
+// record appends an entry for a change that has already been made. before and after are
+// snapshotted as JSON; pass nil for a side that didn't exist. A failure to record is
+// returned so the change is reported as failed rather than silently left out of the log.
+func (a *AuditStore) record(ctx context.Context, action, entity, entityID string, before, after any) error {
+	e := game.AuditEntry{
+		At:       time.Now(),
+		Actor:    ActorFrom(ctx),
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+	}
+	var err error
+	if e.Before, err = snapshot(before); err != nil {
+		return fmt.Errorf("audit %s %s: %w", action, entity, err)
+	}
+	if e.After, err = snapshot(after); err != nil {
+		return fmt.Errorf("audit %s %s: %w", action, entity, err)
+	}
+	if err := a.log.AppendAudit(ctx, e); err != nil {
+		return fmt.Errorf("audit %s %s: %w", action, entity, err)
+	}
+	return nil
+}
+
+// snapshot marshals v, or returns nil for a nil v.
+func snapshot(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func idString(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func activeAction(active bool) string {
+	if active {
+		return game.AuditActivate
+	}
+	return game.AuditDeactivate
+}
+
+// ============================
+// Snapshots
+// ============================
+
+// The entity lookups below return an API-shaped snapshot (so the log reads like the API),
+// or nil if the entity doesn't exist.
+
+func (a *AuditStore) gameSnapshot(ctx context.Context, id int64) any {
+	g, ok, err := a.Store.GetGame(ctx, id)
+	if err != nil || !ok {
+		return nil
+	}
+	return toAPIGame(g)
+}
+
+func (a *AuditStore) playerSnapshot(ctx context.Context, id int64) any {
+	players, err := a.Store.ListPlayers(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, p := range players {
+		if p.ID == id {
+			return apiPlayer{ID: p.ID, Name: p.Name, IsActive: p.IsActive}
+		}
+	}
+	return nil
+}
+
+func (a *AuditStore) titleSnapshot(ctx context.Context, id int64) any {
+	titles, err := a.Store.ListTitles(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, t := range titles {
+		if t.ID == id {
+			return apiTitle{ID: t.ID, Name: t.Name, IsActive: t.IsActive}
+		}
+	}
+	return nil
+}
+
+func (a *AuditStore) rulesetSnapshot(ctx context.Context, id int64) any {
+	rulesets, err := a.Store.ListRulesets(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, rs := range rulesets {
+		if rs.ID == id {
+			return toAPIRuleset(rs, a.loc)
+		}
+	}
+	return nil
+}
+
+func (a *AuditStore) seasonSnapshot(ctx context.Context, id int64) any {
+	seasons, err := a.Store.ListSeasons(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, se := range seasons {
+		if se.ID == id {
+			return toAPISeason(se)
+		}
+	}
+	return nil
+}
+
+func (a *AuditStore) tiebreakerSnapshot(ctx context.Context, scope, scopeKey string) any {
+	tb, ok, err := a.Store.GetTiebreaker(ctx, scope, scopeKey)
+	if err != nil || !ok {
+		return nil
+	}
+	return toAPITiebreaker(tb)
+}
+
+// userSnapshot is a user as the audit log records it: never the password hash, only
+// whether it changed.
+type userSnapshot struct {
+	ID              int64  `json:"id"`
+	Username        string `json:"username"`
+	Role            string `json:"role"`
+	PlayerID        *int64 `json:"player_id"`
+	IsActive        bool   `json:"is_active"`
+	PasswordChanged bool   `json:"password_changed,omitempty"`
+}
+
+func toUserSnapshot(u game.User) userSnapshot {
+	return userSnapshot{ID: u.ID, Username: u.Username, Role: u.Role, PlayerID: u.PlayerID, IsActive: u.IsActive}
+}
+
+// apiTokenSnapshot is an API token as the audit log records it, without its hash.
+type apiTokenSnapshot struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	CreatedBy string     `json:"created_by"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func (a *AuditStore) apiTokenSnapshot(ctx context.Context, id int64) any {
+	tokens, err := a.Store.ListAPITokens(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, t := range tokens {
+		if t.ID == id {
+			return apiTokenSnapshot{ID: t.ID, Name: t.Name, Scope: t.Scope, CreatedBy: t.CreatedBy, RevokedAt: t.RevokedAt}
+		}
+	}
+	return nil
+}
+
+// webhookSnapshot is a webhook as the audit log records it, without its secret.
+type webhookSnapshot struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	IsActive  bool     `json:"is_active"`
+	CreatedBy string   `json:"created_by"`
+}
+
+func (a *AuditStore) webhookSnapshot(ctx context.Context, id int64) any {
+	hooks, err := a.Store.ListWebhooks(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, h := range hooks {
+		if h.ID == id {
+			return webhookSnapshot{ID: h.ID, Name: h.Name, URL: h.URL, Events: h.Events, IsActive: h.IsActive, CreatedBy: h.CreatedBy}
+		}
+	}
+	return nil
+}
+
+// ============================
+// Games
+// ============================
+
+func (a *AuditStore) AddGame(ctx context.Context, g game.Game) (game.Game, error) {
+	g, err := a.Store.AddGame(ctx, g)
+	if err != nil {
+		return g, err
+	}
+	return g, a.record(ctx, game.AuditCreate, game.AuditGame, idString(g.ID), nil, toAPIGame(g))
+}
+
+func (a *AuditStore) UpdateGame(ctx context.Context, g game.Game) error {
+	before := a.gameSnapshot(ctx, g.ID)
+	if err := a.Store.UpdateGame(ctx, g); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditUpdate, game.AuditGame, idString(g.ID), before, a.gameSnapshot(ctx, g.ID))
+}
+
+func (a *AuditStore) DeleteGame(ctx context.Context, id int64) error {
+	before := a.gameSnapshot(ctx, id)
+	if err := a.Store.DeleteGame(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditGame, idString(id), before, nil)
+}
+
+func (a *AuditStore) SetGameActive(ctx context.Context, id int64, active bool) error {
+	before := a.gameSnapshot(ctx, id)
+	if err := a.Store.SetGameActive(ctx, id, active); err != nil {
+		return err
+	}
+	return a.record(ctx, activeAction(active), game.AuditGame, idString(id), before, a.gameSnapshot(ctx, id))
+}
+
+// ============================
+// Players
+// ============================
+
+func (a *AuditStore) AddPlayer(ctx context.Context, name string) (game.Player, error) {
+	p, err := a.Store.AddPlayer(ctx, name)
+	if err != nil {
+		return p, err
+	}
+	return p, a.record(ctx, game.AuditCreate, game.AuditPlayer, idString(p.ID), nil, apiPlayer{ID: p.ID, Name: p.Name, IsActive: p.IsActive})
+}
+
+func (a *AuditStore) UpdatePlayer(ctx context.Context, id int64, name string) error {
+	before := a.playerSnapshot(ctx, id)
+	if err := a.Store.UpdatePlayer(ctx, id, name); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditUpdate, game.AuditPlayer, idString(id), before, a.playerSnapshot(ctx, id))
+}
+
+func (a *AuditStore) SetPlayerActive(ctx context.Context, id int64, active bool) error {
+	before := a.playerSnapshot(ctx, id)
+	if err := a.Store.SetPlayerActive(ctx, id, active); err != nil {
+		return err
+	}
+	return a.record(ctx, activeAction(active), game.AuditPlayer, idString(id), before, a.playerSnapshot(ctx, id))
+}
+
+func (a *AuditStore) DeletePlayer(ctx context.Context, id int64) error {
+	before := a.playerSnapshot(ctx, id)
+	if err := a.Store.DeletePlayer(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditPlayer, idString(id), before, nil)
+}
+
+// ============================
+// Titles
+// ============================
+
+func (a *AuditStore) AddTitle(ctx context.Context, name string) (game.Title, error) {
+	t, err := a.Store.AddTitle(ctx, name)
+	if err != nil {
+		return t, err
+	}
+	return t, a.record(ctx, game.AuditCreate, game.AuditTitle, idString(t.ID), nil, apiTitle{ID: t.ID, Name: t.Name, IsActive: t.IsActive})
+}
+
+func (a *AuditStore) UpdateTitle(ctx context.Context, id int64, name string) error {
+	before := a.titleSnapshot(ctx, id)
+	if err := a.Store.UpdateTitle(ctx, id, name); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditUpdate, game.AuditTitle, idString(id), before, a.titleSnapshot(ctx, id))
+}
+
+func (a *AuditStore) SetTitleActive(ctx context.Context, id int64, active bool) error {
+	before := a.titleSnapshot(ctx, id)
+	if err := a.Store.SetTitleActive(ctx, id, active); err != nil {
+		return err
+	}
+	return a.record(ctx, activeAction(active), game.AuditTitle, idString(id), before, a.titleSnapshot(ctx, id))
+}
+
+func (a *AuditStore) DeleteTitle(ctx context.Context, id int64) error {
+	before := a.titleSnapshot(ctx, id)
+	if err := a.Store.DeleteTitle(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditTitle, idString(id), before, nil)
+}
+
+// ============================
+// Tiebreakers, rulesets, seasons
+// ============================
+
+func (a *AuditStore) SetTiebreaker(ctx context.Context, tb game.Tiebreaker) error {
+	before := a.tiebreakerSnapshot(ctx, tb.Scope, tb.ScopeKey)
+	if err := a.Store.SetTiebreaker(ctx, tb); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDecide, game.AuditTiebreaker, tb.Scope+"/"+tb.ScopeKey, before, toAPITiebreaker(tb))
+}
+
+func (a *AuditStore) AddRuleset(ctx context.Context, r game.Ruleset) (game.Ruleset, error) {
+	r, err := a.Store.AddRuleset(ctx, r)
+	if err != nil {
+		return r, err
+	}
+	return r, a.record(ctx, game.AuditCreate, game.AuditRuleset, idString(r.ID), nil, toAPIRuleset(r, a.loc))
+}
+
+func (a *AuditStore) DeleteRuleset(ctx context.Context, id int64) error {
+	before := a.rulesetSnapshot(ctx, id)
+	if err := a.Store.DeleteRuleset(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditRuleset, idString(id), before, nil)
+}
+
+func (a *AuditStore) AddSeason(ctx context.Context, se game.Season) (game.Season, error) {
+	se, err := a.Store.AddSeason(ctx, se)
+	if err != nil {
+		return se, err
+	}
+	return se, a.record(ctx, game.AuditCreate, game.AuditSeason, idString(se.ID), nil, toAPISeason(se))
+}
+
+func (a *AuditStore) DeleteSeason(ctx context.Context, id int64) error {
+	before := a.seasonSnapshot(ctx, id)
+	if err := a.Store.DeleteSeason(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditSeason, idString(id), before, nil)
+}
+
+// ============================
+// Users
+// ============================
+
+// Sign-ins and sign-outs aren't changes; only the accounts themselves are recorded.
+
+func (a *AuditStore) AddUser(ctx context.Context, u game.User) (game.User, error) {
+	u, err := a.Store.AddUser(ctx, u)
+	if err != nil {
+		return u, err
+	}
+	return u, a.record(ctx, game.AuditCreate, game.AuditUser, idString(u.ID), nil, toUserSnapshot(u))
+}
+
+func (a *AuditStore) UpdateUser(ctx context.Context, u game.User) error {
+	old, ok, err := a.Store.GetUser(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := a.Store.UpdateUser(ctx, u); err != nil {
+		return err
+	}
+	var before any
+	action := game.AuditUpdate
+	after := toUserSnapshot(u)
+	if ok {
+		before = toUserSnapshot(old)
+		after.Username = old.Username
+		after.PasswordChanged = u.PasswordHash != old.PasswordHash
+		if old.IsActive != u.IsActive {
+			action = activeAction(u.IsActive)
+		}
+	}
+	return a.record(ctx, action, game.AuditUser, idString(u.ID), before, after)
+}
+
+func (a *AuditStore) DeleteUser(ctx context.Context, id int64) error {
+	var before any
+	if u, ok, err := a.Store.GetUser(ctx, id); err == nil && ok {
+		before = toUserSnapshot(u)
+	}
+	if err := a.Store.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditUser, idString(id), before, nil)
+}
+
+// ============================
+// API tokens
+// ============================
+
+// Issuing and revoking tokens is recorded; using them (TouchAPIToken) isn't.
+
+func (a *AuditStore) AddAPIToken(ctx context.Context, t game.APIToken) (game.APIToken, error) {
+	t, err := a.Store.AddAPIToken(ctx, t)
+	if err != nil {
+		return t, err
+	}
+	return t, a.record(ctx, game.AuditCreate, game.AuditAPIToken, idString(t.ID), nil, a.apiTokenSnapshot(ctx, t.ID))
+}
+
+func (a *AuditStore) RevokeAPIToken(ctx context.Context, id int64) error {
+	before := a.apiTokenSnapshot(ctx, id)
+	if err := a.Store.RevokeAPIToken(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditRevoke, game.AuditAPIToken, idString(id), before, a.apiTokenSnapshot(ctx, id))
+}
+
+// ============================
+// Webhooks
+// ============================
+
+// Changes to webhooks are recorded; their deliveries, which the worker writes, aren't.
+
+func (a *AuditStore) AddWebhook(ctx context.Context, h game.Webhook) (game.Webhook, error) {
+	h, err := a.Store.AddWebhook(ctx, h)
+	if err != nil {
+		return h, err
+	}
+	return h, a.record(ctx, game.AuditCreate, game.AuditWebhook, idString(h.ID), nil, a.webhookSnapshot(ctx, h.ID))
+}
+
+func (a *AuditStore) SetWebhookActive(ctx context.Context, id int64, active bool) error {
+	before := a.webhookSnapshot(ctx, id)
+	if err := a.Store.SetWebhookActive(ctx, id, active); err != nil {
+		return err
+	}
+	return a.record(ctx, activeAction(active), game.AuditWebhook, idString(id), before, a.webhookSnapshot(ctx, id))
+}
+
+func (a *AuditStore) DeleteWebhook(ctx context.Context, id int64) error {
+	before := a.webhookSnapshot(ctx, id)
+	if err := a.Store.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+	return a.record(ctx, game.AuditDelete, game.AuditWebhook, idString(id), before, nil)
+}
+
+// ============================
+// Import
+// ============================
+
+// ImportDataset records one entry for the whole import, with what it added as After.
+func (a *AuditStore) ImportDataset(ctx context.Context, d game.Dataset) (game.ImportSummary, error) {
+	sum, err := a.Store.ImportDataset(ctx, d)
+	if err != nil {
+		return sum, err
+	}
+	return sum, a.record(ctx, game.AuditImport, game.AuditDataset, "", nil, toAPIImportSummary(sum))
+}
+
+// ============================
+// Audit page
+// ============================
+
+// auditPageLimit caps the entries shown at once; narrow the filter to see older ones.
+const auditPageLimit = 200
+
+// parseAuditFilter reads the audit filter from a query: actor, action, entity, entity_id,
+// and from/to days (YYYY-MM-DD, inclusive). The form is returned as entered; error
+// messages are user-facing.
+func (s *Server) parseAuditFilter(q url.Values) (game.AuditFilter, AuditFilterForm, error) {
+	form := AuditFilterForm{
+		Actor:    strings.TrimSpace(q.Get("actor")),
+		Action:   q.Get("action"),
+		Entity:   q.Get("entity"),
+		EntityID: strings.TrimSpace(q.Get("entity_id")),
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+	}
+	f := game.AuditFilter{
+		Actor:    form.Actor,
+		Action:   form.Action,
+		Entity:   form.Entity,
+		EntityID: form.EntityID,
+		Limit:    auditPageLimit,
+	}
+
+	if f.Action != "" && !slices.Contains(game.AuditActions, f.Action) {
+		return f, form, errors.New("Please choose a valid action.")
+	}
+	if f.Entity != "" && !slices.Contains(game.AuditEntities, f.Entity) {
+		return f, form, errors.New("Please choose a valid kind of record.")
+	}
+	from, fromErr := s.parseDay(form.From)
+	to, toErr := s.parseDay(form.To)
+	if fromErr != nil || toErr != nil {
+		return f, form, errors.New("Dates must look like 2026-01-31.")
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return f, form, errors.New("The end date must not be before the start date.")
+	}
+	f.From = from
+	if !to.IsZero() {
+		f.To = to.AddDate(0, 0, 1)
+	}
+	return f, form, nil
+}
+
+// auditLog returns the store's audit log, if it keeps one.
+func (s *Server) auditLog() (AuditLog, bool) {
+	log, ok := s.store.(AuditLog)
+	return log, ok
+}
+
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	vm := AuditVM{
+		Title:     "Audit log",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Actions:   game.AuditActions,
+		Entities:  game.AuditEntities,
+		Limit:     auditPageLimit,
+	}
+
+	log, ok := s.auditLog()
+	if !ok {
+		vm.FormError = "This server doesn't keep an audit log."
+		if err := s.r.HTML(w, "audit", "audit", vm); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	f, form, err := s.parseAuditFilter(r.URL.Query())
+	vm.Filter = form
+	if err != nil {
+		vm.FormError = err.Error()
+		f = game.AuditFilter{Limit: auditPageLimit}
+	}
+
+	entries, err := log.ListAudit(r.Context(), f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, e := range entries {
+		vm.Entries = append(vm.Entries, s.auditEntryVM(e))
+	}
+	vm.Truncated = len(entries) == auditPageLimit
+
+	if err := s.r.HTML(w, "audit", "audit", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) auditEntryVM(e game.AuditEntry) auditEntryVM {
+	vm := auditEntryVM{
+		At:       e.At.In(s.loc).Format("Jan 2, 2006 3:04:05 PM"),
+		Actor:    e.Actor,
+		Action:   e.Action,
+		Entity:   e.Entity,
+		EntityID: e.EntityID,
+		Changes:  auditChanges(e.Before, e.After),
+	}
+	if e.EntityID != "" {
+		vm.HistoryURL = "/audit?" + url.Values{"entity": {e.Entity}, "entity_id": {e.EntityID}}.Encode()
+	}
+	if e.Action != game.AuditDelete {
+		vm.EntityURL = auditEntityURL(e.Entity, e.EntityID)
+	}
+	return vm
+}
+
+// auditEntityURL links to the page for an audited entity, if it has one.
+func auditEntityURL(entity, id string) string {
+	switch entity {
+	case game.AuditPlayer:
+		return "/players/" + id
+	case game.AuditTitle:
+		return "/titles/" + id
+	case game.AuditSeason:
+		return "/seasons/" + id
+	case game.AuditRuleset:
+		return "/rules"
+	case game.AuditUser:
+		return "/users"
+	case game.AuditAPIToken:
+		return "/tokens"
+	case game.AuditWebhook:
+		return "/webhooks"
+	case game.AuditTiebreaker:
+		if scope, key, ok := strings.Cut(id, "/"); ok {
+			return historyPath(scope, key)
+		}
+	}
+	return ""
+}
+
+// auditChanges lists the top-level fields that differ between two JSON object snapshots,
+// sorted by field name. A missing snapshot contributes no values, so a create lists every field
+// of after and a delete every field of before.
+func auditChanges(before, after []byte) []auditChangeVM {
+	var b, a map[string]json.RawMessage
+	_ = json.Unmarshal(before, &b)
+	_ = json.Unmarshal(after, &a)
+
+	fields := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		fields = append(fields, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	slices.Sort(fields)
+
+	var out []auditChangeVM
+	for _, k := range fields {
+		bv, av := auditValue(b[k]), auditValue(a[k])
+		if bv == av && b != nil && a != nil {
+			continue
+		}
+		out = append(out, auditChangeVM{Field: k, Before: bv, After: av})
+	}
+	return out
+}
+
+// auditValue shows one JSON value: strings without quotes, anything else as compact JSON.
+func auditValue(raw json.RawMessage) string {
+	if raw == nil {
+		return ""
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,568 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// Signed-in browsers carry a random session token in this cookie; only its hash is stored.
+const (
+	sessionCookie = "mog_session"
+	sessionTTL    = 30 * 24 * time.Hour
+)
+
+type userKey struct{}
+
+// withUser returns ctx carrying the signed-in user, who is also the audit log's actor.
+func withUser(ctx context.Context, u game.User) context.Context {
+	return WithActor(context.WithValue(ctx, userKey{}, u), u.Username)
+}
+
+// UserFrom returns the signed-in user set by requireRole.
+func UserFrom(ctx context.Context) (game.User, bool) {
+	u, ok := ctx.Value(userKey{}).(game.User)
+	return u, ok
+}
+
+// authenticate returns the active user making r: the owner of its session cookie, or, for
+// scripts, the account named by HTTP Basic credentials. API requests may instead carry an
+// API token (see tokenUser).
+func (s *Server) authenticate(r *http.Request) (game.User, bool, error) {
+	ctx := r.Context()
+
+	if token, ok := bearerToken(r); ok {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			return game.User{}, false, nil
+		}
+		return s.tokenUser(ctx, token)
+	}
+
+	if c, err := r.Cookie(sessionCookie); err == nil && c.Value != "" {
+		sess, ok, err := s.store.GetSession(ctx, game.HashSessionToken(c.Value))
+		if err != nil {
+			return game.User{}, false, err
+		}
+		if ok {
+			u, ok, err := s.store.GetUser(ctx, sess.UserID)
+			if err != nil || !ok || !u.IsActive {
+				return game.User{}, false, err
+			}
+			return u, true, nil
+		}
+	}
+
+	if name, pass, ok := r.BasicAuth(); ok {
+		return s.checkCredentials(ctx, name, pass)
+	}
+	return game.User{}, false, nil
+}
+
+// apiTokenTouchEvery limits how often a token's last-used time is written back.
+const apiTokenTouchEvery = time.Minute
+
+// bearerToken returns the token from an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// tokenUser returns a stand-in user for an unrevoked API token: named "token:<name>" in the
+// audit log, with the role its scope grants. Using the token updates its last-used time.
+func (s *Server) tokenUser(ctx context.Context, token string) (game.User, bool, error) {
+	t, ok, err := s.store.GetAPITokenByHash(ctx, game.HashAPIToken(token))
+	if err != nil || !ok || !t.Active() {
+		return game.User{}, false, err
+	}
+
+	now := time.Now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= apiTokenTouchEvery {
+		if err := s.store.TouchAPIToken(ctx, t.ID, now); err != nil {
+			return game.User{}, false, err
+		}
+	}
+	return game.User{Username: "token:" + t.Name, Role: t.Role(), IsActive: true}, true, nil
+}
+
+// checkCredentials returns the active user with this name and password.
+func (s *Server) checkCredentials(ctx context.Context, username, password string) (game.User, bool, error) {
+	u, ok, err := s.store.GetUserByUsername(ctx, strings.TrimSpace(username))
+	if err != nil || !ok || !u.IsActive || !game.CheckPassword(u.PasswordHash, password) {
+		return game.User{}, false, err
+	}
+	return u, true, nil
+}
+
+// requireRole lets only signed-in users with at least role reach h. Anyone else is sent to
+// the login page, or gets a JSON 401 under /api/; a user whose role is too low gets 403.
+func (s *Server) requireRole(role string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok, err := s.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		isAPI := strings.HasPrefix(r.URL.Path, "/api/")
+
+		if !ok {
+			switch {
+			case isAPI:
+				w.Header().Add("WWW-Authenticate", `Bearer realm="Master of Games"`)
+				w.Header().Add("WWW-Authenticate", `Basic realm="Master of Games"`)
+				writeJSONError(w, http.StatusUnauthorized, "sign in required")
+			case r.Header.Get("HX-Request") == "true":
+				w.Header().Set("HX-Redirect", "/login")
+				w.WriteHeader(http.StatusUnauthorized)
+			default:
+				next := ""
+				if r.Method == http.MethodGet {
+					next = r.URL.RequestURI()
+				}
+				http.Redirect(w, r, loginPath(next), http.StatusSeeOther)
+			}
+			return
+		}
+
+		if !game.RoleAllows(u.Role, role) {
+			msg := fmt.Sprintf("This needs the %s role; you are signed in as a %s.", role, u.Role)
+			switch {
+			case isAPI:
+				writeJSONError(w, http.StatusForbidden, msg)
+			case r.Header.Get("HX-Request") == "true":
+				setToast(w, msg)
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				http.Error(w, msg, http.StatusForbidden)
+			}
+			return
+		}
+
+		h(w, r.WithContext(withUser(r.Context(), u)))
+	}
+}
+
+// loginPath is the login page, returning to next afterwards.
+func loginPath(next string) string {
+	if next == "" || next == "/" {
+		return "/login"
+	}
+	return "/login?" + url.Values{"next": {next}}.Encode()
+}
+
+// safeNext keeps a post-login redirect on this site.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// startSession signs u in on this browser.
+func (s *Server) startSession(w http.ResponseWriter, r *http.Request, u game.User) error {
+	token, err := game.NewSessionToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	sess := game.Session{
+		TokenHash: game.HashSessionToken(token),
+		UserID:    u.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(sessionTTL),
+	}
+	if err := s.store.AddSession(r.Context(), sess); err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  sess.ExpiresAt,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// endSession signs this browser out.
+func (s *Server) endSession(w http.ResponseWriter, r *http.Request) error {
+	if c, err := r.Cookie(sessionCookie); err == nil && c.Value != "" {
+		if err := s.store.DeleteSession(r.Context(), game.HashSessionToken(c.Value)); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// isHTTPS reports whether the browser reached us over TLS, directly or via a proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// BootstrapAdmin creates an admin account from username and password when there are no
+// users yet, so a fresh install can be signed into. It does nothing once any user exists.
+func (s *Server) BootstrapAdmin(ctx context.Context, username, password string) error {
+	users, err := s.store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+	if strings.TrimSpace(username) == "" || password == "" {
+		log.Printf("no user accounts exist; set ADMIN_USER and ADMIN_PASS to create the first admin")
+		return nil
+	}
+
+	u := game.User{Username: strings.TrimSpace(username), Role: game.RoleAdmin, IsActive: true}
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("ADMIN_USER: %w", err)
+	}
+	if u.PasswordHash, err = game.HashPassword(password); err != nil {
+		return fmt.Errorf("ADMIN_PASS: %w", err)
+	}
+	if _, err := s.store.AddUser(WithActor(ctx, "system"), u); err != nil {
+		return err
+	}
+	log.Printf("created admin user %q", u.Username)
+	return nil
+}
+
+// ============================
+// Login, logout and account
+// ============================
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	s.renderLogin(w, LoginVM{Next: safeNext(r.URL.Query().Get("next"))})
+}
+
+func (s *Server) handleLoginPost(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.renderLogin(w, LoginVM{Next: "/", FormError: "Invalid form submission."})
+		return
+	}
+	vm := LoginVM{
+		Username: strings.TrimSpace(r.FormValue("username")),
+		Next:     safeNext(r.FormValue("next")),
+	}
+
+	u, ok, err := s.checkCredentials(r.Context(), vm.Username, r.FormValue("password"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		vm.FormError = "Wrong user name or password."
+		s.renderLogin(w, vm)
+		return
+	}
+	if err := s.startSession(w, r, u); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, vm.Next, http.StatusSeeOther)
+}
+
+func (s *Server) renderLogin(w http.ResponseWriter, vm LoginVM) {
+	vm.Title = "Sign in"
+	vm.Version = s.meta.Version
+	vm.BuildTime = s.meta.BuildTime
+	vm.StartTime = s.meta.StartTime
+	vm.YearNow = s.now().Year()
+	if err := s.r.HTML(w, "login", "login", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if err := s.endSession(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	s.renderAccount(r.Context(), w, "account", "")
+}
+
+// handleAccountPassword changes the signed-in user's password and signs them out
+// everywhere else.
+func (s *Server) handleAccountPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u, _ := UserFrom(ctx)
+
+	if err := r.ParseForm(); err != nil {
+		s.renderAccount(ctx, w, "main", "Invalid form submission.")
+		return
+	}
+	if !game.CheckPassword(u.PasswordHash, r.FormValue("current_password")) {
+		s.renderAccount(ctx, w, "main", "Your current password is wrong.")
+		return
+	}
+	if r.FormValue("new_password") != r.FormValue("confirm_password") {
+		s.renderAccount(ctx, w, "main", "The new passwords don't match.")
+		return
+	}
+	hash, err := game.HashPassword(r.FormValue("new_password"))
+	if err != nil {
+		s.renderAccount(ctx, w, "main", err.Error())
+		return
+	}
+
+	u.PasswordHash = hash
+	err = s.store.UpdateUser(ctx, u)
+	if err == nil {
+		err = s.store.DeleteUserSessions(ctx, u.ID)
+	}
+	if err == nil {
+		err = s.startSession(w, r, u)
+	}
+	if err != nil {
+		s.renderAccount(ctx, w, "main", "Unable to change your password.")
+		return
+	}
+	setToast(w, "Password changed.")
+	s.renderAccount(ctx, w, "main", "")
+}
+
+// renderAccount renders the account page; layout is "account" for a full page or "main" for
+// an HTMX swap after a change.
+func (s *Server) renderAccount(ctx context.Context, w http.ResponseWriter, layout, formErr string) {
+	u, _ := UserFrom(ctx)
+	vm := AccountVM{
+		Title:     "Account",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Username:  u.Username,
+		Role:      u.Role,
+		MinLength: game.MinPasswordLength,
+		FormError: formErr,
+	}
+	if u.PlayerID != nil {
+		players, err := s.store.ListPlayers(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, p := range players {
+			if p.ID == *u.PlayerID {
+				vm.Player = &p
+			}
+		}
+	}
+
+	if err := s.r.HTML(w, layout, "account", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ============================
+// Users (admin)
+// ============================
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	s.renderUsers(r.Context(), w, "users", UserForm{Role: game.RoleViewer}, "")
+}
+
+func (s *Server) handleUsersPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "Invalid form submission.")
+		return
+	}
+
+	form := UserForm{
+		Username: strings.TrimSpace(r.FormValue("username")),
+		Role:     r.FormValue("role"),
+		PlayerID: r.FormValue("player_id"),
+	}
+	if err := s.addUser(ctx, form, r.FormValue("password")); err != nil {
+		s.renderUsers(ctx, w, "main", form, err.Error())
+		return
+	}
+	setToast(w, "User added.")
+	s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "")
+}
+
+// addUser validates and stores a new account. Error messages are user-facing.
+func (s *Server) addUser(ctx context.Context, form UserForm, password string) error {
+	u := game.User{Username: form.Username, Role: form.Role, IsActive: true}
+	var err error
+	if u.PlayerID, err = s.parseUserPlayer(ctx, form.PlayerID); err != nil {
+		return err
+	}
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	if _, taken, err := s.store.GetUserByUsername(ctx, u.Username); err != nil {
+		return errors.New("Unable to load the existing users.")
+	} else if taken {
+		return errors.New("A user with that name already exists.")
+	}
+	if u.PasswordHash, err = game.HashPassword(password); err != nil {
+		return err
+	}
+	if _, err := s.store.AddUser(ctx, u); err != nil {
+		return errors.New("Unable to save the user.")
+	}
+	return nil
+}
+
+// handleUserUpdate changes a user's role, player, active flag and, if one is given, their
+// password. Deactivating a user or resetting their password signs them out.
+func (s *Server) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/users", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "Invalid form submission.")
+		return
+	}
+	if err := s.updateUser(ctx, id, r); err != nil {
+		s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, err.Error())
+		return
+	}
+	setToast(w, "User updated.")
+	s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "")
+}
+
+func (s *Server) updateUser(ctx context.Context, id int64, r *http.Request) error {
+	u, ok, err := s.store.GetUser(ctx, id)
+	if err != nil {
+		return errors.New("Unable to load the user.")
+	}
+	if !ok {
+		return errors.New("That user no longer exists.")
+	}
+	old := u
+
+	u.Role = r.FormValue("role")
+	u.IsActive = r.FormValue("active") == "1"
+	if u.PlayerID, err = s.parseUserPlayer(ctx, r.FormValue("player_id")); err != nil {
+		return err
+	}
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	if me, _ := UserFrom(ctx); me.ID == u.ID && (u.Role != game.RoleAdmin || !u.IsActive) {
+		return errors.New("You can't demote or deactivate yourself.")
+	}
+	if pw := r.FormValue("password"); pw != "" {
+		if u.PasswordHash, err = game.HashPassword(pw); err != nil {
+			return err
+		}
+	}
+
+	if err := s.store.UpdateUser(ctx, u); err != nil {
+		return errors.New("Unable to save the user.")
+	}
+	if !u.IsActive || u.PasswordHash != old.PasswordHash {
+		if err := s.store.DeleteUserSessions(ctx, u.ID); err != nil {
+			return errors.New("Saved, but unable to sign the user out.")
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleUserDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/users", http.StatusSeeOther)
+		return
+	}
+	if me, _ := UserFrom(ctx); me.ID == id {
+		s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "You can't delete yourself.")
+		return
+	}
+	if err := s.store.DeleteUser(ctx, id); err != nil {
+		s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "Unable to delete the user.")
+		return
+	}
+	setToast(w, "User deleted.")
+	s.renderUsers(ctx, w, "main", UserForm{Role: game.RoleViewer}, "")
+}
+
+// parseUserPlayer reads the optional player a user is linked to; "" means none.
+func (s *Server) parseUserPlayer(ctx context.Context, v string) (*int64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		return nil, errors.New("Unable to load the players.")
+	}
+	for _, p := range players {
+		if idString(p.ID) == v {
+			return &p.ID, nil
+		}
+	}
+	return nil, errors.New("Please choose a valid player.")
+}
+
+// renderUsers renders the users page; layout is "users" for a full page or "main" for an
+// HTMX swap after a change.
+func (s *Server) renderUsers(ctx context.Context, w http.ResponseWriter, layout string, form UserForm, formErr string) {
+	users, err := s.store.ListUsers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	me, _ := UserFrom(ctx)
+
+	vm := UsersVM{
+		Title:     "Users",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Roles:     game.Roles,
+		Players:   players,
+		MinLength: game.MinPasswordLength,
+		Form:      form,
+		FormError: formErr,
+	}
+	for _, u := range users {
+		row := userRowVM{User: u, IsMe: u.ID == me.ID}
+		if u.PlayerID != nil {
+			row.PlayerID = *u.PlayerID
+		}
+		vm.Users = append(vm.Users, row)
+	}
+
+	if err := s.r.HTML(w, layout, "users", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// maxImportBytes caps uploaded datasets; a league's full history is far smaller.
+const maxImportBytes = 10 << 20
+
+// handleExport serves the whole dataset as JSON, or one table as CSV (?format=csv&table=games).
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	table := r.URL.Query().Get("table")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+	if format == "csv" && !slices.Contains(game.DatasetTables, table) {
+		http.Error(w, "table must be one of "+strings.Join(game.DatasetTables, ", "), http.StatusBadRequest)
+		return
+	}
+
+	d, err := game.LoadDataset(r.Context(), s.store, time.Now().UTC())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stamp := d.ExportedAt.Format("20060102")
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="master-of-games-%s.json"`, stamp))
+		_ = d.WriteJSON(w)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="master-of-games-%s-%s.csv"`, table, stamp))
+	_ = d.WriteCSV(w, table)
+}
+
+func (s *Server) handleData(w http.ResponseWriter, r *http.Request) {
+	s.renderData(w, DataVM{})
+}
+
+// handleImport accepts a JSON dataset or a single-table CSV upload (multipart field "file").
+// CSV uploads name their table in the "table" field.
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		s.renderData(w, DataVM{FormError: "Please choose a file to import (10 MB max)."})
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		s.renderData(w, DataVM{FormError: "Please choose a file to import."})
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	var d game.Dataset
+	var rowErrs []game.RowError
+	if strings.EqualFold(path.Ext(header.Filename), ".csv") {
+		table := r.FormValue("table")
+		if !slices.Contains(game.DatasetTables, table) {
+			s.renderData(w, DataVM{FormError: "Please choose which table the CSV file contains."})
+			return
+		}
+		d, rowErrs, err = game.ReadDatasetCSV(file, table)
+	} else {
+		d, err = game.ReadDatasetJSON(file)
+	}
+	if err != nil {
+		s.renderData(w, DataVM{FormError: err.Error()})
+		return
+	}
+
+	vm := DataVM{}
+	sum, planErrs, err := s.importDataset(r.Context(), d)
+	rowErrs = append(rowErrs, planErrs...)
+	switch {
+	case len(rowErrs) > 0:
+		vm.FormError = fmt.Sprintf("Nothing was imported: %d row(s) have errors.", len(rowErrs))
+		vm.RowErrors = rowErrs
+	case err != nil:
+		vm.FormError = "Import failed: " + err.Error()
+	default:
+		vm.Summary = &sum
+		setToast(w, "Import complete.")
+	}
+	s.renderData(w, vm)
+}
+
+func (s *Server) renderData(w http.ResponseWriter, vm DataVM) {
+	vm.Title = "Data"
+	vm.Version = s.meta.Version
+	vm.BuildTime = s.meta.BuildTime
+	vm.StartTime = s.meta.StartTime
+	vm.YearNow = s.now().Year()
+	vm.Tables = game.DatasetTables
+
+	if err := s.r.HTML(w, "data", "data", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// importDataset validates every row of d and, if all rows pass, imports it in one store call
+// and announces it so open pages refresh. Games already in the store are skipped. Row errors
+// mean nothing was written; the error is for store failures.
+func (s *Server) importDataset(ctx context.Context, d game.Dataset) (game.ImportSummary, []game.RowError, error) {
+	plan, skipped, rowErrs, err := s.planImport(ctx, d)
+	if err != nil || len(rowErrs) > 0 {
+		return game.ImportSummary{}, rowErrs, err
+	}
+
+	sum, err := s.store.ImportDataset(ctx, plan)
+	if err != nil {
+		return game.ImportSummary{}, nil, err
+	}
+	sum.GamesSkipped = skipped
+	s.publishImport(ctx, plan)
+	return sum, nil, nil
+}
+
+// planImport resolves names against the store plus the dataset's own players and titles,
+// runs each game through validateGame, checks rulesets and seasons, and drops games that
+// already exist.
+func (s *Server) planImport(ctx context.Context, d game.Dataset) (game.Dataset, int, []game.RowError, error) {
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		return game.Dataset{}, 0, nil, err
+	}
+	titles, err := s.store.ListTitles(ctx)
+	if err != nil {
+		return game.Dataset{}, 0, nil, err
+	}
+	existing, err := s.store.ListGames(ctx)
+	if err != nil {
+		return game.Dataset{}, 0, nil, err
+	}
+
+	var rowErrs []game.RowError
+	fail := func(table string, row int, format string, args ...any) {
+		rowErrs = append(rowErrs, game.RowError{Table: table, Row: row, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Names that don't exist yet get provisional negative IDs so validateGame can run on them.
+	playerIDs := map[string]int64{}
+	for _, p := range players {
+		playerIDs[p.Name] = p.ID
+	}
+	for i, p := range d.Players {
+		d.Players[i].Name = strings.TrimSpace(p.Name)
+		if d.Players[i].Name == "" {
+			fail("players", i+1, "name is required")
+			continue
+		}
+		if _, ok := playerIDs[d.Players[i].Name]; !ok {
+			playerIDs[d.Players[i].Name] = -int64(len(playerIDs) + 1)
+		}
+	}
+
+	titleIDs := map[string]int64{}
+	for _, t := range titles {
+		titleIDs[t.Name] = t.ID
+	}
+	for i, t := range d.Titles {
+		d.Titles[i].Name = strings.TrimSpace(t.Name)
+		if d.Titles[i].Name == "" {
+			fail("titles", i+1, "name is required")
+			continue
+		}
+		if _, ok := titleIDs[d.Titles[i].Name]; !ok {
+			id := -int64(len(titleIDs) + 1)
+			titleIDs[d.Titles[i].Name] = id
+			titles = append(titles, game.Title{ID: id, Name: d.Titles[i].Name, IsActive: t.IsActive})
+		}
+	}
+	titleNames := make(map[int64]string, len(titleIDs))
+	for name, id := range titleIDs {
+		titleNames[id] = name
+	}
+
+	resolve := func(table string, row int, names []string) ([]int64, bool) {
+		ids := make([]int64, 0, len(names))
+		ok := true
+		for _, n := range names {
+			id, found := playerIDs[n]
+			if !found {
+				fail(table, row, "unknown player %q", n)
+				ok = false
+				continue
+			}
+			ids = append(ids, id)
+		}
+		return ids, ok
+	}
+
+	seen := map[string]bool{}
+	for _, g := range existing {
+		seen[gameKey(g.PlayedAt, titleNames[g.TitleID], g.ParticipantIDs, g.WinnerIDs)] = true
+	}
+
+	skipped := 0
+	games := make([]game.DatasetGame, 0, len(d.Games))
+	for i, dg := range d.Games {
+		row := i + 1
+		titleID, ok := titleIDs[dg.Title]
+		if !ok {
+			fail("games", row, "unknown title %q", dg.Title)
+			continue
+		}
+		participants, pok := resolve("games", row, dg.Participants)
+		winners, wok := resolve("games", row, dg.Winners)
+		var results []game.Result
+		rok := true
+		for _, dr := range dg.Results {
+			id, ok := resolve("games", row, []string{dr.Player})
+			if !ok {
+				rok = false
+				continue
+			}
+			results = append(results, game.Result{PlayerID: id[0], Position: dr.Position, Score: dr.Score})
+		}
+		var teams [][]int64
+		for _, team := range dg.Teams {
+			ids, ok := resolve("games", row, team)
+			if !ok {
+				rok = false
+				continue
+			}
+			teams = append(teams, ids)
+		}
+		if !pok || !wok || !rok {
+			continue
+		}
+
+		if _, err := validateGame(gameInput{
+			TitleID:        titleID,
+			PlayedAt:       dg.PlayedAt.Format(time.RFC3339),
+			Mode:           dg.Mode,
+			ParticipantIDs: participants,
+			WinnerIDs:      winners,
+			Teams:          teams,
+			Results:        results,
+			Notes:          dg.Notes,
+		}, titles, s.loc); err != nil {
+			fail("games", row, "%s", err.Error())
+			continue
+		}
+
+		key := gameKey(dg.PlayedAt, dg.Title, participants, winners)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+
+		dg.Notes = strings.TrimSpace(dg.Notes)
+		games = append(games, dg)
+	}
+	d.Games = games
+
+	for i, tb := range d.Tiebreakers {
+		row := i + 1
+		if tb.Scope != "weekly" && tb.Scope != "yearly" && tb.Scope != "season" {
+			fail("tiebreakers", row, "scope must be weekly, yearly or season")
+			continue
+		}
+		if tb.ScopeKey == "" {
+			fail("tiebreakers", row, "scope_key is required")
+			continue
+		}
+		tied, ok := resolve("tiebreakers", row, tb.Tied)
+		if !ok {
+			continue
+		}
+		winner, ok := resolve("tiebreakers", row, []string{tb.Winner})
+		if !ok {
+			continue
+		}
+		if !containsInt64(tied, winner[0]) {
+			fail("tiebreakers", row, "winner %q is not one of the tied players", tb.Winner)
+			continue
+		}
+		if tb.Seed != "" {
+			drawn, err := game.VerifyDraw(game.Tiebreaker{
+				Scope:         tb.Scope,
+				ScopeKey:      tb.ScopeKey,
+				TiedPlayerIDs: tied,
+				Seed:          tb.Seed,
+				Algorithm:     tb.Algorithm,
+			})
+			if err != nil {
+				fail("tiebreakers", row, "%v", err)
+				continue
+			}
+			if drawn != winner[0] {
+				fail("tiebreakers", row, "the recorded draw does not pick %q", tb.Winner)
+				continue
+			}
+		}
+		if ref := tb.PlayoffGame; ref != nil {
+			if tb.Method != "" && tb.Method != game.MethodPlayoff {
+				fail("tiebreakers", row, "method must be playoff when a play-off game is given")
+				continue
+			}
+			found := false
+			for _, g := range existing {
+				found = found || (g.PlayedAt.Equal(ref.PlayedAt) && titleNames[g.TitleID] == ref.Title)
+			}
+			for _, dg := range d.Games {
+				found = found || (dg.PlayedAt.Equal(ref.PlayedAt) && dg.Title == ref.Title)
+			}
+			if !found {
+				fail("tiebreakers", row, "play-off game %s at %s is not in the league or the dataset", ref.Title, ref.PlayedAt.Format(time.RFC3339))
+				continue
+			}
+			d.Tiebreakers[i].Method = game.MethodPlayoff
+		} else if tb.Method == game.MethodPlayoff {
+			fail("tiebreakers", row, "a play-off needs its play-off game")
+			continue
+		}
+		if d.Tiebreakers[i].Method == "" {
+			d.Tiebreakers[i].Method = game.MethodChance
+		}
+	}
+
+	for i, dr := range d.Rulesets {
+		rs, err := dr.Ruleset(s.loc)
+		if err == nil {
+			err = rs.Validate()
+		}
+		if err != nil {
+			fail("rulesets", i+1, "%s", err.Error())
+		}
+	}
+
+	for i, ds := range d.Seasons {
+		se, err := ds.Season(s.loc)
+		if err == nil {
+			err = se.Validate()
+		}
+		if err != nil {
+			fail("seasons", i+1, "%s", err.Error())
+		}
+	}
+
+	return d, skipped, rowErrs, nil
+}
+
+// gameKey identifies a game for duplicate detection: same moment, title, table and winners.
+func gameKey(playedAt time.Time, title string, participants, winners []int64) string {
+	p := slices.Clone(participants)
+	wn := slices.Clone(winners)
+	slices.Sort(p)
+	slices.Sort(wn)
+	return fmt.Sprintf("%d|%s|%v|%v", playedAt.Unix(), title, p, wn)
+}
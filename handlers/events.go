@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// Event types published on the EventBus.
+const (
+	EventGameAdded     = "game.added"
+	EventGameUpdated   = "game.updated"
+	EventGameToggled   = "game.toggled"
+	EventTiebreakerSet = "tiebreaker.set"
+	EventImported      = "data.imported"
+)
+
+// Event is a change to the league that open pages may want to show straight away.
+type Event struct {
+	Type   string `json:"type"`
+	GameID int64  `json:"game_id,omitempty"`
+	// Topics are the periods whose standings may have changed, as "<scope>:<scope key>"
+	// ("weekly:2026-W07", "yearly:2026", "season:2026-06-01..2026-08-31"). A page listens
+	// for its own topic.
+	Topics []string  `json:"topics"`
+	At     time.Time `json:"at"`
+}
+
+// eventBuffer is how many events a subscriber can fall behind before it misses some.
+const eventBuffer = 16
+
+// EventBus fans events out to every subscriber in this process.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+	all  map[chan Event]struct{} // SubscribeAll subscribers, which never miss an event
+}
+
+// NewEventBus returns a bus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: map[chan Event]struct{}{}, all: map[chan Event]struct{}{}}
+}
+
+// Subscribe returns a channel receiving every event published from now on, and a function
+// that ends the subscription and closes the channel.
+func (b *EventBus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// SubscribeAll is Subscribe for a subscriber that must see every event, such as the webhook
+// worker: while it is busy, events queue up in memory instead of being dropped. Events still
+// queued when the subscription ends are dropped.
+func (b *EventBus) SubscribeAll() (<-chan Event, func()) {
+	in := make(chan Event, eventBuffer)
+	out := make(chan Event)
+	done := make(chan struct{})
+	b.mu.Lock()
+	b.all[in] = struct{}{}
+	b.mu.Unlock()
+
+	// Publish hands events to in, which this relay drains straight into the queue, so a
+	// publisher only ever waits for an append.
+	go func() {
+		defer close(out)
+		var queue []Event
+		for {
+			var send chan Event
+			var next Event
+			if len(queue) > 0 {
+				send, next = out, queue[0]
+			}
+			select {
+			case e := <-in:
+				queue = append(queue, e)
+			case send <- next:
+				queue = queue[1:]
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return out, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.all, in)
+			b.mu.Unlock()
+			close(done)
+		})
+	}
+}
+
+// Publish sends e to every subscriber without waiting: a subscriber whose buffer is full
+// misses it rather than holding up the request that made the change. SubscribeAll
+// subscribers get it however far behind they are. A nil bus drops every event.
+func (b *EventBus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	for ch := range b.all {
+		ch <- e
+	}
+}
+
+// ============================
+// Publishing
+// ============================
+
+// gameTopics returns the week, year and season topics of games played at each of times,
+// without duplicates. Zero times are skipped.
+func (s *Server) gameTopics(ctx context.Context, times ...time.Time) []string {
+	// Without the seasons, week and year pages still refresh; season pages catch up on
+	// their next load.
+	seasons, _ := s.store.ListSeasons(ctx)
+	var topics []string
+	add := func(topic string) {
+		if !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	for _, t := range times {
+		if t.IsZero() {
+			continue
+		}
+		t = t.In(s.loc)
+		y, w := t.ISOWeek()
+		add("weekly:" + game.WeekScopeKey(y, w))
+		add("yearly:" + game.YearScopeKey(t.Year()))
+		for _, se := range seasons {
+			if start, end := se.Bounds(s.loc); !t.Before(start) && t.Before(end) {
+				add("season:" + se.ScopeKey())
+			}
+		}
+	}
+	return topics
+}
+
+// publishGame announces a change of type to game g. For an edit, also pass the time the
+// game was played before it, so pages for the period it left refresh too.
+func (s *Server) publishGame(ctx context.Context, typ string, g game.Game, before ...time.Time) {
+	s.events.Publish(Event{Type: typ, GameID: g.ID, Topics: s.gameTopics(ctx, append(before, g.PlayedAt)...)})
+}
+
+// publishImport announces an import of d, naming the periods of its games and tiebreakers.
+func (s *Server) publishImport(ctx context.Context, d game.Dataset) {
+	times := make([]time.Time, 0, len(d.Games))
+	for _, g := range d.Games {
+		times = append(times, g.PlayedAt)
+	}
+	topics := s.gameTopics(ctx, times...)
+	for _, tb := range d.Tiebreakers {
+		if topic := tb.Scope + ":" + tb.ScopeKey; !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	if len(topics) > 0 {
+		s.events.Publish(Event{Type: EventImported, Topics: topics})
+	}
+}
+
+// findGame returns the stored game with id, or false if there is none or it can't be read.
+func (s *Server) findGame(ctx context.Context, id int64) (game.Game, bool) {
+	g, ok, err := s.store.GetGame(ctx, id)
+	return g, ok && err == nil
+}
+
+// setTiebreaker stores tb and announces it.
+func (s *Server) setTiebreaker(ctx context.Context, tb game.Tiebreaker) error {
+	if err := s.store.SetTiebreaker(ctx, tb); err != nil {
+		return err
+	}
+	s.events.Publish(Event{Type: EventTiebreakerSet, Topics: []string{tb.Scope + ":" + tb.ScopeKey}})
+	return nil
+}
+
+// ============================
+// Stream
+// ============================
+
+// sseKeepAlive is how often an idle stream gets a comment line, so proxies don't close it.
+const sseKeepAlive = 25 * time.Second
+
+// handleEvents streams every published Event as a Server-Sent Event whose data is the
+// event as JSON. Pages use it through base.go.html, which turns each topic into a
+// "live:<topic>" event on the body for hx-trigger to pick up.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(sseKeepAlive)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+		case <-ping.C:
+			_, _ = fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func TestEventBus_FansOutAndUnsubscribes(t *testing.T) {
+	b := NewEventBus()
+	a, stopA := b.Subscribe()
+	c, stopC := b.Subscribe()
+
+	b.Publish(Event{Type: EventGameAdded, GameID: 1})
+	for _, ch := range []<-chan Event{a, c} {
+		if e := <-ch; e.Type != EventGameAdded || e.GameID != 1 || e.At.IsZero() {
+			t.Errorf("event = %+v", e)
+		}
+	}
+
+	stopA()
+	stopA() // a second call is harmless
+	if _, ok := <-a; ok {
+		t.Error("channel still open after unsubscribing")
+	}
+	b.Publish(Event{Type: EventGameToggled})
+	if e := <-c; e.Type != EventGameToggled {
+		t.Errorf("event = %+v, want the remaining subscriber to get it", e)
+	}
+	stopC()
+}
+
+func TestEventBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	b := NewEventBus()
+	ch, stop := b.Subscribe()
+	defer stop()
+
+	for i := range eventBuffer + 5 {
+		b.Publish(Event{Type: EventGameAdded, GameID: int64(i)})
+	}
+	if len(ch) != eventBuffer {
+		t.Errorf("buffered = %d, want %d (the rest dropped)", len(ch), eventBuffer)
+	}
+
+	var nilBus *EventBus
+	nilBus.Publish(Event{Type: EventGameAdded}) // must not panic
+}
+
+func TestEventBus_SubscribeAllMissesNothing(t *testing.T) {
+	b := NewEventBus()
+	ch, stop := b.SubscribeAll()
+
+	// Nobody reads while these are published, and none of them is dropped.
+	n := 10 * eventBuffer
+	for i := range n {
+		b.Publish(Event{Type: EventGameAdded, GameID: int64(i)})
+	}
+	for i := range n {
+		if e := <-ch; e.GameID != int64(i) {
+			t.Fatalf("event %d = %+v, want game %d", i, e, i)
+		}
+	}
+
+	stop()
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after unsubscribing")
+	}
+	b.Publish(Event{Type: EventGameToggled}) // must not block
+}
+
+func TestGameTopics_LeagueTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC-6", -6*60*60)
+	s := &Server{store: game.NewMemoryStore(loc), loc: loc}
+	// The season ends on that Sunday, so the game is in it.
+	if _, err := s.store.AddSeason(context.Background(), game.Season{
+		Name:      "Winter",
+		StartDate: time.Date(2026, 1, 1, 0, 0, 0, 0, loc),
+		EndDate:   time.Date(2026, 2, 8, 0, 0, 0, 0, loc),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sunday := time.Date(2026, 2, 9, 3, 0, 0, 0, time.UTC) // Sunday evening in the league
+	got := s.gameTopics(context.Background(), sunday, time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC), time.Time{})
+	if want := []string{"weekly:2026-W06", "yearly:2026", "season:2026-01-01..2026-02-08"}; !slices.Equal(got, want) {
+		t.Errorf("topics = %v, want %v", got, want)
+	}
+}
+
+// readEvent reads the next data line from an event stream.
+func readEvent(t *testing.T, sc *bufio.Scanner) Event {
+	t.Helper()
+	for sc.Scan() {
+		line := sc.Text()
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var e Event
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				t.Fatalf("event data %q: %v", data, err)
+			}
+			return e
+		}
+	}
+	t.Fatalf("stream ended: %v", sc.Err())
+	return Event{}
+}
+
+func TestEvents_StreamsGameAndTiebreakerChanges(t *testing.T) {
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+
+	ts := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer ts.Close()
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	sc := bufio.NewScanner(resp.Body)
+	if !sc.Scan() || !strings.HasPrefix(sc.Text(), "retry:") {
+		t.Fatalf("first line = %q, want the retry hint", sc.Text())
+	}
+
+	// Two games on Monday 2026-01-05 tie the week.
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	} {
+		if w := doJSON(t, api, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("add game: status = %d (%s)", w.Code, w.Body.String())
+		}
+		e := readEvent(t, sc)
+		if e.Type != EventGameAdded || e.GameID == 0 || !slices.Equal(e.Topics, []string{"weekly:2026-W02", "yearly:2026"}) {
+			t.Errorf("event = %+v", e)
+		}
+	}
+
+	// Moving the first game into the next week refreshes both weeks.
+	w := doJSON(t, api, "PUT", "/api/v1/games/1", `{"title_id":1,"played_at":"2026-01-12T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("update game: status = %d (%s)", w.Code, w.Body.String())
+	}
+	e := readEvent(t, sc)
+	if want := []string{"weekly:2026-W02", "yearly:2026", "weekly:2026-W03"}; e.Type != EventGameUpdated || e.GameID != 1 || !slices.Equal(e.Topics, want) {
+		t.Errorf("event = %+v, want topics %v", e, want)
+	}
+
+	// Put it back, tie the week again and settle it.
+	doJSON(t, api, "PUT", "/api/v1/games/1", `{"title_id":1,"played_at":"2026-01-05T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	_ = readEvent(t, sc)
+	if w := doJSON(t, api, "POST", "/api/v1/weeks/2026/2/tiebreak", `{"winner_id":1}`); w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+	e = readEvent(t, sc)
+	if e.Type != EventTiebreakerSet || !slices.Equal(e.Topics, []string{"weekly:2026-W02"}) {
+		t.Errorf("event = %+v", e)
+	}
+}
+
+func TestEvents_ImportAnnouncesItsPeriods(t *testing.T) {
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+	ch, stop := s.events.Subscribe()
+	defer stop()
+
+	body := `{"version":1,"exported_at":"2026-02-01T00:00:00Z","players":[],"titles":[],
+		"games":[{"played_at":"2026-01-05T12:00:00Z","title":"Bang","participants":["ESMITH"],"winners":["ESMITH"],"notes":"","is_active":true}],
+		"tiebreakers":[{"scope":"yearly","scope_key":"2025","tied":["ESMITH"],"winner":"ESMITH","method":"manual","decided_at":"2025-12-31T17:00:00Z"}]}`
+	if w := doJSON(t, api, "POST", "/api/v1/import", body); w.Code != http.StatusOK {
+		t.Fatalf("import: status = %d (%s)", w.Code, w.Body.String())
+	}
+	e := <-ch
+	if want := []string{"weekly:2026-W02", "yearly:2026", "yearly:2025"}; e.Type != EventImported || !slices.Equal(e.Topics, want) {
+		t.Errorf("event = %+v, want topics %v", e, want)
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func (s *Server) handleYearH2H(w http.ResponseWriter, r *http.Request) {
+	year, ok := pathInt(r, "year")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Unknown or malformed title IDs fall back to all titles.
+	titleID, _ := strconv.ParseInt(r.URL.Query().Get("title"), 10, 64)
+
+	games, err := s.store.GetYear(r.Context(), year)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	titles, err := s.store.ListTitles(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Only offer titles that were played this year.
+	playedTitles := map[int64]bool{}
+	for _, g := range games {
+		playedTitles[g.TitleID] = true
+	}
+	var filter []game.Title
+	for _, t := range titles {
+		if playedTitles[t.ID] {
+			filter = append(filter, t)
+		}
+	}
+
+	h := game.ComputeHeadToHead(games, titleID)
+
+	vm := YearH2HVM{
+		Title:     "Head-to-Head",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Year:      year,
+		Titles:    filter,
+		TitleID:   titleID,
+	}
+	for _, pid := range h.PlayerIDs {
+		vm.Players = append(vm.Players, pMap[pid].Name)
+	}
+	for _, a := range h.PlayerIDs {
+		row := h2hRowVM{Name: pMap[a].Name}
+		for _, b := range h.PlayerIDs {
+			rec := h.Record(a, b)
+			cell := h2hCellVM{Self: a == b, Games: rec.Games, Wins: rec.Wins, Losses: rec.Losses}
+			if !cell.Self && rec.Games > 0 {
+				cell.Tip = fmt.Sprintf("%s vs %s: %d–%d in %d games",
+					pMap[a].Name, pMap[b].Name, rec.Wins, rec.Losses, rec.Games)
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		vm.Rows = append(vm.Rows, row)
+	}
+
+	if err := s.r.HTML(w, "year_h2h", "year_h2h", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -2,13 +2,12 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
 	"net/http"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/eithansmith/master-of-games/game"
 )
@@ -37,33 +36,39 @@ func (s *Server) newHomeVM(ctx context.Context) (HomeVM, error) {
 		return HomeVM{}, err
 	}
 
+	editForms := make(map[int64]HomeForm, len(recentGames))
+	for _, g := range recentGames {
+		f := gameForm(g, s.loc)
+		if !titleIsActive(allTitles, g.TitleID) {
+			f.InactiveTitle = g.Title
+		}
+		editForms[g.ID] = f
+	}
+
 	vm := HomeVM{
 		Title:        "Master of Games",
 		Version:      s.meta.Version,
 		BuildTime:    s.meta.BuildTime,
 		StartTime:    s.meta.StartTime,
-		YearNow:      time.Now().Year(),
+		YearNow:      s.now().Year(),
 		Players:      players,
 		PlayerNames:  pMap,
 		Titles:       titles,
 		Games:        recentGames,
 		ShowAllGames: true,
 		Form:         s.defaultHomeForm(players, titles),
+		EditForms:    editForms,
 	}
 	return vm, nil
 }
 
 func (s *Server) defaultHomeForm(_ []game.Player, _ []game.Title) HomeForm {
-	loc, err := time.LoadLocation("America/Chicago")
-	if err != nil {
-		loc = time.UTC
-	}
-
 	return HomeForm{
 		TitleID:      0,
-		PlayedAt:     time.Now().In(loc).Format("2006-01-02T15:04"),
+		PlayedAt:     s.now().Format("2006-01-02T15:04"),
 		Participants: map[int64]bool{},
 		Winners:      map[int64]bool{},
+		Mode:         game.ModeCompetitive,
 		Notes:        "",
 	}
 }
@@ -100,92 +105,72 @@ func (s *Server) handleAddGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	titleIDStr := strings.TrimSpace(r.FormValue("title_id"))
-	playedAtStr := strings.TrimSpace(r.FormValue("played_at"))
-	notes := strings.TrimSpace(r.FormValue("notes"))
-
-	loc, err := time.LoadLocation("America/Chicago")
+	g, form, err := parseGameForm(r, allTitles, s.loc)
 	if err != nil {
-		loc = time.UTC
-	}
-
-	if playedAtStr == "" {
-		playedAtStr = time.Now().In(loc).Format("2006-01-02T15:04")
-	}
-
-	titleID, err := strconv.ParseInt(titleIDStr, 10, 64)
-	if err != nil || titleID <= 0 {
-		form := HomeForm{
-			TitleID:      0,
-			PlayedAt:     playedAtStr,
-			Participants: parseInt64Map(r.Form["participants"]),
-			Winners:      parseInt64Map(r.Form["winners"]),
-			Notes:        notes,
-		}
-		s.renderHomeWithError(r.Context(), w, "Please select a valid game title.", form)
+		s.renderHomeWithError(r.Context(), w, err.Error(), form)
 		return
 	}
 
-	participantIDs := parseInt64Slice(r.Form["participants"])
-	winnerIDs := parseInt64Slice(r.Form["winners"])
-
-	form := HomeForm{
-		TitleID:      titleID,
-		PlayedAt:     playedAtStr,
-		Participants: parseInt64Map(r.Form["participants"]),
-		Winners:      parseInt64Map(r.Form["winners"]),
-		Notes:        notes,
+	saved, err := s.store.AddGame(r.Context(), g)
+	if err != nil {
+		s.renderHomeWithError(r.Context(), w, "Unable to save game.", form)
+		return
 	}
+	s.publishGame(r.Context(), EventGameAdded, saved)
 
-	playedAt, err := time.Parse("2006-01-02T15:04", playedAtStr)
+	// HTMX will swap #main, but a redirect works fine too.
+	vm, err := s.newHomeVM(r.Context())
 	if err != nil {
-		s.renderHomeWithError(r.Context(), w, "Please provide a valid date/time.", form)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if !game.IsWeekdayLocal(playedAt) {
-		s.renderHomeWithError(r.Context(), w, "Only weekday games are allowed (Mon–Fri).", form)
-		return
+	vm.Form = s.defaultHomeForm(vm.Players, vm.Titles)
+	setToast(w, "Game saved.")
+	if err := s.r.HTML(w, "main", "home", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
+}
 
-	if len(participantIDs) == 0 {
-		s.renderHomeWithError(r.Context(), w, "Please select at least one participant.", form)
+func (s *Server) handleUpdateGame(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
 
-	if len(winnerIDs) == 0 {
-		s.renderHomeWithError(r.Context(), w, "Please select at least one winner.", form)
+	allTitles, err := s.store.ListTitles(r.Context())
+	if err != nil {
+		s.renderHomeWithEditError(r.Context(), w, id, "Unable to load title list.", HomeForm{})
 		return
 	}
 
-	if !isSubset(winnerIDs, participantIDs) {
-		s.renderHomeWithError(r.Context(), w, "Winners must also be selected as participants.", form)
+	if err := r.ParseForm(); err != nil {
+		s.renderHomeWithEditError(r.Context(), w, id, "Invalid form submission.", HomeForm{})
 		return
 	}
 
-	g := game.Game{
-		TitleID:        titleID,
-		PlayedAt:       playedAt,
-		ParticipantIDs: participantIDs,
-		WinnerIDs:      winnerIDs,
-		Notes:          notes,
+	old, _ := s.findGame(r.Context(), id)
+	g, form, err := parseGameForm(r, editableTitles(allTitles, old.TitleID), s.loc)
+	if err != nil {
+		s.renderHomeWithEditError(r.Context(), w, id, err.Error(), form)
+		return
 	}
 
-	_, err = s.store.AddGame(r.Context(), g)
-	if err != nil {
-		s.renderHomeWithError(r.Context(), w, "Unable to save game.", form)
+	g.ID = id
+	if err := s.store.UpdateGame(r.Context(), g); err != nil {
+		s.renderHomeWithEditError(r.Context(), w, id, "Unable to update game.", form)
 		return
 	}
+	s.publishGame(r.Context(), EventGameUpdated, g, old.PlayedAt)
 
-	// HTMX will swap #main, but a redirect works fine too.
 	vm, err := s.newHomeVM(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	vm.Form = s.defaultHomeForm(vm.Players, vm.Titles)
-	setToast(w, "Game saved.")
+	setToast(w, "Game updated.")
 	if err := s.r.HTML(w, "main", "home", vm); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
@@ -202,6 +187,9 @@ func (s *Server) handleDeleteGame(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if g, ok := s.findGame(r.Context(), id); ok {
+		s.publishGame(r.Context(), EventGameToggled, g)
+	}
 
 	vm, err := s.newHomeVM(r.Context())
 	if err != nil {
@@ -231,6 +219,9 @@ func (s *Server) handleGameToggle(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if g, ok := s.findGame(r.Context(), id); ok {
+		s.publishGame(r.Context(), EventGameToggled, g)
+	}
 
 	vm, err := s.newHomeVM(r.Context())
 	if err != nil {
@@ -253,7 +244,7 @@ func (s *Server) handleGameToggle(w http.ResponseWriter, r *http.Request) {
 // ============================
 
 func (s *Server) handleWeekCurrent(w http.ResponseWriter, r *http.Request) {
-	year, week := time.Now().ISOWeek()
+	year, week := s.now().ISOWeek()
 	http.Redirect(w, r, fmt.Sprintf("/weeks/%d/%d", year, week), http.StatusSeeOther)
 }
 
@@ -265,7 +256,7 @@ func (s *Server) handleWeek(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.renderWeek(r.Context(), w, year, week, "")
+	s.renderWeek(r.Context(), w, pageLayout(r, "week"), year, week, "")
 }
 
 func (s *Server) handleWeekTiebreak(w http.ResponseWriter, r *http.Request) {
@@ -278,7 +269,7 @@ func (s *Server) handleWeekTiebreak(w http.ResponseWriter, r *http.Request) {
 	s.handleWeekTiebreakPost(w, r, year, week)
 }
 
-func (s *Server) renderWeek(ctx context.Context, w http.ResponseWriter, year, week int, formErr string) {
+func (s *Server) renderWeek(ctx context.Context, w http.ResponseWriter, layout string, year, week int, formErr string) {
 	allPlayers, err := s.store.ListPlayers(ctx)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -290,11 +281,13 @@ func (s *Server) renderWeek(ctx context.Context, w http.ResponseWriter, year, we
 		pMap[p.ID] = p
 	}
 
-	var years []int
-	yNow := time.Now().Year()
-	for y := yNow - 2; y <= yNow+1; y++ {
-		years = append(years, y)
+	allGames, err := s.store.ListGames(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	yNow := s.now().Year()
+	years := leagueYears(allGames, s.loc, min(year, yNow), max(year, yNow+1))
 
 	weeks := make([]int, 0, isoWeeksInYear(year))
 	for wNum := 1; wNum <= isoWeeksInYear(year); wNum++ {
@@ -310,10 +303,17 @@ func (s *Server) renderWeek(ctx context.Context, w http.ResponseWriter, year, we
 		return
 	}
 
+	start, _ := game.WeekBounds(year, week, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
 		return s.store.GetTiebreaker(ctx, scope, scopeKey)
 	}
-	ws := game.ComputeWeekStandings(gamesByWeek, year, week, getTB)
+	ws := game.ComputeWeekStandings(gamesByWeek, year, week, s.loc, rules.Weekly, getTB)
 
 	vm := WeekVM{
 		Title:         "Week",
@@ -339,61 +339,71 @@ func (s *Server) renderWeek(ctx context.Context, w http.ResponseWriter, year, we
 		TopIDs:        ws.TopIDs,
 		WinnerID:      ws.WinnerID,
 		TieUnresolved: ws.TieUnresolved,
+		RulesName:     rules.Name,
+		RulesSummary:  describeRules(rules.Weekly),
+		Metric:        rules.Weekly.Metric,
+		TieNote:       tieNote(ws.Standings),
+		DrawURL:       s.drawURL(ctx, ws.Standings),
+		StaleNote:     staleNote(ws.Standings, pMap),
+		HistoryURL:    s.historyURL(ctx, ws.Standings),
+		PlayoffGames:  s.playoffGames(ctx, ws.Standings, start, pMap),
+		LiveTopic:     "weekly:" + ws.ScopeKey,
 		FormError:     formErr,
 	}
+	if _, ok, err := s.store.GetWeekRecap(ctx, year, week); err == nil && ok {
+		vm.RecapURL = recapPath(year, week)
+	}
 
-	if err := s.r.HTML(w, "week", "week", vm); err != nil {
+	if err := s.r.HTML(w, layout, "week", vm); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
 func (s *Server) handleWeekTiebreakPost(w http.ResponseWriter, r *http.Request, year, week int) {
 	if err := r.ParseForm(); err != nil {
-		s.renderWeek(r.Context(), w, year, week, "Invalid form submission.")
+		s.renderWeek(r.Context(), w, "main", year, week, "Invalid form submission.")
 		return
 	}
 
-	gamesByWeek, err := s.store.GetWeek(r.Context(), year, week)
-	if err != nil {
-		s.renderWeek(r.Context(), w, year, week, "Unable to load games for this week.")
+	if err := s.setWeekTiebreaker(r.Context(), year, week, parseTiebreakChoice(r)); err != nil {
+		s.renderWeek(r.Context(), w, "main", year, week, err.Error())
 		return
 	}
 
+	s.renderWeek(r.Context(), w, "main", year, week, "Tiebreaker saved.")
+}
+
+// setWeekTiebreaker records the tiebreaker settling a tied week as c says.
+// Error messages are user-facing; both the week page and the API show them as-is.
+func (s *Server) setWeekTiebreaker(ctx context.Context, year, week int, c tiebreakChoice) error {
+	gamesByWeek, err := s.store.GetWeek(ctx, year, week)
+	if err != nil {
+		return errors.New("Unable to load games for this week.")
+	}
+
+	start, _ := game.WeekBounds(year, week, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		return errors.New("Unable to load the rules for this week.")
+	}
+
 	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
-		return s.store.GetTiebreaker(r.Context(), scope, scopeKey)
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
 	}
-	ws := game.ComputeWeekStandings(gamesByWeek, year, week, getTB)
+	ws := game.ComputeWeekStandings(gamesByWeek, year, week, s.loc, rules.Weekly, getTB)
 
 	if ws.TotalGames == 0 {
-		s.renderWeek(r.Context(), w, year, week, "No games were played this week—no tiebreaker needed.")
-		return
+		return errors.New("No games were played this week—no tiebreaker needed.")
 	}
 	if len(ws.TopIDs) <= 1 {
-		s.renderWeek(r.Context(), w, year, week, "This week is not tied—no tiebreaker needed.")
-		return
+		return errors.New("This week is not tied—no tiebreaker needed.")
 	}
 
-	winnerID, err := strconv.ParseInt(r.FormValue("winner_id"), 10, 64)
-	if err != nil || !containsInt64(ws.TopIDs, winnerID) {
-		s.renderWeek(r.Context(), w, year, week, "Please select a valid winner from the tied leaders.")
-		return
-	}
-
-	tb := game.Tiebreaker{
-		Scope:         "weekly",
-		ScopeKey:      ws.ScopeKey,
-		TiedPlayerIDs: ws.TopIDs,
-		WinnerID:      winnerID,
-		Method:        "chance",
-		DecidedAt:     time.Now(),
-	}
-	err = s.store.SetTiebreaker(r.Context(), tb)
+	tb, err := s.decideTiebreaker(ctx, "weekly", ws.ScopeKey, ws.TopIDs, c)
 	if err != nil {
-		s.renderWeek(r.Context(), w, year, week, err.Error())
-		return
+		return err
 	}
-
-	s.renderWeek(r.Context(), w, year, week, "Tiebreaker saved.")
+	return s.setTiebreaker(ctx, tb)
 }
 
 func (s *Server) handleYear(w http.ResponseWriter, r *http.Request) {
@@ -403,7 +413,7 @@ func (s *Server) handleYear(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.renderYear(r.Context(), w, year, "")
+	s.renderYear(r.Context(), w, pageLayout(r, "year"), year, "")
 }
 
 func (s *Server) handleYearTiebreak(w http.ResponseWriter, r *http.Request) {
@@ -415,7 +425,7 @@ func (s *Server) handleYearTiebreak(w http.ResponseWriter, r *http.Request) {
 	s.handleYearTiebreakPost(w, r, year)
 }
 
-func (s *Server) renderYear(ctx context.Context, w http.ResponseWriter, year int, formErr string) {
+func (s *Server) renderYear(ctx context.Context, w http.ResponseWriter, layout string, year int, formErr string) {
 	allPlayers, err := s.store.ListPlayers(ctx)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -433,18 +443,27 @@ func (s *Server) renderYear(ctx context.Context, w http.ResponseWriter, year int
 		return
 	}
 
+	start, _ := game.YearBounds(year, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
 		return s.store.GetTiebreaker(ctx, scope, scopeKey)
 	}
-	ys := game.ComputeYearStandings(gamesByYear, year, getTB)
+	ys := game.ComputeYearStandings(gamesByYear, year, s.loc, rules.Yearly, getTB)
 
 	vm := YearVM{
 		Title:         "Year",
 		Version:       s.meta.Version,
 		BuildTime:     s.meta.BuildTime,
 		StartTime:     s.meta.StartTime,
-		YearNow:       time.Now().Year(),
+		YearNow:       s.now().Year(),
 		Year:          year,
+		PrevYear:      year - 1,
+		NextYear:      year + 1,
 		Players:       allPlayers,
 		PlayerMap:     pMap,
 		Stats:         ys.Stats,
@@ -452,57 +471,64 @@ func (s *Server) renderYear(ctx context.Context, w http.ResponseWriter, year int
 		TopIDs:        ys.TopIDs,
 		WinnerID:      ys.WinnerID,
 		TieUnresolved: ys.TieUnresolved,
+		RulesName:     rules.Name,
+		RulesSummary:  describeRules(rules.Yearly),
+		TieNote:       tieNote(ys.Standings),
+		DrawURL:       s.drawURL(ctx, ys.Standings),
+		StaleNote:     staleNote(ys.Standings, pMap),
+		HistoryURL:    s.historyURL(ctx, ys.Standings),
+		PlayoffGames:  s.playoffGames(ctx, ys.Standings, start, pMap),
+		LiveTopic:     "yearly:" + ys.ScopeKey,
 		FormError:     formErr,
 	}
 
-	if err := s.r.HTML(w, "year", "year", vm); err != nil {
+	if err := s.r.HTML(w, layout, "year", vm); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
 func (s *Server) handleYearTiebreakPost(w http.ResponseWriter, r *http.Request, year int) {
 	if err := r.ParseForm(); err != nil {
-		s.renderYear(r.Context(), w, year, "Invalid form submission.")
+		s.renderYear(r.Context(), w, "main", year, "Invalid form submission.")
 		return
 	}
 
-	gamesByYear, err := s.store.GetYear(r.Context(), year)
-	if err != nil {
-		s.renderYear(r.Context(), w, year, "Unable to load games for this year.")
+	if err := s.setYearTiebreaker(r.Context(), year, parseTiebreakChoice(r)); err != nil {
+		s.renderYear(r.Context(), w, "main", year, err.Error())
 		return
 	}
 
-	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
-		return s.store.GetTiebreaker(r.Context(), scope, scopeKey)
+	s.renderYear(r.Context(), w, "main", year, "Tiebreaker saved.")
+}
+
+// setYearTiebreaker records the tiebreaker settling a tied year as c says.
+// Error messages are user-facing; both the year page and the API show them as-is.
+func (s *Server) setYearTiebreaker(ctx context.Context, year int, c tiebreakChoice) error {
+	gamesByYear, err := s.store.GetYear(ctx, year)
+	if err != nil {
+		return errors.New("Unable to load games for this year.")
 	}
-	ys := game.ComputeYearStandings(gamesByYear, year, getTB)
 
-	if len(ys.TopIDs) <= 1 {
-		s.renderYear(r.Context(), w, year, "This year is not tied—no tiebreaker needed.")
-		return
+	start, _ := game.YearBounds(year, s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		return errors.New("Unable to load the rules for this year.")
 	}
 
-	winnerID, err := strconv.ParseInt(r.FormValue("winner_id"), 10, 64)
-	if err != nil || !containsInt64(ys.TopIDs, winnerID) {
-		s.renderYear(r.Context(), w, year, "Please select a valid winner from the tied leaders.")
-		return
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
 	}
+	ys := game.ComputeYearStandings(gamesByYear, year, s.loc, rules.Yearly, getTB)
 
-	tb := game.Tiebreaker{
-		Scope:         "yearly",
-		ScopeKey:      ys.ScopeKey,
-		TiedPlayerIDs: ys.TopIDs,
-		WinnerID:      winnerID,
-		Method:        "chance",
-		DecidedAt:     time.Now(),
+	if len(ys.TopIDs) <= 1 {
+		return errors.New("This year is not tied—no tiebreaker needed.")
 	}
-	err = s.store.SetTiebreaker(r.Context(), tb)
+
+	tb, err := s.decideTiebreaker(ctx, "yearly", ys.ScopeKey, ys.TopIDs, c)
 	if err != nil {
-		s.renderYear(r.Context(), w, year, err.Error())
-		return
+		return err
 	}
-
-	s.renderYear(r.Context(), w, year, "Tiebreaker saved.")
+	return s.setTiebreaker(ctx, tb)
 }
 
 func (s *Server) handleYearRace(w http.ResponseWriter, r *http.Request) {
@@ -517,8 +543,9 @@ func (s *Server) handleYearRace(w http.ResponseWriter, r *http.Request) {
 		Version:   s.meta.Version,
 		BuildTime: s.meta.BuildTime,
 		StartTime: s.meta.StartTime,
-		YearNow:   time.Now().Year(),
+		YearNow:   s.now().Year(),
 		Year:      year,
+		LiveTopic: "yearly:" + game.YearScopeKey(year),
 	}
 
 	if err := s.r.HTML(w, "year_race", "year_race", vm); err != nil {
@@ -545,7 +572,7 @@ func (s *Server) handleYearRaceChart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	race := game.ComputeYearRace(games, year, game.RaceMetricWins, 5, players)
+	race := game.ComputeYearRace(games, year, s.loc, game.RaceMetricWins, 5, players)
 
 	vm := buildYearRaceChartVM(race)
 
@@ -726,7 +753,7 @@ func (s *Server) handlePlayers(w http.ResponseWriter, r *http.Request) {
 		Version:   s.meta.Version,
 		BuildTime: s.meta.BuildTime,
 		StartTime: s.meta.StartTime,
-		YearNow:   time.Now().Year(),
+		YearNow:   s.now().Year(),
 		Players:   players,
 	}
 	if err := s.r.HTML(w, "players", "players", vm); err != nil {
@@ -755,7 +782,7 @@ func (s *Server) renderPlayers(ctx context.Context, w http.ResponseWriter, errMs
 		Version:   s.meta.Version,
 		BuildTime: s.meta.BuildTime,
 		StartTime: s.meta.StartTime,
-		YearNow:   time.Now().Year(),
+		YearNow:   s.now().Year(),
 		Players:   players,
 		FormError: errMsg,
 	}
@@ -839,13 +866,19 @@ func (s *Server) handleTitles(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	specialists, err := s.specialistNames(r.Context(), titles)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	vm := TitlesVM{
-		Title:     "Titles",
-		Version:   s.meta.Version,
-		BuildTime: s.meta.BuildTime,
-		StartTime: s.meta.StartTime,
-		YearNow:   time.Now().Year(),
-		Titles:    titles,
+		Title:       "Titles",
+		Version:     s.meta.Version,
+		BuildTime:   s.meta.BuildTime,
+		StartTime:   s.meta.StartTime,
+		YearNow:     s.now().Year(),
+		Titles:      titles,
+		Specialists: specialists,
 	}
 	if err := s.r.HTML(w, "titles", "titles", vm); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -867,15 +900,21 @@ func (s *Server) renderTitles(ctx context.Context, w http.ResponseWriter, errMsg
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	specialists, err := s.specialistNames(ctx, titles)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	vm := TitlesVM{
-		Title:     "Titles",
-		Version:   s.meta.Version,
-		BuildTime: s.meta.BuildTime,
-		StartTime: s.meta.StartTime,
-		YearNow:   time.Now().Year(),
-		Titles:    titles,
-		FormError: errMsg,
+		Title:       "Titles",
+		Version:     s.meta.Version,
+		BuildTime:   s.meta.BuildTime,
+		StartTime:   s.meta.StartTime,
+		YearNow:     s.now().Year(),
+		Titles:      titles,
+		FormError:   errMsg,
+		Specialists: specialists,
 	}
 	if err := s.r.HTML(w, "main", "titles", vm); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -964,3 +1003,22 @@ func (s *Server) renderHomeWithError(ctx context.Context, w http.ResponseWriter,
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
+
+// renderHomeWithEditError re-renders the home page with the edit form for one game reopened.
+// An empty form keeps the stored values for that game.
+func (s *Server) renderHomeWithEditError(ctx context.Context, w http.ResponseWriter, id int64, msg string, form HomeForm) {
+	vm, err := s.newHomeVM(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	vm.EditGameID = id
+	vm.EditError = msg
+	if form.Participants != nil {
+		form.InactiveTitle = vm.EditForms[id].InactiveTitle
+		vm.EditForms[id] = form
+	}
+	if err := s.r.HTML(w, "main", "home", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -19,6 +19,15 @@ func setToast(w http.ResponseWriter, message string) {
 	w.Header().Set("HX-Trigger", string(b))
 }
 
+// pageLayout is the layout to render page in: the whole page, or just its main block for an
+// HTMX request swapping #main.
+func pageLayout(r *http.Request, page string) string {
+	if r.Header.Get("HX-Request") == "true" {
+		return "main"
+	}
+	return page
+}
+
 func activePlayers(all []game.Player) []game.Player {
 	out := make([]game.Player, 0, len(all))
 	for _, p := range all {
@@ -222,3 +231,358 @@ func addTitle(r *http.Request, s *Server) error {
 	}
 	return nil
 }
+
+// gameInput is a game submission before validation, shared by the HTML forms and the JSON API.
+type gameInput struct {
+	TitleID        int64
+	PlayedAt       string // "2006-01-02T15:04" from the forms; the API may also send RFC 3339
+	Mode           string // empty means competitive
+	ParticipantIDs []int64
+	WinnerIDs      []int64
+	Teams          [][]int64     // team games only
+	Results        []game.Result // optional placements/scores
+	Notes          string
+}
+
+// validateGame applies the rules every logged game must satisfy and builds the game to store.
+// Times without an offset are read in loc, and the weekday rule is checked in loc.
+// Error messages are user-facing.
+func validateGame(in gameInput, titles []game.Title, loc *time.Location) (game.Game, error) {
+	if in.TitleID <= 0 || !titleIsActive(titles, in.TitleID) {
+		return game.Game{}, errors.New("Please select a valid game title.")
+	}
+
+	playedAt, err := time.ParseInLocation("2006-01-02T15:04", in.PlayedAt, loc)
+	if err != nil {
+		playedAt, err = time.Parse(time.RFC3339, in.PlayedAt)
+	}
+	if err != nil {
+		return game.Game{}, errors.New("Please provide a valid date/time.")
+	}
+	playedAt = playedAt.In(loc)
+
+	if !game.IsWeekdayLocal(playedAt) {
+		return game.Game{}, errors.New("Only weekday games are allowed (Mon–Fri).")
+	}
+
+	if len(in.ParticipantIDs) == 0 {
+		return game.Game{}, errors.New("Please select at least one participant.")
+	}
+
+	mode := game.Game{Mode: in.Mode}.GameMode()
+	if mode != game.ModeCompetitive && mode != game.ModeTeam && mode != game.ModeCoop {
+		return game.Game{}, errors.New("Please choose a valid game type.")
+	}
+
+	// A co-op game the table lost has no winners.
+	if len(in.WinnerIDs) == 0 && mode != game.ModeCoop {
+		return game.Game{}, errors.New("Please select at least one winner.")
+	}
+
+	if !isSubset(in.WinnerIDs, in.ParticipantIDs) {
+		return game.Game{}, errors.New("Winners must also be selected as participants.")
+	}
+
+	if mode == game.ModeCoop && len(in.WinnerIDs) > 0 && !isSubset(in.ParticipantIDs, in.WinnerIDs) {
+		return game.Game{}, errors.New("In a co-op game the whole table wins or loses together.")
+	}
+
+	if err := validateTeams(mode, in); err != nil {
+		return game.Game{}, err
+	}
+
+	results, err := validateResults(in)
+	if err != nil {
+		return game.Game{}, err
+	}
+	if mode == game.ModeCoop && (game.Game{Results: results}).HasPlacements() {
+		return game.Game{}, errors.New("Placements are only for competitive and team games.")
+	}
+
+	g := game.Game{
+		TitleID:        in.TitleID,
+		PlayedAt:       playedAt,
+		Mode:           mode,
+		ParticipantIDs: in.ParticipantIDs,
+		WinnerIDs:      in.WinnerIDs,
+		Results:        results,
+		Notes:          strings.TrimSpace(in.Notes),
+	}
+	if mode == game.ModeTeam {
+		g.Teams = in.Teams
+	}
+	return g, nil
+}
+
+// validateTeams checks that a team game splits every participant onto exactly one of at
+// least two teams, and that the winners are one whole team. Other modes can't have teams.
+func validateTeams(mode string, in gameInput) error {
+	if mode != game.ModeTeam {
+		if len(in.Teams) > 0 {
+			return errors.New("Teams are only for team games.")
+		}
+		return nil
+	}
+
+	if len(in.Teams) < 2 {
+		return errors.New("Please split the participants into at least two teams.")
+	}
+	seen := map[int64]bool{}
+	for _, team := range in.Teams {
+		if len(team) == 0 {
+			return errors.New("Teams can't be empty.")
+		}
+		for _, pid := range team {
+			if seen[pid] || !containsInt64(in.ParticipantIDs, pid) {
+				return errors.New("Every participant must be on exactly one team.")
+			}
+			seen[pid] = true
+		}
+	}
+	for _, pid := range in.ParticipantIDs {
+		if !seen[pid] {
+			return errors.New("Every participant must be on exactly one team.")
+		}
+	}
+
+	for _, team := range in.Teams {
+		if isSubset(team, in.WinnerIDs) && isSubset(in.WinnerIDs, team) {
+			return nil
+		}
+	}
+	return errors.New("Winners must be exactly one whole team.")
+}
+
+// validateResults checks a game's optional placements and scores and returns them ordered by
+// position (unplaced last). Results with neither a position nor a score are dropped. If anyone
+// is placed, everyone must be, and the winners must be exactly the players placed first.
+func validateResults(in gameInput) ([]game.Result, error) {
+	var results []game.Result
+	seen := map[int64]bool{}
+	placed := 0
+	for _, r := range in.Results {
+		if !containsInt64(in.ParticipantIDs, r.PlayerID) {
+			return nil, errors.New("Placements and scores can only be given for participants.")
+		}
+		if seen[r.PlayerID] {
+			return nil, errors.New("Each participant can only have one placement and score.")
+		}
+		seen[r.PlayerID] = true
+		if r.Position < 0 || r.Position > len(in.ParticipantIDs) {
+			return nil, errors.New("Placements must be between 1 and the number of participants.")
+		}
+		if r.Position == 0 && r.Score == nil {
+			continue
+		}
+		if r.Position > 0 {
+			placed++
+		}
+		results = append(results, r)
+	}
+
+	if placed > 0 {
+		if placed != len(in.ParticipantIDs) {
+			return nil, errors.New("Please place every participant, or none.")
+		}
+		var first []int64
+		for _, r := range results {
+			if r.Position == 1 {
+				first = append(first, r.PlayerID)
+			}
+		}
+		if len(first) != len(in.WinnerIDs) || !isSubset(first, in.WinnerIDs) {
+			return nil, errors.New("Winners must be exactly the players placed first.")
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		pi, pj := results[i].Position, results[j].Position
+		return pi != 0 && (pj == 0 || pi < pj)
+	})
+	return results, nil
+}
+
+// parseGameForm reads a game submission (add or edit) and applies the home-page validation rules.
+// The returned HomeForm always reflects what was submitted, so it can be re-rendered on error.
+func parseGameForm(r *http.Request, titles []game.Title, loc *time.Location) (game.Game, HomeForm, error) {
+	titleIDStr := strings.TrimSpace(r.FormValue("title_id"))
+	playedAtStr := strings.TrimSpace(r.FormValue("played_at"))
+	notes := strings.TrimSpace(r.FormValue("notes"))
+
+	if playedAtStr == "" {
+		playedAtStr = time.Now().In(loc).Format("2006-01-02T15:04")
+	}
+
+	titleID, _ := strconv.ParseInt(titleIDStr, 10, 64)
+
+	form := HomeForm{
+		TitleID:      max(titleID, 0),
+		PlayedAt:     playedAtStr,
+		Participants: parseInt64Map(r.Form["participants"]),
+		Winners:      parseInt64Map(r.Form["winners"]),
+		Positions:    map[int64]string{},
+		Scores:       map[int64]string{},
+		TeamOf:       map[int64]string{},
+		Notes:        notes,
+	}
+	if !titleIsActive(titles, titleID) {
+		form.TitleID = 0
+	}
+
+	mode := strings.TrimSpace(r.FormValue("mode"))
+	form.Mode = game.Game{Mode: mode}.GameMode()
+	form.CoopResult = r.FormValue("coop_result")
+
+	// Placement, score and team boxes are shown for every player; only participants' are read.
+	participants := parseInt64Slice(r.Form["participants"])
+	winners := parseInt64Slice(r.Form["winners"])
+	var results []game.Result
+	var resultErr error
+	for _, pid := range participants {
+		pos := strings.TrimSpace(r.FormValue(fmt.Sprintf("position_%d", pid)))
+		score := strings.TrimSpace(r.FormValue(fmt.Sprintf("score_%d", pid)))
+		form.Positions[pid], form.Scores[pid] = pos, score
+		form.HasResults = form.HasResults || pos != "" || score != ""
+
+		res := game.Result{PlayerID: pid}
+		if pos != "" {
+			n, err := strconv.Atoi(pos)
+			if err != nil {
+				resultErr = errors.New("Placements and scores must be whole numbers.")
+			}
+			res.Position = n
+		}
+		if score != "" {
+			n, err := strconv.Atoi(score)
+			if err != nil {
+				resultErr = errors.New("Placements and scores must be whole numbers.")
+			}
+			res.Score = &n
+		}
+		results = append(results, res)
+	}
+
+	// Team numbers and the co-op outcome are form-only inputs, so their errors are reported
+	// before validateGame's (which couldn't explain what went wrong).
+	var teams [][]int64
+	if form.Mode == game.ModeTeam {
+		var teamErr error
+		byNumber := map[int][]int64{}
+		for _, pid := range participants {
+			v := strings.TrimSpace(r.FormValue(fmt.Sprintf("team_%d", pid)))
+			form.TeamOf[pid] = v
+			if v == "" {
+				continue
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				teamErr = errors.New("Team numbers must be whole numbers from 1.")
+				continue
+			}
+			byNumber[n] = append(byNumber[n], pid)
+		}
+		if teamErr != nil {
+			return game.Game{}, form, teamErr
+		}
+		numbers := make([]int, 0, len(byNumber))
+		for n := range byNumber {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		for _, n := range numbers {
+			teams = append(teams, byNumber[n])
+		}
+	}
+
+	// Co-op games ask whether the table won instead of who won.
+	if form.Mode == game.ModeCoop {
+		switch form.CoopResult {
+		case "won":
+			winners = participants
+		case "lost":
+			winners = nil
+		default:
+			return game.Game{}, form, errors.New("Please choose whether the table won or lost.")
+		}
+	}
+
+	g, err := validateGame(gameInput{
+		TitleID:        titleID,
+		PlayedAt:       playedAtStr,
+		Mode:           mode,
+		ParticipantIDs: participants,
+		WinnerIDs:      winners,
+		Teams:          teams,
+		Results:        results,
+		Notes:          notes,
+	}, titles, loc)
+	if err == nil && resultErr != nil {
+		err = resultErr
+	}
+	return g, form, err
+}
+
+// titleIsActive returns true iff id names an active title in titles.
+func titleIsActive(titles []game.Title, id int64) bool {
+	for _, t := range titles {
+		if t.ID == id {
+			return t.IsActive
+		}
+	}
+	return false
+}
+
+// editableTitles is the titles an existing game on title currentID may be saved with: the
+// active ones plus its own, even if that has since been deactivated.
+func editableTitles(titles []game.Title, currentID int64) []game.Title {
+	out := make([]game.Title, len(titles))
+	for i, t := range titles {
+		if t.ID == currentID {
+			t.IsActive = true
+		}
+		out[i] = t
+	}
+	return out
+}
+
+// gameForm pre-fills an edit form from a stored game, showing its time in loc.
+func gameForm(g game.Game, loc *time.Location) HomeForm {
+	form := HomeForm{
+		TitleID:      g.TitleID,
+		PlayedAt:     g.PlayedAt.In(loc).Format("2006-01-02T15:04"),
+		Participants: make(map[int64]bool, len(g.ParticipantIDs)),
+		Winners:      make(map[int64]bool, len(g.WinnerIDs)),
+		Positions:    make(map[int64]string, len(g.Results)),
+		Scores:       make(map[int64]string, len(g.Results)),
+		Notes:        g.Notes,
+	}
+	for _, id := range g.ParticipantIDs {
+		form.Participants[id] = true
+	}
+	for _, id := range g.WinnerIDs {
+		form.Winners[id] = true
+	}
+	form.Mode = g.GameMode()
+	form.TeamOf = make(map[int64]string, len(g.ParticipantIDs))
+	for i, team := range g.Teams {
+		for _, pid := range team {
+			form.TeamOf[pid] = strconv.Itoa(i + 1)
+		}
+	}
+	if form.Mode == game.ModeCoop {
+		form.CoopResult = "lost"
+		if len(g.WinnerIDs) > 0 {
+			form.CoopResult = "won"
+		}
+	}
+	form.HasResults = len(g.Results) > 0
+	for _, r := range g.Results {
+		if r.Position > 0 {
+			form.Positions[r.PlayerID] = strconv.Itoa(r.Position)
+		}
+		if r.Score != nil {
+			form.Scores[r.PlayerID] = strconv.Itoa(*r.Score)
+		}
+	}
+	return form
+}
@@ -3,7 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
 )
 
 // ============================
@@ -255,3 +260,217 @@ func TestSetToast_EscapesSpecialChars(t *testing.T) {
 		t.Fatalf("HX-Trigger with special chars is not valid JSON: %v", err)
 	}
 }
+
+// ============================
+// parseGameForm
+// ============================
+
+func TestParseGameForm(t *testing.T) {
+	titles := []game.Title{
+		{ID: 1, Name: "Coup", IsActive: true},
+		{ID: 2, Name: "Bang", IsActive: false},
+	}
+	valid := func() url.Values {
+		return url.Values{
+			"title_id":     {"1"},
+			"played_at":    {"2026-01-05T12:30"}, // Monday
+			"participants": {"1", "2"},
+			"winners":      {"2"},
+			"notes":        {"  close one  "},
+		}
+	}
+
+	cases := []struct {
+		name    string
+		edit    func(v url.Values)
+		wantErr string
+	}{
+		{"valid", func(url.Values) {}, ""},
+		{"missing title", func(v url.Values) { v.Del("title_id") }, "valid game title"},
+		{"inactive title", func(v url.Values) { v.Set("title_id", "2") }, "valid game title"},
+		{"bad date", func(v url.Values) { v.Set("played_at", "yesterday") }, "valid date/time"},
+		{"weekend", func(v url.Values) { v.Set("played_at", "2026-01-10T12:30") }, "weekday"},
+		{"no participants", func(v url.Values) { v.Del("participants") }, "participant"},
+		{"no winners", func(v url.Values) { v.Del("winners") }, "winner"},
+		{"winner not participant", func(v url.Values) { v.Set("winners", "3") }, "also be selected"},
+		{"placements", func(v url.Values) { v.Set("position_1", "2"); v.Set("position_2", "1"); v.Set("score_2", "40") }, ""},
+		{"non-participant score ignored", func(v url.Values) { v.Set("score_3", "12") }, ""},
+		{"partial placements", func(v url.Values) { v.Set("position_2", "1") }, "every participant"},
+		{"first place not winner", func(v url.Values) { v.Set("position_1", "1"); v.Set("position_2", "2") }, "placed first"},
+		{"placement out of range", func(v url.Values) { v.Set("position_1", "3"); v.Set("position_2", "1") }, "between 1"},
+		{"score not a number", func(v url.Values) { v.Set("score_1", "lots") }, "whole numbers"},
+		{"unknown mode", func(v url.Values) { v.Set("mode", "solo") }, "valid game type"},
+		{"team missing number", func(v url.Values) {
+			v.Set("mode", "team")
+			v.Add("participants", "3")
+			v.Set("team_1", "1")
+			v.Set("team_2", "2")
+		}, "exactly one team"},
+		{"team bad number", func(v url.Values) { v.Set("mode", "team"); v.Set("team_1", "0"); v.Set("team_2", "1") }, "from 1"},
+		{"single team", func(v url.Values) { v.Set("mode", "team"); v.Set("team_1", "1"); v.Set("team_2", "1") }, "at least two teams"},
+		{"coop without outcome", func(v url.Values) { v.Set("mode", "coop") }, "won or lost"},
+		{"coop placements", func(v url.Values) {
+			v.Set("mode", "coop")
+			v.Set("coop_result", "won")
+			v.Set("position_1", "1")
+			v.Set("position_2", "1")
+		}, "competitive and team"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := valid()
+			tc.edit(v)
+			r := httptest.NewRequest("POST", "/games", strings.NewReader(v.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+
+			g, form, err := parseGameForm(r, titles, time.UTC)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if g.TitleID != 1 || len(g.ParticipantIDs) != 2 || g.WinnerIDs[0] != 2 || g.Notes != "close one" {
+					t.Errorf("parsed game = %+v", g)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want containing %q", err, tc.wantErr)
+			}
+			if form.Participants == nil || form.Winners == nil {
+				t.Error("form maps should be populated for re-rendering")
+			}
+		})
+	}
+}
+
+func TestParseGameForm_Modes(t *testing.T) {
+	titles := []game.Title{{ID: 1, Name: "Coup", IsActive: true}}
+	parse := func(v url.Values) (game.Game, HomeForm, error) {
+		v.Set("title_id", "1")
+		v.Set("played_at", "2026-01-05T12:30")
+		r := httptest.NewRequest("POST", "/games", strings.NewReader(v.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		return parseGameForm(r, titles, time.UTC)
+	}
+
+	g, _, err := parse(url.Values{
+		"mode": {"team"}, "participants": {"1", "2", "3", "4"}, "winners": {"2", "4"},
+		"team_1": {"2"}, "team_2": {"1"}, "team_3": {"2"}, "team_4": {"1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Mode != game.ModeTeam || len(g.Teams) != 2 || g.Teams[0][0] != 2 || g.Teams[0][1] != 4 || g.Teams[1][0] != 1 {
+		t.Errorf("team game = %+v", g)
+	}
+
+	_, _, err = parse(url.Values{
+		"mode": {"team"}, "participants": {"1", "2", "3", "4"}, "winners": {"2"},
+		"team_1": {"2"}, "team_2": {"1"}, "team_3": {"2"}, "team_4": {"1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "whole team") {
+		t.Errorf("partial team win: err = %v", err)
+	}
+
+	g, _, err = parse(url.Values{"mode": {"coop"}, "participants": {"1", "2"}, "coop_result": {"won"}, "winners": {"1"}})
+	if err != nil || len(g.WinnerIDs) != 2 || g.Mode != game.ModeCoop {
+		t.Errorf("coop win = %+v, err = %v", g, err)
+	}
+
+	g, form, err := parse(url.Values{"mode": {"coop"}, "participants": {"1", "2"}, "coop_result": {"lost"}})
+	if err != nil || len(g.WinnerIDs) != 0 || len(g.Teams) != 0 {
+		t.Errorf("coop loss = %+v, err = %v", g, err)
+	}
+	if form.Mode != game.ModeCoop || form.CoopResult != "lost" {
+		t.Errorf("coop form = %+v", form)
+	}
+}
+
+func TestGameForm_RoundTrip(t *testing.T) {
+	g := game.Game{
+		TitleID:        3,
+		PlayedAt:       time.Date(2026, 1, 5, 12, 30, 0, 0, time.UTC),
+		ParticipantIDs: []int64{1, 2},
+		WinnerIDs:      []int64{1},
+		Notes:          "n",
+	}
+	f := gameForm(g, time.UTC)
+	if f.PlayedAt != "2026-01-05T12:30" || !f.Participants[2] || !f.Winners[1] || f.Winners[2] {
+		t.Errorf("gameForm = %+v", f)
+	}
+
+	score := 7
+	g.Results = []game.Result{{PlayerID: 1, Position: 1, Score: &score}, {PlayerID: 2, Position: 2}}
+	f = gameForm(g, time.UTC)
+	if !f.HasResults || f.Positions[1] != "1" || f.Scores[1] != "7" || f.Positions[2] != "2" || f.Scores[2] != "" {
+		t.Errorf("gameForm results = %+v / %+v", f.Positions, f.Scores)
+	}
+}
+
+func TestValidateGame_LeagueTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	titles := []game.Title{{ID: 1, Name: "Coup", IsActive: true}}
+	in := func(playedAt string) gameInput {
+		return gameInput{TitleID: 1, PlayedAt: playedAt, ParticipantIDs: []int64{1}, WinnerIDs: []int64{1}}
+	}
+
+	// Friday 11:30pm in Chicago is Saturday in UTC, but it's a weekday game for the league.
+	g, err := validateGame(in("2026-01-09T23:30"), titles, loc)
+	if err != nil {
+		t.Fatalf("late Friday rejected: %v", err)
+	}
+	if got := g.PlayedAt.UTC().Format(time.RFC3339); got != "2026-01-10T05:30:00Z" {
+		t.Errorf("PlayedAt = %s, want 2026-01-10T05:30:00Z", got)
+	}
+	if f := gameForm(g, loc); f.PlayedAt != "2026-01-09T23:30" {
+		t.Errorf("edit form shows %s, want the league-local 2026-01-09T23:30", f.PlayedAt)
+	}
+
+	// RFC 3339 times are converted to league time before the weekday check.
+	if _, err := validateGame(in("2026-01-10T03:00:00Z"), titles, loc); err != nil {
+		t.Errorf("Friday 9pm Chicago given in UTC rejected: %v", err)
+	}
+	if _, err := validateGame(in("2026-01-05T03:00:00Z"), titles, loc); err == nil {
+		t.Error("Sunday 9pm Chicago given in UTC should be rejected")
+	}
+}
+
+func TestValidateResults_OrdersByPosition(t *testing.T) {
+	score := 3
+	in := gameInput{
+		ParticipantIDs: []int64{1, 2, 3},
+		WinnerIDs:      []int64{2, 3},
+		Results: []game.Result{
+			{PlayerID: 1, Position: 3, Score: &score},
+			{PlayerID: 2, Position: 1},
+			{PlayerID: 3, Position: 1},
+		},
+	}
+	got, err := validateResults(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].PlayerID != 2 || got[1].PlayerID != 3 || got[2].PlayerID != 1 {
+		t.Errorf("results = %+v, want tied winners first", got)
+	}
+
+	in.Results = []game.Result{{PlayerID: 1}, {PlayerID: 2, Score: &score}}
+	got, err = validateResults(in)
+	if err != nil || len(got) != 1 || got[0].PlayerID != 2 {
+		t.Errorf("results = %+v, %v; want the empty result dropped", got, err)
+	}
+
+	in.Results = []game.Result{{PlayerID: 2, Score: &score}, {PlayerID: 2, Score: &score}}
+	if _, err := validateResults(in); err == nil || !strings.Contains(err.Error(), "only have one") {
+		t.Errorf("err = %v, want duplicate error", err)
+	}
+}
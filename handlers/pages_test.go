@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// pageTestServer returns a server rendering the real templates, with players 1 and 2 named
+// Alice and Bob, and a handler for every route signed in as an admin.
+func pageTestServer(t *testing.T) (*Server, http.Handler) {
+	t.Helper()
+	// Templates are loaded relative to the repository root.
+	t.Chdir("..")
+	ctx := context.Background()
+	s := New(game.NewMemoryStore(time.UTC), nil, Meta{}, time.UTC)
+	_ = s.store.UpdatePlayer(ctx, 1, "Alice")
+	_ = s.store.UpdatePlayer(ctx, 2, "Bob")
+
+	mux := http.NewServeMux()
+	s.RegisterRoutes(mux)
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+	return s, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			api.ServeHTTP(w, r)
+			return
+		}
+		r.AddCookie(&http.Cookie{Name: sessionCookie, Value: "token-admin"})
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func getPage(t *testing.T, h http.Handler, path string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d (%s)", path, w.Code, w.Body.String())
+	}
+	return w.Body.String()
+}
+
+func TestPlayerProfilePage(t *testing.T) {
+	_, h := pageTestServer(t)
+	addTestGames(t, h,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-07T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":2,"played_at":"2025-01-08T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	)
+
+	// Alice's favorite and best title is Bang, title 1.
+	body := getPage(t, h, "/players/1")
+	if !strings.Contains(body, "Alice") || !strings.Contains(body, "Bang") {
+		t.Errorf("profile of Alice doesn't name her or her best title:\n%s", body)
+	}
+}
+
+func TestHomePage_EditFormOffersInactiveTitle(t *testing.T) {
+	s, h := pageTestServer(t)
+	addTestGames(t, h, `{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`)
+	if err := s.store.SetTitleActive(context.Background(), 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	body := getPage(t, h, "/")
+	if !strings.Contains(body, `<option value="1" selected>Bang (inactive)</option>`) {
+		t.Errorf("edit form doesn't keep the game's deactivated title:\n%s", body)
+	}
+}
+
+func TestSeasonPage_ListensForItsTopic(t *testing.T) {
+	s, h := pageTestServer(t)
+	se, err := s.store.AddSeason(context.Background(), game.Season{
+		Name:      "Winter",
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := getPage(t, h, fmt.Sprintf("/seasons/%d", se.ID))
+	if !strings.Contains(body, `hx-trigger="live:season:2025-01-01..2025-03-31 from:body"`) {
+		t.Errorf("season page doesn't refresh on its topic:\n%s", body)
+	}
+}
+
+func TestWeekRecapPage_FlagsAWeekChangedSinceTheRecap(t *testing.T) {
+	s, h := pageTestServer(t)
+	addTestGames(t, h,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-07T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	)
+	if _, _, err := s.WriteWeekRecap(context.Background(), 2025, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	const changed = "The week has changed since this recap was written."
+	body := getPage(t, h, "/weeks/2025/2/recap")
+	if !strings.Contains(body, "needs a tiebreaker") || strings.Contains(body, changed) {
+		t.Errorf("recap of the tied week:\n%s", body)
+	}
+
+	// Settling the tie afterwards shows the week's current result next to the recap.
+	if w := doJSON(t, h, "POST", "/api/v1/weeks/2025/2/tiebreak", `{"winner_id":1}`); w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+	body = getPage(t, h, "/weeks/2025/2/recap")
+	if !strings.Contains(body, changed) || !strings.Contains(body, "It now stands: Alice wins week 2025-W02") {
+		t.Errorf("recap of the settled week:\n%s", body)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func (s *Server) handlePlayerProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+	ctx := r.Context()
+
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+	player, ok := pMap[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	titles, err := s.store.ListTitles(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	titleNames := make(map[int64]string, len(titles))
+	for _, t := range titles {
+		titleNames[t.ID] = t.Name
+	}
+
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rulesets, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	pp := game.ComputePlayerProfile(games, id, s.loc, s.now(), rulesets, getTB)
+
+	vm := PlayerProfileVM{
+		Title:      player.Name,
+		Version:    s.meta.Version,
+		BuildTime:  s.meta.BuildTime,
+		StartTime:  s.meta.StartTime,
+		YearNow:    s.now().Year(),
+		Player:     player,
+		Profile:    pp,
+		PlayerMap:  pMap,
+		TitleNames: titleNames,
+	}
+	for _, g := range pp.Recent {
+		vm.Recent = append(vm.Recent, profileGameVM{
+			Date:         g.PlayedAt.In(s.loc).Format("Mon Jan 2, 2006"),
+			Title:        titleNames[g.TitleID],
+			Won:          containsInt64(g.WinnerIDs, id),
+			Participants: len(g.ParticipantIDs),
+		})
+	}
+
+	if err := s.r.HTML(w, "player", "player", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func (s *Server) handleRatings(w http.ResponseWriter, r *http.Request) {
+	games, err := s.store.ListGames(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pMap := make(map[int64]string, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p.Name
+	}
+
+	ratings := game.ComputeRatings(games)
+
+	vm := RatingsVM{
+		Title:     "Ratings",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+	}
+	for i, pr := range ratings.Players {
+		vm.Rows = append(vm.Rows, buildRatingRowVM(i+1, pMap[pr.PlayerID], pr))
+	}
+
+	if err := s.r.HTML(w, "ratings", "ratings", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func buildRatingRowVM(rank int, name string, pr game.PlayerRating) ratingRowVM {
+	row := ratingRowVM{
+		Rank:   rank,
+		Name:   name,
+		Rating: int(math.Round(pr.Rating)),
+		Peak:   int(math.Round(pr.Peak)),
+		Games:  pr.Games,
+		Wins:   pr.Wins,
+		Spark:  ratingSparkline(pr.History, 160, 32),
+	}
+
+	// Newest first reads better in the expanded history list.
+	for i := len(pr.History) - 1; i >= 0; i-- {
+		p := pr.History[i]
+		row.History = append(row.History, ratingHistoryVM{
+			Date:   p.PlayedAt.Format("2006-01-02"),
+			Title:  p.Title,
+			Won:    p.Won,
+			Delta:  fmt.Sprintf("%+.1f", p.Delta),
+			Rating: int(math.Round(p.Rating)),
+		})
+	}
+	return row
+}
+
+// ratingSparkline returns an SVG path "d" for a player's rating history,
+// starting from the default rating and scaled to fit w×h.
+func ratingSparkline(history []game.RatingPoint, w, h float64) string {
+	values := make([]float64, 0, len(history)+1)
+	values = append(values, game.DefaultRating)
+	for _, p := range history {
+		values = append(values, p.Rating)
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	span := hi - lo
+	if span < 1 {
+		span = 1
+	}
+
+	var d string
+	for i, v := range values {
+		x := w * float64(i) / float64(len(values)-1)
+		y := h - (h * (v - lo) / span)
+		if i == 0 {
+			d = fmt.Sprintf("M %.2f %.2f", x, y)
+		} else {
+			d += fmt.Sprintf(" L %.2f %.2f", x, y)
+		}
+	}
+	return d
+}
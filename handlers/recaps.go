@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// Weekly recaps: once a week closes (game.WeekClose), WriteWeekRecap computes and stores its
+// game.WeekRecap, and RecapClosedWeeks, which cmd/server runs, hands it to every
+// RecapNotifier.
+
+// RecapNotifier is told about each weekly recap, e.g. to post it to chat. A recap is told
+// again if telling any notifier failed, so notifiers must cope with hearing it twice.
+type RecapNotifier interface {
+	NotifyWeekRecap(ctx context.Context, r game.WeekRecap) error
+}
+
+// RecapClosedWeeks recaps and announces every week that has closed since the last recapped
+// one, or just the last closed week if none has been recapped yet. Weeks go in order, and a
+// week's recap is marked notified once every notifier has it, so one that couldn't be
+// announced is tried again on the next call before any later week is recapped.
+func (s *Server) RecapClosedWeeks(ctx context.Context, notifiers ...RecapNotifier) error {
+	year, week := game.LastClosedWeek(s.now(), s.loc)
+	if last, ok, err := s.store.LastWeekRecap(ctx); err != nil {
+		return err
+	} else if ok {
+		year, week = last.Year, last.Week
+	}
+
+	for ; !s.now().Before(game.WeekClose(year, week, s.loc)); year, week = nextWeek(year, week, s.loc) {
+		r, written, err := s.WriteWeekRecap(ctx, year, week)
+		if err != nil {
+			return fmt.Errorf("%s: %w", game.WeekScopeKey(year, week), err)
+		}
+		if written {
+			log.Printf("recaps: wrote %s", r.ScopeKey)
+		}
+		if r.NotifiedAt != nil {
+			continue
+		}
+		// A week nobody played isn't news.
+		if r.TotalGames > 0 {
+			for _, n := range notifiers {
+				if err := n.NotifyWeekRecap(ctx, r); err != nil {
+					return fmt.Errorf("notify %s: %w", r.ScopeKey, err)
+				}
+			}
+		}
+		if err := s.store.MarkWeekRecapNotified(ctx, year, week, s.now()); err != nil {
+			return fmt.Errorf("%s: %w", r.ScopeKey, err)
+		}
+	}
+	return nil
+}
+
+// nextWeek returns the ISO year and week after `week` of `year`.
+func nextWeek(year, week int, loc *time.Location) (int, int) {
+	start, _ := game.WeekBounds(year, week, loc)
+	return start.AddDate(0, 0, 7).ISOWeek()
+}
+
+// WriteWeekRecap writes the recap of ISO week `week` of `year`, which must have closed, and
+// returns it. A week is recapped once: if it already has a recap, possibly written at the
+// same moment by another app instance, that is returned and written is false.
+func (s *Server) WriteWeekRecap(ctx context.Context, year, week int) (r game.WeekRecap, written bool, err error) {
+	if r, ok, err := s.store.GetWeekRecap(ctx, year, week); err != nil || ok {
+		return r, false, err
+	}
+	if s.now().Before(game.WeekClose(year, week, s.loc)) {
+		return game.WeekRecap{}, false, fmt.Errorf("week %s has not closed yet", game.WeekScopeKey(year, week))
+	}
+
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		return game.WeekRecap{}, false, err
+	}
+	rulesets, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		return game.WeekRecap{}, false, err
+	}
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+
+	r = game.ComputeWeekRecap(games, year, week, s.loc, rulesets, getTB)
+	r.CreatedAt = s.now()
+	return s.store.AddWeekRecap(ctx, r)
+}
+
+// playerMap returns every player by ID.
+func (s *Server) playerMap(ctx context.Context) (map[int64]game.Player, error) {
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+	return pMap, nil
+}
+
+// recapPath is the page showing a week's recap.
+func recapPath(year, week int) string {
+	return fmt.Sprintf("/weeks/%d/%d/recap", year, week)
+}
+
+// recapHeadline is the one-sentence result of the week r recaps, e.g. "Alice wins week
+// 2026-W03 with 4 wins in 9 games."
+func recapHeadline(r game.WeekRecap, pMap map[int64]game.Player) string {
+	switch {
+	case r.TotalGames == 0:
+		return fmt.Sprintf("No games were played in week %s.", r.ScopeKey)
+	case r.WinnerID != nil:
+		text := fmt.Sprintf("%s wins week %s with %s in %s.", pMap[*r.WinnerID].Name, r.ScopeKey,
+			plural(r.Wins[*r.WinnerID], "win"), plural(r.TotalGames, "game"))
+		if note := tieNote(game.Standings{WinnerID: r.WinnerID, DecidedBy: r.DecidedBy}); note != "" {
+			text += " " + note
+		}
+		return text
+	case r.TieUnresolved:
+		return fmt.Sprintf("Week %s closed tied between %s and needs a tiebreaker.", r.ScopeKey, joinNames(r.TopIDs, pMap))
+	}
+	return fmt.Sprintf("Week %s closed with no winner after %s.", r.ScopeKey, plural(r.TotalGames, "game"))
+}
+
+// currentRecap is r's result part brought up to date with ws, the week's standings now.
+func currentRecap(r game.WeekRecap, ws apiWeekStandings) game.WeekRecap {
+	r.TotalGames, r.Wins, r.TopIDs = ws.TotalGames, ws.Wins, ws.TopIDs
+	r.WinnerID, r.DecidedBy, r.TieUnresolved = ws.WinnerID, ws.DecidedBy, ws.TieUnresolved
+	return r
+}
+
+// recapChanged reports whether the week's result in now differs from the recap r, e.g.
+// because a tiebreaker settled it or a game was edited after r was written.
+func recapChanged(r, now game.WeekRecap) bool {
+	sameWinner := (r.WinnerID == nil) == (now.WinnerID == nil) && (r.WinnerID == nil || *r.WinnerID == *now.WinnerID)
+	return !sameWinner || r.TotalGames != now.TotalGames || r.TieUnresolved != now.TieUnresolved ||
+		!slices.Equal(r.TopIDs, now.TopIDs) || !maps.Equal(r.Wins, now.Wins)
+}
+
+// streakText describes a streak, e.g. "Alice has won 3 weeks in a row."
+func streakText(st game.RecapStreak, pMap map[int64]game.Player) string {
+	what := "games"
+	if st.Kind == game.StreakWeeklyTitles {
+		what = "weeks"
+	}
+	return fmt.Sprintf("%s has won %d %s in a row.", pMap[st.PlayerID].Name, st.Length, what)
+}
+
+// recapText is the recap as a short paragraph for chat: the headline, the most played
+// titles and any notable streaks.
+func recapText(r game.WeekRecap, pMap map[int64]game.Player) string {
+	parts := []string{recapHeadline(r, pMap)}
+	if len(r.Titles) > 0 {
+		played := make([]string, 0, 3)
+		for _, t := range r.Titles[:min(3, len(r.Titles))] {
+			played = append(played, fmt.Sprintf("%s (%d)", t.Title, t.Plays))
+		}
+		parts = append(parts, "Most played: "+strings.Join(played, ", ")+".")
+	}
+	for _, st := range r.Streaks {
+		parts = append(parts, streakText(st, pMap))
+	}
+	return strings.Join(parts, " ")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// ============================
+// Page
+// ============================
+
+func (s *Server) handleWeekRecap(w http.ResponseWriter, r *http.Request) {
+	year, ok1 := pathInt(r, "year")
+	week, ok2 := pathInt(r, "week")
+	if !ok1 || !ok2 || week < 1 || week > 53 {
+		http.NotFound(w, r)
+		return
+	}
+	ctx := r.Context()
+
+	recap, found, err := s.store.GetWeekRecap(ctx, year, week)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap, err := s.playerMap(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	const layoutTime = "Mon Jan 2, 2006 3:04 PM"
+	closes := game.WeekClose(year, week, s.loc)
+	vm := WeekRecapVM{
+		Title:     "Week recap",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Year:      year,
+		Week:      week,
+		ScopeKey:  game.WeekScopeKey(year, week),
+		Found:     found,
+		Closed:    !s.now().Before(closes),
+		ClosesAt:  closes.Format(layoutTime),
+	}
+	if found {
+		ws, err := s.apiWeek(ctx, year, week)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if now := currentRecap(recap, ws); recapChanged(recap, now) {
+			vm.Current = recapHeadline(now, pMap)
+		}
+		vm.Headline = recapHeadline(recap, pMap)
+		vm.Written = recap.CreatedAt.In(s.loc).Format(layoutTime)
+		vm.TotalGames = recap.TotalGames
+		vm.HasWinner = recap.WinnerID != nil
+		vm.TieUnresolved = recap.TieUnresolved
+		vm.Titles = recap.Titles
+		for id, n := range recap.Wins {
+			vm.Wins = append(vm.Wins, recapWinVM{
+				PlayerID: id,
+				Name:     pMap[id].Name,
+				Wins:     n,
+				Leader:   slices.Contains(recap.TopIDs, id),
+			})
+		}
+		sort.Slice(vm.Wins, func(i, j int) bool {
+			if vm.Wins[i].Wins != vm.Wins[j].Wins {
+				return vm.Wins[i].Wins > vm.Wins[j].Wins
+			}
+			return vm.Wins[i].Name < vm.Wins[j].Name
+		})
+		for _, st := range recap.Streaks {
+			vm.Streaks = append(vm.Streaks, streakText(st, pMap))
+		}
+	}
+
+	if err := s.r.HTML(w, pageLayout(r, "week_recap"), "week_recap", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
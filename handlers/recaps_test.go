@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// recapTestServer returns a server with players 1 and 2 named Alice and Bob, an API handler
+// signed in as an admin, and the name of title 1.
+func recapTestServer(t *testing.T) (*Server, http.Handler, string) {
+	t.Helper()
+	ctx := context.Background()
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+	_ = s.store.UpdatePlayer(ctx, 1, "Alice")
+	_ = s.store.UpdatePlayer(ctx, 2, "Bob")
+	titles, err := s.store.ListTitles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ti := range titles {
+		if ti.ID == 1 {
+			return s, api, ti.Name
+		}
+	}
+	t.Fatal("no title 1")
+	return nil, nil, ""
+}
+
+func addTestGames(t *testing.T, api http.Handler, bodies ...string) {
+	t.Helper()
+	for _, body := range bodies {
+		if w := doJSON(t, api, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("add game: status = %d (%s)", w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestWriteWeekRecap_OncePerClosedWeek(t *testing.T) {
+	ctx := context.Background()
+	s, api, title := recapTestServer(t)
+
+	// 2025-W02: Alice wins two of three games.
+	addTestGames(t, api,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-07T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2025-01-08T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+	)
+	if w := doJSON(t, api, "GET", "/api/v1/weeks/2025/2/recap", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("recap before it is written: status = %d, want 404", w.Code)
+	}
+
+	r, written, err := s.WriteWeekRecap(ctx, 2025, 2)
+	if err != nil || !written {
+		t.Fatalf("WriteWeekRecap = %v, %v; want written", written, err)
+	}
+	if r.WinnerID == nil || *r.WinnerID != 1 || r.TotalGames != 3 || r.CreatedAt.IsZero() {
+		t.Errorf("recap = %+v, want Alice winning 3 games", r)
+	}
+
+	// A later edit to the week doesn't rewrite its recap.
+	addTestGames(t, api, `{"title_id":1,"played_at":"2025-01-09T12:00","participant_ids":[1,2],"winner_ids":[2]}`)
+	again, written, err := s.WriteWeekRecap(ctx, 2025, 2)
+	if err != nil || written || again.TotalGames != 3 || !again.CreatedAt.Equal(r.CreatedAt) {
+		t.Errorf("second WriteWeekRecap = %+v, %v, %v; want the first recap unchanged", again, written, err)
+	}
+
+	w := doJSON(t, api, "GET", "/api/v1/weeks/2025/2/recap", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("recap: status = %d (%s)", w.Code, w.Body.String())
+	}
+	var got apiWeekRecap
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Alice wins week 2025-W02 with 2 wins in 3 games. Most played: " + title + " (3)."; got.Text != want {
+		t.Errorf("text = %q, want %q", got.Text, want)
+	}
+	if got.ScopeKey != "2025-W02" || got.Wins[1] != 2 || got.Wins[2] != 1 || len(got.Titles) != 1 || got.Titles[0].Plays != 3 {
+		t.Errorf("recap = %+v", got)
+	}
+
+	// A week that hasn't closed can't be recapped yet.
+	y, wk := time.Now().AddDate(0, 0, 14).ISOWeek()
+	if _, _, err := s.WriteWeekRecap(ctx, y, wk); err == nil {
+		t.Errorf("WriteWeekRecap(%d-W%02d) succeeded before the week closed", y, wk)
+	}
+}
+
+func TestWebhookWorker_NotifyWeekRecap(t *testing.T) {
+	ctx := context.Background()
+	s, api, _ := recapTestServer(t)
+	addTestGames(t, api,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-07T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	)
+	recaps, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "recaps", URL: "http://recaps.invalid", Secret: "s", Events: []string{game.WebhookWeekRecap}, IsActive: true})
+	ties, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "ties", URL: "http://ties.invalid", Secret: "s", Events: []string{game.WebhookTieNeedsBreaking}, IsActive: true})
+
+	r, _, err := s.WriteWeekRecap(ctx, 2025, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker := s.NewWebhookWorker()
+	defer worker.stop()
+	if err := worker.NotifyWeekRecap(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := func(hookID int64) webhookPayload {
+		t.Helper()
+		ds, err := s.store.ListWebhookDeliveries(ctx, hookID, 0)
+		if err != nil || len(ds) != 1 {
+			t.Fatalf("deliveries = %+v, %v; want one", ds, err)
+		}
+		var p webhookPayload
+		if err := json.Unmarshal(ds[0].Payload, &p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	if p := payload(recaps.ID); p.Event != game.WebhookWeekRecap || p.Recap == nil || !p.Recap.TieUnresolved ||
+		p.Text != p.Recap.Text || !strings.HasPrefix(p.Text, "Week 2025-W02 closed tied between Alice and Bob and needs a tiebreaker.") {
+		t.Errorf("recap payload = %+v", p)
+	}
+	if p := payload(ties.ID); p.Event != game.WebhookTieNeedsBreaking || p.Week == nil || p.Week.ScopeKey != "2025-W02" {
+		t.Errorf("tie payload = %+v", p)
+	}
+}
+
+// flakyNotifier fails its first `fail` calls and records the recaps it is told about after that.
+type flakyNotifier struct {
+	fail int
+	got  []string
+}
+
+func (n *flakyNotifier) NotifyWeekRecap(_ context.Context, r game.WeekRecap) error {
+	if n.fail > 0 {
+		n.fail--
+		return errors.New("chat is down")
+	}
+	n.got = append(n.got, r.ScopeKey)
+	return nil
+}
+
+func TestRecapClosedWeeks_CatchesUpInOrderAndRetriesNotifying(t *testing.T) {
+	ctx := context.Background()
+	s, api, _ := recapTestServer(t)
+
+	// A game on the Monday of each of the last three closed weeks, oldest first.
+	type isoWeek struct{ year, week int }
+	var weeks []isoWeek
+	year, week := game.LastClosedWeek(s.now(), s.loc)
+	for range 3 {
+		start, _ := game.WeekBounds(year, week, s.loc)
+		addTestGames(t, api, fmt.Sprintf(`{"title_id":1,"played_at":%q,"participant_ids":[1,2],"winner_ids":[1]}`, start.Add(12*time.Hour).Format("2006-01-02T15:04")))
+		weeks = append([]isoWeek{{year, week}}, weeks...)
+		year, week = start.AddDate(0, 0, -7).ISOWeek()
+	}
+	var want []string
+	for _, w := range weeks {
+		want = append(want, game.WeekScopeKey(w.year, w.week))
+	}
+
+	// The oldest was recapped before the server went down, but never announced.
+	if _, written, err := s.WriteWeekRecap(ctx, weeks[0].year, weeks[0].week); err != nil || !written {
+		t.Fatalf("WriteWeekRecap(%s) = %v, %v", want[0], written, err)
+	}
+
+	// Announcing it fails, so no later week is recapped yet.
+	n := &flakyNotifier{fail: 1}
+	if err := s.RecapClosedWeeks(ctx, n); err == nil {
+		t.Fatal("RecapClosedWeeks succeeded with a failing notifier")
+	}
+	if _, ok, _ := s.store.GetWeekRecap(ctx, weeks[1].year, weeks[1].week); ok {
+		t.Error("a later week was recapped before an earlier recap was announced")
+	}
+
+	// The next run announces every week once, in order.
+	if err := s.RecapClosedWeeks(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(n.got, want) {
+		t.Errorf("announced %v, want %v", n.got, want)
+	}
+	for _, w := range weeks {
+		if r, ok, err := s.store.GetWeekRecap(ctx, w.year, w.week); err != nil || !ok || r.NotifiedAt == nil {
+			t.Errorf("recap of %d-W%02d = %+v, %v, %v; want it marked notified", w.year, w.week, r, ok, err)
+		}
+	}
+	if err := s.RecapClosedWeeks(ctx, n); err != nil || len(n.got) != len(want) {
+		t.Errorf("a third run announced %v (err %v), want nothing new", n.got, err)
+	}
+}
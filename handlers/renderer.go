@@ -14,6 +14,25 @@ type Renderer struct {
 	yearRaceChart *template.Template
 	players       *template.Template
 	titles        *template.Template
+	ratings       *template.Template
+	titleStats    *template.Template
+	yearH2H       *template.Template
+	data          *template.Template
+	player        *template.Template
+	standings     *template.Template
+	champions     *template.Template
+	rules         *template.Template
+	seasons       *template.Template
+	season        *template.Template
+	draw          *template.Template
+	tbHistory     *template.Template
+	audit         *template.Template
+	login         *template.Template
+	account       *template.Template
+	users         *template.Template
+	tokens        *template.Template
+	webhooks      *template.Template
+	weekRecap     *template.Template
 }
 
 // RendererConfig centralizes template paths.
@@ -26,6 +45,25 @@ type RendererConfig struct {
 	YearRaceChart string
 	Players       string
 	Titles        string
+	Ratings       string
+	TitleStats    string
+	YearH2H       string
+	Data          string
+	Player        string
+	Standings     string
+	Champions     string
+	Rules         string
+	Seasons       string
+	Season        string
+	Draw          string
+	TBHistory     string
+	Audit         string
+	Login         string
+	Account       string
+	Users         string
+	Tokens        string
+	Webhooks      string
+	WeekRecap     string
 }
 
 func NewRenderer(cfg RendererConfig) *Renderer {
@@ -61,6 +99,25 @@ func NewRenderer(cfg RendererConfig) *Renderer {
 		yearRaceChart: parse(cfg.Base, cfg.YearRaceChart),
 		players:       parse(cfg.Base, cfg.Players),
 		titles:        parse(cfg.Base, cfg.Titles),
+		ratings:       parse(cfg.Base, cfg.Ratings),
+		titleStats:    parse(cfg.Base, cfg.TitleStats),
+		yearH2H:       parse(cfg.Base, cfg.YearH2H),
+		data:          parse(cfg.Base, cfg.Data),
+		player:        parse(cfg.Base, cfg.Player),
+		standings:     parse(cfg.Base, cfg.Standings),
+		champions:     parse(cfg.Base, cfg.Champions),
+		rules:         parse(cfg.Base, cfg.Rules),
+		seasons:       parse(cfg.Base, cfg.Seasons),
+		season:        parse(cfg.Base, cfg.Season),
+		draw:          parse(cfg.Base, cfg.Draw),
+		tbHistory:     parse(cfg.Base, cfg.TBHistory),
+		audit:         parse(cfg.Base, cfg.Audit),
+		login:         parse(cfg.Base, cfg.Login),
+		account:       parse(cfg.Base, cfg.Account),
+		users:         parse(cfg.Base, cfg.Users),
+		tokens:        parse(cfg.Base, cfg.Tokens),
+		webhooks:      parse(cfg.Base, cfg.Webhooks),
+		weekRecap:     parse(cfg.Base, cfg.WeekRecap),
 	}
 }
 
@@ -72,6 +129,8 @@ func (r *Renderer) HTML(w http.ResponseWriter, layout, name string, data any) er
 		return r.home.ExecuteTemplate(w, layout, data)
 	case "week":
 		return r.week.ExecuteTemplate(w, layout, data)
+	case "week_recap":
+		return r.weekRecap.ExecuteTemplate(w, layout, data)
 	case "year":
 		return r.year.ExecuteTemplate(w, layout, data)
 	case "year_race":
@@ -82,6 +141,42 @@ func (r *Renderer) HTML(w http.ResponseWriter, layout, name string, data any) er
 		return r.players.ExecuteTemplate(w, layout, data)
 	case "titles":
 		return r.titles.ExecuteTemplate(w, layout, data)
+	case "ratings":
+		return r.ratings.ExecuteTemplate(w, layout, data)
+	case "title_stats":
+		return r.titleStats.ExecuteTemplate(w, layout, data)
+	case "year_h2h":
+		return r.yearH2H.ExecuteTemplate(w, layout, data)
+	case "data":
+		return r.data.ExecuteTemplate(w, layout, data)
+	case "player":
+		return r.player.ExecuteTemplate(w, layout, data)
+	case "standings":
+		return r.standings.ExecuteTemplate(w, layout, data)
+	case "champions":
+		return r.champions.ExecuteTemplate(w, layout, data)
+	case "rules":
+		return r.rules.ExecuteTemplate(w, layout, data)
+	case "seasons":
+		return r.seasons.ExecuteTemplate(w, layout, data)
+	case "season":
+		return r.season.ExecuteTemplate(w, layout, data)
+	case "draw":
+		return r.draw.ExecuteTemplate(w, layout, data)
+	case "tiebreaker_history":
+		return r.tbHistory.ExecuteTemplate(w, layout, data)
+	case "audit":
+		return r.audit.ExecuteTemplate(w, layout, data)
+	case "login":
+		return r.login.ExecuteTemplate(w, layout, data)
+	case "account":
+		return r.account.ExecuteTemplate(w, layout, data)
+	case "users":
+		return r.users.ExecuteTemplate(w, layout, data)
+	case "tokens":
+		return r.tokens.ExecuteTemplate(w, layout, data)
+	case "webhooks":
+		return r.webhooks.ExecuteTemplate(w, layout, data)
 	default:
 		return errors.New("unknown template: " + name)
 	}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// rulesetAt returns the ruleset in force at t (a period's start in league time).
+func (s *Server) rulesetAt(ctx context.Context, t time.Time) (game.Ruleset, error) {
+	rulesets, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		return game.Ruleset{}, err
+	}
+	return game.RulesetFor(rulesets, t), nil
+}
+
+// describeRules summarizes r for the standings pages, e.g.
+// "Best win rate · top half by attendance · ties: stored tiebreaker".
+func describeRules(r game.PeriodRules) string {
+	parts := make([]string, 0, 3)
+	switch r.Metric {
+	case game.MetricWinRate:
+		parts = append(parts, "Best win rate")
+	case game.MetricAvgFinish:
+		parts = append(parts, "Best average finish")
+	default:
+		parts = append(parts, "Most wins")
+	}
+
+	qualify := "everyone qualifies"
+	if r.Qualifier == game.QualifyTopHalfAttendance {
+		qualify = "top half by attendance"
+	}
+	if r.MinAttendance > 0 {
+		qualify += fmt.Sprintf(", at least %d day", r.MinAttendance)
+		if r.MinAttendance > 1 {
+			qualify += "s"
+		}
+	}
+	parts = append(parts, qualify)
+
+	switch r.TiePolicy {
+	case game.TieMostGames:
+		parts = append(parts, "ties: most games played, then stored tiebreaker")
+	case game.TieHeadToHead:
+		parts = append(parts, "ties: head-to-head wins, then most games played, then stored tiebreaker")
+	default:
+		parts = append(parts, "ties: stored tiebreaker")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	s.renderRules(r.Context(), w, "rules", defaultRulesetForm(), "")
+}
+
+func (s *Server) handleRulesPost(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.renderRules(r.Context(), w, "main", defaultRulesetForm(), "Invalid form submission.")
+		return
+	}
+
+	rs, form, err := parseRulesetForm(r, s.loc)
+	if err == nil {
+		_, err = s.addRuleset(r.Context(), rs)
+	}
+	if err != nil {
+		s.renderRules(r.Context(), w, "main", form, err.Error())
+		return
+	}
+
+	setToast(w, "Ruleset added.")
+	s.renderRules(r.Context(), w, "main", defaultRulesetForm(), "")
+}
+
+// addRuleset stores a validated rs unless another ruleset starts on the same day.
+// Error messages are user-facing; both the rules page and the API show them as-is.
+func (s *Server) addRuleset(ctx context.Context, rs game.Ruleset) (game.Ruleset, error) {
+	existing, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		return game.Ruleset{}, errors.New("Unable to load the existing rulesets.")
+	}
+	for _, e := range existing {
+		if e.EffectiveFrom.Equal(rs.EffectiveFrom) {
+			return game.Ruleset{}, errors.New("A ruleset already starts on that date.")
+		}
+	}
+	rs, err = s.store.AddRuleset(ctx, rs)
+	if err != nil {
+		return game.Ruleset{}, errors.New("Unable to save the ruleset.")
+	}
+	return rs, nil
+}
+
+func (s *Server) handleRulesDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/rules", http.StatusSeeOther)
+		return
+	}
+	if err := s.store.DeleteRuleset(r.Context(), id); err != nil {
+		s.renderRules(r.Context(), w, "main", defaultRulesetForm(), "Unable to delete the ruleset.")
+		return
+	}
+	setToast(w, "Ruleset deleted.")
+	s.renderRules(r.Context(), w, "main", defaultRulesetForm(), "")
+}
+
+// renderRules renders the rules page; layout is "rules" for a full page or "main" for an
+// HTMX swap after a change.
+func (s *Server) renderRules(ctx context.Context, w http.ResponseWriter, layout string, form RulesetForm, formErr string) {
+	rulesets, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	current := game.RulesetFor(rulesets, s.now())
+	row := func(rs game.Ruleset) rulesetVM {
+		rv := rulesetVM{
+			ID:      rs.ID,
+			Name:    rs.Name,
+			Weekly:  describeRules(rs.Weekly),
+			Yearly:  describeRules(rs.Yearly),
+			Current: rs.ID == current.ID,
+		}
+		if !rs.EffectiveFrom.IsZero() {
+			rv.From = rs.EffectiveFrom.In(s.loc).Format("2006-01-02")
+		}
+		return rv
+	}
+
+	vm := RulesVM{
+		Title:     "Rules",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Rulesets:  []rulesetVM{row(game.DefaultRuleset())},
+		Form:      form,
+		FormError: formErr,
+	}
+	for _, rs := range rulesets {
+		vm.Rulesets = append(vm.Rulesets, row(rs))
+	}
+
+	if err := s.r.HTML(w, layout, "rules", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// defaultRulesetForm prefills the add form with the default rules.
+func defaultRulesetForm() RulesetForm {
+	d := game.DefaultRuleset()
+	return RulesetForm{Weekly: periodRulesForm(d.Weekly), Yearly: periodRulesForm(d.Yearly)}
+}
+
+func periodRulesForm(r game.PeriodRules) PeriodRulesForm {
+	f := PeriodRulesForm{Metric: r.Metric, Qualifier: r.Qualifier, TiePolicy: r.TiePolicy}
+	if r.MinAttendance > 0 {
+		f.MinAttendance = strconv.Itoa(r.MinAttendance)
+	}
+	return f
+}
+
+// parseRulesetForm reads and validates the add-ruleset form. The form is returned as
+// entered so it can be re-rendered with an error.
+func parseRulesetForm(r *http.Request, loc *time.Location) (game.Ruleset, RulesetForm, error) {
+	period := func(prefix string) (game.PeriodRules, PeriodRulesForm, error) {
+		f := PeriodRulesForm{
+			Metric:        r.FormValue(prefix + "_metric"),
+			Qualifier:     r.FormValue(prefix + "_qualifier"),
+			MinAttendance: strings.TrimSpace(r.FormValue(prefix + "_min_attendance")),
+			TiePolicy:     r.FormValue(prefix + "_tie_policy"),
+		}
+		pr := game.PeriodRules{Metric: f.Metric, Qualifier: f.Qualifier, TiePolicy: f.TiePolicy}
+		if f.MinAttendance != "" {
+			n, err := strconv.Atoi(f.MinAttendance)
+			if err != nil {
+				return pr, f, errors.New("Minimum attendance must be a whole number of days.")
+			}
+			pr.MinAttendance = n
+		}
+		return pr, f, nil
+	}
+
+	weekly, wf, werr := period("weekly")
+	yearly, yf, yerr := period("yearly")
+	form := RulesetForm{
+		Name:          strings.TrimSpace(r.FormValue("name")),
+		EffectiveFrom: strings.TrimSpace(r.FormValue("effective_from")),
+		Weekly:        wf,
+		Yearly:        yf,
+	}
+
+	rs := game.Ruleset{Name: form.Name, Weekly: weekly, Yearly: yearly}
+	if form.EffectiveFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", form.EffectiveFrom, loc)
+		if err != nil {
+			return rs, form, errors.New("Please choose a valid date.")
+		}
+		rs.EffectiveFrom = from
+	}
+	if werr != nil {
+		return rs, form, werr
+	}
+	if yerr != nil {
+		return rs, form, yerr
+	}
+	if err := rs.Validate(); err != nil {
+		return rs, form, err
+	}
+	return rs, form, nil
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// season looks up a stored season by ID.
+func (s *Server) season(ctx context.Context, id int64) (game.Season, bool, error) {
+	seasons, err := s.store.ListSeasons(ctx)
+	if err != nil {
+		return game.Season{}, false, err
+	}
+	for _, se := range seasons {
+		if se.ID == id {
+			return se, true, nil
+		}
+	}
+	return game.Season{}, false, nil
+}
+
+// seasonStandings ranks se under the yearly rules in force on its first day.
+func (s *Server) seasonStandings(ctx context.Context, se game.Season) (game.SeasonStandings, game.Ruleset, error) {
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		return game.SeasonStandings{}, game.Ruleset{}, err
+	}
+
+	start, _ := se.Bounds(s.loc)
+	rules, err := s.rulesetAt(ctx, start)
+	if err != nil {
+		return game.SeasonStandings{}, game.Ruleset{}, err
+	}
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	return game.ComputeSeasonStandings(games, se, s.loc, rules.Yearly, getTB), rules, nil
+}
+
+// ============================
+// Season list
+// ============================
+
+func (s *Server) handleSeasons(w http.ResponseWriter, r *http.Request) {
+	s.renderSeasons(r.Context(), w, "seasons", SeasonForm{}, "")
+}
+
+func (s *Server) handleSeasonsPost(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.renderSeasons(r.Context(), w, "main", SeasonForm{}, "Invalid form submission.")
+		return
+	}
+
+	se, form, err := parseSeasonForm(r, s.loc)
+	if err == nil {
+		_, err = s.addSeason(r.Context(), se)
+	}
+	if err != nil {
+		s.renderSeasons(r.Context(), w, "main", form, err.Error())
+		return
+	}
+
+	setToast(w, "Season added.")
+	s.renderSeasons(r.Context(), w, "main", SeasonForm{}, "")
+}
+
+// addSeason stores a validated se unless another season has the same name.
+// Error messages are user-facing; both the seasons page and the API show them as-is.
+func (s *Server) addSeason(ctx context.Context, se game.Season) (game.Season, error) {
+	existing, err := s.store.ListSeasons(ctx)
+	if err != nil {
+		return game.Season{}, errors.New("Unable to load the existing seasons.")
+	}
+	for _, e := range existing {
+		if strings.EqualFold(e.Name, se.Name) {
+			return game.Season{}, errors.New("A season with that name already exists.")
+		}
+	}
+	se, err = s.store.AddSeason(ctx, se)
+	if err != nil {
+		return game.Season{}, errors.New("Unable to save the season.")
+	}
+	return se, nil
+}
+
+func (s *Server) handleSeasonDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/seasons", http.StatusSeeOther)
+		return
+	}
+	if err := s.store.DeleteSeason(r.Context(), id); err != nil {
+		s.renderSeasons(r.Context(), w, "main", SeasonForm{}, "Unable to delete the season.")
+		return
+	}
+	setToast(w, "Season deleted.")
+	s.renderSeasons(r.Context(), w, "main", SeasonForm{}, "")
+}
+
+// renderSeasons renders the seasons page; layout is "seasons" for a full page or "main" for
+// an HTMX swap after a change.
+func (s *Server) renderSeasons(ctx context.Context, w http.ResponseWriter, layout string, form SeasonForm, formErr string) {
+	seasons, err := s.store.ListSeasons(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := s.now()
+	vm := SeasonsVM{
+		Title:     "Seasons",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   now.Year(),
+		Form:      form,
+		FormError: formErr,
+	}
+	for _, se := range seasons {
+		start, end := se.Bounds(s.loc)
+		vm.Seasons = append(vm.Seasons, seasonRowVM{
+			ID:      se.ID,
+			Name:    se.Name,
+			Dates:   seasonDates(se),
+			Current: !now.Before(start) && now.Before(end),
+		})
+	}
+
+	if err := s.r.HTML(w, layout, "seasons", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseSeasonForm reads and validates the add-season form. The form is returned as entered
+// so it can be re-rendered with an error.
+func parseSeasonForm(r *http.Request, loc *time.Location) (game.Season, SeasonForm, error) {
+	form := SeasonForm{
+		Name:      strings.TrimSpace(r.FormValue("name")),
+		StartDate: strings.TrimSpace(r.FormValue("start_date")),
+		EndDate:   strings.TrimSpace(r.FormValue("end_date")),
+	}
+
+	se := game.Season{Name: form.Name}
+	for _, f := range []struct {
+		v   string
+		dst *time.Time
+	}{{form.StartDate, &se.StartDate}, {form.EndDate, &se.EndDate}} {
+		if f.v == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", f.v, loc)
+		if err != nil {
+			return se, form, errors.New("Please choose valid dates.")
+		}
+		*f.dst = t
+	}
+	if err := se.Validate(); err != nil {
+		return se, form, err
+	}
+	return se, form, nil
+}
+
+func seasonDates(se game.Season) string {
+	return se.StartDate.Format("Jan 2, 2006") + " – " + se.EndDate.Format("Jan 2, 2006")
+}
+
+// ============================
+// Season standings
+// ============================
+
+func (s *Server) handleSeason(w http.ResponseWriter, r *http.Request) {
+	se, ok := s.pathSeason(w, r)
+	if !ok {
+		return
+	}
+	s.renderSeason(r.Context(), w, pageLayout(r, "season"), se, "")
+}
+
+func (s *Server) handleSeasonTiebreak(w http.ResponseWriter, r *http.Request) {
+	se, ok := s.pathSeason(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.renderSeason(r.Context(), w, "main", se, "Invalid form submission.")
+		return
+	}
+
+	if err := s.setSeasonTiebreaker(r.Context(), se, parseTiebreakChoice(r)); err != nil {
+		s.renderSeason(r.Context(), w, "main", se, err.Error())
+		return
+	}
+
+	setToast(w, "Tiebreaker saved.")
+	s.renderSeason(r.Context(), w, "main", se, "")
+}
+
+// pathSeason resolves the {id} path value to a stored season, writing a 404 if there is none.
+func (s *Server) pathSeason(w http.ResponseWriter, r *http.Request) (game.Season, bool) {
+	id, err := pathInt64(r, "id")
+	if err != nil {
+		http.NotFound(w, r)
+		return game.Season{}, false
+	}
+	se, ok, err := s.season(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return game.Season{}, false
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return game.Season{}, false
+	}
+	return se, true
+}
+
+// renderSeason renders a season's standings; layout is "season" for a full page or "main"
+// for an HTMX swap after a tiebreak.
+func (s *Server) renderSeason(ctx context.Context, w http.ResponseWriter, layout string, se game.Season, formErr string) {
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	ss, rules, err := s.seasonStandings(ctx, se)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	start, _ := se.Bounds(s.loc)
+
+	vm := SeasonVM{
+		Title:        se.Name,
+		Version:      s.meta.Version,
+		BuildTime:    s.meta.BuildTime,
+		StartTime:    s.meta.StartTime,
+		YearNow:      s.now().Year(),
+		Season:       se,
+		Dates:        seasonDates(se),
+		PlayerMap:    pMap,
+		Standings:    ss.Standings,
+		RulesName:    rules.Name,
+		RulesSummary: describeRules(rules.Yearly),
+		TieNote:      tieNote(ss.Standings),
+		DrawURL:      s.drawURL(ctx, ss.Standings),
+		StaleNote:    staleNote(ss.Standings, pMap),
+		HistoryURL:   s.historyURL(ctx, ss.Standings),
+		PlayoffGames: s.playoffGames(ctx, ss.Standings, start, pMap),
+		LiveTopic:    "season:" + se.ScopeKey(),
+		FormError:    formErr,
+	}
+
+	if err := s.r.HTML(w, layout, "season", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// setSeasonTiebreaker records the tiebreaker settling a tied season as c says.
+// Error messages are user-facing; both the season page and the API show them as-is.
+func (s *Server) setSeasonTiebreaker(ctx context.Context, se game.Season, c tiebreakChoice) error {
+	ss, _, err := s.seasonStandings(ctx, se)
+	if err != nil {
+		return errors.New("Unable to load the standings for this season.")
+	}
+
+	if len(ss.TopIDs) <= 1 {
+		return errors.New("This season is not tied—no tiebreaker needed.")
+	}
+
+	tb, err := s.decideTiebreaker(ctx, "season", ss.ScopeKey, ss.TopIDs, c)
+	if err != nil {
+		return err
+	}
+	return s.setTiebreaker(ctx, tb)
+}
+
+func (s *Server) handleSeasonRaceChart(w http.ResponseWriter, r *http.Request) {
+	se, ok := s.pathSeason(w, r)
+	if !ok {
+		return
+	}
+
+	games, err := s.store.ListGames(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	race := game.ComputeSeasonRace(games, se, s.loc, game.RaceMetricWins, 5, players)
+
+	vm := buildYearRaceChartVM(race)
+
+	if err := s.r.HTML(w, "year_race_chart", "year_race_chart", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
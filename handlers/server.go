@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
 )
 
 // Meta holds build/runtime metadata you want available in templates.
@@ -13,14 +16,16 @@ type Meta struct {
 
 // Server owns HTTP handlers and template rendering for the app.
 type Server struct {
-	r     *Renderer
-	store Store
-	db    Pinger
-	meta  Meta
+	r      *Renderer
+	store  Store
+	db     Pinger
+	meta   Meta
+	loc    *time.Location // league time zone: weeks, years and weekdays are judged here
+	events *EventBus      // changes pushed to open pages; see handleEvents
 }
 
-// New constructs a Server with default template paths.
-func New(store Store, db Pinger, meta Meta) *Server {
+// New constructs a Server with default template paths. loc is the league time zone.
+func New(store Store, db Pinger, meta Meta, loc *time.Location) *Server {
 	r := NewRenderer(RendererConfig{
 		Base:          "web/templates/base.go.html",
 		Home:          "web/templates/home.go.html",
@@ -30,55 +35,153 @@ func New(store Store, db Pinger, meta Meta) *Server {
 		YearRaceChart: "web/templates/year_race_chart.go.html",
 		Players:       "web/templates/players.go.html",
 		Titles:        "web/templates/titles.go.html",
+		Ratings:       "web/templates/ratings.go.html",
+		TitleStats:    "web/templates/title_stats.go.html",
+		YearH2H:       "web/templates/year_h2h.go.html",
+		Data:          "web/templates/data.go.html",
+		Player:        "web/templates/player.go.html",
+		Standings:     "web/templates/standings.go.html",
+		Champions:     "web/templates/champions.go.html",
+		Rules:         "web/templates/rules.go.html",
+		Seasons:       "web/templates/seasons.go.html",
+		Season:        "web/templates/season.go.html",
+		Draw:          "web/templates/draw.go.html",
+		TBHistory:     "web/templates/tiebreaker_history.go.html",
+		Audit:         "web/templates/audit.go.html",
+		Login:         "web/templates/login.go.html",
+		Account:       "web/templates/account.go.html",
+		Users:         "web/templates/users.go.html",
+		Tokens:        "web/templates/tokens.go.html",
+		Webhooks:      "web/templates/webhooks.go.html",
+		WeekRecap:     "web/templates/week_recap.go.html",
 	})
 
 	return &Server{
-		r:     r,
-		store: store,
-		db:    db,
-		meta:  meta,
+		r:      r,
+		store:  store,
+		db:     db,
+		meta:   meta,
+		loc:    loc,
+		events: NewEventBus(),
 	}
 }
 
+// now is the current time in the league time zone.
+func (s *Server) now() time.Time {
+	return time.Now().In(s.loc)
+}
+
 // RegisterRoutes attaches all application routes to the provided mux.
+//
+// Every page needs a signed-in user (API routes also take API tokens; see authenticate):
+// viewers can browse, recorders can also log and edit games, and admins can also change
+// players, titles, tiebreakers, rules, seasons, imports, users and webhooks. Only sign-in
+// and the health checks are public.
 func (s *Server) RegisterRoutes(mux *http.ServeMux) {
+	viewer, recorder, admin := s.role(game.RoleViewer), s.role(game.RoleRecorder), s.role(game.RoleAdmin)
+
+	// Sign-in and accounts
+	mux.HandleFunc("GET /login", s.handleLogin)
+	mux.HandleFunc("POST /login", s.handleLoginPost)
+	mux.HandleFunc("POST /logout", s.handleLogout)
+	mux.HandleFunc("GET /account", viewer(s.handleAccount))
+	mux.HandleFunc("POST /account/password", viewer(s.handleAccountPassword))
+	mux.HandleFunc("GET /users", admin(s.handleUsers))
+	mux.HandleFunc("POST /users", admin(s.handleUsersPost))
+	mux.HandleFunc("POST /users/{id}/update", admin(s.handleUserUpdate))
+	mux.HandleFunc("POST /users/{id}/delete", admin(s.handleUserDelete))
+	mux.HandleFunc("GET /tokens", admin(s.handleTokens))
+	mux.HandleFunc("POST /tokens", admin(s.handleTokensPost))
+	mux.HandleFunc("POST /tokens/{id}/revoke", admin(s.handleTokenRevoke))
+	mux.HandleFunc("GET /webhooks", admin(s.handleWebhooks))
+	mux.HandleFunc("POST /webhooks", admin(s.handleWebhooksPost))
+	mux.HandleFunc("POST /webhooks/{id}/toggle", admin(s.handleWebhookToggle))
+	mux.HandleFunc("POST /webhooks/{id}/delete", admin(s.handleWebhookDelete))
+
 	// Home
-	mux.HandleFunc("GET /", s.handleHome)
+	mux.HandleFunc("GET /", viewer(s.handleHome))
 
 	// Games
-	mux.HandleFunc("POST /games", s.handleAddGame)
+	mux.HandleFunc("POST /games", recorder(s.handleAddGame))
+	mux.HandleFunc("POST /games/{id}/update", recorder(s.handleUpdateGame))
 	// Toggle/retire a game (uses path params; HTMX posts here)
-	mux.HandleFunc("POST /games/{id}/toggle", s.handleGameToggle)
+	mux.HandleFunc("POST /games/{id}/toggle", recorder(s.handleGameToggle))
 	// Optional: hard-delete/retire endpoint if you want a distinct button later
-	mux.HandleFunc("POST /games/{id}/delete", s.handleDeleteGame)
+	mux.HandleFunc("POST /games/{id}/delete", admin(s.handleDeleteGame))
 
 	// Weeks
-	mux.HandleFunc("GET /weeks/current", s.handleWeekCurrent)
-	mux.HandleFunc("GET /weeks/{year}/{week}", s.handleWeek)
-	mux.HandleFunc("POST /weeks/{year}/{week}/tiebreak", s.handleWeekTiebreak)
+	mux.HandleFunc("GET /weeks/current", viewer(s.handleWeekCurrent))
+	mux.HandleFunc("GET /weeks/{year}/{week}", viewer(s.handleWeek))
+	mux.HandleFunc("GET /weeks/{year}/{week}/recap", viewer(s.handleWeekRecap))
+	mux.HandleFunc("POST /weeks/{year}/{week}/tiebreak", admin(s.handleWeekTiebreak))
 
 	// Years
-	mux.HandleFunc("GET /years/{year}", s.handleYear)
-	mux.HandleFunc("POST /years/{year}/tiebreak", s.handleYearTiebreak)
+	mux.HandleFunc("GET /years/{year}", viewer(s.handleYear))
+	mux.HandleFunc("POST /years/{year}/tiebreak", admin(s.handleYearTiebreak))
 
 	// Race charts
-	mux.HandleFunc("GET /years/{year}/race", s.handleYearRace)
-	mux.HandleFunc("GET /years/{year}/race/chart", s.handleYearRaceChart)
+	mux.HandleFunc("GET /years/{year}/race", viewer(s.handleYearRace))
+	mux.HandleFunc("GET /years/{year}/race/chart", viewer(s.handleYearRaceChart))
+	mux.HandleFunc("GET /years/{year}/h2h", viewer(s.handleYearH2H))
+
+	// All-time / date-range standings and past champions
+	mux.HandleFunc("GET /standings", viewer(s.handleStandings))
+	mux.HandleFunc("GET /champions", viewer(s.handleChampions))
+
+	// Seasons
+	mux.HandleFunc("GET /seasons", viewer(s.handleSeasons))
+	mux.HandleFunc("POST /seasons", admin(s.handleSeasonsPost))
+	mux.HandleFunc("GET /seasons/{id}", viewer(s.handleSeason))
+	mux.HandleFunc("POST /seasons/{id}/tiebreak", admin(s.handleSeasonTiebreak))
+	mux.HandleFunc("GET /seasons/{id}/race/chart", viewer(s.handleSeasonRaceChart))
+	mux.HandleFunc("POST /seasons/{id}/delete", admin(s.handleSeasonDelete))
+
+	// Tiebreaker history and draws
+	mux.HandleFunc("GET /tiebreakers/{scope}/{key}", viewer(s.handleTiebreakerHistory))
+	mux.HandleFunc("GET /tiebreakers/{scope}/{key}/verify", viewer(s.handleTiebreakerVerify))
+
+	// Ratings
+	mux.HandleFunc("GET /ratings", viewer(s.handleRatings))
 
 	// Admin-ish lists (simple CRUD)
-	mux.HandleFunc("GET /players", s.handlePlayers)
-	mux.HandleFunc("POST /players", s.handlePlayersPost)
-	mux.HandleFunc("POST /players/{id}/update", s.handlePlayerUpdate)
-	mux.HandleFunc("POST /players/{id}/toggle", s.handlePlayerToggle)
-	mux.HandleFunc("POST /players/{id}/delete", s.handlePlayerDelete)
-
-	mux.HandleFunc("GET /titles", s.handleTitles)
-	mux.HandleFunc("POST /titles", s.handleTitlesPost)
-	mux.HandleFunc("POST /titles/{id}/update", s.handleTitleUpdate)
-	mux.HandleFunc("POST /titles/{id}/toggle", s.handleTitleToggle)
-	mux.HandleFunc("POST /titles/{id}/delete", s.handleTitleDelete)
-
-	// Health
+	mux.HandleFunc("GET /players", viewer(s.handlePlayers))
+	mux.HandleFunc("POST /players", admin(s.handlePlayersPost))
+	mux.HandleFunc("GET /players/{id}", viewer(s.handlePlayerProfile))
+	mux.HandleFunc("POST /players/{id}/update", admin(s.handlePlayerUpdate))
+	mux.HandleFunc("POST /players/{id}/toggle", admin(s.handlePlayerToggle))
+	mux.HandleFunc("POST /players/{id}/delete", admin(s.handlePlayerDelete))
+
+	mux.HandleFunc("GET /rules", viewer(s.handleRules))
+	mux.HandleFunc("POST /rules", admin(s.handleRulesPost))
+	mux.HandleFunc("POST /rules/{id}/delete", admin(s.handleRulesDelete))
+
+	mux.HandleFunc("GET /titles", viewer(s.handleTitles))
+	mux.HandleFunc("POST /titles", admin(s.handleTitlesPost))
+	mux.HandleFunc("GET /titles/{id}", viewer(s.handleTitleStats))
+	mux.HandleFunc("POST /titles/{id}/update", admin(s.handleTitleUpdate))
+	mux.HandleFunc("POST /titles/{id}/toggle", admin(s.handleTitleToggle))
+	mux.HandleFunc("POST /titles/{id}/delete", admin(s.handleTitleDelete))
+
+	// Export / import
+	mux.HandleFunc("GET /data", viewer(s.handleData))
+	mux.HandleFunc("GET /export", viewer(s.handleExport))
+	mux.HandleFunc("POST /import", admin(s.handleImport))
+
+	// Audit log
+	mux.HandleFunc("GET /audit", viewer(s.handleAudit))
+
+	// Live updates (Server-Sent Events)
+	mux.HandleFunc("GET /events", viewer(s.handleEvents))
+
+	// JSON API
+	s.registerAPIRoutes(mux)
+
+	// Health (public, for the platform's checks)
 	mux.HandleFunc("GET /healthz", s.handleHealthz)
 	mux.HandleFunc("GET /readyz", s.handleReadyz)
 }
+
+// role returns middleware that lets through only users with at least the given role.
+func (s *Server) role(name string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc { return s.requireRole(name, h) }
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// handleStandings serves the all-time leaderboard, or a date range with ?from=&to=
+// (YYYY-MM-DD, both inclusive, either may be left open). Ranked by the yearly rules in force
+// on the last day.
+func (s *Server) handleStandings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	vm := StandingsVM{
+		Title:     "Standings",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		From:      q.Get("from"),
+		To:        q.Get("to"),
+		Label:     "All-time",
+	}
+
+	from, fromErr := s.parseDay(vm.From)
+	to, toErr := s.parseDay(vm.To)
+	switch {
+	case fromErr != nil || toErr != nil:
+		vm.FormError = "Dates must look like 2026-01-31."
+	case !from.IsZero() && !to.IsZero() && to.Before(from):
+		vm.FormError = "The end date must not be before the start date."
+	}
+	if vm.FormError != "" {
+		from, to = time.Time{}, time.Time{}
+		vm.From, vm.To = "", ""
+	}
+
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	vm.PlayerMap = make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		vm.PlayerMap[p.ID] = p
+	}
+
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	vm.Years = leagueYears(games, s.loc, vm.YearNow, vm.YearNow)
+
+	// A leaderboard spanning several periods uses the yearly rules in force on its last day.
+	lastDay := to
+	if lastDay.IsZero() {
+		lastDay = s.now()
+	}
+	rules, err := s.rulesetAt(ctx, lastDay)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	vm.RulesName, vm.RulesSummary = rules.Name, describeRules(rules.Yearly)
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	if from.IsZero() && to.IsZero() {
+		vm.Standings = game.ComputeRangeStandings(games, from, to, s.loc, "alltime", game.AllTimeScopeKey, rules.Yearly, getTB)
+	} else {
+		// The range is inclusive of the "to" day.
+		end := to
+		if !end.IsZero() {
+			end = end.AddDate(0, 0, 1)
+		}
+		vm.Label = rangeLabel(vm.From, vm.To)
+		vm.Standings = game.ComputeRangeStandings(games, from, end, s.loc, "range", game.RangeScopeKey(from, to), rules.Yearly, getTB)
+	}
+
+	if err := s.r.HTML(w, "standings", "standings", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleChampions serves the Hall of Champions: every weekly and yearly winner by period.
+func (s *Server) handleChampions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rulesets, err := s.store.ListRulesets(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	getTB := func(scope, scopeKey string) (game.Tiebreaker, bool, error) {
+		return s.store.GetTiebreaker(ctx, scope, scopeKey)
+	}
+	hall := game.ComputeHallOfChampions(games, s.loc, s.now(), rulesets, getTB)
+
+	champion := func(c game.PeriodChampion) championVM {
+		cv := championVM{Year: c.Year, Week: c.Week, Games: c.Games, Stale: c.StaleTiebreak, InProgress: c.InProgress}
+		if c.WinnerID != nil {
+			cv.Winner = pMap[*c.WinnerID].Name
+		} else if len(c.TopIDs) > 1 {
+			for _, id := range c.TopIDs {
+				cv.Tied = append(cv.Tied, pMap[id].Name)
+			}
+		}
+		return cv
+	}
+
+	vm := ChampionsVM{
+		Title:     "Hall of Champions",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+	}
+	for _, cy := range hall.Years {
+		yv := championYearVM{Year: cy.Year}
+		if cy.Yearly != nil {
+			c := champion(*cy.Yearly)
+			yv.Yearly = &c
+		}
+		for _, wk := range cy.Weeks {
+			yv.Weeks = append(yv.Weeks, champion(wk))
+		}
+		vm.Years = append(vm.Years, yv)
+	}
+
+	if err := s.r.HTML(w, "champions", "champions", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseDay parses a YYYY-MM-DD form value as midnight in the league time zone.
+// An empty value is the zero time.
+func (s *Server) parseDay(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", v, s.loc)
+}
+
+func rangeLabel(from, to string) string {
+	switch {
+	case from == "":
+		return "Through " + to
+	case to == "":
+		return "Since " + from
+	default:
+		return from + " – " + to
+	}
+}
+
+// leagueYears lists every calendar year from the first active game's year (or from, if
+// earlier) through to, for year pickers.
+func leagueYears(games []game.Game, loc *time.Location, from, to int) []int {
+	for _, g := range games {
+		if g.IsActive {
+			from = min(from, g.PlayedAt.In(loc).Year())
+		}
+	}
+	years := make([]int, 0, to-from+1)
+	for y := from; y <= to; y++ {
+		years = append(years, y)
+	}
+	return years
+}
@@ -2,20 +2,24 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/eithansmith/master-of-games/game"
 )
 
 // Store is the dependency boundary for handlers.
-// Anything (MemoryStore, PostgresStore, etc.) that implements this can back the app.
+// Anything (MemoryStore, PostgresStore, SQLiteStore, etc.) that implements this can back the app.
 //
 //goland:noinspection GoCommentStart
 type Store interface {
 	// games
 	AddGame(ctx context.Context, g game.Game) (game.Game, error)
+	UpdateGame(ctx context.Context, g game.Game) error
 	DeleteGame(ctx context.Context, id int64) error
 	SetGameActive(ctx context.Context, id int64, active bool) error
 	RecentGames(ctx context.Context, limit int) ([]game.Game, error)
+	ListGames(ctx context.Context) ([]game.Game, error)
+	GetGame(ctx context.Context, id int64) (game.Game, bool, error)
 
 	GetWeek(ctx context.Context, year, week int) ([]game.Game, error)
 	GetYear(ctx context.Context, year int) ([]game.Game, error)
@@ -34,9 +38,76 @@ type Store interface {
 	SetTitleActive(ctx context.Context, id int64, active bool) error
 	DeleteTitle(ctx context.Context, id int64) error
 
-	// tiebreakers
+	// tiebreakers; a period without one is (zero, false, nil), not an error
 	GetTiebreaker(ctx context.Context, scope, scopeKey string) (game.Tiebreaker, bool, error)
 	SetTiebreaker(ctx context.Context, tb game.Tiebreaker) error
+	ListTiebreakers(ctx context.Context) ([]game.Tiebreaker, error)
+	// every decision for one period, latest first
+	ListTiebreakerHistory(ctx context.Context, scope, scopeKey string) ([]game.Tiebreaker, error)
+
+	// rulesets, oldest EffectiveFrom first
+	ListRulesets(ctx context.Context) ([]game.Ruleset, error)
+	AddRuleset(ctx context.Context, r game.Ruleset) (game.Ruleset, error)
+	DeleteRuleset(ctx context.Context, id int64) error
+
+	// seasons, latest StartDate first
+	ListSeasons(ctx context.Context) ([]game.Season, error)
+	AddSeason(ctx context.Context, se game.Season) (game.Season, error)
+	DeleteSeason(ctx context.Context, id int64) error
+
+	// users, by user name; user names are unique ignoring case
+	ListUsers(ctx context.Context) ([]game.User, error)
+	GetUser(ctx context.Context, id int64) (game.User, bool, error)
+	GetUserByUsername(ctx context.Context, username string) (game.User, bool, error)
+	AddUser(ctx context.Context, u game.User) (game.User, error)
+	UpdateUser(ctx context.Context, u game.User) error
+	DeleteUser(ctx context.Context, id int64) error
+
+	// sessions; GetSession ignores expired ones
+	AddSession(ctx context.Context, sess game.Session) error
+	GetSession(ctx context.Context, tokenHash string) (game.Session, bool, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteUserSessions(ctx context.Context, userID int64) error
+
+	// API tokens, newest first; revoked tokens are kept
+	ListAPITokens(ctx context.Context) ([]game.APIToken, error)
+	AddAPIToken(ctx context.Context, t game.APIToken) (game.APIToken, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (game.APIToken, bool, error)
+	RevokeAPIToken(ctx context.Context, id int64) error
+	TouchAPIToken(ctx context.Context, id int64, at time.Time) error
+
+	// webhooks, by name; deleting one deletes its deliveries
+	ListWebhooks(ctx context.Context) ([]game.Webhook, error)
+	AddWebhook(ctx context.Context, h game.Webhook) (game.Webhook, error)
+	SetWebhookActive(ctx context.Context, id int64, active bool) error
+	DeleteWebhook(ctx context.Context, id int64) error
+
+	// webhook deliveries; AddWebhookDelivery returns a webhook's existing delivery with the
+	// same Key instead of queueing another, and UpdateWebhookDelivery saves the outcome of an attempt
+	AddWebhookDelivery(ctx context.Context, d game.WebhookDelivery) (game.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, d game.WebhookDelivery) error
+	// claims pending deliveries due at now by moving their next attempt to now+lease, so
+	// no other worker sends them meanwhile; oldest first
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]game.WebhookDelivery, error)
+	// latest first; webhookID 0 lists every webhook's and limit 0 lists them all
+	ListWebhookDeliveries(ctx context.Context, webhookID int64, limit int) ([]game.WebhookDelivery, error)
+
+	// weekly recaps, one per ISO week; AddWeekRecap keeps an existing one and returns it
+	GetWeekRecap(ctx context.Context, year, week int) (game.WeekRecap, bool, error)
+	LastWeekRecap(ctx context.Context) (game.WeekRecap, bool, error)
+	AddWeekRecap(ctx context.Context, r game.WeekRecap) (game.WeekRecap, bool, error)
+	MarkWeekRecapNotified(ctx context.Context, year, week int, at time.Time) error
+
+	// import: adds missing players/titles by name, appends games, upserts tiebreakers.
+	// Must be all-or-nothing.
+	ImportDataset(ctx context.Context, d game.Dataset) (game.ImportSummary, error)
+}
+
+// AuditLog is the append-only record of changes written by AuditStore.
+type AuditLog interface {
+	AppendAudit(ctx context.Context, e game.AuditEntry) error
+	// entries matching f, latest first
+	ListAudit(ctx context.Context, f game.AuditFilter) ([]game.AuditEntry, error)
 }
 
 // Pinger is a simple interface for testing.
@@ -0,0 +1,1400 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/db"
+	"github.com/eithansmith/master-of-games/game"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The conformance suite runs every case below against every Store implementation, so the
+// backends can't drift apart. PostgresStore runs when TEST_DATABASE_URL points at a
+// Postgres database the tests may wipe, e.g. one started locally with
+//
+//	docker run --rm -e POSTGRES_PASSWORD=pw -e POSTGRES_DB=mog_test -p 5432:5432 postgres
+//	TEST_DATABASE_URL=postgres://postgres:pw@localhost:5432/mog_test?sslmode=disable go test ./handlers
+//
+// Its app schema is dropped first, so the database's name must contain "test".
+//
+// Every backend starts from its initial schema, with the seeded players and titles.
+
+// conformanceStore is what each backend implements: the handlers' Store plus the audit log.
+type conformanceStore interface {
+	Store
+	AuditLog
+}
+
+// conformanceLoc is the league time zone for every backend. Games played late on a
+// Sunday evening here are already Monday in UTC.
+var conformanceLoc = time.FixedZone("UTC-6", -6*60*60)
+
+type storeBackend struct {
+	name string
+	skip string // why the backend can't run here, if it can't
+	open func(t *testing.T) conformanceStore
+}
+
+func storeBackends() []storeBackend {
+	pgSkip := ""
+	if os.Getenv("TEST_DATABASE_URL") == "" {
+		pgSkip = "TEST_DATABASE_URL is not set"
+	}
+
+	return []storeBackend{
+		{
+			name: "memory",
+			open: func(t *testing.T) conformanceStore {
+				return game.NewMemoryStore(conformanceLoc)
+			},
+		},
+		{
+			name: "sqlite",
+			open: func(t *testing.T) conformanceStore {
+				ctx := context.Background()
+				sqlDB, err := db.OpenSQLite(ctx, filepath.Join(t.TempDir(), "conformance.db"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = sqlDB.Close() })
+				if _, err := db.MigrateSQLite(ctx, sqlDB); err != nil {
+					t.Fatal(err)
+				}
+				return game.NewSQLiteStore(sqlDB, conformanceLoc)
+			},
+		},
+		{
+			name: "postgres",
+			skip: pgSkip,
+			open: func(t *testing.T) conformanceStore {
+				pool := openTestPostgres(t)
+				if _, err := db.Migrate(context.Background(), pool, conformanceLoc); err != nil {
+					t.Fatal(err)
+				}
+				return game.NewPostgresStore(pool, conformanceLoc)
+			},
+		},
+	}
+}
+
+// openTestPostgres connects to the TEST_DATABASE_URL database with its app schema dropped,
+// or skips the test if it isn't set.
+func openTestPostgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := cfg.ConnConfig.Database; !strings.Contains(strings.ToLower(name), "test") {
+		t.Fatalf("TEST_DATABASE_URL names database %q; refusing to wipe a database whose name doesn't contain \"test\"", name)
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, `DROP SCHEMA IF EXISTS app CASCADE`); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// Databases from before the league time zone stored the wall-clock time entered, tagged as
+// UTC; migrating reads those times in the league time zone.
+func TestPostgresMigration_BaselinePlayedAt(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	ctx := context.Background()
+	pool := openTestPostgres(t)
+	if _, err := db.Migrate(ctx, pool, loc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Rollback(ctx, pool, 1, loc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Logged for Monday 3am of 2026-W02; read as UTC, that's Sunday evening of W01.
+	if _, err := pool.Exec(ctx,
+		`INSERT INTO app.games (played_at, title_id, participant_ids, winner_ids)
+		 VALUES ('2026-01-05 03:00:00+00', 1, '{1,2}', '{1}')`,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Migrate(ctx, pool, loc); err != nil {
+		t.Fatal(err)
+	}
+
+	games, err := game.NewPostgresStore(pool, loc).GetWeek(ctx, 2026, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 1, 5, 3, 0, 0, 0, loc); len(games) != 1 || !games[0].PlayedAt.Equal(want) {
+		t.Errorf("2026-W02 games = %+v, want the baseline game at %v", games, want)
+	}
+}
+
+func TestStoreConformance(t *testing.T) {
+	for _, b := range storeBackends() {
+		t.Run(b.name, func(t *testing.T) {
+			if b.skip != "" {
+				t.Skip(b.skip)
+			}
+			for _, c := range storeConformance {
+				t.Run(c.name, func(t *testing.T) {
+					c.run(t, b.open(t))
+				})
+			}
+		})
+	}
+}
+
+var cctx = context.Background()
+
+// at is a time of day in the league time zone.
+func at(y int, m time.Month, d, hour, min int) time.Time {
+	return time.Date(y, m, d, hour, min, 0, 0, conformanceLoc)
+}
+
+// check stops the case on a store error.
+func check(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("store error: %v", err)
+	}
+}
+
+func wantErr(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("err = nil, want %q", want)
+	} else if want != "" && err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+}
+
+func gameIDs(games []game.Game) []int64 {
+	out := make([]int64, len(games))
+	for i, g := range games {
+		out[i] = g.ID
+	}
+	return out
+}
+
+func playerNames(players []game.Player) []string {
+	out := make([]string, len(players))
+	for i, p := range players {
+		out[i] = p.Name
+	}
+	return out
+}
+
+// sameGame compares every stored field; nil and empty lists are the same.
+func sameGame(t *testing.T, got, want game.Game) {
+	t.Helper()
+	if got.ID != want.ID || got.TitleID != want.TitleID || got.Title != want.Title || got.Mode != want.Mode ||
+		got.Notes != want.Notes || got.IsActive != want.IsActive {
+		t.Errorf("game = %+v, want %+v", got, want)
+	}
+	if !got.PlayedAt.Equal(want.PlayedAt) || got.PlayedAt.Location() != conformanceLoc {
+		t.Errorf("PlayedAt = %v, want %v in the league time zone", got.PlayedAt, want.PlayedAt)
+	}
+	if !slices.Equal(got.ParticipantIDs, want.ParticipantIDs) || !slices.Equal(got.WinnerIDs, want.WinnerIDs) {
+		t.Errorf("participants/winners = %v/%v, want %v/%v", got.ParticipantIDs, got.WinnerIDs, want.ParticipantIDs, want.WinnerIDs)
+	}
+	if len(got.Teams) != len(want.Teams) || !slices.EqualFunc(got.Teams, want.Teams, slices.Equal) {
+		t.Errorf("teams = %v, want %v", got.Teams, want.Teams)
+	}
+	if len(got.Results) != len(want.Results) || (len(want.Results) > 0 && !reflect.DeepEqual(got.Results, want.Results)) {
+		t.Errorf("results = %+v, want %+v", got.Results, want.Results)
+	}
+}
+
+// fixture is a title and three players added to a fresh store.
+type fixture struct {
+	title   game.Title
+	a, b, c game.Player
+}
+
+func newFixture(t *testing.T, s conformanceStore) fixture {
+	t.Helper()
+	title, err := s.AddTitle(cctx, "Hanabi")
+	check(t, err)
+	a, err := s.AddPlayer(cctx, "Alice")
+	check(t, err)
+	b, err := s.AddPlayer(cctx, "Bob")
+	check(t, err)
+	c, err := s.AddPlayer(cctx, "Cleo")
+	check(t, err)
+	return fixture{title: title, a: a, b: b, c: c}
+}
+
+func (f fixture) game(playedAt time.Time) game.Game {
+	return game.Game{
+		PlayedAt:       playedAt,
+		TitleID:        f.title.ID,
+		ParticipantIDs: []int64{f.a.ID, f.b.ID},
+		WinnerIDs:      []int64{f.a.ID},
+	}
+}
+
+var storeConformance = []struct {
+	name string
+	run  func(t *testing.T, s conformanceStore)
+}{
+	// ============================
+	// Games
+	// ============================
+
+	{"AddGame returns the stored game", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		in := f.game(at(2026, 2, 3, 12, 30).UTC())
+		in.Notes = "close one"
+
+		g, err := s.AddGame(cctx, in)
+		check(t, err)
+		if g.ID == 0 {
+			t.Fatal("ID not set")
+		}
+		want := in
+		want.ID, want.Title, want.Mode, want.IsActive = g.ID, "Hanabi", game.ModeCompetitive, true
+		sameGame(t, g, want)
+
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if len(games) != 1 {
+			t.Fatalf("games = %+v, want 1", games)
+		}
+		sameGame(t, games[0], want)
+	}},
+
+	{"AddGame keeps teams and results", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		score := 12
+		in := game.Game{
+			PlayedAt:       at(2026, 2, 3, 12, 0),
+			TitleID:        f.title.ID,
+			Mode:           game.ModeTeam,
+			ParticipantIDs: []int64{f.a.ID, f.b.ID, f.c.ID},
+			WinnerIDs:      []int64{f.a.ID, f.c.ID},
+			Teams:          [][]int64{{f.a.ID, f.c.ID}, {f.b.ID}},
+			Results:        []game.Result{{PlayerID: f.a.ID, Position: 1, Score: &score}, {PlayerID: f.b.ID, Position: 2}},
+		}
+		g, err := s.AddGame(cctx, in)
+		check(t, err)
+
+		want := in
+		want.ID, want.Title, want.IsActive = g.ID, "Hanabi", true
+		week, err := s.GetWeek(cctx, 2026, 6)
+		check(t, err)
+		if len(week) != 1 {
+			t.Fatalf("week = %+v, want 1 game", week)
+		}
+		sameGame(t, week[0], want)
+	}},
+
+	{"UpdateGame replaces the editable fields and keeps the active flag", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		other, err := s.AddTitle(cctx, "Azul")
+		check(t, err)
+		g, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		check(t, s.SetGameActive(cctx, g.ID, false))
+
+		upd := game.Game{
+			ID:             g.ID,
+			PlayedAt:       at(2026, 2, 4, 13, 0),
+			TitleID:        other.ID,
+			Mode:           game.ModeCoop,
+			ParticipantIDs: []int64{f.b.ID, f.c.ID},
+			WinnerIDs:      []int64{f.b.ID, f.c.ID},
+			Notes:          "edited",
+			IsActive:       true, // ignored
+		}
+		check(t, s.UpdateGame(cctx, upd))
+
+		want := upd
+		want.Title, want.IsActive = "Azul", false
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		sameGame(t, games[0], want)
+
+		upd.ID = g.ID + 100
+		wantErr(t, s.UpdateGame(cctx, upd), "game not found")
+	}},
+
+	{"DeleteGame removes the game for good", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		g, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+
+		check(t, s.DeleteGame(cctx, g.ID))
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if len(games) != 0 {
+			t.Errorf("games = %+v, want none (deleted, not deactivated)", games)
+		}
+		wantErr(t, s.DeleteGame(cctx, g.ID), "game not found")
+	}},
+
+	{"SetGameActive hides games from weeks and years only", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		g1, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		g2, err := s.AddGame(cctx, f.game(at(2026, 2, 4, 12, 0)))
+		check(t, err)
+
+		check(t, s.SetGameActive(cctx, g2.ID, false))
+		week, err := s.GetWeek(cctx, 2026, 6)
+		check(t, err)
+		if got := gameIDs(week); !slices.Equal(got, []int64{g1.ID}) {
+			t.Errorf("week = %v, want %v", got, []int64{g1.ID})
+		}
+		year, err := s.GetYear(cctx, 2026)
+		check(t, err)
+		if got := gameIDs(year); !slices.Equal(got, []int64{g1.ID}) {
+			t.Errorf("year = %v, want %v", got, []int64{g1.ID})
+		}
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if got := gameIDs(games); !slices.Equal(got, []int64{g1.ID, g2.ID}) {
+			t.Errorf("all games = %v, want both", got)
+		}
+
+		check(t, s.SetGameActive(cctx, g2.ID, true))
+		got, err := s.GetWeek(cctx, 2026, 6)
+		check(t, err)
+		if len(got) != 2 {
+			t.Errorf("week = %v, want both after reactivating", gameIDs(got))
+		}
+		wantErr(t, s.SetGameActive(cctx, g2.ID+100, false), "game not found")
+	}},
+
+	{"RecentGames lists active games first, latest first", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		older, err := s.AddGame(cctx, f.game(at(2026, 2, 2, 12, 0)))
+		check(t, err)
+		same1, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		same2, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		newest, err := s.AddGame(cctx, f.game(at(2026, 2, 4, 12, 0)))
+		check(t, err)
+		check(t, s.SetGameActive(cctx, newest.ID, false))
+
+		want := []int64{same2.ID, same1.ID, older.ID, newest.ID}
+		recent, err := s.RecentGames(cctx, 0)
+		check(t, err)
+		if got := gameIDs(recent); !slices.Equal(got, want) {
+			t.Errorf("recent = %v, want %v", got, want)
+		}
+		recent, err = s.RecentGames(cctx, 2)
+		check(t, err)
+		if got := gameIDs(recent); !slices.Equal(got, want[:2]) {
+			t.Errorf("recent(2) = %v, want %v", got, want[:2])
+		}
+	}},
+
+	{"ListGames lists every game in play order", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		later, err := s.AddGame(cctx, f.game(at(2026, 2, 4, 12, 0)))
+		check(t, err)
+		tie1, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		tie2, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+		check(t, s.SetGameActive(cctx, tie1.ID, false))
+
+		want := []int64{tie1.ID, tie2.ID, later.ID}
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if got := gameIDs(games); !slices.Equal(got, want) {
+			t.Errorf("games = %v, want %v", got, want)
+		}
+	}},
+
+	{"GetGame finds one game, active or not", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		in := f.game(at(2026, 2, 3, 12, 0))
+		in.Notes = "found"
+		g, err := s.AddGame(cctx, in)
+		check(t, err)
+		check(t, s.SetGameActive(cctx, g.ID, false))
+
+		got, ok, err := s.GetGame(cctx, g.ID)
+		check(t, err)
+		if !ok {
+			t.Fatal("game not found")
+		}
+		want := in
+		want.ID, want.Title, want.Mode = g.ID, "Hanabi", game.ModeCompetitive
+		sameGame(t, got, want)
+
+		_, ok, err = s.GetGame(cctx, g.ID+100)
+		check(t, err)
+		if ok {
+			t.Error("unknown game found")
+		}
+	}},
+
+	{"GetWeek uses ISO weeks in the league time zone", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		sunday, err := s.AddGame(cctx, f.game(at(2026, 2, 8, 23, 30))) // W06, already Monday in UTC
+		check(t, err)
+		monday, err := s.AddGame(cctx, f.game(at(2026, 2, 9, 0, 0))) // W07
+		check(t, err)
+		dec, err := s.AddGame(cctx, f.game(at(2025, 12, 30, 12, 0))) // 2026-W01
+		check(t, err)
+		tie1, err := s.AddGame(cctx, f.game(at(2026, 2, 10, 12, 0))) // W07
+		check(t, err)
+		tie2, err := s.AddGame(cctx, f.game(at(2026, 2, 10, 12, 0))) // W07
+		check(t, err)
+		_, err = s.AddGame(cctx, f.game(at(2026, 2, 16, 0, 0).Add(-1))) // W07, last instant
+		check(t, err)
+
+		week, err := s.GetWeek(cctx, 2026, 6)
+		check(t, err)
+		if got := gameIDs(week); !slices.Equal(got, []int64{sunday.ID}) {
+			t.Errorf("W06 = %v, want %v", got, []int64{sunday.ID})
+		}
+		w7, err := s.GetWeek(cctx, 2026, 7)
+		check(t, err)
+		if got := gameIDs(w7); len(got) != 4 || got[0] != monday.ID || got[1] != tie1.ID || got[2] != tie2.ID {
+			t.Errorf("W07 = %v, want %v, %v, %v and the last-instant game", got, monday.ID, tie1.ID, tie2.ID)
+		}
+		week, err = s.GetWeek(cctx, 2026, 1)
+		check(t, err)
+		if got := gameIDs(week); !slices.Equal(got, []int64{dec.ID}) {
+			t.Errorf("2026-W01 = %v, want the December game %d", got, dec.ID)
+		}
+		got, err := s.GetWeek(cctx, 2026, 8)
+		check(t, err)
+		if len(got) != 0 {
+			t.Errorf("W08 = %v, want none", gameIDs(got))
+		}
+		got, err = s.GetWeek(cctx, 2026, 8)
+		check(t, err)
+		if got == nil {
+			t.Error("empty week is nil, want an empty list")
+		}
+	}},
+
+	{"GetYear uses calendar years in the league time zone", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		nye, err := s.AddGame(cctx, f.game(at(2025, 12, 31, 23, 0))) // already 2026 in UTC
+		check(t, err)
+		dec, err := s.AddGame(cctx, f.game(at(2025, 12, 30, 12, 0))) // in 2026-W01
+		check(t, err)
+		jan, err := s.AddGame(cctx, f.game(at(2026, 1, 1, 0, 0)))
+		check(t, err)
+
+		year, err := s.GetYear(cctx, 2025)
+		check(t, err)
+		if got := gameIDs(year); !slices.Equal(got, []int64{dec.ID, nye.ID}) {
+			t.Errorf("2025 = %v, want %v", got, []int64{dec.ID, nye.ID})
+		}
+		year, err = s.GetYear(cctx, 2026)
+		check(t, err)
+		if got := gameIDs(year); !slices.Equal(got, []int64{jan.ID}) {
+			t.Errorf("2026 = %v, want %v", got, []int64{jan.ID})
+		}
+	}},
+
+	// ============================
+	// Players
+	// ============================
+
+	{"AddPlayer and ListPlayers", func(t *testing.T, s conformanceStore) {
+		seeded, err := s.ListPlayers(cctx)
+		check(t, err)
+		if got := playerNames(seeded); len(got) != len(game.SeedPlayers) || !slices.IsSorted(got) {
+			t.Errorf("seeded players = %v, want the %d seeds by name", got, len(game.SeedPlayers))
+		}
+
+		p, err := s.AddPlayer(cctx, "Alice")
+		check(t, err)
+		if p.ID == 0 || p.Name != "Alice" || !p.IsActive {
+			t.Errorf("player = %+v", p)
+		}
+		all, err := s.ListPlayers(cctx)
+		check(t, err)
+		if !slices.Contains(all, p) {
+			t.Errorf("players = %+v, want %+v among them", all, p)
+		}
+		_, err = s.AddPlayer(cctx, "Alice")
+		wantErr(t, err, "")
+	}},
+
+	{"ListPlayers lists active players first, each group by name", func(t *testing.T, s conformanceStore) {
+		players, err := s.ListPlayers(cctx)
+		check(t, err)
+		for _, p := range players {
+			check(t, s.SetPlayerActive(cctx, p.ID, false))
+		}
+		z, err := s.AddPlayer(cctx, "Zed")
+		check(t, err)
+		_, err = s.AddPlayer(cctx, "Amy")
+		check(t, err)
+		check(t, s.SetPlayerActive(cctx, z.ID, false))
+
+		got, err := s.ListPlayers(cctx)
+		check(t, err)
+		if got[0].Name != "Amy" || !got[0].IsActive {
+			t.Fatalf("first = %+v, want Amy, the only active player", got[0])
+		}
+		rest := playerNames(got[1:])
+		if !slices.IsSorted(rest) || !slices.Contains(rest, "Zed") {
+			t.Errorf("inactive players = %v, want sorted and including Zed", rest)
+		}
+	}},
+
+	{"UpdatePlayer renames", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		check(t, s.UpdatePlayer(cctx, f.a.ID, "Alicia"))
+		players, err := s.ListPlayers(cctx)
+		check(t, err)
+		if !slices.Contains(players, game.Player{ID: f.a.ID, Name: "Alicia", IsActive: true}) {
+			t.Error("rename not stored")
+		}
+		wantErr(t, s.UpdatePlayer(cctx, f.a.ID, "Bob"), "")
+		wantErr(t, s.UpdatePlayer(cctx, f.c.ID+100, "Nobody"), "player not found")
+	}},
+
+	{"SetPlayerActive", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		check(t, s.SetPlayerActive(cctx, f.a.ID, false))
+		players, err := s.ListPlayers(cctx)
+		check(t, err)
+		if !slices.Contains(players, game.Player{ID: f.a.ID, Name: "Alice", IsActive: false}) {
+			t.Error("player still active")
+		}
+		wantErr(t, s.SetPlayerActive(cctx, f.c.ID+100, true), "player not found")
+	}},
+
+	{"DeletePlayer refuses players in games and unlinks accounts", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		g := f.game(at(2026, 2, 3, 12, 0))
+		g.ParticipantIDs, g.WinnerIDs = []int64{f.a.ID}, []int64{f.b.ID}
+		_, err := s.AddGame(cctx, g)
+		check(t, err)
+
+		wantErr(t, s.DeletePlayer(cctx, f.a.ID), "player is referenced by a game")
+		wantErr(t, s.DeletePlayer(cctx, f.b.ID), "player is referenced by a game")
+
+		u, err := s.AddUser(cctx, game.User{Username: "cleo", PasswordHash: "x", Role: game.RoleViewer, PlayerID: &f.c.ID, IsActive: true})
+		check(t, err)
+		check(t, s.DeletePlayer(cctx, f.c.ID))
+		players, err := s.ListPlayers(cctx)
+		check(t, err)
+		for _, p := range players {
+			if p.ID == f.c.ID {
+				t.Error("deleted player still listed")
+			}
+		}
+		if got, _, _ := s.GetUser(cctx, u.ID); got.PlayerID != nil {
+			t.Errorf("user still plays as %d", *got.PlayerID)
+		}
+		wantErr(t, s.DeletePlayer(cctx, f.c.ID), "player not found")
+	}},
+
+	// ============================
+	// Titles
+	// ============================
+
+	{"AddTitle and ListTitles", func(t *testing.T, s conformanceStore) {
+		seeded, err := s.ListTitles(cctx)
+		check(t, err)
+		if len(seeded) != len(game.SeedTitles) {
+			t.Errorf("seeded titles = %d, want %d", len(seeded), len(game.SeedTitles))
+		}
+
+		check(t, s.SetTitleActive(cctx, seeded[0].ID, false))
+		tt, err := s.AddTitle(cctx, "Azul")
+		check(t, err)
+		if tt.ID == 0 || tt.Name != "Azul" || !tt.IsActive {
+			t.Errorf("title = %+v", tt)
+		}
+		all, err := s.ListTitles(cctx)
+		check(t, err)
+		if all[0] != tt {
+			t.Errorf("first title = %+v, want %+v (active, sorted by name)", all[0], tt)
+		}
+		if last := all[len(all)-1]; last.ID != seeded[0].ID || last.IsActive {
+			t.Errorf("last title = %+v, want the inactive %+v", last, seeded[0])
+		}
+		_, err = s.AddTitle(cctx, "Azul")
+		wantErr(t, err, "")
+	}},
+
+	{"UpdateTitle renames, and games show the new name", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		_, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+
+		check(t, s.UpdateTitle(cctx, f.title.ID, "Hanabi Deluxe"))
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if g := games[0]; g.Title != "Hanabi Deluxe" {
+			t.Errorf("game title = %q, want the new name", g.Title)
+		}
+		wantErr(t, s.UpdateTitle(cctx, f.title.ID, "Coup"), "")
+		wantErr(t, s.UpdateTitle(cctx, f.title.ID+100, "Nothing"), "title not found")
+	}},
+
+	{"SetTitleActive", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		check(t, s.SetTitleActive(cctx, f.title.ID, false))
+		titles, err := s.ListTitles(cctx)
+		check(t, err)
+		if !slices.Contains(titles, game.Title{ID: f.title.ID, Name: "Hanabi", IsActive: false}) {
+			t.Error("title still active")
+		}
+		wantErr(t, s.SetTitleActive(cctx, f.title.ID+100, true), "title not found")
+	}},
+
+	{"DeleteTitle refuses titles in games", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		g, err := s.AddGame(cctx, f.game(at(2026, 2, 3, 12, 0)))
+		check(t, err)
+
+		wantErr(t, s.DeleteTitle(cctx, f.title.ID), "title is referenced by a game")
+		check(t, s.DeleteGame(cctx, g.ID))
+		check(t, s.DeleteTitle(cctx, f.title.ID))
+		wantErr(t, s.DeleteTitle(cctx, f.title.ID), "title not found")
+	}},
+
+	// ============================
+	// Tiebreakers
+	// ============================
+
+	{"GetTiebreaker of a missing key is not an error", func(t *testing.T, s conformanceStore) {
+		tb, ok, err := s.GetTiebreaker(cctx, "weekly", "2026-W07")
+		if ok || err != nil || !reflect.DeepEqual(tb, game.Tiebreaker{}) {
+			t.Errorf("GetTiebreaker = %+v, %v, %v; want zero, false, nil", tb, ok, err)
+		}
+		hist, err := s.ListTiebreakerHistory(cctx, "weekly", "2026-W07")
+		check(t, err)
+		if len(hist) != 0 {
+			t.Errorf("history = %+v, want none", hist)
+		}
+	}},
+
+	{"SetTiebreaker replaces the decision and keeps the history", func(t *testing.T, s conformanceStore) {
+		first := game.Tiebreaker{
+			Scope: "weekly", ScopeKey: "2026-W07", TiedPlayerIDs: []int64{1, 2}, WinnerID: 1,
+			Method: game.MethodChance, DecidedAt: at(2026, 2, 13, 17, 0), Seed: "abc", Algorithm: "sha256-mod",
+		}
+		second := game.Tiebreaker{
+			Scope: "weekly", ScopeKey: "2026-W07", TiedPlayerIDs: []int64{1, 2}, WinnerID: 2,
+			Method: game.MethodPlayoff, DecidedAt: at(2026, 2, 14, 9, 0), GameID: 7,
+		}
+		check(t, s.SetTiebreaker(cctx, first))
+		check(t, s.SetTiebreaker(cctx, second))
+		check(t, s.SetTiebreaker(cctx, game.Tiebreaker{Scope: "weekly", ScopeKey: "2026-W08", WinnerID: 3}))
+
+		same := func(got, want game.Tiebreaker) bool {
+			return got.DecidedAt.Equal(want.DecidedAt) && func() bool {
+				got.DecidedAt, want.DecidedAt = time.Time{}, time.Time{}
+				return reflect.DeepEqual(got, want)
+			}()
+		}
+		tb, ok, err := s.GetTiebreaker(cctx, "weekly", "2026-W07")
+		check(t, err)
+		if !ok || !same(tb, second) {
+			t.Errorf("tiebreaker = %+v, want %+v", tb, second)
+		}
+		hist, err := s.ListTiebreakerHistory(cctx, "weekly", "2026-W07")
+		check(t, err)
+		if len(hist) != 2 || !same(hist[0], second) || !same(hist[1], first) {
+			t.Errorf("history = %+v, want latest first", hist)
+		}
+	}},
+
+	{"ListTiebreakers lists current decisions by scope and key", func(t *testing.T, s conformanceStore) {
+		for _, k := range [][2]string{{"yearly", "2026"}, {"weekly", "2026-W08"}, {"weekly", "2026-W07"}, {"weekly", "2026-W08"}} {
+			check(t, s.SetTiebreaker(cctx, game.Tiebreaker{Scope: k[0], ScopeKey: k[1], WinnerID: 1}))
+		}
+		var got []string
+		tbs, err := s.ListTiebreakers(cctx)
+		check(t, err)
+		for _, tb := range tbs {
+			got = append(got, tb.Scope+"/"+tb.ScopeKey)
+		}
+		if want := []string{"weekly/2026-W07", "weekly/2026-W08", "yearly/2026"}; !slices.Equal(got, want) {
+			t.Errorf("tiebreakers = %v, want %v", got, want)
+		}
+	}},
+
+	// ============================
+	// Rulesets and seasons
+	// ============================
+
+	{"Rulesets are dated, unique per date and listed oldest first", func(t *testing.T, s conformanceStore) {
+		r := game.DefaultRuleset()
+		r.Name = "Spring rules"
+		r.Weekly.Metric = game.MetricWinRate
+		r.EffectiveFrom = at(2026, 3, 1, 18, 45)
+		spring, err := s.AddRuleset(cctx, r)
+		check(t, err)
+		if spring.ID == 0 || !spring.EffectiveFrom.Equal(at(2026, 3, 1, 0, 0)) {
+			t.Errorf("ruleset = %+v, want an ID and midnight on 2026-03-01", spring)
+		}
+
+		r.Name, r.EffectiveFrom = "New year rules", at(2026, 1, 1, 0, 0)
+		newYear, err := s.AddRuleset(cctx, r)
+		check(t, err)
+		r.EffectiveFrom = at(2026, 3, 1, 9, 0)
+		_, err = s.AddRuleset(cctx, r)
+		wantErr(t, err, "")
+
+		got, err := s.ListRulesets(cctx)
+		check(t, err)
+		if len(got) != 2 || !reflect.DeepEqual(got[0], newYear) || !reflect.DeepEqual(got[1], spring) {
+			t.Errorf("rulesets = %+v, want %+v then %+v", got, newYear, spring)
+		}
+
+		check(t, s.DeleteRuleset(cctx, newYear.ID))
+		got, err = s.ListRulesets(cctx)
+		check(t, err)
+		if len(got) != 1 {
+			t.Errorf("rulesets = %+v, want 1", got)
+		}
+		wantErr(t, s.DeleteRuleset(cctx, newYear.ID), "ruleset not found")
+	}},
+
+	{"Seasons are dated, unique per name and listed latest first", func(t *testing.T, s conformanceStore) {
+		summer, err := s.AddSeason(cctx, game.Season{Name: "Summer", StartDate: at(2026, 6, 1, 15, 0), EndDate: at(2026, 8, 31, 23, 0)})
+		check(t, err)
+		if summer.ID == 0 || !summer.StartDate.Equal(at(2026, 6, 1, 0, 0)) || !summer.EndDate.Equal(at(2026, 8, 31, 0, 0)) {
+			t.Errorf("season = %+v, want an ID and league dates", summer)
+		}
+		spring, err := s.AddSeason(cctx, game.Season{Name: "Spring", StartDate: at(2026, 3, 1, 0, 0), EndDate: at(2026, 5, 31, 0, 0)})
+		check(t, err)
+
+		_, err = s.AddSeason(cctx, game.Season{Name: "Summer", StartDate: at(2027, 6, 1, 0, 0), EndDate: at(2027, 8, 31, 0, 0)})
+		wantErr(t, err, "")
+		_, err = s.AddSeason(cctx, game.Season{Name: "Backwards", StartDate: at(2026, 9, 2, 0, 0), EndDate: at(2026, 9, 1, 0, 0)})
+		wantErr(t, err, "")
+
+		got, err := s.ListSeasons(cctx)
+		check(t, err)
+		if len(got) != 2 || !reflect.DeepEqual(got[0], summer) || !reflect.DeepEqual(got[1], spring) {
+			t.Errorf("seasons = %+v, want %+v then %+v", got, summer, spring)
+		}
+
+		check(t, s.DeleteSeason(cctx, summer.ID))
+		wantErr(t, s.DeleteSeason(cctx, summer.ID), "season not found")
+	}},
+
+	// ============================
+	// Audit
+	// ============================
+
+	{"AppendAudit and ListAudit", func(t *testing.T, s conformanceStore) {
+		t0 := at(2026, 2, 3, 12, 0)
+		entries := []game.AuditEntry{
+			{At: t0, Actor: "Alice", Action: game.AuditCreate, Entity: game.AuditGame, EntityID: "1", After: []byte(`{"id":1}`)},
+			{At: t0.Add(time.Hour), Actor: "bob", Action: game.AuditUpdate, Entity: game.AuditGame, EntityID: "1", Before: []byte(`{"id":1}`), After: []byte(`{"id":1,"notes":"x"}`)},
+			{At: t0.Add(2 * time.Hour), Actor: "alice", Action: game.AuditDelete, Entity: game.AuditPlayer, EntityID: "7", Before: []byte(`{"id":7}`)},
+			{Actor: "system", Action: game.AuditCreate, Entity: game.AuditUser, EntityID: "1"},
+		}
+		for _, e := range entries {
+			check(t, s.AppendAudit(cctx, e))
+		}
+
+		all, err := s.ListAudit(cctx, game.AuditFilter{})
+		check(t, err)
+		if len(all) != 4 {
+			t.Fatalf("entries = %d, want 4", len(all))
+		}
+		for i, e := range all {
+			want := entries[len(entries)-1-i]
+			if e.ID == 0 || e.Actor != want.Actor || e.Action != want.Action || e.Entity != want.Entity || e.EntityID != want.EntityID {
+				t.Errorf("entry %d = %+v, want %+v", i, e, want)
+			}
+			if i > 0 && e.ID >= all[i-1].ID {
+				t.Errorf("entries not latest first: %d after %d", e.ID, all[i-1].ID)
+			}
+			if !want.At.IsZero() && !e.At.Equal(want.At) {
+				t.Errorf("entry %d at %v, want %v", i, e.At, want.At)
+			}
+			if !sameJSON(e.Before, want.Before) || !sameJSON(e.After, want.After) {
+				t.Errorf("entry %d snapshots = %s / %s, want %s / %s", i, e.Before, e.After, want.Before, want.After)
+			}
+		}
+		if all[0].At.IsZero() || time.Since(all[0].At) > time.Minute {
+			t.Errorf("unset time stored as %v, want now", all[0].At)
+		}
+
+		cases := []struct {
+			f    game.AuditFilter
+			want []string // EntityIDs
+		}{
+			{game.AuditFilter{Actor: "ALICE"}, []string{"7", "1"}},
+			{game.AuditFilter{Action: game.AuditUpdate}, []string{"1"}},
+			{game.AuditFilter{Entity: game.AuditPlayer, EntityID: "7"}, []string{"7"}},
+			{game.AuditFilter{From: t0.Add(time.Hour), To: t0.Add(2 * time.Hour)}, []string{"1"}},
+			{game.AuditFilter{Entity: game.AuditGame, Limit: 1}, []string{"1"}},
+			{game.AuditFilter{Actor: "nobody"}, nil},
+		}
+		for _, c := range cases {
+			var got []string
+			entries, err := s.ListAudit(cctx, c.f)
+			check(t, err)
+			for _, e := range entries {
+				got = append(got, e.EntityID)
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("ListAudit(%+v) = %v, want %v", c.f, got, c.want)
+			}
+		}
+	}},
+
+	// ============================
+	// Users, sessions and API tokens
+	// ============================
+
+	{"Users are unique ignoring case and listed by name", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		bob, err := s.AddUser(cctx, game.User{Username: "bob", PasswordHash: "h1", Role: game.RoleRecorder, IsActive: true})
+		check(t, err)
+		alice, err := s.AddUser(cctx, game.User{Username: "Alice", PasswordHash: "h2", Role: game.RoleAdmin, PlayerID: &f.a.ID, IsActive: true})
+		check(t, err)
+		if alice.ID == 0 || alice.ID == bob.ID || alice.CreatedAt.IsZero() || alice.PlayerID == nil || *alice.PlayerID != f.a.ID {
+			t.Errorf("user = %+v", alice)
+		}
+		_, err = s.AddUser(cctx, game.User{Username: "ALICE", PasswordHash: "h3", Role: game.RoleViewer})
+		wantErr(t, err, "")
+
+		var names []string
+		users, err := s.ListUsers(cctx)
+		check(t, err)
+		for _, u := range users {
+			names = append(names, u.Username)
+		}
+		if !slices.Equal(names, []string{"Alice", "bob"}) {
+			t.Errorf("users = %v, want Alice, bob", names)
+		}
+
+		got, ok, err := s.GetUserByUsername(cctx, "aLiCe")
+		check(t, err)
+		if !ok || got.ID != alice.ID || got.PasswordHash != "h2" || got.Role != game.RoleAdmin || !got.IsActive || !got.CreatedAt.Equal(alice.CreatedAt) {
+			t.Errorf("GetUserByUsername = %+v, %v; want %+v", got, ok, alice)
+		}
+		_, ok, err = s.GetUserByUsername(cctx, "carol")
+		check(t, err)
+		if ok {
+			t.Error("unknown user name found")
+		}
+		got, ok, err = s.GetUser(cctx, bob.ID)
+		check(t, err)
+		if !ok || got.Username != "bob" || got.PlayerID != nil {
+			t.Errorf("GetUser = %+v, %v", got, ok)
+		}
+		_, ok, err = s.GetUser(cctx, alice.ID+100)
+		check(t, err)
+		if ok {
+			t.Error("unknown user ID found")
+		}
+	}},
+
+	{"UpdateUser changes the password, role, player and active flag only", func(t *testing.T, s conformanceStore) {
+		f := newFixture(t, s)
+		u, err := s.AddUser(cctx, game.User{Username: "alice", PasswordHash: "old", Role: game.RoleViewer, IsActive: true})
+		check(t, err)
+
+		check(t, s.UpdateUser(cctx, game.User{ID: u.ID, Username: "mallory", PasswordHash: "new", Role: game.RoleAdmin, PlayerID: &f.b.ID, IsActive: false}))
+		got, _, err := s.GetUser(cctx, u.ID)
+		check(t, err)
+		if got.Username != "alice" || got.PasswordHash != "new" || got.Role != game.RoleAdmin || got.PlayerID == nil || *got.PlayerID != f.b.ID || got.IsActive || !got.CreatedAt.Equal(u.CreatedAt) {
+			t.Errorf("user = %+v", got)
+		}
+		wantErr(t, s.UpdateUser(cctx, game.User{ID: u.ID + 100, Role: game.RoleViewer}), "user not found")
+	}},
+
+	{"DeleteUser signs the user out", func(t *testing.T, s conformanceStore) {
+		u, err := s.AddUser(cctx, game.User{Username: "alice", PasswordHash: "x", Role: game.RoleViewer, IsActive: true})
+		check(t, err)
+		check(t, s.AddSession(cctx, game.Session{TokenHash: "t1", UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)}))
+
+		check(t, s.DeleteUser(cctx, u.ID))
+		_, ok, err := s.GetUser(cctx, u.ID)
+		check(t, err)
+		if ok {
+			t.Error("deleted user found")
+		}
+		_, ok, err = s.GetSession(cctx, "t1")
+		check(t, err)
+		if ok {
+			t.Error("session outlived its user")
+		}
+		wantErr(t, s.DeleteUser(cctx, u.ID), "user not found")
+	}},
+
+	{"Sessions expire and can be ended one at a time or all at once", func(t *testing.T, s conformanceStore) {
+		alice, err := s.AddUser(cctx, game.User{Username: "alice", PasswordHash: "x", Role: game.RoleViewer, IsActive: true})
+		check(t, err)
+		bob, err := s.AddUser(cctx, game.User{Username: "bob", PasswordHash: "x", Role: game.RoleViewer, IsActive: true})
+		check(t, err)
+		expires := time.Now().Add(time.Hour).Truncate(time.Second)
+		for _, sess := range []game.Session{
+			{TokenHash: "a1", UserID: alice.ID, ExpiresAt: expires},
+			{TokenHash: "a2", UserID: alice.ID, ExpiresAt: expires},
+			{TokenHash: "b1", UserID: bob.ID, ExpiresAt: expires},
+			{TokenHash: "old", UserID: bob.ID, ExpiresAt: time.Now().Add(-time.Minute)},
+		} {
+			check(t, s.AddSession(cctx, sess))
+		}
+		wantErr(t, s.AddSession(cctx, game.Session{TokenHash: "a1", UserID: bob.ID, ExpiresAt: expires}), "")
+
+		sess, ok, err := s.GetSession(cctx, "a1")
+		check(t, err)
+		if !ok || sess.TokenHash != "a1" || sess.UserID != alice.ID || !sess.ExpiresAt.Equal(expires) || sess.CreatedAt.IsZero() {
+			t.Errorf("session = %+v, %v", sess, ok)
+		}
+		_, ok, err = s.GetSession(cctx, "old")
+		check(t, err)
+		if ok {
+			t.Error("expired session found")
+		}
+
+		check(t, s.DeleteSession(cctx, "a1"))
+		check(t, s.DeleteSession(cctx, "a1")) // already gone: not an error
+		check(t, s.DeleteUserSessions(cctx, bob.ID))
+		for hash, want := range map[string]bool{"a1": false, "a2": true, "b1": false} {
+			_, ok, err := s.GetSession(cctx, hash)
+			check(t, err)
+			if ok != want {
+				t.Errorf("session %s found = %v, want %v", hash, ok, want)
+			}
+		}
+	}},
+
+	{"API tokens", func(t *testing.T, s conformanceStore) {
+		t1, err := s.AddAPIToken(cctx, game.APIToken{Name: "bot", TokenHash: "h1", Scope: game.ScopeRead, CreatedBy: "admin"})
+		check(t, err)
+		t2, err := s.AddAPIToken(cctx, game.APIToken{Name: "scorer", TokenHash: "h2", Scope: game.ScopeWriteGames, CreatedBy: "admin"})
+		check(t, err)
+		if t1.ID == 0 || t1.CreatedAt.IsZero() || t1.LastUsedAt != nil || t1.RevokedAt != nil || !t1.Active() {
+			t.Errorf("token = %+v", t1)
+		}
+		_, err = s.AddAPIToken(cctx, game.APIToken{Name: "copy", TokenHash: "h1", Scope: game.ScopeRead})
+		wantErr(t, err, "")
+
+		got, err := s.ListAPITokens(cctx)
+		check(t, err)
+		if len(got) != 2 || got[0].ID != t2.ID || got[1].ID != t1.ID {
+			t.Errorf("tokens = %+v, want newest first", got)
+		}
+		byHash, ok, err := s.GetAPITokenByHash(cctx, "h2")
+		check(t, err)
+		if !ok || byHash.ID != t2.ID || byHash.Name != "scorer" || byHash.Scope != game.ScopeWriteGames || byHash.CreatedBy != "admin" {
+			t.Errorf("GetAPITokenByHash = %+v, %v", byHash, ok)
+		}
+		_, ok, err = s.GetAPITokenByHash(cctx, "nope")
+		check(t, err)
+		if ok {
+			t.Error("unknown token found")
+		}
+
+		used := at(2026, 2, 3, 12, 0)
+		check(t, s.TouchAPIToken(cctx, t1.ID, used))
+		check(t, s.RevokeAPIToken(cctx, t1.ID))
+		first, _, err := s.GetAPITokenByHash(cctx, "h1")
+		check(t, err)
+		if first.LastUsedAt == nil || !first.LastUsedAt.Equal(used) || first.RevokedAt == nil || first.Active() {
+			t.Fatalf("token = %+v, want used at %v and revoked", first, used)
+		}
+		check(t, s.RevokeAPIToken(cctx, t1.ID))
+		again, _, err := s.GetAPITokenByHash(cctx, "h1")
+		check(t, err)
+		if !again.RevokedAt.Equal(*first.RevokedAt) {
+			t.Errorf("revoked again at %v, want the first time %v kept", again.RevokedAt, first.RevokedAt)
+		}
+
+		wantErr(t, s.RevokeAPIToken(cctx, t2.ID+100), "token not found")
+		wantErr(t, s.TouchAPIToken(cctx, t2.ID+100, used), "token not found")
+	}},
+
+	// ============================
+	// Webhooks
+	// ============================
+
+	{"webhooks list by name, toggle and delete with their deliveries", func(t *testing.T, s conformanceStore) {
+		chat, err := s.AddWebhook(cctx, game.Webhook{Name: "league chat", URL: "https://chat.example/hook", Secret: "whsec_1",
+			Events: []string{game.WebhookGameLogged, game.WebhookTieNeedsBreaking}, IsActive: true, CreatedBy: "admin"})
+		check(t, err)
+		bot, err := s.AddWebhook(cctx, game.Webhook{Name: "Bot", URL: "http://bot.local", Secret: "whsec_2", Events: game.WebhookEvents})
+		check(t, err)
+		if chat.ID == 0 || chat.CreatedAt.IsZero() || chat.Secret != "whsec_1" || !chat.IsActive || chat.CreatedBy != "admin" {
+			t.Errorf("webhook = %+v", chat)
+		}
+
+		hooks, err := s.ListWebhooks(cctx)
+		check(t, err)
+		if len(hooks) != 2 || hooks[0].ID != bot.ID || hooks[1].ID != chat.ID {
+			t.Fatalf("webhooks = %+v, want by name ignoring case", hooks)
+		}
+		if !slices.Equal(hooks[1].Events, chat.Events) || hooks[1].URL != chat.URL || hooks[0].IsActive {
+			t.Errorf("listed = %+v", hooks)
+		}
+
+		check(t, s.SetWebhookActive(cctx, bot.ID, true))
+		hooks, err = s.ListWebhooks(cctx)
+		check(t, err)
+		if !hooks[0].IsActive {
+			t.Error("webhook not activated")
+		}
+		wantErr(t, s.SetWebhookActive(cctx, bot.ID+100, true), "webhook not found")
+
+		_, err = s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: bot.ID, Event: game.WebhookGameLogged, Payload: []byte(`{}`)})
+		check(t, err)
+		check(t, s.DeleteWebhook(cctx, bot.ID))
+		wantErr(t, s.DeleteWebhook(cctx, bot.ID), "webhook not found")
+		hooks, err = s.ListWebhooks(cctx)
+		check(t, err)
+		if len(hooks) != 1 || hooks[0].ID != chat.ID {
+			t.Errorf("webhooks = %+v, want chat left", hooks)
+		}
+		ds, err := s.ListWebhookDeliveries(cctx, 0, 0)
+		check(t, err)
+		if len(ds) != 0 {
+			t.Errorf("deliveries = %+v, want the deleted webhook's gone", ds)
+		}
+	}},
+
+	{"webhook deliveries queue, are claimed when due and keep their outcome", func(t *testing.T, s conformanceStore) {
+		a, err := s.AddWebhook(cctx, game.Webhook{Name: "a", URL: "https://a.example", Secret: "x", Events: game.WebhookEvents, IsActive: true})
+		check(t, err)
+		b, err := s.AddWebhook(cctx, game.Webhook{Name: "b", URL: "https://b.example", Secret: "y", Events: game.WebhookEvents, IsActive: true})
+		check(t, err)
+
+		payload := []byte(`{"event":"game.logged","text":"Game logged: Hanabi"}`)
+		d1, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: a.ID, Event: game.WebhookGameLogged, Payload: payload})
+		check(t, err)
+		if d1.ID == 0 || d1.Status != game.DeliveryPending || d1.Attempts != 0 || d1.NextAttemptAt.IsZero() || d1.CreatedAt.IsZero() ||
+			string(d1.Payload) != string(payload) || d1.DeliveredAt != nil {
+			t.Errorf("delivery = %+v", d1)
+		}
+		later := time.Now().Add(time.Hour)
+		d2, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: b.ID, Event: game.WebhookTieNeedsBreaking, Payload: []byte(`{}`), NextAttemptAt: later})
+		check(t, err)
+		d3, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: a.ID, Event: game.WebhookWeekWinnerDecided, Payload: []byte(`{}`)})
+		check(t, err)
+		_, err = s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: b.ID + 100, Event: game.WebhookGameLogged, Payload: []byte(`{}`)})
+		wantErr(t, err, "webhook not found")
+
+		// A claimed delivery isn't handed out again until its lease runs out.
+		now := time.Now().Add(time.Second)
+		due, err := s.ClaimWebhookDeliveries(cctx, now, time.Minute, 1)
+		check(t, err)
+		if len(due) != 1 || due[0].ID != d1.ID || string(due[0].Payload) != string(payload) || due[0].NextAttemptAt.Sub(now) < 59*time.Second {
+			t.Fatalf("claimed with limit 1 = %+v, want d1 leased for a minute", due)
+		}
+		due, err = s.ClaimWebhookDeliveries(cctx, now, time.Minute, 0)
+		check(t, err)
+		if len(due) != 1 || due[0].ID != d3.ID {
+			t.Fatalf("claimed = %+v, want just d3", due)
+		}
+		due, err = s.ClaimWebhookDeliveries(cctx, now, time.Minute, 0)
+		check(t, err)
+		if len(due) != 0 {
+			t.Fatalf("claimed again = %+v, want none", due)
+		}
+
+		// d1 fails once and is due again later; d3 is delivered.
+		d1.Attempts, d1.LastStatus, d1.LastError, d1.NextAttemptAt = 1, 500, "receiver answered 500", later.Add(time.Minute)
+		check(t, s.UpdateWebhookDelivery(cctx, d1))
+		delivered := at(2026, 2, 3, 12, 0)
+		d3.Status, d3.Attempts, d3.LastStatus, d3.DeliveredAt = game.DeliveryDelivered, 1, 200, &delivered
+		check(t, s.UpdateWebhookDelivery(cctx, d3))
+		wantErr(t, s.UpdateWebhookDelivery(cctx, game.WebhookDelivery{ID: d3.ID + 100, Status: game.DeliveryFailed, NextAttemptAt: now}), "delivery not found")
+
+		due, err = s.ClaimWebhookDeliveries(cctx, now.Add(2*time.Minute), time.Minute, 0)
+		check(t, err)
+		if len(due) != 0 {
+			t.Errorf("claimed = %+v, want none", due)
+		}
+		due, err = s.ClaimWebhookDeliveries(cctx, later.Add(2*time.Minute), time.Minute, 1)
+		check(t, err)
+		if len(due) != 1 || due[0].ID != d2.ID {
+			t.Errorf("claimed later with limit 1 = %+v, want d2, due first", due)
+		}
+		due, err = s.ClaimWebhookDeliveries(cctx, later.Add(2*time.Minute), time.Minute, 0)
+		check(t, err)
+		if len(due) != 1 || due[0].ID != d1.ID {
+			t.Errorf("claimed later = %+v, want d1", due)
+		}
+
+		all, err := s.ListWebhookDeliveries(cctx, 0, 0)
+		check(t, err)
+		if len(all) != 3 || all[0].ID != d3.ID || all[2].ID != d1.ID {
+			t.Fatalf("deliveries = %+v, want latest first", all)
+		}
+		if got := all[0]; got.Status != game.DeliveryDelivered || got.LastStatus != 200 || got.DeliveredAt == nil || !got.DeliveredAt.Equal(delivered) {
+			t.Errorf("delivered = %+v", got)
+		}
+		if got := all[2]; got.Status != game.DeliveryPending || got.Attempts != 1 || got.LastStatus != 500 || got.LastError != "receiver answered 500" {
+			t.Errorf("retrying = %+v", got)
+		}
+		got, err := s.ListWebhookDeliveries(cctx, a.ID, 1)
+		check(t, err)
+		if len(got) != 1 || got[0].ID != d3.ID {
+			t.Errorf("a's latest delivery = %+v", got)
+		}
+		got, err = s.ListWebhookDeliveries(cctx, b.ID, 0)
+		check(t, err)
+		if len(got) != 1 || got[0].ID != d2.ID {
+			t.Errorf("b's deliveries = %+v", got)
+		}
+	}},
+
+	{"webhook deliveries with a key are queued once per webhook", func(t *testing.T, s conformanceStore) {
+		a, err := s.AddWebhook(cctx, game.Webhook{Name: "a", URL: "https://a.example", Secret: "x", Events: game.WebhookEvents, IsActive: true})
+		check(t, err)
+		b, err := s.AddWebhook(cctx, game.Webhook{Name: "b", URL: "https://b.example", Secret: "y", Events: game.WebhookEvents, IsActive: true})
+		check(t, err)
+
+		const key = "tie.needs_breaking:2026-W07:[1 2]"
+		first, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: a.ID, Event: game.WebhookTieNeedsBreaking, Payload: []byte(`{"n":1}`), Key: key})
+		check(t, err)
+		again, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: a.ID, Event: game.WebhookTieNeedsBreaking, Payload: []byte(`{"n":2}`), Key: key})
+		check(t, err)
+		if again.ID != first.ID || string(again.Payload) != `{"n":1}` || again.Key != key {
+			t.Errorf("second delivery with the key = %+v, want the first", again)
+		}
+		other, err := s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: b.ID, Event: game.WebhookTieNeedsBreaking, Payload: []byte(`{}`), Key: key})
+		check(t, err)
+		for range 2 {
+			_, err = s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: a.ID, Event: game.WebhookGameLogged, Payload: []byte(`{}`)})
+			check(t, err)
+		}
+		_, err = s.AddWebhookDelivery(cctx, game.WebhookDelivery{WebhookID: b.ID + 100, Event: game.WebhookGameLogged, Payload: []byte(`{}`), Key: key})
+		wantErr(t, err, "webhook not found")
+
+		all, err := s.ListWebhookDeliveries(cctx, 0, 0)
+		check(t, err)
+		if len(all) != 4 || other.ID == first.ID || all[0].Key != "" {
+			t.Errorf("deliveries = %+v, want one keyed per webhook and both unkeyed", all)
+		}
+	}},
+
+	// ============================
+	// Week recaps
+	// ============================
+
+	{"week recaps round-trip, are kept as first added and are marked notified", func(t *testing.T, s conformanceStore) {
+		_, ok, err := s.GetWeekRecap(cctx, 2026, 3)
+		check(t, err)
+		if ok {
+			t.Fatal("recap found before any was saved")
+		}
+		_, ok, err = s.LastWeekRecap(cctx)
+		check(t, err)
+		if ok {
+			t.Fatal("last recap found before any was saved")
+		}
+
+		winner := int64(1)
+		r := game.WeekRecap{
+			Year: 2026, Week: 3, ScopeKey: "2026-W03",
+			TotalGames: 3, Wins: map[int64]int{1: 2, 3: 1}, TopIDs: []int64{1}, WinnerID: &winner,
+			Titles:   []game.RecapTitle{{TitleID: 1, Title: "Hanabi", Plays: 2}, {TitleID: 2, Title: "Azul", Plays: 1}},
+			Streaks:  []game.RecapStreak{{PlayerID: 1, Kind: game.StreakGameWins, Length: 3}},
+			ClosedAt: at(2026, 1, 17, 0, 0), CreatedAt: at(2026, 1, 17, 0, 1),
+		}
+		_, added, err := s.AddWeekRecap(cctx, r)
+		check(t, err)
+		if !added {
+			t.Fatal("first recap of 2026-W03 not added")
+		}
+		_, added, err = s.AddWeekRecap(cctx, game.WeekRecap{Year: 2026, Week: 4, ScopeKey: "2026-W04", ClosedAt: at(2026, 1, 24, 0, 0)})
+		check(t, err)
+		if !added {
+			t.Fatal("recap of 2026-W04 not added")
+		}
+
+		got, ok, err := s.GetWeekRecap(cctx, 2026, 3)
+		check(t, err)
+		if !ok || got.ScopeKey != r.ScopeKey || got.TotalGames != 3 || got.Wins[1] != 2 || got.Wins[3] != 1 ||
+			got.WinnerID == nil || *got.WinnerID != 1 || !slices.Equal(got.TopIDs, r.TopIDs) ||
+			!slices.Equal(got.Titles, r.Titles) || !slices.Equal(got.Streaks, r.Streaks) ||
+			!got.ClosedAt.Equal(r.ClosedAt) || !got.CreatedAt.Equal(r.CreatedAt) || got.NotifiedAt != nil {
+			t.Errorf("recap = %+v, want %+v", got, r)
+		}
+
+		// Adding the week again keeps and returns the first recap.
+		second := r
+		second.WinnerID, second.TopIDs, second.TieUnresolved = nil, []int64{1, 3}, true
+		got, added, err = s.AddWeekRecap(cctx, second)
+		check(t, err)
+		if added || got.WinnerID == nil || got.TieUnresolved || len(got.TopIDs) != 1 {
+			t.Errorf("second AddWeekRecap = %+v, %v; want the first recap", got, added)
+		}
+
+		notified := at(2026, 1, 24, 0, 5)
+		check(t, s.MarkWeekRecapNotified(cctx, 2026, 4, notified))
+		wantErr(t, s.MarkWeekRecapNotified(cctx, 2026, 5, notified), "recap not found")
+		got, ok, err = s.LastWeekRecap(cctx)
+		check(t, err)
+		if !ok || got.ScopeKey != "2026-W04" || got.TotalGames != 0 || got.NotifiedAt == nil || !got.NotifiedAt.Equal(notified) {
+			t.Errorf("last recap = %+v, %v; want 2026-W04, notified", got, ok)
+		}
+		if got, _, err = s.GetWeekRecap(cctx, 2026, 3); err != nil || got.NotifiedAt != nil {
+			t.Errorf("W03 recap = %+v, %v; want it still unnotified", got, err)
+		}
+	}},
+
+	// ============================
+	// Import
+	// ============================
+
+	{"ImportDataset adds, appends and upserts in one go", func(t *testing.T, s conformanceStore) {
+		_, err := s.AddPlayer(cctx, "Alice")
+		check(t, err)
+		_, err = s.AddRuleset(cctx, game.Ruleset{Name: "Old", EffectiveFrom: at(2026, 1, 1, 0, 0), Weekly: game.DefaultRuleset().Weekly, Yearly: game.DefaultRuleset().Yearly})
+		check(t, err)
+		rules := game.DatasetRules{Metric: game.MetricWins, Qualifier: game.QualifyAll, TiePolicy: game.TieTiebreaker}
+		score := 10
+
+		d := game.Dataset{
+			Players: []game.DatasetPlayer{{Name: "Alice", IsActive: true}, {Name: "Bob", IsActive: false}, {Name: "Cleo", IsActive: true}},
+			Titles:  []game.DatasetTitle{{Name: "Hanabi", IsActive: true}},
+			Games: []game.DatasetGame{
+				{
+					PlayedAt: at(2026, 1, 5, 12, 0), Title: "Hanabi", Participants: []string{"Alice", "Bob"}, Winners: []string{"Bob"},
+					Results: []game.DatasetResult{{Player: "Bob", Position: 1, Score: &score}, {Player: "Alice", Position: 2}}, IsActive: true,
+				},
+				{
+					PlayedAt: at(2026, 1, 6, 12, 0), Title: "Hanabi", Mode: game.ModeTeam, Participants: []string{"Alice", "Bob", "Cleo"},
+					Winners: []string{"Alice", "Cleo"}, Teams: [][]string{{"Alice", "Cleo"}, {"Bob"}}, Notes: "teams", IsActive: false,
+				},
+			},
+			Tiebreakers: []game.DatasetTiebreaker{{
+				Scope: "weekly", ScopeKey: "2026-W02", Tied: []string{"Alice", "Bob"}, Winner: "Bob", Method: game.MethodPlayoff,
+				DecidedAt: at(2026, 1, 9, 17, 0), PlayoffGame: &game.DatasetGameRef{PlayedAt: at(2026, 1, 6, 12, 0), Title: "Hanabi"},
+			}},
+			Rulesets: []game.DatasetRuleset{
+				{Name: "Replaced", EffectiveFrom: "2026-01-01", Weekly: rules, Yearly: rules},
+				{Name: "Spring", EffectiveFrom: "2026-03-01", Weekly: rules, Yearly: rules},
+			},
+			Seasons: []game.DatasetSeason{{Name: "Winter", StartDate: "2026-01-01", EndDate: "2026-02-28"}},
+		}
+		sum, err := s.ImportDataset(cctx, d)
+		check(t, err)
+		if want := (game.ImportSummary{PlayersAdded: 2, TitlesAdded: 1, GamesAdded: 2, TiebreakersSet: 1, RulesetsSet: 2, SeasonsSet: 1}); sum != want {
+			t.Errorf("summary = %+v, want %+v", sum, want)
+		}
+
+		ids := map[string]int64{}
+		players, err := s.ListPlayers(cctx)
+		check(t, err)
+		for _, p := range players {
+			ids[p.Name] = p.ID
+			if p.Name == "Bob" && p.IsActive {
+				t.Error("Bob imported active")
+			}
+		}
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if len(games) != 2 {
+			t.Fatalf("games = %+v, want 2", games)
+		}
+		titleID := games[0].TitleID
+		sameGame(t, games[0], game.Game{
+			ID: games[0].ID, PlayedAt: at(2026, 1, 5, 12, 0), TitleID: titleID, Title: "Hanabi", Mode: game.ModeCompetitive,
+			ParticipantIDs: []int64{ids["Alice"], ids["Bob"]}, WinnerIDs: []int64{ids["Bob"]},
+			Results:  []game.Result{{PlayerID: ids["Bob"], Position: 1, Score: &score}, {PlayerID: ids["Alice"], Position: 2}},
+			IsActive: true,
+		})
+		sameGame(t, games[1], game.Game{
+			ID: games[1].ID, PlayedAt: at(2026, 1, 6, 12, 0), TitleID: titleID, Title: "Hanabi", Mode: game.ModeTeam,
+			ParticipantIDs: []int64{ids["Alice"], ids["Bob"], ids["Cleo"]}, WinnerIDs: []int64{ids["Alice"], ids["Cleo"]},
+			Teams: [][]int64{{ids["Alice"], ids["Cleo"]}, {ids["Bob"]}}, Notes: "teams",
+		})
+
+		tb, ok, err := s.GetTiebreaker(cctx, "weekly", "2026-W02")
+		check(t, err)
+		if !ok || tb.WinnerID != ids["Bob"] || tb.GameID != games[1].ID || !slices.Equal(tb.TiedPlayerIDs, []int64{ids["Alice"], ids["Bob"]}) {
+			t.Errorf("tiebreaker = %+v, want Bob by play-off game %d", tb, games[1].ID)
+		}
+		rulesets, err := s.ListRulesets(cctx)
+		check(t, err)
+		var names []string
+		for _, r := range rulesets {
+			names = append(names, r.Name)
+		}
+		if !slices.Equal(names, []string{"Replaced", "Spring"}) {
+			t.Errorf("rulesets = %v, want the 2026-01-01 one replaced", names)
+		}
+		seasons, err := s.ListSeasons(cctx)
+		check(t, err)
+		if len(seasons) != 1 || !seasons[0].EndDate.Equal(at(2026, 2, 28, 0, 0)) {
+			t.Errorf("seasons = %+v", seasons)
+		}
+
+		// Importing again upserts the season by name.
+		d2 := game.Dataset{Seasons: []game.DatasetSeason{{Name: "Winter", StartDate: "2026-01-01", EndDate: "2026-03-15"}}}
+		_, err = s.ImportDataset(cctx, d2)
+		check(t, err)
+		seasons, err = s.ListSeasons(cctx)
+		check(t, err)
+		if len(seasons) != 1 || !seasons[0].EndDate.Equal(at(2026, 3, 15, 0, 0)) {
+			t.Errorf("seasons = %+v, want Winter extended", seasons)
+		}
+	}},
+
+	{"ImportDataset changes nothing when a name is unknown", func(t *testing.T, s conformanceStore) {
+		before, err := s.ListPlayers(cctx)
+		check(t, err)
+		_, err = s.ImportDataset(cctx, game.Dataset{
+			Players: []game.DatasetPlayer{{Name: "Alice", IsActive: true}},
+			Titles:  []game.DatasetTitle{{Name: "Hanabi", IsActive: true}},
+			Games:   []game.DatasetGame{{PlayedAt: at(2026, 1, 5, 12, 0), Title: "Hanabi", Participants: []string{"Alice", "Zed"}, IsActive: true}},
+			Seasons: []game.DatasetSeason{{Name: "Winter", StartDate: "2026-01-01", EndDate: "2026-02-28"}},
+		})
+		wantErr(t, err, "")
+
+		after, err := s.ListPlayers(cctx)
+		check(t, err)
+		if len(after) != len(before) {
+			t.Errorf("players = %d, want %d", len(after), len(before))
+		}
+		games, err := s.ListGames(cctx)
+		check(t, err)
+		if len(games) != 0 {
+			t.Errorf("games = %+v, want none", games)
+		}
+		seasons, err := s.ListSeasons(cctx)
+		check(t, err)
+		if len(seasons) != 0 {
+			t.Errorf("seasons = %+v, want none", seasons)
+		}
+	}},
+}
+
+// sameJSON compares two JSON snapshots by value; Postgres doesn't keep the original text.
+func sameJSON(a, b []byte) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
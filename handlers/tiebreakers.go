@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// tiebreakChoice is how a tie is being settled: a server draw, a logged play-off game, or
+// else a hand-picked winner.
+type tiebreakChoice struct {
+	WinnerID int64
+	Draw     bool
+	GameID   int64
+}
+
+// parseTiebreakChoice reads a tiebreak form: winner_id, draw or game_id.
+func parseTiebreakChoice(r *http.Request) tiebreakChoice {
+	c := tiebreakChoice{Draw: r.FormValue("draw") != ""}
+	c.WinnerID, _ = strconv.ParseInt(r.FormValue("winner_id"), 10, 64)
+	c.GameID, _ = strconv.ParseInt(r.FormValue("game_id"), 10, 64)
+	return c
+}
+
+// decideTiebreaker builds the tiebreaker settling a tie among tied as c says.
+// Error messages are user-facing.
+func (s *Server) decideTiebreaker(ctx context.Context, scope, scopeKey string, tied []int64, c tiebreakChoice) (game.Tiebreaker, error) {
+	switch {
+	case c.Draw:
+		tb, err := game.DrawTiebreaker(scope, scopeKey, tied)
+		if err != nil {
+			return game.Tiebreaker{}, errors.New("Unable to run the draw.")
+		}
+		tb.DecidedAt = time.Now()
+		return tb, nil
+
+	case c.GameID != 0:
+		games, err := s.store.ListGames(ctx)
+		if err != nil {
+			return game.Tiebreaker{}, errors.New("Unable to load the play-off game.")
+		}
+		for _, g := range games {
+			if g.ID != c.GameID {
+				continue
+			}
+			winnerID, err := playoffWinner(g, tied)
+			if err != nil {
+				return game.Tiebreaker{}, err
+			}
+			return game.Tiebreaker{
+				Scope:         scope,
+				ScopeKey:      scopeKey,
+				TiedPlayerIDs: tied,
+				WinnerID:      winnerID,
+				Method:        game.MethodPlayoff,
+				DecidedAt:     time.Now(),
+				GameID:        g.ID,
+			}, nil
+		}
+		return game.Tiebreaker{}, errors.New("Please select a logged play-off game.")
+	}
+
+	if !containsInt64(tied, c.WinnerID) {
+		return game.Tiebreaker{}, errors.New("Please select a valid winner from the tied leaders.")
+	}
+	return game.Tiebreaker{
+		Scope:         scope,
+		ScopeKey:      scopeKey,
+		TiedPlayerIDs: tied,
+		WinnerID:      c.WinnerID,
+		Method:        game.MethodChance,
+		DecidedAt:     time.Now(),
+	}, nil
+}
+
+// playoffWinner returns the tied player who won g, if g can settle the tie: it must be
+// active, every tied player must have played, and exactly one of them won.
+func playoffWinner(g game.Game, tied []int64) (int64, error) {
+	if !g.IsActive {
+		return 0, errors.New("The play-off game has been deactivated.")
+	}
+	if !isSubset(tied, g.ParticipantIDs) {
+		return 0, errors.New("Every tied player must have played in the play-off game.")
+	}
+	var winners []int64
+	for _, pid := range tied {
+		if containsInt64(g.WinnerIDs, pid) {
+			winners = append(winners, pid)
+		}
+	}
+	if len(winners) != 1 {
+		return 0, errors.New("Exactly one of the tied players must have won the play-off game.")
+	}
+	return winners[0], nil
+}
+
+// playoffGames lists the games played from start on that could settle st's unresolved
+// tie, latest first, for the play-off picker.
+func (s *Server) playoffGames(ctx context.Context, st game.Standings, start time.Time, pMap map[int64]game.Player) []playoffGameVM {
+	if !st.TieUnresolved {
+		return nil
+	}
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		return nil
+	}
+	var out []playoffGameVM
+	for _, g := range games {
+		if g.PlayedAt.Before(start) {
+			continue
+		}
+		winnerID, err := playoffWinner(g, st.TopIDs)
+		if err != nil {
+			continue
+		}
+		out = append(out, playoffGameVM{
+			ID:       g.ID,
+			PlayedAt: g.PlayedAt,
+			Label:    g.PlayedAt.In(s.loc).Format("Mon Jan 2, 3:04 PM") + " · " + g.Title + " · won by " + pMap[winnerID].Name,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PlayedAt.After(out[j].PlayedAt) })
+	return out
+}
+
+// tieNote says how a tie for the lead was settled, for the standings pages.
+func tieNote(st game.Standings) string {
+	if st.WinnerID == nil {
+		return ""
+	}
+	switch st.DecidedBy {
+	case game.TieStepHeadToHead:
+		return "Tie broken by head-to-head wins."
+	case game.TieStepMostGames:
+		return "Tie broken by most games played."
+	case game.MethodPlayoff:
+		return "Tie settled by a play-off game."
+	case game.MethodChance:
+		return "Tie settled by a game of chance."
+	}
+	return ""
+}
+
+// staleNote explains why st's stored tiebreaker no longer decides it, or is "" if it does.
+func staleNote(st game.Standings, pMap map[int64]game.Player) string {
+	tb := st.StaleTiebreaker
+	if tb == nil {
+		return ""
+	}
+	return fmt.Sprintf("A tiebreaker picked %s from %s, but the tied players have changed since. Please decide the tie again.",
+		pMap[tb.WinnerID].Name, joinNames(tb.TiedPlayerIDs, pMap))
+}
+
+// joinNames lists players as "A, B and C".
+func joinNames(ids []int64, pMap map[int64]game.Player) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, pMap[id].Name)
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// historyPath is the page listing every tiebreaker decision for a period.
+func historyPath(scope, scopeKey string) string {
+	return "/tiebreakers/" + url.PathEscape(scope) + "/" + url.PathEscape(scopeKey)
+}
+
+// historyURL returns the tiebreaker history page for st's period, or "" if no tiebreaker
+// was ever recorded for it.
+func (s *Server) historyURL(ctx context.Context, st game.Standings) string {
+	history, err := s.store.ListTiebreakerHistory(ctx, st.Scope, st.ScopeKey)
+	if err != nil || len(history) == 0 {
+		return ""
+	}
+	return historyPath(st.Scope, st.ScopeKey)
+}
+
+// methodLabel describes how a tiebreaker was decided.
+func methodLabel(tb game.Tiebreaker) string {
+	switch {
+	case tb.Method == game.MethodPlayoff:
+		return "Play-off game"
+	case tb.Drawn():
+		return "Drawn by the server"
+	}
+	return "Game of chance, picked by hand"
+}
+
+// handleTiebreakerHistory lists every tiebreaker decision for a period, latest first.
+func (s *Server) handleTiebreakerHistory(w http.ResponseWriter, r *http.Request) {
+	scope := r.PathValue("scope")
+	key := r.PathValue("key")
+
+	history, err := s.store.ListTiebreakerHistory(r.Context(), scope, key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(history) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	period, periodURL := s.tiebreakerPeriod(r.Context(), scope, key)
+	vm := TiebreakerHistoryVM{
+		Title:     "Tiebreaker history",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Period:    period,
+		PeriodURL: periodURL,
+	}
+	for i, tb := range history {
+		d := tiebreakerDecisionVM{
+			Winner:    pMap[tb.WinnerID].Name,
+			Method:    methodLabel(tb),
+			DecidedAt: tb.DecidedAt.In(s.loc).Format("Jan 2, 2006 3:04 PM"),
+			Seed:      tb.Seed,
+			Current:   i == 0,
+		}
+		for _, id := range tb.TiedPlayerIDs {
+			d.Tied = append(d.Tied, pMap[id].Name)
+		}
+		if d.Current && tb.Drawn() {
+			d.VerifyURL = verifyURL(tb.Scope, tb.ScopeKey)
+		}
+		vm.Decisions = append(vm.Decisions, d)
+	}
+
+	if err := s.r.HTML(w, "tiebreaker_history", "tiebreaker_history", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// verifyURL is the verification page for a stored tiebreaker.
+func verifyURL(scope, scopeKey string) string {
+	return historyPath(scope, scopeKey) + "/verify"
+}
+
+// drawURL returns the verification page for the tiebreaker deciding st, or "" unless
+// the server drew it.
+func (s *Server) drawURL(ctx context.Context, st game.Standings) string {
+	if st.WinnerID == nil || len(st.TopIDs) <= 1 {
+		return ""
+	}
+	tb, ok, err := s.store.GetTiebreaker(ctx, st.Scope, st.ScopeKey)
+	if err != nil || !ok || !tb.Drawn() {
+		return ""
+	}
+	return verifyURL(tb.Scope, tb.ScopeKey)
+}
+
+// tiebreakerPeriod names the period a tiebreaker decides and links to its page, if it has one.
+func (s *Server) tiebreakerPeriod(ctx context.Context, scope, scopeKey string) (string, string) {
+	switch scope {
+	case "weekly":
+		var year, week int
+		if _, err := fmt.Sscanf(scopeKey, "%d-W%d", &year, &week); err == nil {
+			return fmt.Sprintf("Week %d, %d", week, year), fmt.Sprintf("/weeks/%d/%d", year, week)
+		}
+	case "yearly":
+		var year int
+		if _, err := fmt.Sscanf(scopeKey, "%d", &year); err == nil {
+			return scopeKey, fmt.Sprintf("/years/%d", year)
+		}
+	case "season":
+		seasons, err := s.store.ListSeasons(ctx)
+		if err == nil {
+			for _, se := range seasons {
+				if se.ScopeKey() == scopeKey {
+					return se.Name, fmt.Sprintf("/seasons/%d", se.ID)
+				}
+			}
+		}
+	}
+	return scopeKey, ""
+}
+
+// handleTiebreakerVerify re-runs a drawn tiebreaker and shows every step, so anyone can
+// check the recorded winner is the one the seed gives.
+func (s *Server) handleTiebreakerVerify(w http.ResponseWriter, r *http.Request) {
+	scope := r.PathValue("scope")
+	key := r.PathValue("key")
+
+	tb, ok, _ := s.store.GetTiebreaker(r.Context(), scope, key)
+	if !ok {
+		// MemoryStore reports a missing key as an error too, so not-found takes precedence.
+		http.NotFound(w, r)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	period, periodURL := s.tiebreakerPeriod(r.Context(), scope, key)
+	vm := DrawVM{
+		Title:      "Verify draw",
+		Version:    s.meta.Version,
+		BuildTime:  s.meta.BuildTime,
+		StartTime:  s.meta.StartTime,
+		YearNow:    s.now().Year(),
+		Period:     period,
+		PeriodURL:  periodURL,
+		Tiebreaker: tb,
+		PlayerMap:  pMap,
+		DecidedAt:  tb.DecidedAt.In(s.loc).Format("Jan 2, 2006 3:04 PM"),
+	}
+
+	if tb.Drawn() {
+		drawnID, err := game.VerifyDraw(tb)
+		if err != nil {
+			vm.VerifyError = err.Error()
+		} else {
+			vm.DrawnWinnerID = drawnID
+			vm.Verified = drawnID == tb.WinnerID
+			for round := 0; ; round++ {
+				dr := game.DrawRoundFor(tb.Seed, tb.Scope, tb.ScopeKey, len(tb.TiedPlayerIDs), round)
+				vm.Rounds = append(vm.Rounds, dr)
+				if dr.Accepted {
+					break
+				}
+			}
+		}
+	}
+
+	if err := s.r.HTML(w, "draw", "draw", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
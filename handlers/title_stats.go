@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func (s *Server) handleTitleStats(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	minGames := game.DefaultTitleMinGames
+	if v := r.URL.Query().Get("min"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			minGames = n
+		}
+	}
+
+	titles, err := s.store.ListTitles(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var title game.Title
+	for _, t := range titles {
+		if t.ID == id {
+			title = t
+		}
+	}
+	if title.ID == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	games, err := s.store.ListGames(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	players, err := s.store.ListPlayers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	ts := game.ComputeTitleStats(games, id, minGames, s.loc)
+
+	vm := TitleStatsVM{
+		Title:      title.Name,
+		Version:    s.meta.Version,
+		BuildTime:  s.meta.BuildTime,
+		StartTime:  s.meta.StartTime,
+		YearNow:    s.now().Year(),
+		GameTitle:  title,
+		Stats:      ts,
+		PlayerMap:  pMap,
+		Frequency:  buildTitleFrequencyVM(ts.Frequency),
+		FirstPlays: ts.FirstPlayed.In(s.loc).Format("2006-01-02"),
+		LastPlays:  ts.LastPlayed.In(s.loc).Format("2006-01-02"),
+	}
+
+	if err := s.r.HTML(w, "title_stats", "title_stats", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// buildTitleFrequencyVM lays out one SVG bar per month.
+func buildTitleFrequencyVM(freq []game.TitlePeriodCount) titleFrequencyVM {
+	const (
+		h      = 120.0
+		pad    = 20.0
+		barW   = 18.0
+		barGap = 6.0
+	)
+
+	vm := titleFrequencyVM{Height: h}
+	if len(freq) == 0 {
+		return vm
+	}
+
+	maximum := 1
+	for _, f := range freq {
+		maximum = max(maximum, f.Games)
+	}
+
+	vm.Width = pad*2 + float64(len(freq))*(barW+barGap)
+	vm.SvgView = fmt.Sprintf("0 0 %.0f %.0f", vm.Width, h)
+	plotH := h - 2*pad
+
+	for i, f := range freq {
+		bh := plotH * float64(f.Games) / float64(maximum)
+		vm.Bars = append(vm.Bars, titleFrequencyBarVM{
+			X:     pad + float64(i)*(barW+barGap),
+			Y:     h - pad - bh,
+			W:     barW,
+			H:     bh,
+			Title: fmt.Sprintf("%s: %d games", f.Period, f.Games),
+		})
+	}
+	vm.FirstLabel = freq[0].Period
+	vm.LastLabel = freq[len(freq)-1].Period
+	return vm
+}
+
+// specialistNames maps title ID to the name of that title's specialist, for titles that have one.
+func (s *Server) specialistNames(ctx context.Context, titles []game.Title) (map[int64]string, error) {
+	games, err := s.store.ListGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(players))
+	for _, p := range players {
+		names[p.ID] = p.Name
+	}
+
+	out := map[int64]string{}
+	for _, t := range titles {
+		ts := game.ComputeTitleStats(games, t.ID, game.DefaultTitleMinGames, s.loc)
+		if ts.SpecialistID != nil {
+			out[t.ID] = names[*ts.SpecialistID]
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	s.renderTokens(r.Context(), w, "tokens", TokenForm{Scope: game.ScopeRead}, "", "")
+}
+
+// handleTokensPost issues a token and shows it once; only its hash is kept.
+func (s *Server) handleTokensPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		s.renderTokens(ctx, w, "main", TokenForm{Scope: game.ScopeRead}, "Invalid form submission.", "")
+		return
+	}
+
+	form := TokenForm{Name: strings.TrimSpace(r.FormValue("name")), Scope: r.FormValue("scope")}
+	token, err := s.issueAPIToken(ctx, form)
+	if err != nil {
+		s.renderTokens(ctx, w, "main", form, err.Error(), "")
+		return
+	}
+	setToast(w, "Token issued.")
+	s.renderTokens(ctx, w, "main", TokenForm{Scope: game.ScopeRead}, "", token)
+}
+
+// issueAPIToken stores a new token for form and returns it. Error messages are user-facing.
+func (s *Server) issueAPIToken(ctx context.Context, form TokenForm) (string, error) {
+	t := game.APIToken{Name: form.Name, Scope: form.Scope}
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+	token, err := game.NewAPIToken()
+	if err != nil {
+		return "", errors.New("Unable to generate a token.")
+	}
+	t.TokenHash = game.HashAPIToken(token)
+	if me, ok := UserFrom(ctx); ok {
+		t.CreatedBy = me.Username
+	}
+	if _, err := s.store.AddAPIToken(ctx, t); err != nil {
+		return "", errors.New("Unable to save the token.")
+	}
+	return token, nil
+}
+
+func (s *Server) handleTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/tokens", http.StatusSeeOther)
+		return
+	}
+	if err := s.store.RevokeAPIToken(ctx, id); err != nil {
+		s.renderTokens(ctx, w, "main", TokenForm{Scope: game.ScopeRead}, "Unable to revoke the token.", "")
+		return
+	}
+	setToast(w, "Token revoked.")
+	s.renderTokens(ctx, w, "main", TokenForm{Scope: game.ScopeRead}, "", "")
+}
+
+// renderTokens renders the API tokens page; layout is "tokens" for a full page or "main" for
+// an HTMX swap after a change. newToken is a just-issued token to show once.
+func (s *Server) renderTokens(ctx context.Context, w http.ResponseWriter, layout string, form TokenForm, formErr, newToken string) {
+	tokens, err := s.store.ListAPITokens(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	vm := TokensVM{
+		Title:     "API tokens",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Scopes:    game.APITokenScopes,
+		Form:      form,
+		FormError: formErr,
+		NewToken:  newToken,
+	}
+	const layoutTime = "Jan 2, 2006 3:04 PM"
+	for _, t := range tokens {
+		row := tokenRowVM{
+			ID:        t.ID,
+			Name:      t.Name,
+			Scope:     t.Scope,
+			CreatedBy: t.CreatedBy,
+			Created:   t.CreatedAt.In(s.loc).Format(layoutTime),
+			LastUsed:  "never",
+			Active:    t.Active(),
+		}
+		if t.LastUsedAt != nil {
+			row.LastUsed = t.LastUsedAt.In(s.loc).Format(layoutTime)
+		}
+		if t.RevokedAt != nil {
+			row.Revoked = t.RevokedAt.In(s.loc).Format(layoutTime)
+		}
+		vm.Tokens = append(vm.Tokens, row)
+	}
+
+	if err := s.r.HTML(w, layout, "tokens", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
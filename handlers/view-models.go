@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"html/template"
+	"time"
 
 	"github.com/eithansmith/master-of-games/game"
 )
@@ -11,7 +12,16 @@ type HomeForm struct {
 	PlayedAt     string
 	Participants map[int64]bool
 	Winners      map[int64]bool
+	Positions    map[int64]string // as typed, by player
+	Scores       map[int64]string
+	HasResults   bool // any placement or score entered; opens the results section
+	Mode         string
+	TeamOf       map[int64]string // team number as typed, by player
+	CoopResult   string           // "won" | "lost"
 	Notes        string
+	// InactiveTitle names an edited game's own title once it has been deactivated; it is
+	// still offered so the game can be saved unchanged.
+	InactiveTitle string
 }
 
 type HomeVM struct {
@@ -29,6 +39,11 @@ type HomeVM struct {
 
 	FormError string
 	Form      HomeForm
+
+	// EditForms holds a pre-filled edit form per listed game (by game ID).
+	EditForms  map[int64]HomeForm
+	EditGameID int64 // game whose edit form is reopened after a failed update
+	EditError  string
 }
 
 type WeekVM struct {
@@ -63,9 +78,54 @@ type WeekVM struct {
 	WinnerID      *int64
 	TieUnresolved bool
 
+	RulesName    string // ruleset in force for the week
+	RulesSummary string
+	Metric       string
+	TieNote      string // how a tie for the lead was settled, if there was one
+	DrawURL      string // verification page when the server drew the winner
+	StaleNote    string // why the stored tiebreaker no longer applies, if it doesn't
+	HistoryURL   string // every tiebreaker decision for the period, if there were any
+	PlayoffGames []playoffGameVM
+
+	LiveTopic string // the week's topic on /events; the page reloads when it changes
+	RecapURL  string // the week's recap, once it has been written
+
 	FormError string
 }
 
+type WeekRecapVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Year     int
+	Week     int
+	ScopeKey string
+
+	Found    bool   // a recap has been written for the week
+	Closed   bool   // the week is over; a recap is written when it closes
+	ClosesAt string // when the week closes, in league time
+
+	Headline      string
+	Current       string // the week's result now, if it has changed since the recap was written
+	Written       string
+	TotalGames    int
+	HasWinner     bool
+	TieUnresolved bool
+	Wins          []recapWinVM // most wins first
+	Titles        []game.RecapTitle
+	Streaks       []string
+}
+
+type recapWinVM struct {
+	PlayerID int64
+	Name     string
+	Wins     int
+	Leader   bool
+}
+
 type YearVM struct {
 	Title     string
 	Version   string
@@ -73,7 +133,9 @@ type YearVM struct {
 	StartTime string
 	YearNow   int
 
-	Year int
+	Year     int
+	PrevYear int
+	NextYear int
 
 	Players   []game.Player
 	PlayerMap map[int64]game.Player
@@ -85,6 +147,16 @@ type YearVM struct {
 	WinnerID      *int64
 	TieUnresolved bool
 
+	RulesName    string // ruleset in force for the year
+	RulesSummary string
+	TieNote      string // how a tie for the lead was settled, if there was one
+	DrawURL      string // verification page when the server drew the winner
+	StaleNote    string // why the stored tiebreaker no longer applies, if it doesn't
+	HistoryURL   string // every tiebreaker decision for the period, if there were any
+	PlayoffGames []playoffGameVM
+
+	LiveTopic string // the year's topic on /events; the page reloads when it changes
+
 	FormError string
 }
 
@@ -95,7 +167,8 @@ type YearRaceVM struct {
 	StartTime string
 	YearNow   int
 
-	Year int
+	Year      int
+	LiveTopic string // the year's topic on /events; the chart reloads when it changes
 }
 
 type yearRaceChartVM struct {
@@ -156,4 +229,502 @@ type TitlesVM struct {
 
 	Titles    []game.Title
 	FormError string
+
+	Specialists map[int64]string // title ID -> specialist's name
+}
+
+type TitleStatsVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	GameTitle game.Title
+	Stats     game.TitleStats
+	PlayerMap map[int64]game.Player
+
+	FirstPlays string
+	LastPlays  string
+	Frequency  titleFrequencyVM
+}
+
+type titleFrequencyVM struct {
+	SvgView string
+	Width   float64
+	Height  float64
+
+	Bars       []titleFrequencyBarVM
+	FirstLabel string
+	LastLabel  string
+}
+
+type titleFrequencyBarVM struct {
+	X     float64
+	Y     float64
+	W     float64
+	H     float64
+	Title string // tooltip
+}
+
+type RatingsVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Rows []ratingRowVM
+}
+
+type ratingRowVM struct {
+	Rank   int
+	Name   string
+	Rating int
+	Peak   int
+	Games  int
+	Wins   int
+
+	Spark   string // SVG path "d" of the rating history
+	History []ratingHistoryVM
+}
+
+type ratingHistoryVM struct {
+	Date   string
+	Title  string
+	Won    bool
+	Delta  string // signed, e.g. "+9.8"
+	Rating int
+}
+
+type YearH2HVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Year    int
+	Titles  []game.Title // titles played this year, for the filter
+	TitleID int64        // 0 = all titles
+
+	Players []string // column headers, same order as Rows
+	Rows    []h2hRowVM
+}
+
+type h2hRowVM struct {
+	Name  string
+	Cells []h2hCellVM
+}
+
+type h2hCellVM struct {
+	Self   bool
+	Games  int
+	Wins   int // row player's wins with the column player at the table
+	Losses int // column player's wins with the row player at the table
+	Tip    string
+}
+
+type DataVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Tables    []string // CSV tables, in import order
+	FormError string
+	RowErrors []game.RowError
+	Summary   *game.ImportSummary // set after a successful import
+}
+
+type PlayerProfileVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Player     game.Player
+	Profile    game.PlayerProfile
+	PlayerMap  map[int64]game.Player
+	TitleNames map[int64]string
+	Recent     []profileGameVM
+}
+
+type profileGameVM struct {
+	Date         string
+	Title        string
+	Won          bool
+	Participants int
+}
+
+type StandingsVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	From  string // YYYY-MM-DD, inclusive; empty for open-ended
+	To    string
+	Label string // "All-time" or the range
+	Years []int  // league years, for quick links
+
+	PlayerMap map[int64]game.Player
+	Standings game.Standings
+
+	RulesName    string // ruleset in force at the end of the range
+	RulesSummary string
+
+	FormError string
+}
+
+type ChampionsVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Years []championYearVM
+}
+
+type championYearVM struct {
+	Year   int
+	Yearly *championVM
+	Weeks  []championVM
+}
+
+type championVM struct {
+	Year       int
+	Week       int
+	Games      int
+	Winner     string
+	Tied       []string // unresolved tie
+	Stale      bool     // a stored tiebreaker was decided among other players
+	InProgress bool
+}
+
+type RulesVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Rulesets []rulesetVM // the default first, then by start date
+	Form     RulesetForm
+
+	FormError string
+}
+
+type rulesetVM struct {
+	ID      int64 // 0 for the default
+	Name    string
+	From    string // YYYY-MM-DD; empty for the default
+	Weekly  string // describeRules summaries
+	Yearly  string
+	Current bool // in force today
+}
+
+// RulesetForm holds the add-ruleset form as entered.
+type RulesetForm struct {
+	Name          string
+	EffectiveFrom string
+	Weekly        PeriodRulesForm
+	Yearly        PeriodRulesForm
+}
+
+type PeriodRulesForm struct {
+	Metric        string
+	Qualifier     string
+	MinAttendance string
+	TiePolicy     string
+}
+
+type SeasonsVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Seasons []seasonRowVM // latest first
+	Form    SeasonForm
+
+	FormError string
+}
+
+type seasonRowVM struct {
+	ID      int64
+	Name    string
+	Dates   string // "Jun 1, 2026 – Aug 31, 2026"
+	Current bool   // today falls in the season
+}
+
+// SeasonForm holds the add-season form as entered.
+type SeasonForm struct {
+	Name      string
+	StartDate string // YYYY-MM-DD
+	EndDate   string
+}
+
+type SeasonVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Season    game.Season
+	Dates     string
+	PlayerMap map[int64]game.Player
+	Standings game.Standings
+
+	RulesName    string // yearly rules in force on the season's first day
+	RulesSummary string
+	TieNote      string // how a tie for the lead was settled, if there was one
+	DrawURL      string // verification page when the server drew the winner
+	StaleNote    string // why the stored tiebreaker no longer applies, if it doesn't
+	HistoryURL   string // every tiebreaker decision for the period, if there were any
+	PlayoffGames []playoffGameVM
+
+	LiveTopic string // the season's topic on /events; the page reloads when it changes
+
+	FormError string
+}
+
+// playoffGameVM is a logged game that could settle the tie, for the play-off picker.
+type playoffGameVM struct {
+	ID       int64
+	PlayedAt time.Time
+	Label    string
+}
+
+type DrawVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Period    string // "Week 7, 2026", "2026" or a season name
+	PeriodURL string
+
+	Tiebreaker game.Tiebreaker
+	PlayerMap  map[int64]game.Player
+	DecidedAt  string
+
+	Rounds        []game.DrawRound // rounds of the re-run draw, the last one accepted
+	DrawnWinnerID int64
+	Verified      bool
+	VerifyError   string
+}
+
+type TiebreakerHistoryVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Period    string // "Week 7, 2026", "2026" or a season name
+	PeriodURL string
+
+	Decisions []tiebreakerDecisionVM // latest first
+}
+
+type tiebreakerDecisionVM struct {
+	Winner    string
+	Tied      []string // in the stored (draw) order
+	Method    string   // how it was decided, for display
+	DecidedAt string
+	Seed      string // set for server draws
+	Current   bool   // the decision stored now; the rest were replaced
+	VerifyURL string // current server draws only
+}
+
+type AuditVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Filter   AuditFilterForm
+	Actions  []string // filter choices
+	Entities []string
+
+	Entries   []auditEntryVM // latest first
+	Limit     int
+	Truncated bool // there may be older matching entries than shown
+
+	FormError string
+}
+
+type AuditFilterForm struct {
+	Actor    string
+	Action   string
+	Entity   string
+	EntityID string
+	From     string // YYYY-MM-DD
+	To       string
+}
+
+type auditEntryVM struct {
+	At       string
+	Actor    string
+	Action   string
+	Entity   string
+	EntityID string
+
+	EntityURL  string // the entity's page, unless it was deleted
+	HistoryURL string // the audit log filtered to this entity
+
+	Changes []auditChangeVM
+}
+
+type auditChangeVM struct {
+	Field  string
+	Before string // "" when the entity didn't exist
+	After  string // "" when it was deleted
+}
+
+type LoginVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Username  string // as entered, after a failed attempt
+	Next      string // where to go after signing in
+	FormError string
+}
+
+type AccountVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Username  string
+	Role      string
+	Player    *game.Player // the linked player, if any
+	MinLength int          // shortest allowed password
+	FormError string
+}
+
+type UsersVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Users     []userRowVM
+	Roles     []string
+	Players   []game.Player // choices for the player link
+	MinLength int
+
+	Form      UserForm // the add-user form
+	FormError string
+}
+
+type userRowVM struct {
+	game.User
+	PlayerID int64 // 0 when not linked
+	IsMe     bool  // the signed-in admin, who can't demote, deactivate or delete themselves
+}
+
+// UserForm is the add-user form as entered.
+type UserForm struct {
+	Username string
+	Role     string
+	PlayerID string
+}
+
+type TokensVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Tokens []tokenRowVM // newest first
+	Scopes []string
+
+	Form      TokenForm
+	FormError string
+	NewToken  string // a just-issued token, shown only this once
+}
+
+type tokenRowVM struct {
+	ID        int64
+	Name      string
+	Scope     string
+	CreatedBy string
+	Created   string
+	LastUsed  string // "never" until first used
+	Revoked   string // "" while active
+	Active    bool
+}
+
+// TokenForm is the issue-token form as entered.
+type TokenForm struct {
+	Name  string
+	Scope string
+}
+
+type WebhooksVM struct {
+	Title     string
+	Version   string
+	BuildTime string
+	StartTime string
+	YearNow   int
+
+	Webhooks   []webhookRowVM  // by name
+	Deliveries []deliveryRowVM // latest first
+	Events     []webhookEventVM
+
+	Form      WebhookForm
+	FormError string
+	NewSecret string // a just-added webhook's signing secret, shown only this once
+}
+
+type webhookRowVM struct {
+	ID        int64
+	Name      string
+	URL       string
+	Events    []string
+	CreatedBy string
+	Created   string
+	Active    bool
+}
+
+type webhookEventVM struct {
+	Name    string
+	Checked bool
+}
+
+type deliveryRowVM struct {
+	ID          int64
+	Webhook     string
+	Event       string
+	Status      string
+	Attempts    int
+	LastStatus  int // 0 if the last attempt got no response
+	LastError   string
+	Created     string
+	NextAttempt string // "" unless a retry is pending
+	Delivered   string // "" until delivered
+}
+
+// WebhookForm is the add-webhook form as entered.
+type WebhookForm struct {
+	Name   string
+	URL    string
+	Events []string
 }
@@ -0,0 +1,648 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// Outgoing webhooks: a WebhookWorker listens on the EventBus, turns league events into
+// webhook events (game.WebhookEvents), queues a delivery for every webhook that wants one
+// and POSTs it, retrying failures with game.WebhookBackoff. It also announces each week and
+// year as it closes, and as a RecapNotifier it sends each weekly recap.
+
+// webhookPayload is the JSON body of a delivery. Text is a one-line summary for chat
+// (Slack-style incoming webhooks post it as is); the rest is the API shape of what changed.
+type webhookPayload struct {
+	Event string            `json:"event"`
+	Text  string            `json:"text"`
+	At    time.Time         `json:"at"`
+	Game  *apiGame          `json:"game,omitempty"`
+	Week  *apiWeekStandings `json:"week,omitempty"`
+	Year  *apiYearStandings `json:"year,omitempty"`
+	Recap *apiWeekRecap     `json:"recap,omitempty"`
+}
+
+// key is what p announces, for game.WebhookDelivery.Key: a week's winner, a period's tie
+// between the same players or a week's recap goes to each webhook once. Logged games have
+// no key; each is only ever published once.
+func (p webhookPayload) key() string {
+	switch {
+	case p.Event == game.WebhookWeekWinnerDecided && p.Week != nil && p.Week.WinnerID != nil:
+		return fmt.Sprintf("%s:%s:%d", p.Event, p.Week.ScopeKey, *p.Week.WinnerID)
+	case p.Event == game.WebhookTieNeedsBreaking && p.Week != nil:
+		return fmt.Sprintf("%s:%s:%v", p.Event, p.Week.ScopeKey, p.Week.TopIDs)
+	case p.Event == game.WebhookTieNeedsBreaking && p.Year != nil:
+		return fmt.Sprintf("%s:%s:%v", p.Event, p.Year.ScopeKey, p.Year.TopIDs)
+	case p.Event == game.WebhookWeekRecap && p.Recap != nil:
+		return p.Event + ":" + p.Recap.ScopeKey
+	}
+	return ""
+}
+
+// webhookPayloads works out the webhook events e amounts to:
+//   - an added game is game.logged;
+//   - a weekly tiebreaker that leaves the week with a winner is week.winner_decided;
+//   - any other change to a week or year that is over and still tied is tie.needs_breaking.
+//
+// An import announces nothing: it restores history rather than reporting new results.
+// Weeks and years are also announced when they close; see closedPayloads.
+func (s *Server) webhookPayloads(ctx context.Context, e Event) ([]webhookPayload, error) {
+	if e.Type == EventImported {
+		return nil, nil
+	}
+	players, err := s.store.ListPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pMap := make(map[int64]game.Player, len(players))
+	for _, p := range players {
+		pMap[p.ID] = p
+	}
+
+	var out []webhookPayload
+	if e.Type == EventGameAdded {
+		if g, ok := s.findGame(ctx, e.GameID); ok {
+			ag := toAPIGame(g)
+			out = append(out, webhookPayload{Event: game.WebhookGameLogged, Text: gameLoggedText(g, pMap), Game: &ag})
+		}
+	}
+
+	now := s.now()
+	for _, topic := range e.Topics {
+		var year, week int
+		if n, _ := fmt.Sscanf(topic, "weekly:%d-W%d", &year, &week); n == 2 {
+			ws, err := s.apiWeek(ctx, year, week)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case e.Type == EventTiebreakerSet && ws.WinnerID != nil:
+				out = append(out, weekWinnerPayload(ws, pMap))
+			case e.Type != EventTiebreakerSet && ws.TieUnresolved && !now.Before(game.WeekClose(year, week, s.loc)):
+				out = append(out, weekTiePayload(ws, pMap))
+			}
+			continue
+		}
+		if n, _ := fmt.Sscanf(topic, "yearly:%d", &year); n == 1 && e.Type != EventTiebreakerSet {
+			if _, end := game.YearBounds(year, s.loc); now.Before(end) {
+				continue
+			}
+			ys, err := s.apiYear(ctx, year)
+			if err != nil {
+				return nil, err
+			}
+			if ys.TieUnresolved {
+				out = append(out, yearTiePayload(ys, pMap))
+			}
+		}
+	}
+
+	for i := range out {
+		out[i].At = e.At
+	}
+	return out, nil
+}
+
+// closedPayloads works out what closing the last week and year to have closed by now
+// announces: week.winner_decided for a week with a winner, and tie.needs_breaking for a
+// week or year left tied.
+func (s *Server) closedPayloads(ctx context.Context, now time.Time) ([]webhookPayload, error) {
+	pMap, err := s.playerMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []webhookPayload
+	year, week := game.LastClosedWeek(now, s.loc)
+	ws, err := s.apiWeek(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ws.WinnerID != nil:
+		out = append(out, weekWinnerPayload(ws, pMap))
+	case ws.TieUnresolved:
+		out = append(out, weekTiePayload(ws, pMap))
+	}
+
+	ys, err := s.apiYear(ctx, now.In(s.loc).Year()-1)
+	if err != nil {
+		return nil, err
+	}
+	if ys.TieUnresolved {
+		out = append(out, yearTiePayload(ys, pMap))
+	}
+
+	for i := range out {
+		out[i].At = now
+	}
+	return out, nil
+}
+
+// weekWinnerPayload is week.winner_decided for ws, a week with a winner.
+func weekWinnerPayload(ws apiWeekStandings, pMap map[int64]game.Player) webhookPayload {
+	text := fmt.Sprintf("%s wins week %s.", pMap[*ws.WinnerID].Name, ws.ScopeKey)
+	if note := tieNote(game.Standings{WinnerID: ws.WinnerID, DecidedBy: ws.DecidedBy}); note != "" {
+		text += " " + note
+	}
+	return webhookPayload{Event: game.WebhookWeekWinnerDecided, Text: text, Week: &ws}
+}
+
+// yearTiePayload is tie.needs_breaking for ys, a finished year left tied.
+func yearTiePayload(ys apiYearStandings, pMap map[int64]game.Player) webhookPayload {
+	text := fmt.Sprintf("%s is tied between %s and needs a tiebreaker.", ys.ScopeKey, joinNames(ys.TopIDs, pMap))
+	return webhookPayload{Event: game.WebhookTieNeedsBreaking, Text: text, Year: &ys}
+}
+
+// weekTiePayload is tie.needs_breaking for ws, a closed week left tied.
+func weekTiePayload(ws apiWeekStandings, pMap map[int64]game.Player) webhookPayload {
+	text := fmt.Sprintf("Week %s is tied between %s and needs a tiebreaker.", ws.ScopeKey, joinNames(ws.TopIDs, pMap))
+	return webhookPayload{Event: game.WebhookTieNeedsBreaking, Text: text, Week: &ws}
+}
+
+// gameLoggedText summarizes a logged game, e.g. "Game logged: Hanabi, won by Alice (Alice,
+// Bob and Cleo played)."
+func gameLoggedText(g game.Game, pMap map[int64]game.Player) string {
+	winners := "won by nobody"
+	if len(g.WinnerIDs) > 0 {
+		winners = "won by " + joinNames(g.WinnerIDs, pMap)
+	}
+	return fmt.Sprintf("Game logged: %s, %s (%s played).", g.Title, winners, joinNames(g.ParticipantIDs, pMap))
+}
+
+// ============================
+// Worker
+// ============================
+
+const (
+	webhookPoll       = 15 * time.Second // how often the worker looks for retries that are due
+	webhookBatch      = 50               // deliveries sent per pass
+	webhookTimeout    = 10 * time.Second // per attempt, including reading the response
+	webhookCloseRetry = 5 * time.Minute  // how soon a failed close check is tried again
+
+	// webhookLease is how long a claimed delivery is kept from other workers: longer than
+	// a whole batch can take, so it is only sent again if its worker died mid-batch.
+	webhookLease = webhookBatch * webhookTimeout
+)
+
+// WebhookWorker queues and sends webhook deliveries. Start one per process with Run; any
+// number of processes can share a store.
+type WebhookWorker struct {
+	s       *Server
+	events  <-chan Event
+	stop    func()
+	client  *http.Client
+	poll    time.Duration
+	backoff func(attempt int) time.Duration
+	wake    chan struct{} // tells the sender new deliveries are queued
+}
+
+// NewWebhookWorker subscribes to every one of s's events straight away, so nothing
+// published after it returns is missed.
+func (s *Server) NewWebhookWorker() *WebhookWorker {
+	events, stop := s.events.SubscribeAll()
+	return &WebhookWorker{
+		s:       s,
+		events:  events,
+		stop:    stop,
+		client:  &http.Client{Timeout: webhookTimeout},
+		poll:    webhookPoll,
+		backoff: game.WebhookBackoff,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Run queues deliveries for events and closing periods, and sends them, until ctx is done.
+// Sending happens on a second goroutine, so a slow receiver doesn't hold up queueing.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	defer w.stop()
+
+	var wg sync.WaitGroup
+	wg.Go(func() { w.sendLoop(ctx) })
+	wg.Go(func() { w.closeLoop(ctx) })
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-w.events:
+			if w.enqueue(ctx, e) > 0 {
+				w.wakeSender()
+			}
+		}
+	}
+}
+
+// NotifyWeekRecap queues week.recap for every active webhook that wants it, along with
+// tie.needs_breaking if the week closed tied.
+func (w *WebhookWorker) NotifyWeekRecap(ctx context.Context, r game.WeekRecap) error {
+	hooks, err := w.s.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(hooks, func(h game.Webhook) bool { return h.IsActive }) {
+		return nil
+	}
+	pMap, err := w.s.playerMap(ctx)
+	if err != nil {
+		return err
+	}
+
+	recap := toAPIWeekRecap(r, recapText(r, pMap))
+	payloads := []webhookPayload{{Event: game.WebhookWeekRecap, Text: recap.Text, Recap: &recap}}
+	if r.TieUnresolved {
+		// The recap is a snapshot; only announce the tie if it still stands.
+		ws, err := w.s.apiWeek(ctx, r.Year, r.Week)
+		if err != nil {
+			return err
+		}
+		if ws.TieUnresolved {
+			payloads = append(payloads, weekTiePayload(ws, pMap))
+		}
+	}
+	for i := range payloads {
+		payloads[i].At = r.CreatedAt
+	}
+
+	if w.queue(ctx, hooks, payloads) > 0 {
+		w.wakeSender()
+	}
+	return nil
+}
+
+// closeLoop announces each week and year as it closes, until ctx is done. On start it
+// catches up on the last closed week and year; delivery keys keep whatever was already
+// announced from going out again.
+func (w *WebhookWorker) closeLoop(ctx context.Context) {
+	for {
+		now := time.Now()
+		wait := time.Until(game.NextWeekClose(now, w.s.loc))
+		if _, end := game.YearBounds(now.In(w.s.loc).Year(), w.s.loc); time.Until(end) < wait {
+			wait = time.Until(end)
+		}
+		if err := w.closePeriods(ctx, now); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("webhooks: close check: %v", err)
+			}
+			wait = min(wait, webhookCloseRetry)
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// closePeriods queues what closing the last week and year to have closed by now announces.
+func (w *WebhookWorker) closePeriods(ctx context.Context, now time.Time) error {
+	hooks, err := w.s.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(hooks, func(h game.Webhook) bool { return h.IsActive }) {
+		return nil
+	}
+	payloads, err := w.s.closedPayloads(ctx, now)
+	if err != nil {
+		return err
+	}
+	if w.queue(ctx, hooks, payloads) > 0 {
+		w.wakeSender()
+	}
+	return nil
+}
+
+// wakeSender has the sender look for due deliveries now rather than at its next poll.
+func (w *WebhookWorker) wakeSender() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sendLoop sends due deliveries when woken and every poll interval, until ctx is done.
+func (w *WebhookWorker) sendLoop(ctx context.Context) {
+	tick := time.NewTicker(w.poll)
+	defer tick.Stop()
+	for {
+		w.sendDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-tick.C:
+		}
+	}
+}
+
+// enqueue queues a delivery of each webhook event e amounts to for every active webhook
+// that wants it, and returns how many it queued.
+func (w *WebhookWorker) enqueue(ctx context.Context, e Event) int {
+	hooks, err := w.s.store.ListWebhooks(ctx)
+	if err != nil {
+		log.Printf("webhooks: %s: %v", e.Type, err)
+		return 0
+	}
+	if !slices.ContainsFunc(hooks, func(h game.Webhook) bool { return h.IsActive }) {
+		return 0
+	}
+
+	payloads, err := w.s.webhookPayloads(ctx, e)
+	if err != nil {
+		log.Printf("webhooks: %s: %v", e.Type, err)
+		return 0
+	}
+	return w.queue(ctx, hooks, payloads)
+}
+
+// queue queues a delivery of each payload for every active webhook in hooks that wants it,
+// unless the webhook already has one with the same key, and returns how many it asked the
+// store to queue.
+func (w *WebhookWorker) queue(ctx context.Context, hooks []game.Webhook, payloads []webhookPayload) int {
+	queued := 0
+	for _, p := range payloads {
+		body, err := json.Marshal(p)
+		if err != nil {
+			log.Printf("webhooks: %s: %v", p.Event, err)
+			continue
+		}
+		for _, h := range hooks {
+			if !h.Wants(p.Event) {
+				continue
+			}
+			if _, err := w.s.store.AddWebhookDelivery(ctx, game.WebhookDelivery{WebhookID: h.ID, Event: p.Event, Payload: body, Key: p.key()}); err != nil {
+				log.Printf("webhooks: queue %s for %q: %v", p.Event, h.Name, err)
+				continue
+			}
+			queued++
+		}
+	}
+	return queued
+}
+
+// sendDue claims the deliveries that are due, attempts each and saves the outcome.
+func (w *WebhookWorker) sendDue(ctx context.Context) {
+	due, err := w.s.store.ClaimWebhookDeliveries(ctx, time.Now(), webhookLease, webhookBatch)
+	if err != nil || len(due) == 0 {
+		if err != nil && ctx.Err() == nil {
+			log.Printf("webhooks: %v", err)
+		}
+		return
+	}
+	hooks, err := w.s.store.ListWebhooks(ctx)
+	if err != nil {
+		log.Printf("webhooks: %v", err)
+		return
+	}
+	byID := make(map[int64]game.Webhook, len(hooks))
+	for _, h := range hooks {
+		byID[h.ID] = h
+	}
+
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		d = w.attempt(ctx, byID[d.WebhookID], d)
+		if err := w.s.store.UpdateWebhookDelivery(ctx, d); err != nil {
+			log.Printf("webhooks: delivery %d: %v", d.ID, err)
+		}
+	}
+}
+
+// attempt sends d to h once and returns it updated with the outcome: delivered on a 2xx
+// answer, otherwise due again after the backoff, or failed once it has used up
+// game.WebhookMaxAttempts. Deliveries for a deactivated webhook fail without being sent.
+func (w *WebhookWorker) attempt(ctx context.Context, h game.Webhook, d game.WebhookDelivery) game.WebhookDelivery {
+	if !h.IsActive {
+		d.Status = game.DeliveryFailed
+		d.LastError = "webhook is inactive"
+		return d
+	}
+
+	d.Attempts++
+	status, err := w.post(ctx, h, d)
+	d.LastStatus = status
+	now := time.Now()
+	if err == nil {
+		d.Status = game.DeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return d
+	}
+	d.LastError = err.Error()
+	if d.Attempts >= game.WebhookMaxAttempts {
+		d.Status = game.DeliveryFailed
+	} else {
+		d.NextAttemptAt = now.Add(w.backoff(d.Attempts))
+	}
+	return d
+}
+
+// post sends d's payload to h and returns the HTTP status, or 0 if there was no response.
+func (w *WebhookWorker) post(ctx context.Context, h game.Webhook, d game.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "master-of-games-webhooks")
+	req.Header.Set("X-MOG-Event", d.Event)
+	req.Header.Set("X-MOG-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-MOG-Signature", game.SignWebhook(h.Secret, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// ============================
+// Admin page
+// ============================
+
+// webhookLogSize is how many recent deliveries the webhooks page lists.
+const webhookLogSize = 50
+
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.renderWebhooks(r.Context(), w, "webhooks", WebhookForm{Events: game.WebhookEvents}, "", "")
+}
+
+// handleWebhooksPost adds a webhook and shows its signing secret once.
+func (s *Server) handleWebhooksPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "Invalid form submission.", "")
+		return
+	}
+
+	form := WebhookForm{
+		Name:   strings.TrimSpace(r.FormValue("name")),
+		URL:    strings.TrimSpace(r.FormValue("url")),
+		Events: r.Form["events"],
+	}
+	secret, err := s.addWebhook(ctx, form)
+	if err != nil {
+		s.renderWebhooks(ctx, w, "main", form, err.Error(), "")
+		return
+	}
+	setToast(w, "Webhook added.")
+	s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "", secret)
+}
+
+// addWebhook stores a new webhook for form and returns its secret. Error messages are
+// user-facing.
+func (s *Server) addWebhook(ctx context.Context, form WebhookForm) (string, error) {
+	h := game.Webhook{Name: form.Name, URL: form.URL, Events: form.Events, IsActive: true}
+	if err := h.Validate(); err != nil {
+		return "", err
+	}
+	secret, err := game.NewWebhookSecret()
+	if err != nil {
+		return "", errors.New("Unable to generate a secret.")
+	}
+	h.Secret = secret
+	if me, ok := UserFrom(ctx); ok {
+		h.CreatedBy = me.Username
+	}
+	if _, err := s.store.AddWebhook(ctx, h); err != nil {
+		return "", errors.New("Unable to save the webhook.")
+	}
+	return secret, nil
+}
+
+// handleWebhookToggle pauses or resumes a webhook. Deliveries due while it is paused fail.
+func (s *Server) handleWebhookToggle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+		return
+	}
+	hooks, err := s.store.ListWebhooks(ctx)
+	if err != nil {
+		s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "Unable to load webhooks.", "")
+		return
+	}
+	i := slices.IndexFunc(hooks, func(h game.Webhook) bool { return h.ID == id })
+	if i < 0 {
+		s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "That webhook no longer exists.", "")
+		return
+	}
+	if err := s.store.SetWebhookActive(ctx, id, !hooks[i].IsActive); err != nil {
+		s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "Unable to update the webhook.", "")
+		return
+	}
+	if hooks[i].IsActive {
+		setToast(w, "Webhook paused.")
+	} else {
+		setToast(w, "Webhook resumed.")
+	}
+	s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "", "")
+}
+
+func (s *Server) handleWebhookDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := pathInt64(r, "id")
+	if err != nil || id <= 0 {
+		http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+		return
+	}
+	if err := s.store.DeleteWebhook(ctx, id); err != nil {
+		s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "Unable to delete the webhook.", "")
+		return
+	}
+	setToast(w, "Webhook deleted.")
+	s.renderWebhooks(ctx, w, "main", WebhookForm{Events: game.WebhookEvents}, "", "")
+}
+
+// renderWebhooks renders the webhooks page; layout is "webhooks" for a full page or "main"
+// for an HTMX swap after a change. newSecret is a just-created webhook's secret to show once.
+func (s *Server) renderWebhooks(ctx context.Context, w http.ResponseWriter, layout string, form WebhookForm, formErr, newSecret string) {
+	hooks, err := s.store.ListWebhooks(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	deliveries, err := s.store.ListWebhookDeliveries(ctx, 0, webhookLogSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	vm := WebhooksVM{
+		Title:     "Webhooks",
+		Version:   s.meta.Version,
+		BuildTime: s.meta.BuildTime,
+		StartTime: s.meta.StartTime,
+		YearNow:   s.now().Year(),
+		Form:      form,
+		FormError: formErr,
+		NewSecret: newSecret,
+	}
+	for _, e := range game.WebhookEvents {
+		vm.Events = append(vm.Events, webhookEventVM{Name: e, Checked: slices.Contains(form.Events, e)})
+	}
+
+	const layoutTime = "Jan 2, 2006 3:04 PM"
+	names := make(map[int64]string, len(hooks))
+	for _, h := range hooks {
+		names[h.ID] = h.Name
+		vm.Webhooks = append(vm.Webhooks, webhookRowVM{
+			ID:        h.ID,
+			Name:      h.Name,
+			URL:       h.URL,
+			Events:    h.Events,
+			CreatedBy: h.CreatedBy,
+			Created:   h.CreatedAt.In(s.loc).Format(layoutTime),
+			Active:    h.IsActive,
+		})
+	}
+	for _, d := range deliveries {
+		row := deliveryRowVM{
+			ID:         d.ID,
+			Webhook:    names[d.WebhookID],
+			Event:      d.Event,
+			Status:     d.Status,
+			Attempts:   d.Attempts,
+			LastStatus: d.LastStatus,
+			LastError:  d.LastError,
+			Created:    d.CreatedAt.In(s.loc).Format(layoutTime),
+		}
+		if d.Status == game.DeliveryPending && d.Attempts > 0 {
+			row.NextAttempt = d.NextAttemptAt.In(s.loc).Format(layoutTime)
+		}
+		if d.DeliveredAt != nil {
+			row.Delivered = d.DeliveredAt.In(s.loc).Format(layoutTime)
+		}
+		vm.Deliveries = append(vm.Deliveries, row)
+	}
+
+	if err := s.r.HTML(w, layout, "webhooks", vm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
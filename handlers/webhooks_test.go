@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eithansmith/master-of-games/game"
+)
+
+// webhookReceiver is a local endpoint that records what it is sent. It answers 500 to the
+// first request, so every test also exercises a retry.
+type webhookReceiver struct {
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	requests int
+	got      []webhookPayload // delivered, in order
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if sig := r.Header.Get("X-MOG-Signature"); sig != game.SignWebhook(rc.secret, body) {
+		rc.t.Errorf("X-MOG-Signature = %q does not match the body", sig)
+	}
+	var p webhookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		rc.t.Errorf("payload %s: %v", body, err)
+	}
+	if ev := r.Header.Get("X-MOG-Event"); ev != p.Event || r.Header.Get("X-MOG-Delivery") == "" {
+		rc.t.Errorf("headers: event %q, delivery %q; payload event %q", ev, r.Header.Get("X-MOG-Delivery"), p.Event)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests++
+	if rc.requests == 1 {
+		http.Error(w, "try again", http.StatusInternalServerError)
+		return
+	}
+	rc.got = append(rc.got, p)
+}
+
+// wait returns the payloads received once there are n of them, failing the test if that
+// takes too long.
+func (rc *webhookReceiver) wait(n int) []webhookPayload {
+	rc.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rc.mu.Lock()
+		got := slices.Clone(rc.got)
+		rc.mu.Unlock()
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			rc.t.Fatalf("received %d payloads, want %d: %+v", len(got), n, got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func payloadEvents(ps []webhookPayload) []string {
+	out := make([]string, 0, len(ps))
+	for _, p := range ps {
+		out = append(out, p.Event)
+	}
+	return out
+}
+
+func TestWebhooks_DeliverSignedEventsWithRetry(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+	_ = s.store.UpdatePlayer(ctx, 1, "Alice")
+	_ = s.store.UpdatePlayer(ctx, 2, "Bob")
+
+	rc := &webhookReceiver{t: t, secret: "whsec_test"}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	all, err := s.store.AddWebhook(ctx, game.Webhook{Name: "chat", URL: ts.URL, Secret: rc.secret, Events: game.WebhookEvents, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	winners, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "winners", URL: ts.URL, Secret: rc.secret, Events: []string{game.WebhookWeekWinnerDecided}, IsActive: true})
+	paused, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "paused", URL: ts.URL, Secret: rc.secret, Events: game.WebhookEvents, IsActive: false})
+
+	worker := s.NewWebhookWorker()
+	worker.poll = 5 * time.Millisecond
+	worker.backoff = func(int) time.Duration { return time.Millisecond }
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() { worker.Run(runCtx); close(done) }()
+	defer func() { cancel(); <-done }()
+
+	// Two games on Monday 2025-01-06 tie a finished week and a finished year.
+	for _, body := range []string{
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	} {
+		if w := doJSON(t, api, "POST", "/api/v1/games", body); w.Code != http.StatusCreated {
+			t.Fatalf("add game: status = %d (%s)", w.Code, w.Body.String())
+		}
+	}
+	// The worker may see both games by the time it handles the first; the tie is still
+	// announced once per period.
+	got := rc.wait(4)
+	events := payloadEvents(got)
+	slices.Sort(events)
+	if want := []string{game.WebhookGameLogged, game.WebhookGameLogged, game.WebhookTieNeedsBreaking, game.WebhookTieNeedsBreaking}; !slices.Equal(events, want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for _, p := range got {
+		switch {
+		case p.Game != nil:
+			if p.Game.ID == 1 && p.Text != "Game logged: "+p.Game.Title+", won by Alice (Alice and Bob played)." {
+				t.Errorf("game.logged text = %q", p.Text)
+			}
+		case p.Week != nil:
+			if p.Week.ScopeKey != "2025-W02" || !p.Week.TieUnresolved || p.Text != "Week 2025-W02 is tied between Alice and Bob and needs a tiebreaker." {
+				t.Errorf("week tie payload = %+v", p)
+			}
+		case p.Year != nil:
+			if p.Year.ScopeKey != "2025" || p.Text != "2025 is tied between Alice and Bob and needs a tiebreaker." {
+				t.Errorf("year tie payload = %+v", p)
+			}
+		default:
+			t.Errorf("payload without a subject: %+v", p)
+		}
+	}
+
+	// Settling the week goes to both webhooks that want it.
+	if w := doJSON(t, api, "POST", "/api/v1/weeks/2025/2/tiebreak", `{"winner_id":1}`); w.Code != http.StatusOK {
+		t.Fatalf("tiebreak: status = %d (%s)", w.Code, w.Body.String())
+	}
+	got = rc.wait(6)[4:]
+	for _, p := range got {
+		if p.Event != game.WebhookWeekWinnerDecided || p.Week == nil || p.Week.WinnerID == nil || *p.Week.WinnerID != 1 {
+			t.Errorf("winner payload = %+v", p)
+		}
+	}
+	if got[0].Text != "Alice wins week 2025-W02. Tie settled by a game of chance." {
+		t.Errorf("text = %q", got[0].Text)
+	}
+
+	// The delivery log: one delivery needed a retry, the rest went first time.
+	log, err := s.store.ListWebhookDeliveries(ctx, all.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 5 {
+		t.Fatalf("chat deliveries = %d, want 5", len(log))
+	}
+	retried := 0
+	for _, d := range log {
+		if d.Status != game.DeliveryDelivered || d.LastStatus != http.StatusOK || d.DeliveredAt == nil {
+			t.Errorf("delivery = %+v, want delivered", d)
+		}
+		if d.Attempts == 2 {
+			retried++
+		}
+	}
+	if retried != 1 {
+		t.Errorf("%d deliveries took two attempts, want 1", retried)
+	}
+	if only, _ := s.store.ListWebhookDeliveries(ctx, winners.ID, 0); len(only) != 1 || only[0].Event != game.WebhookWeekWinnerDecided {
+		t.Errorf("winners deliveries = %+v, want just the winner", only)
+	}
+	if none, _ := s.store.ListWebhookDeliveries(ctx, paused.ID, 0); len(none) != 0 {
+		t.Errorf("paused webhook got %d deliveries", len(none))
+	}
+}
+
+func TestWebhookWorker_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	h, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "down", URL: ts.URL, Secret: "s", Events: game.WebhookEvents, IsActive: true})
+	d, _ := s.store.AddWebhookDelivery(ctx, game.WebhookDelivery{WebhookID: h.ID, Event: game.WebhookGameLogged, Payload: []byte(`{}`)})
+
+	worker := s.NewWebhookWorker()
+	defer worker.stop()
+	start := time.Now()
+	for i := 1; i <= game.WebhookMaxAttempts; i++ {
+		d = worker.attempt(ctx, h, d)
+		if d.Attempts != i || d.LastStatus != http.StatusBadGateway || d.LastError == "" {
+			t.Fatalf("attempt %d: %+v", i, d)
+		}
+		if i < game.WebhookMaxAttempts && (d.Status != game.DeliveryPending || d.NextAttemptAt.Before(start.Add(game.WebhookBackoff(i)))) {
+			t.Fatalf("attempt %d: %+v, want pending with backoff", i, d)
+		}
+	}
+	if d.Status != game.DeliveryFailed {
+		t.Errorf("status = %q after %d attempts, want failed", d.Status, d.Attempts)
+	}
+
+	// A paused webhook's deliveries fail without being sent.
+	h.IsActive = false
+	if d = worker.attempt(ctx, h, game.WebhookDelivery{ID: 2}); d.Status != game.DeliveryFailed || d.Attempts != 0 {
+		t.Errorf("paused delivery = %+v", d)
+	}
+}
+
+func TestWebhookWorker_AnnouncesClosedPeriodsOnce(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{store: game.NewMemoryStore(time.UTC), loc: time.UTC, events: NewEventBus()}
+	api := apiTestHandler(s, "admin", game.RoleAdmin)
+	_ = s.store.UpdatePlayer(ctx, 1, "Alice")
+	_ = s.store.UpdatePlayer(ctx, 2, "Bob")
+	h, _ := s.store.AddWebhook(ctx, game.Webhook{Name: "chat", URL: "http://chat.invalid", Secret: "s", Events: game.WebhookEvents, IsActive: true})
+
+	// Alice wins 2025-W02 two games to one; Bob wins 2025-W03, leaving 2025 tied.
+	addTestGames(t, api,
+		`{"title_id":1,"played_at":"2025-01-06T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-07T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+		`{"title_id":1,"played_at":"2025-01-08T12:00","participant_ids":[1,2],"winner_ids":[1]}`,
+		`{"title_id":1,"played_at":"2025-01-13T12:00","participant_ids":[1,2],"winner_ids":[2]}`,
+	)
+
+	events := func() []string {
+		t.Helper()
+		ds, err := s.store.ListWebhookDeliveries(ctx, h.ID, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, d := range ds {
+			var p webhookPayload
+			if err := json.Unmarshal(d.Payload, &p); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, p.Text)
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	// Saturday 2025-01-11: week 2 has closed with Alice ahead.
+	worker := s.NewWebhookWorker()
+	defer worker.stop()
+	if err := worker.closePeriods(ctx, time.Date(2025, 1, 11, 0, 0, 1, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := events(), []string{"Alice wins week 2025-W02."}; !slices.Equal(got, want) {
+		t.Fatalf("after week 2 closed: %q, want %q", got, want)
+	}
+
+	// New Year's Day 2026: the year has ended tied. A restarted worker catching up on the
+	// same close announces nothing twice.
+	newYear := time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC)
+	for range 2 {
+		restarted := s.NewWebhookWorker()
+		if err := restarted.closePeriods(ctx, newYear); err != nil {
+			t.Fatal(err)
+		}
+		restarted.stop()
+	}
+	want := []string{"2025 is tied between Alice and Bob and needs a tiebreaker.", "Alice wins week 2025-W02."}
+	if got := events(); !slices.Equal(got, want) {
+		t.Errorf("after the year ended: %q, want %q", got, want)
+	}
+}